@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrAbbreviationConflict is returned when a claim name could be confused
+// with an abbreviation, or ClaimAbbreviations maps two abbreviations to the
+// same claim.
+var ErrAbbreviationConflict = errors.New("jwt: conflicting claim abbreviation")
+
+// ClaimAbbreviations maps abbreviated claim names to full claim names, such as
+// {"e": "email"}, to shrink tokens used in size-constrained contexts like QR
+// codes or SMS links. Set as Token.Abbreviations, top-level claims are
+// abbreviated when the token is signed; set as Parser.Abbreviations, they are
+// expanded before the claims are decoded, so application code only sees the
+// full names.
+//
+// Both parties must use the same abbreviations. A token carrying both a claim
+// and its abbreviation is rejected.
+type ClaimAbbreviations map[string]string
+
+// Abbreviate replaces the full names of the top-level members of the JSON
+// object claims with their abbreviations.
+func (a ClaimAbbreviations) Abbreviate(claims []byte) ([]byte, error) {
+	full := make(map[string]string, len(a))
+	for short, name := range a {
+		if _, ok := full[name]; ok {
+			return nil, ErrAbbreviationConflict
+		}
+		full[name] = short
+	}
+	return renameClaims(claims, full)
+}
+
+// Expand replaces the abbreviated names of the top-level members of the JSON
+// object claims with their full names.
+func (a ClaimAbbreviations) Expand(claims []byte) ([]byte, error) {
+	return renameClaims(claims, a)
+}
+
+// renameClaims renames the members of the JSON object data. It is an error
+// for a renamed member to collide with another member.
+func renameClaims(data []byte, names map[string]string) ([]byte, error) {
+	if len(names) == 0 {
+		return data, nil
+	}
+	var in map[string]json.RawMessage
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, err
+	}
+	out := make(map[string]json.RawMessage, len(in))
+	for k, v := range in {
+		if renamed, ok := names[k]; ok {
+			k = renamed
+		}
+		if _, ok := out[k]; ok {
+			return nil, ErrAbbreviationConflict
+		}
+		out[k] = v
+	}
+	return json.Marshal(out)
+}
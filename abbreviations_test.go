@@ -0,0 +1,44 @@
+package jwt_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestClaimAbbreviations(t *testing.T) {
+	key := []byte("secret")
+	abbrev := jwt.ClaimAbbreviations{"e": "email", "n": "name"}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"email": "user@example.com", "name": "User", "sub": "1"})
+	token.Abbreviations = abbrev
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, _ := jwt.DecodeSegment(strings.Split(signed, ".")[1])
+	if string(payload) != `{"e":"user@example.com","n":"User","sub":"1"}` {
+		t.Errorf("Unexpected payload: %s", payload)
+	}
+
+	p := &jwt.Parser{Abbreviations: abbrev}
+	parsed, err := p.Parse(signed, func(*jwt.Token) (interface{}, error) { return key, nil })
+	if err != nil {
+		t.Fatalf("Error parsing token: %v", err)
+	}
+	if claims := parsed.Claims.(jwt.MapClaims); claims["email"] != "user@example.com" || claims["name"] != "User" {
+		t.Errorf("Expected expanded claims. Got: %v", claims)
+	}
+
+	// A token carrying both a claim and its abbreviation is rejected.
+	both, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"e": "a", "email": "b"}).SignedString(key)
+	if _, err = p.Parse(both, func(*jwt.Token) (interface{}, error) { return key, nil }); !errors.Is(err, jwt.ErrMalformedToken) {
+		t.Errorf("Expected ErrMalformedToken. Got: %v", err)
+	}
+
+	if _, err = (jwt.ClaimAbbreviations{"a": "x", "b": "x"}).Abbreviate([]byte(`{"x":1}`)); !errors.Is(err, jwt.ErrAbbreviationConflict) {
+		t.Errorf("Expected ErrAbbreviationConflict. Got: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package jwt
+
+// AccessTokenClaims are the claims of an OAuth 2.0 access token in the JWT
+// profile of https://datatracker.ietf.org/doc/html/rfc9068. Parse access
+// tokens with NewAccessTokenParser to enforce the rules of the profile.
+type AccessTokenClaims struct {
+	RegisteredClaims
+	AuthenticationClaims
+
+	// the `client_id` claim, the client the token was issued to. See https://datatracker.ietf.org/doc/html/rfc8693#section-4.3
+	ClientID string `json:"client_id,omitempty"`
+
+	// the `scope` claim, the scopes granted to the client
+	Scope Scopes `json:"scope,omitempty"`
+
+	// the `groups`, `roles` and `entitlements` claims of
+	// https://datatracker.ietf.org/doc/html/rfc7643#section-4.1.2, describing
+	// the authorization of the subject
+	Groups       []string `json:"groups,omitempty"`
+	Roles        []string `json:"roles,omitempty"`
+	Entitlements []string `json:"entitlements,omitempty"`
+}
+
+// AccessTokenRequiredClaims are the claims every access token in the profile
+// of RFC 9068 must carry, as listed in section 2.2.
+var AccessTokenRequiredClaims = []string{"iss", "exp", "aud", "sub", "client_id", "iat", "jti"}
+
+// AccessTokenPolicy returns the Policy of RFC 9068, section 4, for access
+// tokens issued by issuer to the resource server identified by audience: the
+// claims of AccessTokenRequiredClaims must be present, "iss" must be issuer
+// and "aud" must contain audience.
+func AccessTokenPolicy(issuer, audience string) Policy {
+	return Policy{
+		Issuers:           []string{issuer},
+		Audiences:         []string{audience},
+		RequiredClaims:    AccessTokenRequiredClaims,
+		RequireExpiration: true,
+		RequireIssuedAt:   true,
+	}
+}
+
+// NewAccessTokenParser returns a Parser validating access tokens in the
+// profile of RFC 9068 issued by issuer to the resource server identified by
+// audience. The "typ" header must be "at+jwt", so that ID tokens and other
+// JWTs cannot stand in for access tokens, and the claims are validated
+// against AccessTokenPolicy, which may be amended through Parser.Validator.
+// opts are applied after, such as WithValidMethods to restrict the
+// algorithms:
+//
+//	p := jwt.NewAccessTokenParser("https://issuer.example", "https://api.example")
+//	token, err := p.ParseWithClaims(raw, &jwt.AccessTokenClaims{}, keyFunc)
+func NewAccessTokenParser(issuer, audience string, opts ...ParserOption) *Parser {
+	p := &Parser{
+		ValidTypes: []string{TypeAccessToken},
+		Validator:  &Validator{Policy: AccessTokenPolicy(issuer, audience)},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
@@ -0,0 +1,65 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestNewAccessTokenParser(t *testing.T) {
+	now := time.Now()
+	claims := func() *jwt.AccessTokenClaims {
+		return &jwt.AccessTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "https://issuer.example",
+				Subject:   "user",
+				Audience:  jwt.ClaimStrings{"https://api.example"},
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+				IssuedAt:  jwt.NewNumericDate(now),
+				ID:        "id-1",
+			},
+			AuthenticationClaims: jwt.AuthenticationClaims{ACR: "urn:mace:incommon:iap:silver"},
+			ClientID:             "client",
+			Scope:                jwt.Scopes{"read", "write"},
+		}
+	}
+	sign := func(c *jwt.AccessTokenClaims, opts ...jwt.TokenOption) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c, opts...).SignedString(hmacTestKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	p := jwt.NewAccessTokenParser("https://issuer.example", "https://api.example")
+
+	parsed := &jwt.AccessTokenClaims{}
+	if _, err := p.ParseWithClaims(sign(claims(), jwt.WithType(jwt.TypeAccessToken)), parsed, keyFunc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed.ClientID != "client" || !parsed.Scope.Has("write") || parsed.ACR != "urn:mace:incommon:iap:silver" {
+		t.Errorf("Unexpected claims: %+v", parsed)
+	}
+
+	noClientID := claims()
+	noClientID.ClientID = ""
+	otherAudience := claims()
+	otherAudience.Audience = jwt.ClaimStrings{"https://other.example"}
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"ID token", sign(claims()), jwt.ErrTokenInvalidType},
+		{"missing client_id", sign(noClientID, jwt.WithType(jwt.TypeAccessToken)), jwt.ErrTokenRequiredClaimMissing},
+		{"wrong audience", sign(otherAudience, jwt.WithType(jwt.TypeAccessToken)), jwt.ErrTokenInvalidAudience},
+	}
+	for _, tc := range tests {
+		if _, err := p.ParseWithClaims(tc.token, &jwt.AccessTokenClaims{}, keyFunc); !errors.Is(err, tc.err) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+)
+
+// Codes of the findings reported by AuditKey.
+const (
+	FindingShortSecret      = "short-secret"       // An HMAC secret shorter than MinHMACSecretSize
+	FindingSmallModulus     = "small-modulus"      // An RSA modulus shorter than MinRSAKeySize
+	FindingSmallExponent    = "small-exponent"     // An RSA public exponent below 65537
+	FindingNonStandardCurve = "non-standard-curve" // An ECDSA curve other than P-256, P-384 and P-521
+	FindingMissingKeyID     = "missing-kid"        // A member of a key set without a key ID
+)
+
+// Thresholds applied by AuditKey.
+const (
+	MinHMACSecretSize = 32   // Bytes; the output size of SHA-256, as required for HS256
+	MinRSAKeySize     = 2048 // Bits
+)
+
+// Finding is a weakness of a key reported by AuditKey.
+type Finding struct {
+	Code    string // One of the Finding constants
+	KeyID   string // The key ID of the key, if it is a member of a key set
+	Message string
+}
+
+func (f Finding) String() string {
+	if f.KeyID != "" {
+		return fmt.Sprintf("%s: key %q: %s", f.Code, f.KeyID, f.Message)
+	}
+	return f.Code + ": " + f.Message
+}
+
+// AuditKey reports the weaknesses of key, which is a []byte HMAC secret, a
+// SecretProvider, whose secrets are each audited, an RSA, ECDSA or Ed25519
+// key, public or private, or a *VerificationKeySet, whose members are each
+// audited and expected to carry a key ID. A key without findings returns none.
+// The error is ErrInvalidKeyType for keys of other types.
+func AuditKey(key interface{}) ([]Finding, error) {
+	set, ok := key.(*VerificationKeySet)
+	if !ok {
+		return auditKey(key)
+	}
+	var findings []Finding
+	for _, k := range set.Keys {
+		f, err := auditKey(k.Key)
+		if err != nil {
+			return nil, err
+		}
+		if k.KeyID == "" && len(set.Keys) > 1 {
+			f = append(f, Finding{Code: FindingMissingKeyID, Message: "key has no ID, so every key in the set is tried in turn"})
+		}
+		for i := range f {
+			f[i].KeyID = k.KeyID
+		}
+		findings = append(findings, f...)
+	}
+	return findings, nil
+}
+
+func auditKey(key interface{}) ([]Finding, error) {
+	switch k := publicKey(key).(type) {
+	case SecretProvider:
+		var findings []Finding
+		for _, secret := range k.Secrets() {
+			f, _ := auditKey(secret)
+			findings = append(findings, f...)
+		}
+		return findings, nil
+	case []byte:
+		if len(k) < MinHMACSecretSize {
+			return []Finding{{Code: FindingShortSecret, Message: fmt.Sprintf("secret is %d bytes, at least %d are required", len(k), MinHMACSecretSize)}}, nil
+		}
+	case *rsa.PublicKey:
+		var findings []Finding
+		if bits := k.N.BitLen(); bits < MinRSAKeySize {
+			findings = append(findings, Finding{Code: FindingSmallModulus, Message: fmt.Sprintf("modulus is %d bits, at least %d are required", bits, MinRSAKeySize)})
+		}
+		if k.E < 65537 {
+			findings = append(findings, Finding{Code: FindingSmallExponent, Message: fmt.Sprintf("public exponent is %d, 65537 is recommended", k.E)})
+		}
+		return findings, nil
+	case *ecdsa.PublicKey:
+		switch name := k.Curve.Params().Name; name {
+		case "P-256", "P-384", "P-521":
+		default:
+			return []Finding{{Code: FindingNonStandardCurve, Message: fmt.Sprintf("curve %q is not registered for use with JWS", name)}}, nil
+		}
+	case ed25519.PublicKey:
+	default:
+		return nil, ErrInvalidKeyType
+	}
+	return nil, nil
+}
@@ -0,0 +1,74 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestAuditKey(t *testing.T) {
+	smallRSA, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p224, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name  string
+		key   interface{}
+		codes []string
+	}{
+		{"short secret", []byte("secret"), []string{jwt.FindingShortSecret}},
+		{"secret", make([]byte, 32), nil},
+		{"small modulus", smallRSA, []string{jwt.FindingSmallModulus}},
+		{"small exponent", &rsa.PublicKey{N: smallRSA.N, E: 3}, []string{jwt.FindingSmallModulus, jwt.FindingSmallExponent}},
+		{"non-standard curve", &p224.PublicKey, []string{jwt.FindingNonStandardCurve}},
+		{"P-256", p256, nil},
+		{"key set", &jwt.VerificationKeySet{Keys: []jwt.VerificationKey{
+			{KeyID: "a", Key: []byte("secret")},
+			{Key: p256},
+		}}, []string{jwt.FindingShortSecret, jwt.FindingMissingKeyID}},
+		{"single key set member", jwt.NewVerificationKeySet(p256), nil},
+	}
+	for _, data := range tests {
+		findings, err := jwt.AuditKey(data.key)
+		if err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+			continue
+		}
+		var codes []string
+		for _, f := range findings {
+			codes = append(codes, f.Code)
+		}
+		if !reflect.DeepEqual(codes, data.codes) {
+			t.Errorf("[%v] Findings = %v, want %v", data.name, findings, data.codes)
+		}
+	}
+
+	if _, err := jwt.AuditKey("secret"); !errors.Is(err, jwt.ErrInvalidKeyType) {
+		t.Errorf("expected ErrInvalidKeyType, got %v", err)
+	}
+}
+
+func TestAuditKey_secretSet(t *testing.T) {
+	findings, err := jwt.AuditKey(jwt.SecretSet{make([]byte, 32), make([]byte, 8)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Code != jwt.FindingShortSecret {
+		t.Errorf("Unexpected findings: %v", findings)
+	}
+}
@@ -0,0 +1,64 @@
+package jwt
+
+import "encoding/json"
+
+// AuthenticationClaims are the claims describing how the end-user was
+// authenticated, as referenced at
+// https://openid.net/specs/openid-connect-core-1_0.html#IDToken and
+// https://datatracker.ietf.org/doc/html/rfc8176. Embed it alongside
+// RegisteredClaims in a user-defined claim type to enforce step-up
+// authentication with WithRequiredACR, WithRequiredAMR and WithMaxAuthAge.
+type AuthenticationClaims struct {
+	// the `acr` (Authentication Context Class Reference) claim
+	ACR string `json:"acr,omitempty"`
+
+	// the `amr` (Authentication Methods References) claim, such as "pwd" or "otp"
+	AMR ClaimStrings `json:"amr,omitempty"`
+
+	// the `auth_time` claim, the time the end-user authenticated
+	AuthTime *NumericDate `json:"auth_time,omitempty"`
+}
+
+// HasAMR reports whether every one of methods is listed in the amr claim.
+func (c AuthenticationClaims) HasAMR(methods ...string) bool {
+	for _, m := range methods {
+		if !containsString(c.AMR, m) {
+			return false
+		}
+	}
+	return true
+}
+
+// ACR returns the acr field of the MapClaims, or the empty string if it is
+// unset or not a string.
+func (m MapClaims) ACR() string {
+	acr, _ := m["acr"].(string)
+	return acr
+}
+
+// AMR returns the amr field of the MapClaims. Like aud, it may be a single
+// string or an array of strings.
+func (m MapClaims) AMR() ([]string, error) {
+	return claimStrings(m["amr"], "amr")
+}
+
+// AuthTime returns the time.Time parsed auth_time field of the MapClaims if
+// present, or nil otherwise.
+func (m MapClaims) AuthTime() interface{} {
+	v := m["auth_time"]
+	switch at := v.(type) {
+	case float64:
+		if at == 0 {
+			return nil
+		}
+		return newNumericDateFromSeconds(at).Time
+	case json.Number:
+		f, _ := at.Float64()
+		if f == 0 {
+			return nil
+		}
+		return newNumericDateFromSeconds(f).Time
+	default:
+		return v
+	}
+}
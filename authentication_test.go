@@ -0,0 +1,69 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+type stepUpClaims struct {
+	jwt.RegisteredClaims
+	jwt.AuthenticationClaims
+}
+
+func TestValidator_authentication(t *testing.T) {
+	const mfa = "urn:example:acr:mfa"
+
+	var tests = []struct {
+		name   string
+		claims jwt.Claims
+		opts   []jwt.ValidatorOption
+		err    error
+	}{
+		{"acr", &stepUpClaims{AuthenticationClaims: jwt.AuthenticationClaims{ACR: mfa}}, []jwt.ValidatorOption{jwt.WithRequiredACR(mfa)}, nil},
+		{"insufficient acr", &stepUpClaims{AuthenticationClaims: jwt.AuthenticationClaims{ACR: "0"}}, []jwt.ValidatorOption{jwt.WithRequiredACR(mfa)}, jwt.ErrTokenInsufficientACR},
+		{"missing acr", jwt.MapClaims{}, []jwt.ValidatorOption{jwt.WithRequiredACR(mfa)}, jwt.ErrTokenInsufficientACR},
+		{"amr", jwt.MapClaims{"amr": []interface{}{"pwd", "otp"}}, []jwt.ValidatorOption{jwt.WithRequiredAMR("otp")}, nil},
+		{"amr string", jwt.MapClaims{"amr": "otp"}, []jwt.ValidatorOption{jwt.WithRequiredAMR("otp")}, nil},
+		{"insufficient amr", &stepUpClaims{AuthenticationClaims: jwt.AuthenticationClaims{AMR: jwt.ClaimStrings{"pwd"}}}, []jwt.ValidatorOption{jwt.WithRequiredAMR("pwd", "otp")}, jwt.ErrTokenInsufficientAMR},
+		{"both", jwt.MapClaims{"acr": mfa, "amr": []interface{}{"hwk"}}, []jwt.ValidatorOption{jwt.WithRequiredACR(mfa), jwt.WithRequiredAMR("hwk")}, nil},
+	}
+
+	for _, data := range tests {
+		err := jwt.NewValidator(data.opts...).Validate(&jwt.Token{Claims: data.claims})
+		if data.err == nil && err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		} else if !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+}
+
+func TestValidator_maxAuthAge(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	var tests = []struct {
+		name   string
+		claims jwt.Claims
+		err    error
+	}{
+		{"fresh", &stepUpClaims{AuthenticationClaims: jwt.AuthenticationClaims{AuthTime: jwt.NewNumericDate(now.Add(-time.Minute))}}, nil},
+		{"stale", &stepUpClaims{AuthenticationClaims: jwt.AuthenticationClaims{AuthTime: jwt.NewNumericDate(now.Add(-time.Hour))}}, jwt.ErrTokenAuthTooOld},
+		{"stale map", jwt.MapClaims{"auth_time": float64(now.Add(-time.Hour).Unix())}, jwt.ErrTokenAuthTooOld},
+		{"missing", jwt.MapClaims{}, jwt.ErrTokenRequiredClaimMissing},
+	}
+
+	v := jwt.NewValidator(jwt.WithMaxAuthAge(5 * time.Minute))
+	for _, data := range tests {
+		err := v.Validate(&jwt.Token{Claims: data.claims})
+		if data.err == nil && err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		} else if !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+}
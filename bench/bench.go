@@ -0,0 +1,171 @@
+// Package bench provides reproducible end-to-end benchmarks of signing and
+// verifying tokens, so that the effect of options such as pooled signers and
+// key caches can be measured on the hardware they will run on. The cases may
+// be run from a Go benchmark with Run, or from the command line with
+// cmd/jwtbench.
+//
+// Claims are generated from a fixed seed, so every run signs and verifies the
+// same tokens. Keys are generated once per run.
+package bench
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwk"
+	"github.com/chanced/go-jwt/v4/jwttest"
+)
+
+// Defaults used when the corresponding Options field is empty.
+var (
+	DefaultAlgorithms  = []string{"HS256", "RS256", "PS256", "ES256", "EdDSA"}
+	DefaultClaimsSizes = []int{128, 1024, 8192}
+)
+
+// Options selects the benchmark cases.
+type Options struct {
+	Algorithms  []string // The algorithms to benchmark, by "alg" name
+	ClaimsSizes []int    // Approximate sizes in bytes of the encoded claims
+}
+
+// Case is a single benchmark.
+type Case struct {
+	Name      string // Such as "verify/cached/ES256/1024"
+	Algorithm string
+	Size      int
+	Run       func(b *testing.B)
+}
+
+// Cases returns the benchmark cases selected by opts. For each algorithm and
+// claims size, there are cases for:
+//
+//	sign             Token.SignedString
+//	sign/signer      a Signer, which encodes the header once and pools buffers
+//	verify           Parser.ParseWithClaims, with a Keyfunc decoding the key
+//	verify/cached    Parser.ParseWithClaims, with a Keyfunc returning a decoded key
+func Cases(opts Options) ([]Case, error) {
+	algs := opts.Algorithms
+	if len(algs) == 0 {
+		algs = DefaultAlgorithms
+	}
+	sizes := opts.ClaimsSizes
+	if len(sizes) == 0 {
+		sizes = DefaultClaimsSizes
+	}
+
+	var cases []Case
+	for _, alg := range algs {
+		method := jwt.GetSigningMethod(alg)
+		if method == nil {
+			return nil, &jwt.UnregisteredSigningMethodError{Alg: alg}
+		}
+		key, jwkJSON, err := jwt.GenerateKey(alg)
+		if err != nil {
+			return nil, fmt.Errorf("bench: generating %s key: %w", alg, err)
+		}
+		verifyKey, err := decodeJWK(jwkJSON)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := jwt.NewSigner(method, key, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, size := range sizes {
+			claims := Claims(size)
+			token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+			if err != nil {
+				return nil, err
+			}
+			parser := jwt.NewParser(jwt.WithValidMethods(alg))
+			decoding := func(*jwt.Token) (interface{}, error) { return decodeJWK(jwkJSON) }
+			cached := func(*jwt.Token) (interface{}, error) { return verifyKey, nil }
+
+			name := alg + "/" + strconv.Itoa(size)
+			cases = append(cases,
+				Case{Name: "sign/" + name, Algorithm: alg, Size: size, Run: func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						if _, err := jwt.NewWithClaims(method, claims).SignedString(key); err != nil {
+							b.Fatal(err)
+						}
+					}
+				}},
+				Case{Name: "sign/signer/" + name, Algorithm: alg, Size: size, Run: func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						if _, err := signer.Sign(claims); err != nil {
+							b.Fatal(err)
+						}
+					}
+				}},
+				Case{Name: "verify/" + name, Algorithm: alg, Size: size, Run: verify(parser, token, decoding)},
+				Case{Name: "verify/cached/" + name, Algorithm: alg, Size: size, Run: verify(parser, token, cached)},
+			)
+		}
+	}
+	return cases, nil
+}
+
+func verify(parser *jwt.Parser, token string, keyFunc jwt.Keyfunc) func(b *testing.B) {
+	return func(b *testing.B) {
+		b.SetBytes(int64(len(token)))
+		for i := 0; i < b.N; i++ {
+			if _, err := parser.ParseWithClaims(token, jwt.MapClaims{}, keyFunc); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// Claims returns claims whose JSON encoding is approximately size bytes,
+// always the same for a given size.
+func Claims(size int) jwt.MapClaims {
+	r := jwttest.NewDeterministicReader([]byte("bench/" + strconv.Itoa(size)))
+	claims := jwt.MapClaims{"iss": "https://issuer.example", "sub": "subject", "aud": "audience"}
+	buf := make([]byte, 24)
+	for i := 0; estimateSize(claims) < size; i++ {
+		r.Read(buf)
+		claims["c"+strconv.Itoa(i)] = jwt.EncodeSegment(buf)
+	}
+	return claims
+}
+
+func estimateSize(claims jwt.MapClaims) int {
+	n := 2
+	for k, v := range claims {
+		n += len(k) + len(v.(string)) + 6
+	}
+	return n
+}
+
+// decodeJWK decodes a single JWK, as a Keyfunc without a key cache would.
+func decodeJWK(data []byte) (interface{}, error) {
+	set, err := jwk.Parse([]byte(`{"keys":[` + string(data) + `]}`))
+	if err != nil {
+		return nil, err
+	}
+	return set.Keys[0].Materialize()
+}
+
+// Run runs each case with b.Run.
+func Run(b *testing.B, cases []Case) {
+	for _, c := range cases {
+		b.Run(c.Name, c.Run)
+	}
+}
+
+// Report runs each case with testing.Benchmark and writes its result to w, in
+// the format of "go test -bench", named as the sub-benchmarks of
+// BenchmarkCases, so that results can be compared with benchstat. The benchmark time is set with the -test.benchtime flag, after
+// testing.Init has been called.
+func Report(w io.Writer, cases []Case) {
+	for _, c := range cases {
+		r := testing.Benchmark(c.Run)
+		fmt.Fprintf(w, "BenchmarkCases/%s-%d\t%s\t%s\n", strings.Replace(c.Name, " ", "_", -1), runtime.GOMAXPROCS(0), r.String(), r.MemString())
+	}
+}
@@ -0,0 +1,51 @@
+package bench_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4/bench"
+)
+
+func TestClaims(t *testing.T) {
+	for _, size := range []int{128, 1024, 8192} {
+		claims := bench.Claims(size)
+		b, err := json.Marshal(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) < size || len(b) > size+64 {
+			t.Errorf("Claims(%d) encodes to %d bytes", size, len(b))
+		}
+		if !reflect.DeepEqual(claims, bench.Claims(size)) {
+			t.Errorf("Claims(%d) is not reproducible", size)
+		}
+	}
+}
+
+func TestCases(t *testing.T) {
+	cases, err := bench.Cases(bench.Options{Algorithms: []string{"HS256", "ES256"}, ClaimsSizes: []int{256}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 8 {
+		t.Fatalf("len(Cases()) = %d, want 8", len(cases))
+	}
+	for _, c := range cases {
+		if r := testing.Benchmark(c.Run); r.N == 0 {
+			t.Errorf("[%v] did not run", c.Name)
+		}
+	}
+	if _, err := bench.Cases(bench.Options{Algorithms: []string{"XX256"}}); err == nil {
+		t.Error("expected an error for an unregistered algorithm")
+	}
+}
+
+func BenchmarkCases(b *testing.B) {
+	cases, err := bench.Cases(bench.Options{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	bench.Run(b, cases)
+}
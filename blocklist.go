@@ -0,0 +1,102 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/chanced/go-jwt/v4/internal/lru"
+)
+
+// Blocklist records revoked tokens or sessions, identified by keys such as the
+// value of a "jti" or "sid" claim. Entries only need to be kept until the
+// tokens they revoke have expired.
+type Blocklist interface {
+	// Block revokes key until the given time.
+	Block(ctx context.Context, key string, until time.Time) error
+	// Blocked reports whether key is revoked.
+	Blocked(ctx context.Context, key string) (bool, error)
+}
+
+// MemoryBlocklist is a Blocklist held in memory, suitable for a single
+// process and for tests. Expired entries are removed as they are looked up,
+// and periodically as new ones are added. The zero value is ready to use.
+//
+// If MaxEntries is set, the least recently used entries are evicted once it is
+// reached. An evicted key is no longer blocked, so the bound should leave room
+// for every revocation expected within the lifetime of the tokens revoked.
+type MemoryBlocklist struct {
+	MaxEntries int   // Optional. Zero means no limit
+	Hooks      Hooks // Optional. OnCacheEvent is reported to as "blocklist"
+
+	// SweepInterval is how often Start sweeps out expired entries. Defaults
+	// to DefaultSweepInterval.
+	SweepInterval time.Duration
+
+	mu      sync.Mutex
+	entries lru.Expiring
+	stats   CacheStats
+	sweeps  sweeper
+}
+
+// Block implements Blocklist.
+func (b *MemoryBlocklist) Block(_ context.Context, key string, until time.Time) error {
+	b.mu.Lock()
+	now := TimeFunc()
+	if exp, ok := b.entries.Until(key, now); ok && !until.After(exp) {
+		b.mu.Unlock()
+		return nil
+	}
+	b.entries.MaxEntries = b.MaxEntries
+	b.entries.OnEvict = b.evicted
+	evictions := b.stats.Evictions
+	b.entries.AddUntil(key, until, now)
+	evictions = b.stats.Evictions - evictions
+	b.mu.Unlock()
+	b.Hooks.reportEvictions("blocklist", evictions)
+	return nil
+}
+
+// Start removes lapsed revocations every SweepInterval in the background,
+// until Close is called or ctx is done. Without it, they are only removed as
+// they are looked up or as others are added. MemoryBlocklist implements
+// Component.
+func (b *MemoryBlocklist) Start(ctx context.Context) error {
+	return b.sweeps.start(ctx, b.SweepInterval, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.entries.Sweep(TimeFunc())
+	})
+}
+
+// Close stops the sweeps begun by Start.
+func (b *MemoryBlocklist) Close() error {
+	return b.sweeps.close()
+}
+
+func (b *MemoryBlocklist) evicted(string, interface{}) {
+	b.stats.Count(CacheEviction)
+}
+
+// Blocked implements Blocklist.
+func (b *MemoryBlocklist) Blocked(_ context.Context, key string) (bool, error) {
+	b.mu.Lock()
+	_, ok := b.entries.Until(key, TimeFunc())
+	event := CacheMiss
+	if ok {
+		event = CacheHit
+	}
+	b.stats.Count(event)
+	b.mu.Unlock()
+	b.Hooks.ReportCacheEvent("blocklist", event)
+	return ok, nil
+}
+
+// Stats returns a snapshot of the counters of b.
+func (b *MemoryBlocklist) Stats() CacheStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.stats
+	s.Entries = b.entries.Len()
+	return s
+}
@@ -0,0 +1,60 @@
+package jwt_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestMemoryBlocklist(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	ctx := context.Background()
+	var b jwt.MemoryBlocklist
+	if err := b.Block(ctx, "a", now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if blocked, _ := b.Blocked(ctx, "a"); !blocked {
+		t.Error("Expected a to be blocked")
+	}
+	if blocked, _ := b.Blocked(ctx, "b"); blocked {
+		t.Error("Expected b not to be blocked")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if blocked, _ := b.Blocked(ctx, "a"); blocked {
+		t.Error("Expected the entry for a to have expired")
+	}
+}
+
+func TestMemoryBlocklist_MaxEntries(t *testing.T) {
+	ctx := context.Background()
+	until := jwt.TimeFunc().Add(time.Hour)
+	events := map[jwt.CacheEvent]int{}
+	b := &jwt.MemoryBlocklist{
+		MaxEntries: 2,
+		Hooks:      jwt.Hooks{OnCacheEvent: func(cache string, e jwt.CacheEvent) { events[e]++ }},
+	}
+	for _, key := range []string{"a", "b"} {
+		b.Block(ctx, key, until)
+	}
+	b.Blocked(ctx, "a") // b is now the least recently used
+	b.Block(ctx, "c", until)
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if blocked, _ := b.Blocked(ctx, key); blocked != want {
+			t.Errorf("Blocked(%q) = %v, want %v", key, blocked, want)
+		}
+	}
+	want := jwt.CacheStats{Entries: 2, Hits: 3, Misses: 1, Evictions: 1}
+	if got := b.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if events[jwt.CacheHit] != 3 || events[jwt.CacheMiss] != 1 || events[jwt.CacheEviction] != 1 {
+		t.Errorf("events %v", events)
+	}
+}
@@ -0,0 +1,137 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	ErrBundleExpired     = errors.New("jwt: the verification bundle is not valid at this time")
+	ErrBundleKeyExported = errors.New("jwt: only public keys can be exported in a verification bundle")
+)
+
+// VerificationBundle is the configuration needed to verify tokens fully
+// offline, such as on edge devices: the verification keys, the validation
+// policy and the window in which the bundle itself may be used.
+//
+// Bundles are exported with ExportVerificationBundle as a JWS in the
+// flattened JSON serialization, so that the configuration is readable for
+// audits while its integrity is protected by the signature of the exporter.
+type VerificationBundle struct {
+	Policy    Policy
+	Keys      *VerificationKeySet
+	IssuedAt  *NumericDate
+	NotBefore *NumericDate
+	ExpiresAt *NumericDate // Optional. Tokens are rejected once the bundle expires
+}
+
+// bundleClaims is the encoding of a VerificationBundle.
+type bundleClaims struct {
+	Policy    Policy       `json:"policy"`
+	Keys      []bundleKey  `json:"keys"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+}
+
+type bundleKey struct {
+	KeyID     string `json:"kid,omitempty"`
+	PublicKey string `json:"spki"` // The base64 encoded DER SubjectPublicKeyInfo
+}
+
+func (c *bundleClaims) Valid() error {
+	return RegisteredClaims{IssuedAt: c.IssuedAt, NotBefore: c.NotBefore, ExpiresAt: c.ExpiresAt}.Valid()
+}
+
+// ExportVerificationBundle exports policy and the public keys of keys as a
+// VerificationBundle valid from now for validity, or indefinitely if validity
+// is 0. The bundle is signed by signer. Symmetric keys cannot be exported.
+func ExportVerificationBundle(policy Policy, keys *VerificationKeySet, validity time.Duration, signer JSONSigner) ([]byte, error) {
+	now := TimeFunc()
+	claims := &bundleClaims{
+		Policy:    policy,
+		IssuedAt:  NewNumericDate(now),
+		NotBefore: NewNumericDate(now),
+	}
+	if validity > 0 {
+		claims.ExpiresAt = NewNumericDate(now.Add(validity))
+	}
+	for _, k := range keys.Keys {
+		if _, ok := k.Key.([]byte); ok {
+			return nil, ErrBundleKeyExported
+		}
+		der, err := x509.MarshalPKIXPublicKey(publicKey(k.Key))
+		if err != nil {
+			return nil, ErrBundleKeyExported
+		}
+		claims.Keys = append(claims.Keys, bundleKey{KeyID: k.KeyID, PublicKey: base64.StdEncoding.EncodeToString(der)})
+	}
+
+	header := map[string]interface{}{"alg": signer.Method.Alg()}
+	for k, v := range signer.Header {
+		header[k] = v
+	}
+	token := &Token{Method: signer.Method, Header: header, Claims: claims}
+	s, err := token.SignedJSONString(signer.Key)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err = json.Indent(&out, []byte(s), "", "  "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// LoadVerificationBundle verifies a bundle exported by
+// ExportVerificationBundle with the key supplied by keyFunc, and checks that
+// it is within its validity window.
+func LoadVerificationBundle(data []byte, keyFunc Keyfunc) (*VerificationBundle, error) {
+	claims := new(bundleClaims)
+	if _, err := new(Parser).ParseJSON(string(data), claims, keyFunc); err != nil {
+		if errors.Is(err, ErrTokenExpired) || errors.Is(err, ErrTokenNotYetValid) {
+			return nil, ErrBundleExpired
+		}
+		return nil, err
+	}
+
+	b := &VerificationBundle{
+		Policy:    claims.Policy,
+		Keys:      new(VerificationKeySet),
+		IssuedAt:  claims.IssuedAt,
+		NotBefore: claims.NotBefore,
+		ExpiresAt: claims.ExpiresAt,
+	}
+	for _, k := range claims.Keys {
+		der, err := base64.StdEncoding.DecodeString(k.PublicKey)
+		if err != nil {
+			return nil, MalformedTokenError(err.Error())
+		}
+		key, err := x509.ParsePKIXPublicKey(der)
+		if err != nil {
+			return nil, MalformedTokenError(err.Error())
+		}
+		b.Keys.Add(k.KeyID, key)
+	}
+	return b, nil
+}
+
+// Parser returns a Parser applying the policy of the bundle. Use it with
+// the bundle's Keyfunc.
+func (b *VerificationBundle) Parser() *Parser {
+	return &Parser{Validator: &Validator{Policy: b.Policy}}
+}
+
+// Keyfunc supplies the keys of the bundle, as long as the bundle is within
+// its validity window.
+func (b *VerificationBundle) Keyfunc(*Token) (interface{}, error) {
+	now := TimeFunc()
+	if (b.NotBefore != nil && now.Before(b.NotBefore.Time)) || (b.ExpiresAt != nil && now.After(b.ExpiresAt.Time)) {
+		return nil, ErrBundleExpired
+	}
+	return b.Keys, nil
+}
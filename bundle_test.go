@@ -0,0 +1,69 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/test"
+)
+
+func TestVerificationBundle(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	tokenKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	bundleKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	policy := jwt.Policy{Issuers: []string{"https://issuer.example.com"}, Leeway: time.Minute}
+
+	data, err := jwt.ExportVerificationBundle(policy, jwt.NewVerificationKeySet(tokenKey), 24*time.Hour,
+		jwt.JSONSigner{Method: jwt.SigningMethodRS256, Key: bundleKey, Header: map[string]interface{}{"kid": "bundle"}})
+	if err != nil {
+		t.Fatalf("Error exporting bundle: %v", err)
+	}
+	var flattened map[string]interface{}
+	if err = json.Unmarshal(data, &flattened); err != nil || flattened["signature"] == nil {
+		t.Errorf("Expected the flattened JSON serialization. Got: %s", data)
+	}
+
+	trust := func(*jwt.Token) (interface{}, error) { return &bundleKey.PublicKey, nil }
+	bundle, err := jwt.LoadVerificationBundle(data, trust)
+	if err != nil {
+		t.Fatalf("Error loading bundle: %v", err)
+	}
+
+	valid := test.MakeSampleToken(jwt.MapClaims{"iss": "https://issuer.example.com"}, tokenKey)
+	wrongIssuer := test.MakeSampleToken(jwt.MapClaims{"iss": "https://evil.example.com"}, tokenKey)
+	if _, err = bundle.Parser().Parse(valid, bundle.Keyfunc); err != nil {
+		t.Errorf("Error verifying token with bundle: %v", err)
+	}
+	if _, err = bundle.Parser().Parse(wrongIssuer, bundle.Keyfunc); !errors.Is(err, jwt.ErrTokenInvalidIssuer) {
+		t.Errorf("Expected ErrTokenInvalidIssuer. Got: %v", err)
+	}
+
+	// Tampering with the policy invalidates the bundle.
+	flattened["payload"] = jwt.EncodeSegment([]byte(`{"policy":{},"keys":[]}`))
+	tampered, _ := json.Marshal(flattened)
+	if _, err = jwt.LoadVerificationBundle(tampered, trust); !errors.Is(err, jwt.ErrSignatureInvalid) {
+		t.Errorf("Expected ErrSignatureInvalid for a tampered bundle. Got: %v", err)
+	}
+	if _, err = jwt.LoadVerificationBundle(data, func(*jwt.Token) (interface{}, error) { return []byte("other"), nil }); err == nil {
+		t.Error("Expected an error loading the bundle with the wrong key")
+	}
+
+	now = now.Add(48 * time.Hour)
+	if _, err = bundle.Parser().Parse(valid, bundle.Keyfunc); !errors.Is(err, jwt.ErrBundleExpired) {
+		t.Errorf("Expected ErrBundleExpired. Got: %v", err)
+	}
+	if _, err = jwt.LoadVerificationBundle(data, trust); !errors.Is(err, jwt.ErrBundleExpired) {
+		t.Errorf("Expected ErrBundleExpired. Got: %v", err)
+	}
+
+	if _, err = jwt.ExportVerificationBundle(policy, jwt.NewVerificationKeySet([]byte("secret")), 0,
+		jwt.JSONSigner{Method: jwt.SigningMethodRS256, Key: bundleKey}); !errors.Is(err, jwt.ErrBundleKeyExported) {
+		t.Errorf("Expected ErrBundleKeyExported. Got: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package jwt
+
+// CacheEvent is an event reported to Hooks.OnCacheEvent by the in-memory
+// stores of this module, such as MemoryBlocklist.
+type CacheEvent int
+
+const (
+	CacheHit      CacheEvent = iota // A lookup found an entry
+	CacheMiss                       // A lookup found no entry
+	CacheEviction                   // An entry was evicted to stay within the store's bound
+)
+
+func (e CacheEvent) String() string {
+	switch e {
+	case CacheHit:
+		return "hit"
+	case CacheMiss:
+		return "miss"
+	case CacheEviction:
+		return "eviction"
+	}
+	return "unknown"
+}
+
+// CacheStats are counters describing the activity of an in-memory store.
+type CacheStats struct {
+	Entries   int    // Entries currently held
+	Hits      uint64 // Lookups which found an entry
+	Misses    uint64 // Lookups which found no entry
+	Evictions uint64 // Entries evicted to stay within the store's bound
+}
+
+// Record counts event in s and reports it to the OnCacheEvent hook, if set.
+// It is meant for implementations of stores. Stores guarding s with a lock
+// should instead Count under the lock and call Hooks.ReportCacheEvent once it
+// is released, so that the hook may call back into the store.
+func (s *CacheStats) Record(h *Hooks, cache string, event CacheEvent) {
+	s.Count(event)
+	h.ReportCacheEvent(cache, event)
+}
+
+// Count counts event in s, without reporting it.
+func (s *CacheStats) Count(event CacheEvent) {
+	switch event {
+	case CacheHit:
+		s.Hits++
+	case CacheMiss:
+		s.Misses++
+	case CacheEviction:
+		s.Evictions++
+	}
+}
+
+// ReportCacheEvent reports event of cache to the OnCacheEvent hook, if set.
+func (h *Hooks) ReportCacheEvent(cache string, event CacheEvent) {
+	if h != nil && h.OnCacheEvent != nil {
+		h.OnCacheEvent(cache, event)
+	}
+}
+
+// reportEvictions reports n evictions from cache to the OnCacheEvent hook.
+func (h *Hooks) reportEvictions(cache string, n uint64) {
+	for ; n > 0; n-- {
+		h.ReportCacheEvent(cache, CacheEviction)
+	}
+}
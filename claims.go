@@ -3,8 +3,6 @@ package jwt
 import (
 	"crypto/subtle"
 	"time"
-
-	"github.com/hashicorp/go-multierror"
 )
 
 // Claims must just have a Valid method that determines
@@ -13,6 +11,21 @@ type Claims interface {
 	Valid() error
 }
 
+// ClaimsGetter is implemented by claims types which expose the registered
+// claims. The Validator uses it to read "exp", "nbf", "iat", "iss", "sub" and
+// "aud" without converting the claims, so custom claims types participate in
+// validation by implementing it, or by embedding RegisteredClaims. An error
+// is returned if a claim is present but of the wrong type; absent claims
+// return the zero value.
+type ClaimsGetter interface {
+	GetExpirationTime() (*NumericDate, error)
+	GetNotBefore() (*NumericDate, error)
+	GetIssuedAt() (*NumericDate, error)
+	GetIssuer() (string, error)
+	GetSubject() (string, error)
+	GetAudience() (ClaimStrings, error)
+}
+
 // RegisteredClaims are a structured version of the JWT Claims Set,
 // restricted to Registered Claim Names, as referenced at
 // https://datatracker.ietf.org/doc/html/rfc7519#section-4.1
@@ -43,6 +56,12 @@ type RegisteredClaims struct {
 
 	// the `jti` (JWT ID) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.7
 	ID string `json:"jti,omitempty"`
+
+	// the `sid` (Session ID) claim, identifying the session at the issuer. See https://openid.net/specs/openid-connect-frontchannel-1_0.html#ClaimsContents
+	SessionID string `json:"sid,omitempty"`
+
+	// the `cnf` (Confirmation) claim, binding the token to a key. See https://datatracker.ietf.org/doc/html/rfc7800#section-3.1
+	Confirmation *Confirmation `json:"cnf,omitempty"`
 }
 
 // Valid validates time based claims "exp, iat, nbf".
@@ -50,32 +69,57 @@ type RegisteredClaims struct {
 // As well, if any of the above claims are not in the token, it will still
 // be considered a valid claim.
 func (c RegisteredClaims) Valid() error {
-	result := &multierror.Error{}
-	result.ErrorFormat = ValidationErrorFormat
+	var errs []error
 
 	now := TimeFunc()
 	// The claims below are optional, by default, so if they are set to the
 	// default value in Go, let's not fail the verification for them.
 	if !c.VerifyExpiresAt(now, false) {
-		result = multierror.Append(result, &ExpiredError{
-			ExpiredAt:   c.ExpiresAt.Time,
-			AttemptedAt: now,
-		})
+		errs = append(errs, newExpiredError(c.ExpiresAt.Time, now))
 	}
 	if !c.VerifyIssuedAt(now, false) {
-		result = multierror.Append(result, &UsedBeforeIssuedError{
-			IssuedAt:    c.IssuedAt.Time,
-			AttemptedAt: now,
-		})
+		errs = append(errs, newUsedBeforeIssuedError(c.IssuedAt.Time, now))
 	}
 	if !c.VerifyNotBefore(now, false) {
-		result = multierror.Append(result, &NotYetValidError{
-			ValidAt:     c.NotBefore.Time,
-			AttemptedAt: now,
-		})
+		errs = append(errs, newNotYetValidError(c.NotBefore.Time, now))
 	}
 
-	return result.ErrorOrNil()
+	return JoinErrors(errs...)
+}
+
+// GetExpirationTime implements ClaimsGetter.
+func (c RegisteredClaims) GetExpirationTime() (*NumericDate, error) {
+	return c.ExpiresAt, nil
+}
+
+// GetNotBefore implements ClaimsGetter.
+func (c RegisteredClaims) GetNotBefore() (*NumericDate, error) {
+	return c.NotBefore, nil
+}
+
+// GetIssuedAt implements ClaimsGetter.
+func (c RegisteredClaims) GetIssuedAt() (*NumericDate, error) {
+	return c.IssuedAt, nil
+}
+
+// GetIssuer implements ClaimsGetter.
+func (c RegisteredClaims) GetIssuer() (string, error) {
+	return c.Issuer, nil
+}
+
+// GetSubject implements ClaimsGetter.
+func (c RegisteredClaims) GetSubject() (string, error) {
+	return c.Subject, nil
+}
+
+// GetAudience implements ClaimsGetter.
+func (c RegisteredClaims) GetAudience() (ClaimStrings, error) {
+	return c.Audience, nil
+}
+
+// GetSessionID returns the sid claim.
+func (c RegisteredClaims) GetSessionID() (string, error) {
+	return c.SessionID, nil
 }
 
 // VerifyAudience compares the aud claim against cmp.
@@ -84,6 +128,18 @@ func (c *RegisteredClaims) VerifyAudience(cmp string, req bool) bool {
 	return verifyAud(c.Audience, cmp, req)
 }
 
+// VerifyIssuer compares the iss claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (c *RegisteredClaims) VerifyIssuer(cmp string, req bool) bool {
+	return verifyIss(c.Issuer, cmp, req)
+}
+
+// VerifySubject compares the sub claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (c *RegisteredClaims) VerifySubject(cmp string, req bool) bool {
+	return verifyIss(c.Subject, cmp, req)
+}
+
 // VerifyExpiresAt compares the exp claim against cmp (cmp <= exp).
 // If req is false, it will return true, if exp is unset.
 func (c *RegisteredClaims) VerifyExpiresAt(cmp time.Time, req bool) bool {
@@ -137,8 +193,7 @@ type StandardClaims struct {
 // As well, if any of the above claims are not in the token, it will still
 // be considered a valid claim.
 func (c StandardClaims) Valid() error {
-	result := &multierror.Error{}
-	result.ErrorFormat = ValidationErrorFormat
+	var errs []error
 
 	now := TimeFunc()
 	nowUnix := now.Unix()
@@ -146,24 +201,56 @@ func (c StandardClaims) Valid() error {
 	// default value in Go, let's not fail the verification for them.
 
 	if !c.VerifyExpiresAt(nowUnix, false) {
-		result = multierror.Append(result, &ExpiredError{
-			ExpiredAt:   time.Unix(c.ExpiresAt, 0),
-			AttemptedAt: now,
-		})
+		errs = append(errs, newExpiredError(time.Unix(c.ExpiresAt, 0), now))
 	}
 	if !c.VerifyIssuedAt(nowUnix, false) {
-		result = multierror.Append(result, &UsedBeforeIssuedError{
-			IssuedAt:    time.Unix(c.IssuedAt, 0),
-			AttemptedAt: now,
-		})
+		errs = append(errs, newUsedBeforeIssuedError(time.Unix(c.IssuedAt, 0), now))
 	}
 	if !c.VerifyNotBefore(nowUnix, false) {
-		result = multierror.Append(result, &NotYetValidError{
-			ValidAt:     time.Unix(c.NotBefore, 0),
-			AttemptedAt: now,
-		})
+		errs = append(errs, newNotYetValidError(time.Unix(c.NotBefore, 0), now))
 	}
-	return result.ErrorOrNil()
+	return JoinErrors(errs...)
+}
+
+// GetExpirationTime implements ClaimsGetter.
+func (c StandardClaims) GetExpirationTime() (*NumericDate, error) {
+	return unixNumericDate(c.ExpiresAt), nil
+}
+
+// GetNotBefore implements ClaimsGetter.
+func (c StandardClaims) GetNotBefore() (*NumericDate, error) {
+	return unixNumericDate(c.NotBefore), nil
+}
+
+// GetIssuedAt implements ClaimsGetter.
+func (c StandardClaims) GetIssuedAt() (*NumericDate, error) {
+	return unixNumericDate(c.IssuedAt), nil
+}
+
+// GetIssuer implements ClaimsGetter.
+func (c StandardClaims) GetIssuer() (string, error) {
+	return c.Issuer, nil
+}
+
+// GetSubject implements ClaimsGetter.
+func (c StandardClaims) GetSubject() (string, error) {
+	return c.Subject, nil
+}
+
+// GetAudience implements ClaimsGetter.
+func (c StandardClaims) GetAudience() (ClaimStrings, error) {
+	if c.Audience == "" {
+		return nil, nil
+	}
+	return ClaimStrings{c.Audience}, nil
+}
+
+// unixNumericDate returns the NumericDate of a UNIX epoch, or nil for 0.
+func unixNumericDate(sec int64) *NumericDate {
+	if sec == 0 {
+		return nil
+	}
+	return NewNumericDate(time.Unix(sec, 0))
 }
 
 // VerifyAudience compares the aud claim against cmp.
@@ -211,6 +298,12 @@ func (c *StandardClaims) VerifyIssuer(cmp string, req bool) bool {
 	return verifyIss(c.Issuer, cmp, req)
 }
 
+// VerifySubject compares the sub claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (c *StandardClaims) VerifySubject(cmp string, req bool) bool {
+	return verifyIss(c.Subject, cmp, req)
+}
+
 // ----- helpers
 
 func verifyAud(aud []string, cmp string, required bool) bool {
@@ -0,0 +1,142 @@
+package clientassertion
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// AssertionType is the "client_assertion_type" of JWT client assertions.
+const AssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// DefaultTTL is how long assertions are valid, when Config.TTL is unset.
+// Assertions are used once, right after they are made, so it is short.
+const DefaultTTL = time.Minute
+
+var ErrMissingKey = errors.New("clientassertion: Method or Key not provided")
+
+// Config describes how a client authenticates to a token endpoint. Its fields
+// must not be changed while a TokenSource created from it is in use.
+type Config struct {
+	ClientID string // The client ID, the "iss" and "sub" of assertions
+	TokenURL string // The token endpoint
+
+	// Audience is the "aud" of assertions. It defaults to TokenURL; some
+	// authorization servers expect their issuer identifier instead.
+	Audience string
+
+	Method jwt.SigningMethod // The algorithm assertions are signed with
+	Key    interface{}       // The private key or crypto.Signer assertions are signed with
+	KeyID  string            // Optional. The "kid" header of assertions
+	TTL    time.Duration     // Optional. How long assertions are valid. Defaults to DefaultTTL
+
+	// Scopes and EndpointParams are sent with the token requests of
+	// TokenSource.
+	Scopes         []string
+	EndpointParams url.Values
+}
+
+// Assertion returns a new client assertion: a JWT whose "iss" and "sub" are
+// the client ID and whose "aud" is the token endpoint, with a unique "jti",
+// issued now and expiring after TTL.
+func (c *Config) Assertion() (string, error) {
+	if c.Method == nil || c.Key == nil {
+		return "", ErrMissingKey
+	}
+	id, err := jwt.NewID()
+	if err != nil {
+		return "", err
+	}
+	ttl := c.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	aud := c.Audience
+	if aud == "" {
+		aud = c.TokenURL
+	}
+
+	now := jwt.TimeFunc()
+	claims := &jwt.RegisteredClaims{
+		Issuer:    c.ClientID,
+		Subject:   c.ClientID,
+		Audience:  jwt.ClaimStrings{aud},
+		ID:        id,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+	var opts []jwt.TokenOption
+	if c.KeyID != "" {
+		opts = append(opts, jwt.WithKeyID(c.KeyID))
+	}
+	return jwt.NewWithClaims(c.Method, claims, opts...).SignedString(c.Key)
+}
+
+// AuthParams returns the parameters authenticating the client in a request
+// to the token endpoint, with a new assertion: "client_assertion_type",
+// "client_assertion" and "client_id".
+func (c *Config) AuthParams() (url.Values, error) {
+	assertion, err := c.Assertion()
+	if err != nil {
+		return nil, err
+	}
+	return url.Values{
+		"client_assertion_type": {AssertionType},
+		"client_assertion":      {assertion},
+		"client_id":             {c.ClientID},
+	}, nil
+}
+
+// TokenSource returns an oauth2.TokenSource obtaining tokens with the client
+// credentials grant, authenticating with a new assertion for every request.
+// Tokens are reused until they expire.
+func (c *Config) TokenSource(ctx context.Context) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(nil, &tokenSource{ctx: ctx, conf: c})
+}
+
+// Token obtains a token with the client credentials grant.
+func (c *Config) Token(ctx context.Context) (*oauth2.Token, error) {
+	return c.TokenSource(ctx).Token()
+}
+
+// Client returns an HTTP client which authorizes its requests with tokens
+// from TokenSource.
+func (c *Config) Client(ctx context.Context) *http.Client {
+	return oauth2.NewClient(ctx, c.TokenSource(ctx))
+}
+
+type tokenSource struct {
+	ctx  context.Context
+	conf *Config
+}
+
+func (s *tokenSource) Token() (*oauth2.Token, error) {
+	assertion, err := s.conf.Assertion()
+	if err != nil {
+		return nil, err
+	}
+	// clientcredentials sends client_id itself, and no secret.
+	params := url.Values{
+		"client_assertion_type": {AssertionType},
+		"client_assertion":      {assertion},
+	}
+	for k, v := range s.conf.EndpointParams {
+		if _, ok := params[k]; !ok {
+			params[k] = v
+		}
+	}
+	cc := &clientcredentials.Config{
+		ClientID:       s.conf.ClientID,
+		TokenURL:       s.conf.TokenURL,
+		Scopes:         s.conf.Scopes,
+		EndpointParams: params,
+		AuthStyle:      oauth2.AuthStyleInParams,
+	}
+	return cc.Token(s.ctx)
+}
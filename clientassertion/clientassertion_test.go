@@ -0,0 +1,118 @@
+package clientassertion_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/clientassertion"
+)
+
+func newConfig(t *testing.T, tokenURL string) (*clientassertion.Config, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &clientassertion.Config{
+		ClientID: "client",
+		TokenURL: tokenURL,
+		Method:   jwt.SigningMethodES256,
+		Key:      key,
+		KeyID:    "k1",
+	}, key
+}
+
+func TestConfig_Assertion(t *testing.T) {
+	conf, key := newConfig(t, "https://issuer.example/token")
+
+	assertion, err := conf.Assertion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := new(jwt.RegisteredClaims)
+	token, err := jwt.ParseWithClaims(assertion, claims, func(*jwt.Token) (interface{}, error) {
+		return &key.PublicKey, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if token.Header["kid"] != "k1" {
+		t.Errorf("kid = %v, want k1", token.Header["kid"])
+	}
+	if claims.Issuer != "client" || claims.Subject != "client" {
+		t.Errorf("iss = %v, sub = %v, want client", claims.Issuer, claims.Subject)
+	}
+	if len(claims.Audience) != 1 || claims.Audience[0] != conf.TokenURL {
+		t.Errorf("aud = %v, want %v", claims.Audience, conf.TokenURL)
+	}
+	if claims.ID == "" {
+		t.Error("jti is missing")
+	}
+	if ttl := claims.ExpiresAt.Sub(claims.IssuedAt.Time); ttl != clientassertion.DefaultTTL {
+		t.Errorf("exp - iat = %v, want %v", ttl, clientassertion.DefaultTTL)
+	}
+
+	next, _ := conf.Assertion()
+	if next == assertion {
+		t.Error("Assertion() returned the same assertion twice")
+	}
+
+	if _, err = (&clientassertion.Config{ClientID: "client"}).Assertion(); !errors.Is(err, clientassertion.ErrMissingKey) {
+		t.Errorf("Expected ErrMissingKey, got %v", err)
+	}
+}
+
+func TestConfig_TokenSource(t *testing.T) {
+	var conf *clientassertion.Config
+	var key *ecdsa.PrivateKey
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			return
+		}
+		if got := r.PostForm.Get("grant_type"); got != "client_credentials" {
+			t.Errorf("grant_type = %v", got)
+		}
+		if got := r.PostForm.Get("client_assertion_type"); got != clientassertion.AssertionType {
+			t.Errorf("client_assertion_type = %v", got)
+		}
+		if got := r.PostForm.Get("scope"); got != "read write" {
+			t.Errorf("scope = %v", got)
+		}
+		_, err := jwt.Parse(r.PostForm.Get("client_assertion"), func(*jwt.Token) (interface{}, error) {
+			return &key.PublicKey, nil
+		})
+		if err != nil {
+			t.Errorf("Invalid client assertion: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	conf, key = newConfig(t, srv.URL)
+	conf.Scopes = []string{"read", "write"}
+	ts := conf.TokenSource(context.Background())
+	for i := 0; i < 2; i++ {
+		tok, err := ts.Token()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if tok.AccessToken != "token" || tok.Expiry.Before(time.Now()) {
+			t.Errorf("Token() = %+v", tok)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("The token endpoint was called %d times, want 1", requests)
+	}
+}
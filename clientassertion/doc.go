@@ -0,0 +1,23 @@
+// Package clientassertion authenticates OAuth 2.0 clients to the token
+// endpoint with signed JWTs, the "private_key_jwt" method of
+// https://openid.net/specs/openid-connect-core-1_0.html#ClientAuthentication,
+// profiled by https://datatracker.ietf.org/doc/html/rfc7523#section-2.2.
+//
+// A Config names the client, the token endpoint and the key the client
+// signs with. Config.Assertion builds an assertion, and Config.AuthParams
+// the form parameters carrying it, for use with any HTTP client. For the
+// client credentials grant, Config.TokenSource returns an oauth2.TokenSource
+// which signs a fresh assertion for every token request:
+//
+//	conf := &clientassertion.Config{
+//		ClientID: "client",
+//		TokenURL: "https://issuer.example/token",
+//		Method:   jwt.SigningMethodES256,
+//		Key:      key,
+//		KeyID:    "k1",
+//	}
+//	client := oauth2.NewClient(ctx, conf.TokenSource(ctx))
+//
+// clientassertion is a separate module so that users of the jwt package do
+// not depend on golang.org/x/oauth2.
+package clientassertion
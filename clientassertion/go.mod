@@ -0,0 +1,10 @@
+module github.com/chanced/go-jwt/v4/clientassertion
+
+go 1.23.0
+
+require (
+	github.com/chanced/go-jwt/v4 v4.0.0
+	golang.org/x/oauth2 v0.30.0
+)
+
+replace github.com/chanced/go-jwt/v4 => ../
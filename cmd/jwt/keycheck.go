@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwk"
+)
+
+// checkKey audits the key named by -key and prints its weaknesses. It fails
+// if any are found, so that it may gate deployments in CI scripts.
+func checkKey() error {
+	data, err := loadData(*flagKey)
+	if err != nil {
+		return fmt.Errorf("couldn't read key: %w", err)
+	}
+	key, err := parseAnyKey(data)
+	if err != nil {
+		return fmt.Errorf("couldn't parse key: %w", err)
+	}
+
+	findings, err := jwt.AuditKey(key)
+	if err != nil {
+		return fmt.Errorf("couldn't audit key: %w", err)
+	}
+	if len(findings) == 0 {
+		fmt.Println("no weaknesses found")
+		return nil
+	}
+	for _, f := range findings {
+		fmt.Println(f)
+	}
+	return fmt.Errorf("key has %d weaknesses", len(findings))
+}
+
+// parseSet parses a JWK Set, or a single JWK as a set of one key.
+func parseSet(data []byte) (*jwk.Set, error) {
+	var probe struct {
+		Keys json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+	if probe.Keys == nil {
+		data = []byte(`{"keys":[` + string(data) + `]}`)
+	}
+	return jwk.Parse(data)
+}
+
+// parseAnyKey parses a JWK, JWK Set or PEM encoded key. Other data is taken to
+// be an HMAC secret.
+func parseAnyKey(data []byte) (interface{}, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		set, err := parseSet(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		keys := new(jwt.VerificationKeySet)
+		for _, k := range set.Keys {
+			key, err := k.Materialize()
+			if err != nil {
+				return nil, err
+			}
+			keys.Add(k.KeyID, key)
+		}
+		if len(keys.Keys) == 1 {
+			return keys.Keys[0].Key, nil
+		}
+		return keys, nil
+	case bytes.Contains(trimmed, []byte("-----BEGIN")):
+		parsers := []func([]byte) (interface{}, error){
+			func(b []byte) (interface{}, error) { return jwt.ParseRSAPrivateKeyFromPEM(b) },
+			func(b []byte) (interface{}, error) { return jwt.ParseRSAPublicKeyFromPEM(b) },
+			func(b []byte) (interface{}, error) { return jwt.ParseECPrivateKeyFromPEM(b) },
+			func(b []byte) (interface{}, error) { return jwt.ParseECPublicKeyFromPEM(b) },
+			func(b []byte) (interface{}, error) { return jwt.ParseEdPrivateKeyFromPEM(b) },
+			func(b []byte) (interface{}, error) { return jwt.ParseEdPublicKeyFromPEM(b) },
+		}
+		for _, parse := range parsers {
+			if key, err := parse(trimmed); err == nil {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unsupported PEM key")
+	}
+	return data, nil
+}
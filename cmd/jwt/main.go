@@ -7,6 +7,8 @@
 package main
 
 import (
+	"bytes"
+	"crypto"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -17,14 +19,18 @@ import (
 	"strings"
 
 	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwk"
 )
 
 var (
 	// Options
 	flagAlg     = flag.String("alg", "", "signing algorithm identifier")
-	flagKey     = flag.String("key", "", "path to key file or '-' to read from stdin")
+	flagKey     = flag.String("key", "", "path to key file (PEM, JWK or JWK Set) or '-' to read from stdin")
 	flagCompact = flag.Bool("compact", false, "output compact JSON")
 	flagDebug   = flag.Bool("debug", false, "print out all kinds of debug data")
+	flagErrJSON = flag.Bool("json-errors", false, "report verification failures as JSON")
+	flagJWKS    = flag.String("jwks", "", "URL of a JWK Set to verify against instead of -key")
+	flagExpires = flag.Duration("expires", 0, "with -sign, set exp this far in the future and iat to now")
 	flagClaims  = make(ArgList)
 	flagHead    = make(ArgList)
 
@@ -42,7 +48,8 @@ func main() {
 	// Usage message if you ask for -help or if you mess up inputs.
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  One of the following flags is required: sign, verify\n")
+		fmt.Fprintf(os.Stderr, "  One of the following flags is required: sign, verify, show\n")
+		fmt.Fprintf(os.Stderr, "  or: %s -key <file> keycheck\n", os.Args[0])
 		flag.PrintDefaults()
 	}
 
@@ -65,6 +72,8 @@ func start() error {
 		return verifyToken()
 	} else if *flagShow != "" {
 		return showToken()
+	} else if flag.Arg(0) == "keycheck" {
+		return checkKey()
 	} else {
 		flag.Usage()
 		return fmt.Errorf("none of the required flags are present.  What do you want me to do?")
@@ -127,20 +136,11 @@ func verifyToken() error {
 	}
 
 	// Parse the token.  Load the key from command line option
-	token, err := jwt.Parse(string(tokData), func(t *jwt.Token) (interface{}, error) {
-		data, err := loadData(*flagKey)
-		if err != nil {
-			return nil, err
-		}
-		if isEs() {
-			return jwt.ParseECPublicKeyFromPEM(data)
-		} else if isRs() {
-			return jwt.ParseRSAPublicKeyFromPEM(data)
-		} else if isEd() {
-			return jwt.ParseEdPublicKeyFromPEM(data)
-		}
-		return data, nil
-	})
+	keyFunc, err := verificationKeyfunc()
+	if err != nil {
+		return fmt.Errorf("couldn't read key: %w", err)
+	}
+	token, err := jwt.Parse(string(tokData), keyFunc)
 
 	// Print some debug data
 	if *flagDebug && token != nil {
@@ -148,9 +148,13 @@ func verifyToken() error {
 		fmt.Fprintf(os.Stderr, "Claims:\n%v\n", token.Claims)
 	}
 
-	// Print an error if we can't parse for some reason
+	// Print a report of every problem if we can't parse for some reason
 	if err != nil {
-		return fmt.Errorf("couldn't parse token: %w", err)
+		if *flagErrJSON {
+			printJSON(jwt.NewValidationReport(err))
+			return err
+		}
+		return fmt.Errorf("couldn't parse token: %s", jwt.FormatValidationError(err))
 	}
 
 	// Is token invalid?
@@ -166,6 +170,44 @@ func verifyToken() error {
 	return nil
 }
 
+// verificationKeyfunc returns the Keyfunc for the key named by -key, or for
+// the JWK Set at the URL given by -jwks. Keys in JWK Sets are selected by the
+// token's "kid" header; private keys are reduced to their public half.
+func verificationKeyfunc() (jwt.Keyfunc, error) {
+	if *flagJWKS != "" {
+		remote := &jwk.Remote{URL: *flagJWKS}
+		return remote.Keyfunc, nil
+	}
+	data, err := loadData(*flagKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+	if isEs() {
+		key, err = jwt.ParseECPublicKeyFromPEM(data)
+	} else if isRs() {
+		key, err = jwt.ParseRSAPublicKeyFromPEM(data)
+	} else if isEd() {
+		key, err = jwt.ParseEdPublicKeyFromPEM(data)
+	} else if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) {
+		set, err := parseSet(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		return set.Keyfunc, nil
+	} else {
+		key, err = parseAnyKey(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if signer, ok := key.(crypto.Signer); ok {
+		key = signer.Public()
+	}
+	return func(*jwt.Token) (interface{}, error) { return key, nil }, nil
+}
+
 // Create, sign, and output a token.  This is a great, simple example of
 // how to use this library to create and sign a token.
 func signToken() error {
@@ -189,6 +231,11 @@ func signToken() error {
 			claims[k] = v
 		}
 	}
+	if *flagExpires != 0 {
+		now := jwt.TimeFunc()
+		claims["iat"] = jwt.NewNumericDate(now)
+		claims["exp"] = jwt.NewNumericDate(now.Add(*flagExpires))
+	}
 
 	// get the key
 	var key interface{}
@@ -281,6 +328,13 @@ func showToken() error {
 		return fmt.Errorf("failed to output claims: %w", err)
 	}
 
+	if times := describeTimes(token.Claims.(jwt.MapClaims), jwt.TimeFunc()); len(times) > 0 {
+		fmt.Println("Times:")
+		for _, line := range times {
+			fmt.Println("    " + line)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// timeClaims are the claims holding NumericDates which -show describes.
+var timeClaims = []string{"auth_time", "exp", "iat", "nbf"}
+
+// describeTimes describes the timestamps among claims relative to now, one
+// line per claim, ordered by claim name.
+func describeTimes(claims map[string]interface{}, now time.Time) []string {
+	var lines []string
+	for _, name := range timeClaims {
+		var secs float64
+		switch v := claims[name].(type) {
+		case float64:
+			secs = v
+		case json.Number:
+			f, err := v.Float64()
+			if err != nil {
+				continue
+			}
+			secs = f
+		default:
+			continue
+		}
+		t := time.Unix(0, int64(secs*float64(time.Second))).UTC()
+		lines = append(lines, fmt.Sprintf("%-10s %s (%s)", name+":", t.Format(time.RFC3339), relative(t, now)))
+	}
+	return lines
+}
+
+// relative describes t relative to now, such as "in 5m0s" or "2h0m0s ago".
+func relative(t, now time.Time) string {
+	d := t.Sub(now).Round(time.Second)
+	switch {
+	case d > 0:
+		return "in " + d.String()
+	case d < 0:
+		return (-d).String() + " ago"
+	}
+	return "now"
+}
@@ -0,0 +1,64 @@
+// Command jwtbench runs the benchmarks of the bench package and prints their
+// results in the format of "go test -bench", for comparison with benchstat.
+//
+// Example usage:
+//
+//	jwtbench -alg HS256,ES256 -size 128,4096 -test.benchtime 2s > new.txt
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4/bench"
+)
+
+var (
+	flagAlg   = flag.String("alg", strings.Join(bench.DefaultAlgorithms, ","), "comma-separated algorithms to benchmark")
+	flagSize  = flag.String("size", "", "comma-separated approximate claims sizes in bytes")
+	flagMatch = flag.String("run", "", "only run cases whose name matches this regular expression")
+)
+
+func main() {
+	testing.Init()
+	flag.Parse()
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	opts := bench.Options{Algorithms: strings.Split(*flagAlg, ",")}
+	if *flagSize != "" {
+		for _, s := range strings.Split(*flagSize, ",") {
+			size, err := strconv.Atoi(s)
+			if err != nil {
+				return fmt.Errorf("invalid size %q: %w", s, err)
+			}
+			opts.ClaimsSizes = append(opts.ClaimsSizes, size)
+		}
+	}
+	match, err := regexp.Compile(*flagMatch)
+	if err != nil {
+		return err
+	}
+
+	cases, err := bench.Cases(opts)
+	if err != nil {
+		return err
+	}
+	selected := cases[:0]
+	for _, c := range cases {
+		if match.MatchString(c.Name) {
+			selected = append(selected, c)
+		}
+	}
+	bench.Report(os.Stdout, selected)
+	return nil
+}
@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrClaimCollision is returned when signing a token whose claims type
+// declares the same claim more than once.
+var ErrClaimCollision = errors.New("jwt: claim declared by more than one field")
+
+// ClaimCollisionError describes a claim declared by more than one field of a
+// claims struct, typically a user-defined type which embeds RegisteredClaims
+// and also declares one of the registered claims itself. encoding/json
+// silently keeps only the shallowest of the fields, or drops all of them if
+// they are equally deep, so such tokens are rejected when they are signed.
+type ClaimCollisionError struct {
+	Claim  string   // The name of the claim
+	Fields []string // The fields declaring it, such as "RegisteredClaims.ExpiresAt"
+}
+
+func (err *ClaimCollisionError) Error() string {
+	return ErrClaimCollision.Error() + `: "` + err.Claim + `" is declared by ` + strings.Join(err.Fields, " and ")
+}
+
+func (err *ClaimCollisionError) Unwrap() error {
+	return ErrClaimCollision
+}
+
+// claimCollisions caches the result of checkClaimCollisions per type.
+var claimCollisions sync.Map // map[reflect.Type]error
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// checkClaimCollisions reports claims declared by more than one field of the
+// struct type of claims. Types which encode themselves are not checked.
+func checkClaimCollisions(claims Claims) error {
+	t := reflect.TypeOf(claims)
+	if t == nil {
+		return nil
+	}
+	if err, ok := claimCollisions.Load(t); ok {
+		return errOrNil(err)
+	}
+	var err error
+	s := t
+	if s.Kind() == reflect.Ptr {
+		s = s.Elem()
+	}
+	if s.Kind() == reflect.Struct && !t.Implements(jsonMarshalerType) {
+		fields := make(map[string][]string)
+		var names []string
+		collectClaimFields(s, "", fields, &names, map[reflect.Type]bool{})
+		for _, name := range names {
+			if len(fields[name]) > 1 {
+				err = &ClaimCollisionError{Claim: name, Fields: fields[name]}
+				break
+			}
+		}
+	}
+	claimCollisions.Store(t, err)
+	return err
+}
+
+func errOrNil(v interface{}) error {
+	err, _ := v.(error)
+	return err
+}
+
+// collectClaimFields records the JSON names of the fields of t, descending
+// into embedded structs as encoding/json does. names preserves the order in
+// which claims are first seen.
+func collectClaimFields(t reflect.Type, path string, fields map[string][]string, names *[]string, visiting map[reflect.Type]bool) {
+	if visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			collectClaimFields(ft, path+f.Name+".", fields, names, visiting)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, ok := fields[name]; !ok {
+			*names = append(*names, name)
+		}
+		fields[name] = append(fields[name], path+f.Name)
+	}
+}
@@ -0,0 +1,52 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+type shadowingClaims struct {
+	jwt.RegisteredClaims
+	Exp int64 `json:"exp"`
+}
+
+type shadowingPointerClaims struct {
+	*jwt.AuthenticationClaims
+	jwt.RegisteredClaims
+	AMR []string `json:"amr"`
+}
+
+type distinctClaims struct {
+	jwt.RegisteredClaims
+	jwt.AuthenticationClaims
+	jwt.UnknownClaims
+	Email   string `json:"email"`
+	private string
+	Ignored string `json:"-"`
+}
+
+func TestClaimCollisions(t *testing.T) {
+	var tests = []struct {
+		name   string
+		claims jwt.Claims
+		claim  string
+	}{
+		{"shadowing", &shadowingClaims{}, "exp"},
+		{"shadowing pointer", &shadowingPointerClaims{AuthenticationClaims: &jwt.AuthenticationClaims{}}, "amr"},
+		{"distinct", &distinctClaims{}, ""},
+		{"map", jwt.MapClaims{"exp": 1}, ""},
+	}
+
+	for _, data := range tests {
+		_, err := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims).SignedString([]byte("secret"))
+		var collision *jwt.ClaimCollisionError
+		switch {
+		case data.claim == "" && err != nil:
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		case data.claim != "" && (!errors.As(err, &collision) || collision.Claim != data.claim):
+			t.Errorf("[%v] Expected a collision of %q. Got: %v", data.name, data.claim, err)
+		}
+	}
+}
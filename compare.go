@@ -0,0 +1,105 @@
+package jwt
+
+import "crypto/subtle"
+
+// CompareOption configures the comparisons of VerifyAudience, VerifyIssuer
+// and VerifySubject.
+type CompareOption func(*compareOptions)
+
+type compareOptions struct {
+	constantTime bool
+}
+
+// ConstantTime compares claims in time independent of their contents, for
+// claims which must not be guessed by timing the comparison. The methods of
+// the claims types always compare in constant time.
+func ConstantTime() CompareOption {
+	return func(o *compareOptions) {
+		o.constantTime = true
+	}
+}
+
+func (o *compareOptions) equal(a, b string) bool {
+	if o.constantTime {
+		return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+	}
+	return a == b
+}
+
+// VerifyAudience reports whether the "aud" claim of any claims type contains
+// cmp. If required is false, it also returns true if "aud" is unset. A claim
+// of the wrong type never matches.
+func VerifyAudience(claims Claims, cmp string, required bool, opts ...CompareOption) bool {
+	getter, err := claimsGetter(claims)
+	if err != nil {
+		return false
+	}
+	aud, err := getter.GetAudience()
+	if err != nil {
+		return false
+	}
+	o := newCompareOptions(opts)
+	found, empty := false, true
+	for _, a := range aud {
+		// Every entry is compared, so that the time taken does not reveal
+		// which one matched.
+		if o.equal(a, cmp) {
+			found = true
+		}
+		if a != "" {
+			empty = false
+		}
+	}
+	if empty {
+		return !required
+	}
+	return found
+}
+
+// VerifyIssuer reports whether the "iss" claim of any claims type is cmp. If
+// required is false, it also returns true if "iss" is unset. A claim of the
+// wrong type never matches.
+func VerifyIssuer(claims Claims, cmp string, required bool, opts ...CompareOption) bool {
+	getter, err := claimsGetter(claims)
+	if err != nil {
+		return false
+	}
+	iss, err := getter.GetIssuer()
+	return err == nil && verifyString(iss, cmp, required, opts)
+}
+
+// VerifySubject reports whether the "sub" claim of any claims type is cmp. If
+// required is false, it also returns true if "sub" is unset. A claim of the
+// wrong type never matches.
+func VerifySubject(claims Claims, cmp string, required bool, opts ...CompareOption) bool {
+	getter, err := claimsGetter(claims)
+	if err != nil {
+		return false
+	}
+	sub, err := getter.GetSubject()
+	return err == nil && verifyString(sub, cmp, required, opts)
+}
+
+func verifyString(s, cmp string, required bool, opts []CompareOption) bool {
+	if s == "" {
+		return !required
+	}
+	return newCompareOptions(opts).equal(s, cmp)
+}
+
+func newCompareOptions(opts []CompareOption) *compareOptions {
+	o := new(compareOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// claimsGetter returns claims as a ClaimsGetter, converting claims types which
+// do not implement it through their JSON encoding.
+func claimsGetter(claims Claims) (ClaimsGetter, error) {
+	if g, ok := claims.(ClaimsGetter); ok {
+		return g, nil
+	}
+	return claimsMap(claims)
+}
@@ -0,0 +1,60 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// customClaims does not implement jwt.ClaimsGetter.
+type customClaims struct {
+	Issuer  string   `json:"iss"`
+	Subject string   `json:"sub"`
+	Aud     []string `json:"aud"`
+}
+
+func (customClaims) Valid() error { return nil }
+
+func TestVerifyHelpers(t *testing.T) {
+	claimsTypes := []jwt.Claims{
+		jwt.MapClaims{"iss": "issuer", "sub": "user", "aud": []interface{}{"api", "web"}},
+		&jwt.RegisteredClaims{Issuer: "issuer", Subject: "user", Audience: jwt.ClaimStrings{"api", "web"}},
+		customClaims{Issuer: "issuer", Subject: "user", Aud: []string{"api", "web"}},
+	}
+	for _, claims := range claimsTypes {
+		for _, opts := range [][]jwt.CompareOption{nil, {jwt.ConstantTime()}} {
+			if !jwt.VerifyAudience(claims, "web", true, opts...) || jwt.VerifyAudience(claims, "billing", false, opts...) {
+				t.Errorf("%T: unexpected result of VerifyAudience", claims)
+			}
+			if !jwt.VerifyIssuer(claims, "issuer", true, opts...) || jwt.VerifyIssuer(claims, "other", false, opts...) {
+				t.Errorf("%T: unexpected result of VerifyIssuer", claims)
+			}
+			if !jwt.VerifySubject(claims, "user", true, opts...) || jwt.VerifySubject(claims, "admin", false, opts...) {
+				t.Errorf("%T: unexpected result of VerifySubject", claims)
+			}
+		}
+	}
+
+	empty := jwt.MapClaims{}
+	if !jwt.VerifySubject(empty, "user", false) || jwt.VerifySubject(empty, "user", true) {
+		t.Error("unexpected result for an unset claim")
+	}
+	if !jwt.VerifyAudience(empty, "api", false) || jwt.VerifyAudience(empty, "api", true) {
+		t.Error("unexpected result for an unset audience")
+	}
+	if jwt.VerifyIssuer(jwt.MapClaims{"iss": 42}, "42", false) {
+		t.Error("Expected an issuer of the wrong type not to match")
+	}
+}
+
+func TestClaims_VerifySubject(t *testing.T) {
+	if !(&jwt.RegisteredClaims{Subject: "user"}).VerifySubject("user", true) {
+		t.Error("RegisteredClaims: Expected the subject to match")
+	}
+	if !(&jwt.RegisteredClaims{Issuer: "issuer"}).VerifyIssuer("issuer", true) {
+		t.Error("RegisteredClaims: Expected the issuer to match")
+	}
+	if (jwt.MapClaims{"sub": "user"}).VerifySubject("admin", false) {
+		t.Error("MapClaims: Expected the subject not to match")
+	}
+}
@@ -0,0 +1,191 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	ErrMissingConfirmation  = errors.New(`jwt: the token has no "cnf" claim confirming a key`)
+	ErrConfirmationMismatch = errors.New(`jwt: the "cnf" claim does not confirm the presented key`)
+)
+
+// Confirmation is the `cnf` (Confirmation) claim of
+// https://datatracker.ietf.org/doc/html/rfc7800, which binds a token to a key
+// the presenter must prove possession of. A token may be bound by the key
+// itself, its JWK thumbprint, as by DPoP
+// (https://datatracker.ietf.org/doc/html/rfc9449#section-6), or the
+// thumbprint of a certificate, as by mutual TLS
+// (https://datatracker.ietf.org/doc/html/rfc8705#section-3.1).
+type Confirmation struct {
+	// the `jwk` member, the public key as a JWK
+	Key json.RawMessage `json:"jwk,omitempty"`
+
+	// the `kid` member, the ID of a key known to the recipient
+	KeyID string `json:"kid,omitempty"`
+
+	// the `jkt` member, the base64url encoded SHA-256 JWK thumbprint of the key
+	JWKThumbprint string `json:"jkt,omitempty"`
+
+	// the `x5t#S256` member, the base64url encoded SHA-256 thumbprint of the
+	// DER encoding of the certificate
+	X509Thumbprint string `json:"x5t#S256,omitempty"`
+}
+
+// NewKeyConfirmation returns the Confirmation of key, public or private, by
+// its JWK thumbprint.
+func NewKeyConfirmation(key interface{}) (*Confirmation, error) {
+	tp, err := Thumbprint(key, crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	return &Confirmation{JWKThumbprint: EncodeSegment(tp)}, nil
+}
+
+// NewCertificateConfirmation returns the Confirmation of cert by its SHA-256
+// thumbprint.
+func NewCertificateConfirmation(cert *x509.Certificate) *Confirmation {
+	sum := sha256.Sum256(cert.Raw)
+	return &Confirmation{X509Thumbprint: EncodeSegment(sum[:])}
+}
+
+// Verify checks that c confirms presented: an *x509.Certificate, such as the
+// client certificate of a mutual TLS connection, or a public or private key,
+// such as the key of a DPoP proof. A certificate is confirmed by the
+// "x5t#S256" member, or by the "jkt" and "jwk" members like its public key; a
+// key is confirmed by "jkt" or "jwk". Every member present which applies must
+// match, and at least one must. "kid" is not checked, since resolving it
+// depends on the recipient.
+func (c *Confirmation) Verify(presented interface{}) error {
+	var matched bool
+	if cert, ok := presented.(*x509.Certificate); ok {
+		if c.X509Thumbprint != "" {
+			if !equalThumbprint(c.X509Thumbprint, NewCertificateConfirmation(cert).X509Thumbprint) {
+				return ErrConfirmationMismatch
+			}
+			matched = true
+		}
+		presented = cert.PublicKey
+	}
+
+	if c.JWKThumbprint != "" || len(c.Key) > 0 {
+		tp, err := Thumbprint(presented, crypto.SHA256)
+		if err != nil {
+			return err
+		}
+		if c.JWKThumbprint != "" {
+			if !equalThumbprint(c.JWKThumbprint, EncodeSegment(tp)) {
+				return ErrConfirmationMismatch
+			}
+			matched = true
+		}
+		if len(c.Key) > 0 {
+			keyTP, err := jwkThumbprint(c.Key)
+			if err != nil || subtle.ConstantTimeCompare(keyTP, tp) != 1 {
+				return ErrConfirmationMismatch
+			}
+			matched = true
+		}
+	}
+
+	if !matched {
+		return ErrConfirmationMismatch
+	}
+	return nil
+}
+
+// GetConfirmation returns the cnf claim.
+func (c RegisteredClaims) GetConfirmation() (*Confirmation, error) {
+	return c.Confirmation, nil
+}
+
+// VerifyConfirmation checks that the "cnf" claim of token confirms
+// presented, as described by Confirmation.Verify. The claim is read from
+// claims types with a GetConfirmation() (*Confirmation, error) method, such
+// as those embedding RegisteredClaims, and from the JSON encoding of others.
+// Failures are a *ValidationError wrapping ErrMissingConfirmation or
+// ErrConfirmationMismatch.
+func VerifyConfirmation(token *Token, presented interface{}) error {
+	cnf, err := confirmation(token.Claims)
+	if err != nil {
+		return err
+	}
+	if cnf == nil {
+		return &ValidationError{Err: ErrMissingConfirmation, Claim: "cnf"}
+	}
+	if err = cnf.Verify(presented); err != nil {
+		if !errors.Is(err, ErrConfirmationMismatch) {
+			return err
+		}
+		return &ValidationError{Err: err, Claim: "cnf"}
+	}
+	return nil
+}
+
+// confirmation returns the "cnf" claim of claims, or nil if it is absent.
+func confirmation(claims Claims) (*Confirmation, error) {
+	if g, ok := claims.(interface {
+		GetConfirmation() (*Confirmation, error)
+	}); ok {
+		return g.GetConfirmation()
+	}
+	m, err := claimsMap(claims)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := m["cnf"]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	cnf := new(Confirmation)
+	if err = json.Unmarshal(b, cnf); err != nil {
+		return nil, m.invalidType("cnf")
+	}
+	return cnf, nil
+}
+
+// jwkThumbprint computes the SHA-256 JWK thumbprint of the public key encoded
+// as the JWK raw, from its required members.
+func jwkThumbprint(raw json.RawMessage) ([]byte, error) {
+	var members map[string]interface{}
+	if err := json.Unmarshal(raw, &members); err != nil {
+		return nil, err
+	}
+	var required []string
+	switch members["kty"] {
+	case "RSA":
+		required = []string{"e", "kty", "n"}
+	case "EC":
+		required = []string{"crv", "kty", "x", "y"}
+	case "OKP":
+		required = []string{"crv", "kty", "x"}
+	default:
+		return nil, ErrInvalidKeyType
+	}
+	m := make(map[string]string, len(required))
+	for _, name := range required {
+		s, ok := members[name].(string)
+		if !ok {
+			return nil, ErrInvalidKey
+		}
+		m[name] = s
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+	return sum[:], nil
+}
+
+func equalThumbprint(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,85 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestConfirmation_Verify(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	cert := &x509.Certificate{Raw: []byte("certificate"), PublicKey: &key.PublicKey}
+
+	byKey, err := jwt.NewKeyConfirmation(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, _ := json.Marshal(map[string]string{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   jwt.EncodeSegment(key.X.FillBytes(make([]byte, 32))),
+		"y":   jwt.EncodeSegment(key.Y.FillBytes(make([]byte, 32))),
+		"use": "sig",
+	})
+
+	tests := []struct {
+		name      string
+		cnf       *jwt.Confirmation
+		presented interface{}
+		want      error
+	}{
+		{"jkt", byKey, &key.PublicKey, nil},
+		{"jkt private key", byKey, key, nil},
+		{"jkt mismatch", byKey, other, jwt.ErrConfirmationMismatch},
+		{"jkt certificate", byKey, cert, nil},
+		{"jwk", &jwt.Confirmation{Key: x}, key, nil},
+		{"jwk mismatch", &jwt.Confirmation{Key: x}, other, jwt.ErrConfirmationMismatch},
+		{"x5t#S256", jwt.NewCertificateConfirmation(cert), cert, nil},
+		{"x5t#S256 mismatch", jwt.NewCertificateConfirmation(&x509.Certificate{Raw: []byte("other")}), cert, jwt.ErrConfirmationMismatch},
+		{"x5t#S256 key", jwt.NewCertificateConfirmation(cert), key, jwt.ErrConfirmationMismatch},
+		{"kid only", &jwt.Confirmation{KeyID: "k1"}, key, jwt.ErrConfirmationMismatch},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.cnf.Verify(tc.presented); !errors.Is(err, tc.want) {
+				t.Errorf("Verify() = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidator_PresentedKey(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	cnf, err := jwt.NewKeyConfirmation(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bound := &jwt.Token{Claims: &jwt.RegisteredClaims{Confirmation: cnf}}
+	if err = jwt.NewValidator(jwt.WithPresentedKey(&key.PublicKey)).Validate(bound); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err = jwt.NewValidator(jwt.WithPresentedKey(other)).Validate(bound); !errors.Is(err, jwt.ErrConfirmationMismatch) {
+		t.Errorf("Expected ErrConfirmationMismatch, got %v", err)
+	}
+
+	mapClaims := &jwt.Token{Claims: jwt.MapClaims{"cnf": map[string]interface{}{"jkt": cnf.JWKThumbprint}}}
+	if err = jwt.VerifyConfirmation(mapClaims, key); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	unbound := &jwt.Token{Claims: jwt.MapClaims{"sub": "user"}}
+	err = jwt.NewValidator(jwt.WithPresentedKey(key)).Validate(unbound)
+	var verr *jwt.ValidationError
+	if !errors.Is(err, jwt.ErrMissingConfirmation) || !errors.As(err, &verr) || verr.Claim != "cnf" {
+		t.Errorf("Expected ErrMissingConfirmation, got %v", err)
+	}
+}
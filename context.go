@@ -0,0 +1,73 @@
+package jwt
+
+import (
+	"context"
+	"reflect"
+)
+
+type tokenContextKey struct{}
+
+type actorContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the verified token, such as by
+// middleware which verified the token presented with a request.
+func NewContext(ctx context.Context, token *Token) context.Context {
+	return context.WithValue(ctx, tokenContextKey{}, token)
+}
+
+// FromContext returns the verified token stored in ctx by NewContext, if any.
+func FromContext(ctx context.Context) (*Token, bool) {
+	token, ok := ctx.Value(tokenContextKey{}).(*Token)
+	return token, ok && token != nil
+}
+
+// NewActorContext returns a copy of ctx carrying the verified token of the
+// actor: the service or client presenting a request on behalf of the end-user,
+// or subject, whose token is stored with NewContext.
+func NewActorContext(ctx context.Context, token *Token) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, token)
+}
+
+// ActorFromContext returns the verified actor token stored in ctx by
+// NewActorContext, if any.
+func ActorFromContext(ctx context.Context) (*Token, bool) {
+	token, ok := ctx.Value(actorContextKey{}).(*Token)
+	return token, ok && token != nil
+}
+
+// ClaimsFromContext returns the claims of the verified token stored in ctx, if
+// any.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	token, ok := FromContext(ctx)
+	if !ok || token.Claims == nil {
+		return nil, false
+	}
+	return token.Claims, true
+}
+
+// ClaimsAs finds the claims of the verified token stored in ctx and, if they
+// are assignable to the value pointed to by target, sets target to them and
+// returns true. It works like errors.As:
+//
+//	var claims *MyClaims
+//	if jwt.ClaimsAs(ctx, &claims) {
+//		// use claims
+//	}
+//
+// ClaimsAs panics if target is not a non-nil pointer.
+func ClaimsAs(ctx context.Context, target interface{}) bool {
+	val := reflect.ValueOf(target)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		panic("jwt: target must be a non-nil pointer")
+	}
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return false
+	}
+	cv := reflect.ValueOf(claims)
+	if !cv.Type().AssignableTo(val.Type().Elem()) {
+		return false
+	}
+	val.Elem().Set(cv)
+	return true
+}
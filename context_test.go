@@ -0,0 +1,63 @@
+package jwt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestFromContext(t *testing.T) {
+	if _, ok := jwt.FromContext(context.Background()); ok {
+		t.Errorf("Expected no token in empty context")
+	}
+	token := &jwt.Token{Claims: jwt.MapClaims{"sub": "a"}, Valid: true}
+	got, ok := jwt.FromContext(jwt.NewContext(context.Background(), token))
+	if !ok || got != token {
+		t.Errorf("Expected token from context. Got: %v", got)
+	}
+}
+
+type contextTestClaims struct {
+	jwt.RegisteredClaims
+	Tenant string `json:"tenant"`
+}
+
+func TestClaimsAs(t *testing.T) {
+	claims := &contextTestClaims{Tenant: "acme"}
+	ctx := jwt.NewContext(context.Background(), &jwt.Token{Claims: claims, Valid: true})
+
+	var got *contextTestClaims
+	if !jwt.ClaimsAs(ctx, &got) || got.Tenant != "acme" {
+		t.Errorf("Expected claims from context. Got: %v", got)
+	}
+
+	var m jwt.MapClaims
+	if jwt.ClaimsAs(ctx, &m) {
+		t.Errorf("Expected claims of another type not to match")
+	}
+
+	var c jwt.Claims
+	if !jwt.ClaimsAs(ctx, &c) || c != claims {
+		t.Errorf("Expected claims to be assignable to jwt.Claims")
+	}
+
+	if jwt.ClaimsAs(context.Background(), &got) {
+		t.Errorf("Expected no claims in empty context")
+	}
+}
+
+func TestActorFromContext(t *testing.T) {
+	subject, actor := &jwt.Token{Raw: "subject"}, &jwt.Token{Raw: "actor"}
+	ctx := jwt.NewActorContext(jwt.NewContext(context.Background(), subject), actor)
+
+	if got, ok := jwt.FromContext(ctx); !ok || got != subject {
+		t.Errorf("FromContext() = %v, %v", got, ok)
+	}
+	if got, ok := jwt.ActorFromContext(ctx); !ok || got != actor {
+		t.Errorf("ActorFromContext() = %v, %v", got, ok)
+	}
+	if _, ok := jwt.ActorFromContext(context.Background()); ok {
+		t.Error("Expected no actor token")
+	}
+}
@@ -0,0 +1,340 @@
+package cwt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+// This file implements the subset of CBOR (RFC 8949) used by CWTs and COSE:
+// integers, byte and text strings, arrays, maps, tags, booleans, null and
+// floats, with definite lengths only. Values are encoded deterministically,
+// as described in section 4.2.1, with map keys sorted by their encoding.
+//
+// Decoded values are int64, uint64 (for integers beyond the range of int64),
+// []byte, string, []interface{}, cborMap, cborTag, bool, nil and float64.
+
+// CBOR major types.
+const (
+	majorUint   = 0
+	majorNegInt = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorTag    = 6
+	majorSimple = 7
+)
+
+// maxDepth bounds the nesting of arrays, maps and tags accepted by decode.
+const maxDepth = 16
+
+// cborMap is a CBOR map, whose keys are integers or text strings.
+type cborMap []cborPair
+
+type cborPair struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// get returns the value of key in m.
+func (m cborMap) get(key interface{}) (interface{}, bool) {
+	for _, p := range m {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+	return nil, false
+}
+
+// cborTag is a tagged CBOR value.
+type cborTag struct {
+	Number  uint64
+	Content interface{}
+}
+
+func encode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeTo(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeTo(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteByte(majorSimple<<5 | 22)
+	case bool:
+		if v {
+			buf.WriteByte(majorSimple<<5 | 21)
+		} else {
+			buf.WriteByte(majorSimple<<5 | 20)
+		}
+	case int:
+		encodeInt(buf, int64(v))
+	case int64:
+		encodeInt(buf, v)
+	case uint64:
+		writeHead(buf, majorUint, v)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<63 {
+			encodeInt(buf, int64(v))
+			break
+		}
+		buf.WriteByte(majorSimple<<5 | 27)
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], math.Float64bits(v))
+		buf.Write(b[:])
+	case []byte:
+		writeHead(buf, majorBytes, uint64(len(v)))
+		buf.Write(v)
+	case string:
+		writeHead(buf, majorText, uint64(len(v)))
+		buf.WriteString(v)
+	case []interface{}:
+		writeHead(buf, majorArray, uint64(len(v)))
+		for _, e := range v {
+			if err := encodeTo(buf, e); err != nil {
+				return err
+			}
+		}
+	case cborMap:
+		type entry struct{ key, value []byte }
+		entries := make([]entry, len(v))
+		for n, p := range v {
+			key, err := encode(p.Key)
+			if err != nil {
+				return err
+			}
+			value, err := encode(p.Value)
+			if err != nil {
+				return err
+			}
+			entries[n] = entry{key, value}
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i].key, entries[j].key) < 0
+		})
+		writeHead(buf, majorMap, uint64(len(entries)))
+		for _, e := range entries {
+			buf.Write(e.key)
+			buf.Write(e.value)
+		}
+	case cborTag:
+		writeHead(buf, majorTag, v.Number)
+		return encodeTo(buf, v.Content)
+	default:
+		return ErrUnsupportedValue
+	}
+	return nil
+}
+
+func encodeInt(buf *bytes.Buffer, v int64) {
+	if v >= 0 {
+		writeHead(buf, majorUint, uint64(v))
+	} else {
+		writeHead(buf, majorNegInt, uint64(-1-v))
+	}
+}
+
+// writeHead writes the initial byte of a data item of type major, and its
+// argument n in the shortest form.
+func writeHead(buf *bytes.Buffer, major byte, n uint64) {
+	var b [9]byte
+	switch {
+	case n < 24:
+		buf.WriteByte(major<<5 | byte(n))
+		return
+	case n <= math.MaxUint8:
+		b[0], b[1] = major<<5|24, byte(n)
+		buf.Write(b[:2])
+	case n <= math.MaxUint16:
+		b[0] = major<<5 | 25
+		binary.BigEndian.PutUint16(b[1:], uint16(n))
+		buf.Write(b[:3])
+	case n <= math.MaxUint32:
+		b[0] = major<<5 | 26
+		binary.BigEndian.PutUint32(b[1:], uint32(n))
+		buf.Write(b[:5])
+	default:
+		b[0] = major<<5 | 27
+		binary.BigEndian.PutUint64(b[1:], n)
+		buf.Write(b[:9])
+	}
+}
+
+// decode decodes the single CBOR data item data.
+func decode(data []byte) (interface{}, error) {
+	d := decoder{data: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.off != len(d.data) {
+		return nil, ErrMalformed
+	}
+	return v, nil
+}
+
+type decoder struct {
+	data []byte
+	off  int
+}
+
+// head reads the initial byte and argument of a data item.
+func (d *decoder) head() (major, info byte, n uint64, err error) {
+	if d.off >= len(d.data) {
+		return 0, 0, 0, ErrMalformed
+	}
+	b := d.data[d.off]
+	d.off++
+	major, info = b>>5, b&0x1f
+	var size int
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		// Indefinite lengths and reserved values
+		return 0, 0, 0, ErrMalformed
+	}
+	if len(d.data)-d.off < size {
+		return 0, 0, 0, ErrMalformed
+	}
+	for _, c := range d.data[d.off : d.off+size] {
+		n = n<<8 | uint64(c)
+	}
+	d.off += size
+	return major, info, n, nil
+}
+
+func (d *decoder) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.off) {
+		return nil, ErrMalformed
+	}
+	b := d.data[d.off : d.off+int(n)]
+	d.off += int(n)
+	return b, nil
+}
+
+func (d *decoder) value(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, ErrMalformed
+	}
+	major, info, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case majorUint:
+		if n > math.MaxInt64 {
+			return n, nil
+		}
+		return int64(n), nil
+	case majorNegInt:
+		if n > math.MaxInt64 {
+			return nil, ErrUnsupportedValue
+		}
+		return -1 - int64(n), nil
+	case majorBytes:
+		b, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+	case majorText:
+		b, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case majorArray:
+		// Every item takes at least one byte.
+		if n > uint64(len(d.data)-d.off) {
+			return nil, ErrMalformed
+		}
+		a := make([]interface{}, n)
+		for i := range a {
+			if a[i], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return a, nil
+	case majorMap:
+		if n > uint64(len(d.data)-d.off)/2 {
+			return nil, ErrMalformed
+		}
+		m := make(cborMap, n)
+		for i := range m {
+			if m[i].Key, err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+			switch m[i].Key.(type) {
+			case int64, string:
+			default:
+				return nil, ErrUnsupportedValue
+			}
+			if _, dup := m[:i].get(m[i].Key); dup {
+				return nil, ErrMalformed
+			}
+			if m[i].Value, err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case majorTag:
+		content, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return cborTag{Number: n, Content: content}, nil
+	}
+
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		return halfToFloat(uint16(n)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(n))), nil
+	case 27:
+		return math.Float64frombits(n), nil
+	}
+	return nil, ErrUnsupportedValue
+}
+
+// halfToFloat converts an IEEE 754 half-precision float.
+func halfToFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,390 @@
+package cwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+var (
+	ErrMalformed            = errors.New("cwt: the token is not well-formed CBOR or COSE")
+	ErrUnsupportedValue     = errors.New("cwt: the value cannot be represented in a CWT")
+	ErrUnsupportedAlgorithm = errors.New("cwt: the signing method has no COSE algorithm")
+)
+
+// CBOR tags of RFC 8392 and RFC 9052.
+const (
+	TagCWT       = 61
+	TagCOSESign1 = 18
+)
+
+// COSE header parameters.
+const (
+	headerAlg = 1
+	headerKID = 4
+)
+
+// algorithms maps the names of the supported signing methods to their COSE
+// algorithm identifiers.
+var algorithms = map[string]int64{
+	"ES256": -7,
+	"ES384": -35,
+	"ES512": -36,
+	"EdDSA": -8,
+}
+
+// claimKeys maps the names of the registered claims to their CWT keys, as
+// listed in RFC 8392, section 4.
+var claimKeys = map[string]int64{
+	"iss": 1,
+	"sub": 2,
+	"aud": 3,
+	"exp": 4,
+	"nbf": 5,
+	"iat": 6,
+	"jti": 7,
+}
+
+// Algorithm returns the COSE algorithm identifier of method.
+func Algorithm(method jwt.SigningMethod) (int64, error) {
+	alg, ok := algorithms[method.Alg()]
+	if !ok {
+		return 0, ErrUnsupportedAlgorithm
+	}
+	return alg, nil
+}
+
+// Sign serializes claims to CBOR and signs them with method and key,
+// returning a COSE_Sign1 message tagged as a CWT. A non-empty kid is placed
+// in the unprotected header.
+func Sign(method jwt.SigningMethod, claims jwt.Claims, key interface{}, kid []byte) ([]byte, error) {
+	alg, err := Algorithm(method)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := marshalClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+	protected, err := encode(cborMap{{int64(headerAlg), alg}})
+	if err != nil {
+		return nil, err
+	}
+	toBeSigned, err := sigStructure(protected, payload)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := method.Sign(string(toBeSigned), key)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := jwt.DecodeSegment(sig)
+	if err != nil {
+		return nil, err
+	}
+	unprotected := cborMap{}
+	if len(kid) > 0 {
+		unprotected = append(unprotected, cborPair{int64(headerKID), kid})
+	}
+	return encode(cborTag{TagCWT, cborTag{TagCOSESign1, []interface{}{protected, unprotected, payload, signature}}})
+}
+
+// Option configures Parse.
+type Option func(*config)
+
+type config struct {
+	validMethods []string
+	validator    *jwt.Validator
+}
+
+// WithValidMethods restricts the signing methods Parse accepts to those
+// named; by default any supported method is accepted.
+func WithValidMethods(methods ...string) Option {
+	return func(c *config) { c.validMethods = methods }
+}
+
+// WithValidator validates the claims with v once the signature has been
+// verified, in addition to Claims.Valid.
+func WithValidator(v *jwt.Validator) Option {
+	return func(c *config) { c.validator = v }
+}
+
+// Parse verifies the CWT data, which may be tagged as a CWT, a COSE_Sign1
+// message or both, and decodes its claims into claims. keyFunc is called
+// with a token whose Header holds "alg" and, if present, "kid", and whose
+// Method is set. The returned token has Valid set if the signature and
+// claims are valid.
+func Parse(data []byte, claims jwt.Claims, keyFunc jwt.Keyfunc, opts ...Option) (*jwt.Token, error) {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if keyFunc == nil {
+		return nil, jwt.ErrMissingKeyFunc
+	}
+
+	v, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := v.(cborTag); ok && t.Number == TagCWT {
+		v = t.Content
+	}
+	if t, ok := v.(cborTag); ok && t.Number == TagCOSESign1 {
+		v = t.Content
+	}
+	msg, ok := v.([]interface{})
+	if !ok || len(msg) != 4 {
+		return nil, ErrMalformed
+	}
+	protected, ok1 := msg[0].([]byte)
+	unprotected, ok2 := msg[1].(cborMap)
+	payload, ok3 := msg[2].([]byte)
+	signature, ok4 := msg[3].([]byte)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return nil, ErrMalformed
+	}
+
+	var header cborMap
+	if len(protected) > 0 {
+		h, err := decode(protected)
+		if err != nil {
+			return nil, err
+		}
+		if header, ok = h.(cborMap); !ok {
+			return nil, ErrMalformed
+		}
+	}
+	alg, ok := header.get(int64(headerAlg))
+	if !ok {
+		return nil, ErrMalformed
+	}
+	method := methodOf(alg)
+	if method == nil {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	if c.validMethods != nil && !contains(c.validMethods, method.Alg()) {
+		return nil, jwt.ErrInvalidSigningMethod
+	}
+
+	token := &jwt.Token{
+		Method:    method,
+		Header:    map[string]interface{}{"alg": method.Alg()},
+		Claims:    claims,
+		Signature: jwt.EncodeSegment(signature),
+	}
+	if kid, ok := unprotected.get(int64(headerKID)); ok {
+		b, ok := kid.([]byte)
+		if !ok {
+			return nil, ErrMalformed
+		}
+		token.Header["kid"] = string(b)
+	}
+
+	key, err := keyFunc(token)
+	if err != nil {
+		return token, err
+	}
+	toBeSigned, err := sigStructure(protected, payload)
+	if err != nil {
+		return token, err
+	}
+	if err = method.Verify(string(toBeSigned), token.Signature, key); err != nil {
+		return token, jwt.ErrSignatureInvalid
+	}
+
+	if err = unmarshalClaims(payload, claims); err != nil {
+		return token, err
+	}
+	if err = claims.Valid(); err != nil {
+		return token, err
+	}
+	if c.validator != nil {
+		if err = c.validator.Validate(token); err != nil {
+			return token, err
+		}
+	}
+	token.Valid = true
+	return token, nil
+}
+
+// sigStructure returns the Sig_structure of RFC 9052, section 4.4, which
+// is signed in place of the payload.
+func sigStructure(protected, payload []byte) ([]byte, error) {
+	return encode([]interface{}{"Signature1", protected, []byte{}, payload})
+}
+
+func methodOf(alg interface{}) jwt.SigningMethod {
+	id, ok := alg.(int64)
+	if !ok {
+		return nil
+	}
+	for name, v := range algorithms {
+		if v == id {
+			return jwt.GetSigningMethod(name)
+		}
+	}
+	return nil
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// marshalClaims encodes claims, by way of their JSON form, as a CBOR map.
+func marshalClaims(claims jwt.Claims) ([]byte, error) {
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var m map[string]interface{}
+	if err = dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	out := make(cborMap, 0, len(m))
+	for name, value := range m {
+		v, err := fromJSON(value)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := claimKeys[name]
+		if !ok {
+			out = append(out, cborPair{name, v})
+			continue
+		}
+		if name == "jti" {
+			s, ok := v.(string)
+			if !ok {
+				return nil, ErrUnsupportedValue
+			}
+			v = []byte(s)
+		}
+		out = append(out, cborPair{key, v})
+	}
+	return encode(out)
+}
+
+// fromJSON converts a value decoded from JSON to its CBOR form.
+func fromJSON(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			var err error
+			if out[i], err = fromJSON(e); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := make(cborMap, 0, len(v))
+		for k, e := range v {
+			c, err := fromJSON(e)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, cborPair{k, c})
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// unmarshalClaims decodes the CBOR map payload into claims, by way of its
+// JSON form.
+func unmarshalClaims(payload []byte, claims jwt.Claims) error {
+	v, err := decode(payload)
+	if err != nil {
+		return err
+	}
+	m, ok := v.(cborMap)
+	if !ok {
+		return ErrMalformed
+	}
+	out := make(map[string]interface{}, len(m))
+	for _, p := range m {
+		name, ok := p.Key.(string)
+		if !ok {
+			for n, key := range claimKeys {
+				if key == p.Key {
+					name = n
+				}
+			}
+			if name == "" {
+				name = strconv.FormatInt(p.Key.(int64), 10)
+			}
+		}
+		value := p.Value
+		if b, ok := value.([]byte); ok && name == "jti" {
+			value = string(b)
+		}
+		if out[name], err = toJSON(value); err != nil {
+			return err
+		}
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	if c, ok := claims.(jwt.MapClaims); ok {
+		return json.Unmarshal(b, &c)
+	}
+	return json.Unmarshal(b, claims)
+}
+
+// toJSON converts a decoded CBOR value to a form encoding/json can marshal.
+// Byte strings become base64url strings.
+func toJSON(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case []byte:
+		return jwt.EncodeSegment(v), nil
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return nil, ErrUnsupportedValue
+		}
+	case cborTag:
+		return nil, ErrUnsupportedValue
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			var err error
+			if out[i], err = toJSON(e); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case cborMap:
+		out := make(map[string]interface{}, len(v))
+		for _, p := range v {
+			var k string
+			switch key := p.Key.(type) {
+			case string:
+				k = key
+			case int64:
+				k = strconv.FormatInt(key, 10)
+			}
+			var err error
+			if out[k], err = toJSON(p.Value); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	return v, nil
+}
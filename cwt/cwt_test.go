@@ -0,0 +1,149 @@
+package cwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/cwt"
+)
+
+// The signed CWT of RFC 8392, appendix A.3, and its key from appendix A.2.3.
+const (
+	rfcToken = "d28443a10126a104524173796d6d657472696345434453413235365850a70175636f61703a2f2f61732e6578616d706c652e636f6d02656572696b77037818636f61703a2f2f6c696768742e6578616d706c652e636f6d041a5612aeb0051a5610d9f0061a5610d9f007420b7158405427c1ff28d23fbad1f29c4c7c6a555e601d6fa29f9179bc3d7438bacaca5acd08c8d4d4f96131680c429a01f85951ecee743a52b9b63632c57209120e1c9e30"
+	rfcX     = "143329cce7868e416927599cf65a34f3ce2ffda55a7eca69ed8919a394d42f0f"
+	rfcY     = "60f7f1a780d8a783bfb7a2dd6b2796e8128dbbcef9d3d168db9529971a36e7b9"
+)
+
+func fromHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestParse_rfc8392(t *testing.T) {
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return time.Unix(1444000000, 0) }
+
+	key := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(fromHex(t, rfcX)),
+		Y:     new(big.Int).SetBytes(fromHex(t, rfcY)),
+	}
+	var claims jwt.RegisteredClaims
+	token, err := cwt.Parse(fromHex(t, rfcToken), &claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Header["kid"] != "AsymmetricECDSA256" {
+			t.Errorf("Unexpected kid: %v", token.Header["kid"])
+		}
+		return key, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !token.Valid || token.Method != jwt.SigningMethodES256 {
+		t.Errorf("Unexpected token: %+v", token)
+	}
+	if claims.Issuer != "coap://as.example.com" || claims.Subject != "erikw" || claims.ID != "\x0bq" {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+	if len(claims.Audience) != 1 || claims.Audience[0] != "coap://light.example.com" {
+		t.Errorf("Unexpected audience: %v", claims.Audience)
+	}
+	if claims.ExpiresAt.Unix() != 1444064944 || claims.IssuedAt.Unix() != 1443944944 {
+		t.Errorf("Unexpected times: %v %v", claims.ExpiresAt, claims.IssuedAt)
+	}
+
+	jwt.TimeFunc = func() time.Time { return time.Unix(1444064945, 0) }
+	if _, err = cwt.Parse(fromHex(t, rfcToken), &jwt.RegisteredClaims{}, func(*jwt.Token) (interface{}, error) { return key, nil }); !errors.Is(err, jwt.ErrTokenExpired) {
+		t.Errorf("Expected ErrTokenExpired, got %v", err)
+	}
+}
+
+func TestSign(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		method jwt.SigningMethod
+		key    interface{}
+		public interface{}
+	}{
+		{jwt.SigningMethodES256, ecKey, &ecKey.PublicKey},
+		{jwt.SigningMethodEdDSA, edKey, edPub},
+	}
+	for _, tc := range tests {
+		claims := jwt.MapClaims{
+			"iss":   "device",
+			"aud":   []string{"gateway"},
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"jti":   "id",
+			"scope": "read",
+			"temp":  21.5,
+			"loc":   map[string]interface{}{"room": 4},
+		}
+		data, err := cwt.Sign(tc.method, claims, tc.key, []byte("k1"))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.method.Alg(), err)
+		}
+
+		parsed := jwt.MapClaims{}
+		token, err := cwt.Parse(data, parsed, func(token *jwt.Token) (interface{}, error) {
+			if token.Header["kid"] != "k1" {
+				t.Errorf("%s: unexpected kid: %v", tc.method.Alg(), token.Header["kid"])
+			}
+			return tc.public, nil
+		}, cwt.WithValidMethods(tc.method.Alg()))
+		if err != nil || !token.Valid {
+			t.Fatalf("%s: unexpected error: %v", tc.method.Alg(), err)
+		}
+		if parsed["iss"] != "device" || parsed["jti"] != "id" || parsed["scope"] != "read" || parsed["temp"] != 21.5 {
+			t.Errorf("%s: unexpected claims: %v", tc.method.Alg(), parsed)
+		}
+		if loc, ok := parsed["loc"].(map[string]interface{}); !ok || loc["room"] != float64(4) {
+			t.Errorf("%s: unexpected nested claim: %v", tc.method.Alg(), parsed["loc"])
+		}
+
+		data[len(data)-80] ^= 1
+		if _, err = cwt.Parse(data, jwt.MapClaims{}, func(*jwt.Token) (interface{}, error) { return tc.public, nil }); err == nil {
+			t.Errorf("%s: expected an error for a tampered token", tc.method.Alg())
+		}
+	}
+}
+
+func TestSign_errors(t *testing.T) {
+	if _, err := cwt.Sign(jwt.SigningMethodHS256, jwt.MapClaims{}, []byte("secret"), nil); !errors.Is(err, cwt.ErrUnsupportedAlgorithm) {
+		t.Errorf("Expected ErrUnsupportedAlgorithm, got %v", err)
+	}
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	data, err := cwt.Sign(jwt.SigningMethodES256, jwt.MapClaims{"sub": "device"}, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFunc := func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil }
+	if _, err = cwt.Parse(data, jwt.MapClaims{}, keyFunc, cwt.WithValidMethods("EdDSA")); !errors.Is(err, jwt.ErrInvalidSigningMethod) {
+		t.Errorf("Expected ErrInvalidSigningMethod, got %v", err)
+	}
+	if _, err = cwt.Parse(data[:len(data)-1], jwt.MapClaims{}, keyFunc); !errors.Is(err, cwt.ErrMalformed) {
+		t.Errorf("Expected ErrMalformed, got %v", err)
+	}
+	v := &jwt.Validator{Policy: jwt.Policy{Issuers: []string{"issuer"}}}
+	if _, err = cwt.Parse(data, jwt.MapClaims{}, keyFunc, cwt.WithValidator(v)); !errors.Is(err, jwt.ErrTokenInvalidIssuer) {
+		t.Errorf("Expected ErrTokenInvalidIssuer, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+// Package cwt signs and verifies CBOR Web Tokens (CWTs), as described in
+// https://datatracker.ietf.org/doc/html/rfc8392, for constrained devices
+// which cannot afford the size of a JWT.
+//
+// A CWT carries the same claims as a JWT, so the claims types of the jwt
+// package are used unchanged: Sign serializes them to a CBOR map, replacing
+// the names of the registered claims with their integer keys, and signs the
+// map as a COSE_Sign1 message (RFC 9052) with an existing jwt.SigningMethod.
+// Parse reverses this, resolving the key with a jwt.Keyfunc which sees a
+// jwt.Token whose "alg" and "kid" headers are taken from the COSE headers,
+// then validates the claims as jwt.Parser would.
+//
+// ES256, ES384, ES512 and EdDSA are supported. The "cti" claim holds the
+// bytes of the "jti" claim; other claims are carried under their JSON names.
+package cwt
@@ -0,0 +1,31 @@
+//go:build go1.18
+// +build go1.18
+
+package cwt_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/cwt"
+)
+
+func FuzzParse(f *testing.F) {
+	token, err := hex.DecodeString(rfcToken)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(token)
+	f.Add(token[:len(token)/2])
+	f.Add(token[1:])
+	f.Add([]byte{0x9f, 0xff})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		token, err := cwt.Parse(data, jwt.MapClaims{}, func(*jwt.Token) (interface{}, error) {
+			return []byte("fuzz"), nil
+		})
+		if err == nil && !token.Valid {
+			t.Errorf("Parse(%x) returned an invalid token without an error", data)
+		}
+	})
+}
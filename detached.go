@@ -0,0 +1,36 @@
+package jwt
+
+import (
+	"strings"
+)
+
+// SignedStringDetached retrieves the complete, signed token with the payload
+// detached, as described in https://datatracker.ietf.org/doc/html/rfc7515#appendix-F.
+// The result has the form "header..signature"; the claims must be transported
+// separately and supplied to Parser.ParseDetached for verification.
+func (t *Token) SignedStringDetached(key interface{}) (string, error) {
+	sstr, err := t.SignedString(key)
+	if err != nil {
+		return "", err
+	}
+	parts := strings.Split(sstr, ".")
+	return parts[0] + ".." + parts[2], nil
+}
+
+// ParseDetached parses, validates, and returns a token whose payload has been
+// detached (see Token.SignedStringDetached). payload is the externally transported
+// claims set in its JSON form, exactly as it was signed.
+func (p *Parser) ParseDetached(tokenString string, payload []byte, claims Claims, keyFunc Keyfunc) (*Token, error) {
+	parts := strings.Split(tokenString, ".")
+	if len(parts) != 3 {
+		return nil, MalformedTokenError("token contains an invalid number of segments")
+	}
+	if parts[1] != "" {
+		return nil, MalformedTokenError("token payload is not detached")
+	}
+	token, err := p.ParseWithClaims(parts[0]+"."+EncodeSegment(payload)+"."+parts[2], claims, keyFunc)
+	if token != nil {
+		token.Raw = tokenString
+	}
+	return token, err
+}
@@ -0,0 +1,61 @@
+package jwt_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestToken_SignedStringDetached(t *testing.T) {
+	key := []byte("detached secret")
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"})
+
+	detached, err := token.SignedStringDetached(key)
+	if err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+	parts := strings.Split(detached, ".")
+	if len(parts) != 3 || parts[1] != "" {
+		t.Fatalf("Expected header..signature form. Got: %v", detached)
+	}
+
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+
+	var tests = []struct {
+		name    string
+		payload string
+		valid   bool
+	}{
+		{"matching payload", `{"foo":"bar"}`, true},
+		{"tampered payload", `{"foo":"baz"}`, false},
+	}
+
+	for _, data := range tests {
+		parsed, err := new(jwt.Parser).ParseDetached(detached, []byte(data.payload), jwt.MapClaims{}, keyFunc)
+		if data.valid && err != nil {
+			t.Errorf("[%v] Error while verifying token: %v", data.name, err)
+		}
+		if !data.valid && !errors.Is(err, jwt.ErrSignatureInvalid) {
+			t.Errorf("[%v] Expected ErrSignatureInvalid. Got: %v", data.name, err)
+		}
+		if data.valid && parsed.Raw != detached {
+			t.Errorf("[%v] Expected Raw to be the detached token. Got: %v", data.name, parsed.Raw)
+		}
+	}
+}
+
+func TestParser_ParseDetached_notDetached(t *testing.T) {
+	key := []byte("detached secret")
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"}).SignedString(key)
+	if err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+	_, err = new(jwt.Parser).ParseDetached(signed, []byte(`{"foo":"bar"}`), jwt.MapClaims{}, func(*jwt.Token) (interface{}, error) {
+		return key, nil
+	})
+	if !errors.Is(err, jwt.ErrMalformedToken) {
+		t.Errorf("Expected ErrMalformedToken. Got: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package discovery
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/chanced/go-jwt/v4/jwk"
+)
+
+const (
+	// WellKnownPath is the path of the OpenID Connect discovery document.
+	WellKnownPath = "/.well-known/openid-configuration"
+
+	// DefaultJWKSPath is the path the key set is served at when
+	// Provider.JWKSPath is unset.
+	DefaultJWKSPath = "/.well-known/jwks.json"
+)
+
+// Metadata is the metadata document of an issuer.
+type Metadata struct {
+	Issuer                           string   `json:"issuer"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported,omitempty"`
+}
+
+// Provider serves the metadata document and key set of an issuer.
+type Provider struct {
+	Issuer   string          // The issuer identifier, an https URL which the paths are relative to
+	JWKSPath string          // Optional. Defaults to DefaultJWKSPath
+	Keys     func() *jwk.Set // Returns the keys tokens are currently verified with
+
+	// Extra holds additional members of the metadata document, such as
+	// "token_endpoint" or "response_types_supported". Members of Metadata
+	// take precedence.
+	Extra map[string]interface{}
+}
+
+func (p *Provider) jwksPath() string {
+	if p.JWKSPath != "" {
+		return p.JWKSPath
+	}
+	return DefaultJWKSPath
+}
+
+// Metadata returns the metadata document for the current keys.
+func (p *Provider) Metadata() *Metadata {
+	return &Metadata{
+		Issuer:                           p.Issuer,
+		JWKSURI:                          strings.TrimSuffix(p.Issuer, "/") + p.jwksPath(),
+		IDTokenSigningAlgValuesSupported: algorithms(p.publicKeys()),
+	}
+}
+
+// ServeHTTP serves the metadata document at WellKnownPath and the key set at
+// the JWKS path. Other paths are not found. Handlers are typically mounted at
+// the root of the issuer, or the paths stripped of a common prefix.
+func (p *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case WellKnownPath:
+		doc := make(map[string]interface{}, len(p.Extra)+3)
+		for k, v := range p.Extra {
+			doc[k] = v
+		}
+		b, _ := json.Marshal(p.Metadata())
+		_ = json.Unmarshal(b, &doc)
+		writeJSON(w, doc)
+	case p.jwksPath():
+		writeJSON(w, &jwk.Set{Keys: p.publicKeys()})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// publicKeys returns the current keys, without symmetric keys, whose
+// material must never be published.
+func (p *Provider) publicKeys() []*jwk.Key {
+	keys := []*jwk.Key{}
+	if p.Keys == nil {
+		return keys
+	}
+	if set := p.Keys(); set != nil {
+		for _, k := range set.Keys {
+			if k.KeyType != "oct" {
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys
+}
+
+// algorithms returns the signing algorithms of keys, sorted. Keys without an
+// "alg" member contribute the algorithm conventionally used with their type.
+func algorithms(keys []*jwk.Key) []string {
+	seen := make(map[string]bool)
+	var algs []string
+	for _, k := range keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		alg := k.Algorithm
+		if alg == "" {
+			alg = defaultAlgorithm(k)
+		}
+		if alg != "" && !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+	sort.Strings(algs)
+	return algs
+}
+
+func defaultAlgorithm(k *jwk.Key) string {
+	switch k.KeyType {
+	case "RSA":
+		return "RS256"
+	case "EC":
+		switch k.Curve {
+		case "P-256":
+			return "ES256"
+		case "P-384":
+			return "ES384"
+		case "P-521":
+			return "ES512"
+		}
+	case "OKP":
+		if k.Curve == "Ed25519" {
+			return "EdDSA"
+		}
+	}
+	return ""
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_ = json.NewEncoder(w).Encode(v)
+}
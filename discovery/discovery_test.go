@@ -0,0 +1,62 @@
+package discovery_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4/discovery"
+	"github.com/chanced/go-jwt/v4/jwk"
+	"github.com/chanced/go-jwt/v4/test"
+)
+
+func TestProvider(t *testing.T) {
+	rsaKey, _ := jwk.NewKey(test.LoadRSAPrivateKeyFromDisk("../test/sample_key"))
+	rsaKey.KeyID, rsaKey.Algorithm = "rsa", "PS256"
+	_, edPriv, _ := ed25519.GenerateKey(rand.Reader)
+	edKey, _ := jwk.NewKey(edPriv)
+	edKey.KeyID = "ed"
+	secret, _ := jwk.NewKey([]byte("secret"))
+
+	p := &discovery.Provider{
+		Issuer: "https://issuer.example.com/",
+		Keys:   func() *jwk.Set { return &jwk.Set{Keys: []*jwk.Key{rsaKey, edKey, secret}} },
+		Extra:  map[string]interface{}{"token_endpoint": "https://issuer.example.com/token", "issuer": "ignored"},
+	}
+
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, discovery.WellKnownPath, nil))
+	var doc map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Error decoding metadata: %v", err)
+	}
+	want := map[string]interface{}{
+		"issuer":                                "https://issuer.example.com/",
+		"jwks_uri":                              "https://issuer.example.com/.well-known/jwks.json",
+		"id_token_signing_alg_values_supported": []interface{}{"EdDSA", "PS256"},
+		"token_endpoint":                        "https://issuer.example.com/token",
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("Expected %v. Got: %v", want, doc)
+	}
+
+	w = httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, discovery.DefaultJWKSPath, nil))
+	set, err := jwk.Parse(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Error decoding key set: %v", err)
+	}
+	if len(set.Keys) != 2 {
+		t.Errorf("Expected the symmetric key to be withheld. Got: %v keys", len(set.Keys))
+	}
+
+	w = httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/other", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404. Got: %v", w.Code)
+	}
+}
@@ -0,0 +1,9 @@
+// Package discovery publishes the metadata of a token issuer, as described in
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+// and https://datatracker.ietf.org/doc/html/rfc8414, together with its JSON
+// Web Key Set.
+//
+// A Provider derives the metadata document, including the supported signing
+// algorithms, from the keys the issuer currently signs with, so that the
+// document stays consistent with the key set as keys are rotated.
+package discovery
@@ -0,0 +1,14 @@
+// Package dpop creates and verifies DPoP proofs, which demonstrate possession
+// of a key when presenting an access token bound to it, as described in
+// https://datatracker.ietf.org/doc/html/rfc9449.
+//
+// A client signs a proof with NewProof for every request and sends it in the
+// DPoP header. The authorization server checks it with Verify and binds the
+// access tokens it issues to Proof.Thumbprint through their "cnf" claim; a
+// resource server checks it with Verify too, passing the access token with
+// WithAccessToken, then checks the binding with Proof.VerifyBinding.
+//
+// Verify only detects replayed proofs if given a jwt.ReplayDetector with
+// WithReplayDetector. Servers should use one, shared between their instances,
+// to reject a "jti" they have seen before within the accepted age of proofs.
+package dpop
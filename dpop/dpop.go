@@ -0,0 +1,363 @@
+package dpop
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwk"
+)
+
+// TokenType is the "typ" header of DPoP proofs.
+const TokenType = jwt.TypeDPoPProof
+
+// HeaderName is the HTTP header proofs are sent in.
+const HeaderName = "DPoP"
+
+// DefaultMaxAge is how long after it was issued Verify accepts a proof,
+// unless configured with WithMaxAge.
+const DefaultMaxAge = 5 * time.Minute
+
+// DefaultMethods are the algorithms Verify accepts, unless configured with
+// WithValidMethods. Proofs must be signed with an asymmetric algorithm.
+var DefaultMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+var (
+	ErrInvalidKey              = errors.New(`dpop: the "jwk" header is missing or not a public key`)
+	ErrMethodMismatch          = errors.New(`dpop: the "htm" claim does not match the method of the request`)
+	ErrURIMismatch             = errors.New(`dpop: the "htu" claim does not match the URI of the request`)
+	ErrAccessTokenHashMismatch = errors.New(`dpop: the "ath" claim does not match the access token`)
+	ErrNonceMismatch           = errors.New(`dpop: the "nonce" claim does not match the nonce of the server`)
+	ErrMissingConfirmation     = errors.New(`dpop: the access token has no "cnf" claim with a "jkt" member`)
+	ErrBindingMismatch         = errors.New("dpop: the access token is bound to another key")
+)
+
+// Claims are the claims of a DPoP proof.
+type Claims struct {
+	jwt.RegisteredClaims
+
+	// the `htm` claim, the method of the request
+	HTTPMethod string `json:"htm"`
+
+	// the `htu` claim, the URI of the request without query and fragment
+	HTTPURI string `json:"htu"`
+
+	// the `ath` claim, the hash of the access token sent with the request
+	AccessTokenHash string `json:"ath,omitempty"`
+
+	// the `nonce` claim, the last nonce provided by the server
+	Nonce string `json:"nonce,omitempty"`
+}
+
+// Proof is a verified DPoP proof.
+type Proof struct {
+	Token  *jwt.Token
+	Claims *Claims
+
+	// Key is the public key of the "jwk" header, which signed the proof.
+	Key *jwk.Key
+
+	// Thumbprint is the base64url encoded SHA-256 JWK thumbprint of Key,
+	// which the "jkt" member of the "cnf" claim of bound access tokens holds:
+	// jwt.Confirmation{JWKThumbprint: proof.Thumbprint}.
+	Thumbprint string
+}
+
+// Option configures NewProof and Verify.
+type Option func(*config)
+
+type config struct {
+	accessToken   string
+	nonce         string
+	maxAge        time.Duration
+	leeway        time.Duration
+	methods       []string
+	parserOptions []jwt.ParserOption
+	replay        jwt.ReplayDetector
+}
+
+// WithAccessToken binds the proof to the access token sent with it. NewProof
+// sets the "ath" claim to its hash, and Verify requires the claim to match.
+func WithAccessToken(accessToken string) Option {
+	return func(c *config) {
+		c.accessToken = accessToken
+	}
+}
+
+// WithNonce sets the "nonce" claim to the nonce the server provided in the
+// DPoP-Nonce header, with NewProof, and requires it to match, with Verify.
+func WithNonce(nonce string) Option {
+	return func(c *config) {
+		c.nonce = nonce
+	}
+}
+
+// WithMaxAge sets how long after it was issued Verify accepts a proof. It
+// defaults to DefaultMaxAge.
+func WithMaxAge(d time.Duration) Option {
+	return func(c *config) {
+		c.maxAge = d
+	}
+}
+
+// WithLeeway allows for clock skew of up to d when checking "iat".
+func WithLeeway(d time.Duration) Option {
+	return func(c *config) {
+		c.leeway = d
+	}
+}
+
+// WithValidMethods restricts the algorithms Verify accepts, which default to
+// DefaultMethods, such as to those advertised in
+// dpop_signing_alg_values_supported.
+func WithValidMethods(methods ...string) Option {
+	return func(c *config) {
+		c.methods = append(c.methods, methods...)
+	}
+}
+
+// WithReplayDetector makes Verify reject proofs whose "jti" has been seen by
+// d before. Identifiers are remembered for as long as proofs are accepted.
+func WithReplayDetector(d jwt.ReplayDetector) Option {
+	return func(c *config) {
+		c.replay = d
+	}
+}
+
+// WithParserOptions configures the parser of the proof.
+func WithParserOptions(opts ...jwt.ParserOption) Option {
+	return func(c *config) {
+		c.parserOptions = append(c.parserOptions, opts...)
+	}
+}
+
+// NewProof creates a DPoP proof for a request with the HTTP method htm to the
+// URI htu, signed with key, a private key, using method. The public key is
+// embedded in the "jwk" header; "jti" and "iat" are set to a new random ID
+// and the current time, and the query and fragment of htu are removed.
+func NewProof(method jwt.SigningMethod, key interface{}, htm, htu string, opts ...Option) (string, error) {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	pub, err := jwk.NewKey(key)
+	if err != nil {
+		return "", err
+	}
+	if pub.KeyType == "oct" {
+		return "", ErrInvalidKey
+	}
+	uri, err := normalizeURI(htu)
+	if err != nil {
+		return "", err
+	}
+	id, err := jwt.NewID()
+	if err != nil {
+		return "", err
+	}
+
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       id,
+			IssuedAt: jwt.NewNumericDate(jwt.TimeFunc()),
+		},
+		HTTPMethod: htm,
+		HTTPURI:    uri,
+		Nonce:      c.nonce,
+	}
+	if c.accessToken != "" {
+		claims.AccessTokenHash = AccessTokenHash(c.accessToken)
+	}
+	token := jwt.NewWithClaims(method, claims, jwt.WithType(TokenType), jwt.WithHeader("jwk", pub))
+	return token.SignedString(key)
+}
+
+// Verify verifies the DPoP proof raw, sent with a request with the HTTP
+// method htm to the URI htu, following
+// https://datatracker.ietf.org/doc/html/rfc9449#section-4.3:
+//
+//   - the "typ" header must be "dpop+jwt";
+//   - the "jwk" header must hold a public key, which the signature must
+//     verify with, using one of the accepted algorithms;
+//   - "jti", "htm", "htu" and "iat" must be present;
+//   - "htm" must equal htm, and "htu" must equal htu, ignoring the query and
+//     fragment and after normalization;
+//   - "iat" must be within the accepted age of proofs;
+//   - "ath" must match the access token given with WithAccessToken;
+//   - "nonce" must equal the nonce given with WithNonce.
+//
+// All failures of the claims are reported, combined with jwt.JoinErrors.
+func Verify(raw, htm, htu string, opts ...Option) (*Proof, error) {
+	c := config{maxAge: DefaultMaxAge}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	methods := c.methods
+	if len(methods) == 0 {
+		methods = DefaultMethods
+	}
+
+	p := jwt.NewParser(append([]jwt.ParserOption{
+		jwt.WithValidTypes(TokenType),
+		jwt.WithValidMethods(methods...),
+	}, c.parserOptions...)...)
+	p.SkipClaimsValidation = true
+	proof := &Proof{Claims: new(Claims)}
+	token, err := p.ParseWithClaims(raw, proof.Claims, func(token *jwt.Token) (interface{}, error) {
+		key, err := headerKey(token.Header["jwk"])
+		if err != nil {
+			return nil, err
+		}
+		proof.Key = key
+		return key.Materialize()
+	})
+	if err != nil {
+		return nil, err
+	}
+	proof.Token = token
+	if proof.Thumbprint, err = proof.Key.Thumbprint(crypto.SHA256); err != nil {
+		return nil, err
+	}
+
+	v := jwt.Validator{Policy: jwt.Policy{
+		RequiredClaims:     []string{"jti", "htm", "htu"},
+		RequireIssuedAt:    true,
+		IssuedWithinPast:   c.maxAge + c.leeway,
+		IssuedWithinFuture: c.leeway,
+	}}
+	errs := []error{v.Validate(token)}
+
+	claims := proof.Claims
+	if claims.HTTPMethod != htm {
+		errs = append(errs, &jwt.ValidationError{Err: ErrMethodMismatch, Claim: "htm", Expected: htm, Actual: claims.HTTPMethod})
+	}
+	want, err := normalizeURI(htu)
+	if err != nil {
+		return nil, err
+	}
+	if got, err := normalizeURI(claims.HTTPURI); err != nil || got != want {
+		errs = append(errs, &jwt.ValidationError{Err: ErrURIMismatch, Claim: "htu", Expected: want, Actual: claims.HTTPURI})
+	}
+	if c.accessToken != "" && subtle.ConstantTimeCompare([]byte(claims.AccessTokenHash), []byte(AccessTokenHash(c.accessToken))) != 1 {
+		errs = append(errs, &jwt.ValidationError{Err: ErrAccessTokenHashMismatch, Claim: "ath"})
+	}
+	if c.nonce != "" && subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(c.nonce)) != 1 {
+		errs = append(errs, &jwt.ValidationError{Err: ErrNonceMismatch, Claim: "nonce"})
+	}
+
+	if err = jwt.JoinErrors(errs...); err != nil {
+		return nil, err
+	}
+	if c.replay != nil {
+		replay := jwt.NewValidator(jwt.WithReplayDetection(c.replay, c.maxAge+2*c.leeway))
+		if err = replay.Validate(token); err != nil {
+			return nil, err
+		}
+	}
+	return proof, nil
+}
+
+// VerifyBinding checks that the access token with claims is bound to the key
+// of the proof: the "jkt" member of its "cnf" claim must be p.Thumbprint.
+func (p *Proof) VerifyBinding(claims jwt.Claims) error {
+	jkt := confirmationThumbprint(claims)
+	if jkt == "" {
+		return ErrMissingConfirmation
+	}
+	if subtle.ConstantTimeCompare([]byte(jkt), []byte(p.Thumbprint)) != 1 {
+		return ErrBindingMismatch
+	}
+	return nil
+}
+
+// AccessTokenHash returns the value of the "ath" claim for accessToken: the
+// base64url encoded SHA-256 hash of its ASCII encoding.
+func AccessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return jwt.EncodeSegment(sum[:])
+}
+
+// headerKey decodes the "jwk" header v, which must be a public key.
+func headerKey(v interface{}) (*jwk.Key, error) {
+	members, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	// Keys with private members must be rejected, rather than reduced to
+	// their public half: the client has disclosed its key.
+	for _, name := range []string{"d", "p", "q", "dp", "dq", "qi", "oth", "k"} {
+		if _, ok := members[name]; ok {
+			return nil, ErrInvalidKey
+		}
+	}
+	b, err := json.Marshal(members)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	key := new(jwk.Key)
+	if err = json.Unmarshal(b, key); err != nil || key.KeyType == "oct" {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+// normalizeURI returns the absolute URI s without its query and fragment,
+// normalized as described in RFC 3986, section 6.2.2 and 6.2.3: the scheme
+// and host are lowercased, the default port of the scheme is removed, and an
+// empty path becomes "/".
+func normalizeURI(s string) (string, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", err
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return "", ErrURIMismatch
+	}
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	switch {
+	case scheme == "https" && strings.HasSuffix(host, ":443"):
+		host = strings.TrimSuffix(host, ":443")
+	case scheme == "http" && strings.HasSuffix(host, ":80"):
+		host = strings.TrimSuffix(host, ":80")
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	return scheme + "://" + host + path, nil
+}
+
+// confirmationThumbprint returns the "jkt" member of the "cnf" claim of
+// claims.
+func confirmationThumbprint(claims jwt.Claims) string {
+	var cnf interface{}
+	if c, ok := claims.(jwt.MapClaims); ok {
+		cnf = c["cnf"]
+	} else {
+		// Other claims types are inspected through their JSON encoding.
+		b, err := json.Marshal(claims)
+		if err != nil {
+			return ""
+		}
+		var c struct {
+			Confirmation interface{} `json:"cnf"`
+		}
+		json.Unmarshal(b, &c)
+		cnf = c.Confirmation
+	}
+	members, _ := cnf.(map[string]interface{})
+	jkt, _ := members["jkt"].(string)
+	return jkt
+}
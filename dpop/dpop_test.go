@@ -0,0 +1,159 @@
+package dpop_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/dpop"
+)
+
+const uri = "https://server.example.com/token"
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestVerify(t *testing.T) {
+	key := newKey(t)
+	raw, err := dpop.NewProof(jwt.SigningMethodES256, key, "POST", uri+"?q=1#frag", dpop.WithAccessToken("token"), dpop.WithNonce("n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proof, err := dpop.Verify(raw, "POST", "HTTPS://Server.Example.COM:443/token?other", dpop.WithAccessToken("token"), dpop.WithNonce("n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if proof.Claims.HTTPURI != uri {
+		t.Errorf("Claims.HTTPURI = %v, want %v", proof.Claims.HTTPURI, uri)
+	}
+	tp, _ := jwt.Thumbprint(key, crypto.SHA256)
+	if want := jwt.EncodeSegment(tp); proof.Thumbprint != want {
+		t.Errorf("Thumbprint = %v, want %v", proof.Thumbprint, want)
+	}
+
+	tests := []struct {
+		name string
+		htm  string
+		htu  string
+		opts []dpop.Option
+		want error
+	}{
+		{"method", "GET", uri, nil, dpop.ErrMethodMismatch},
+		{"uri", "POST", "https://server.example.com/other", nil, dpop.ErrURIMismatch},
+		{"access token", "POST", uri, []dpop.Option{dpop.WithAccessToken("other")}, dpop.ErrAccessTokenHashMismatch},
+		{"nonce", "POST", uri, []dpop.Option{dpop.WithNonce("other")}, dpop.ErrNonceMismatch},
+		{"method not accepted", "POST", uri, []dpop.Option{dpop.WithValidMethods("EdDSA")}, jwt.ErrInvalidSigningMethod},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := dpop.Verify(raw, tc.htm, tc.htu, tc.opts...); !errors.Is(err, tc.want) {
+				t.Errorf("Expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestVerify_age(t *testing.T) {
+	raw, err := dpop.NewProof(jwt.SigningMethodES256, newKey(t), "GET", uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return time.Now().Add(10 * time.Minute) }
+	if _, err = dpop.Verify(raw, "GET", uri); !errors.Is(err, jwt.ErrTokenOutsideIssuanceWindow) {
+		t.Errorf("Expected ErrTokenOutsideIssuanceWindow, got %v", err)
+	}
+	if _, err = dpop.Verify(raw, "GET", uri, dpop.WithMaxAge(time.Hour)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestVerify_replay(t *testing.T) {
+	raw, err := dpop.NewProof(jwt.SigningMethodES256, newKey(t), "GET", uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay := new(jwt.MemoryReplayDetector)
+	if _, err = dpop.Verify(raw, "GET", "https://server.example.com/other", dpop.WithReplayDetector(replay)); !errors.Is(err, dpop.ErrURIMismatch) {
+		t.Fatalf("Expected ErrURIMismatch, got %v", err)
+	}
+	if _, err = dpop.Verify(raw, "GET", uri, dpop.WithReplayDetector(replay)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err = dpop.Verify(raw, "GET", uri, dpop.WithReplayDetector(replay)); !errors.Is(err, jwt.ErrTokenReplayed) {
+		t.Errorf("Expected ErrTokenReplayed, got %v", err)
+	}
+}
+
+func TestVerify_invalidKey(t *testing.T) {
+	key := newKey(t)
+	claims := &dpop.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{ID: "id", IssuedAt: jwt.NewNumericDate(time.Now())},
+		HTTPMethod:       "GET",
+		HTTPURI:          uri,
+	}
+
+	tests := []struct {
+		name string
+		jwk  interface{}
+	}{
+		{"missing", nil},
+		{"private", map[string]interface{}{"kty": "EC", "crv": "P-256", "x": "x", "y": "y", "d": "d"}},
+		{"symmetric", map[string]interface{}{"kty": "oct", "k": "c2VjcmV0"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims, jwt.WithType(dpop.TokenType), jwt.WithHeader("jwk", tc.jwk)).SignedString(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = dpop.Verify(raw, "GET", uri); !errors.Is(err, dpop.ErrInvalidKey) {
+				t.Errorf("Expected ErrInvalidKey, got %v", err)
+			}
+		})
+	}
+
+	raw, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = dpop.Verify(raw, "GET", uri); !errors.Is(err, jwt.ErrTokenInvalidType) {
+		t.Errorf("Expected ErrTokenInvalidType, got %v", err)
+	}
+}
+
+func TestProof_VerifyBinding(t *testing.T) {
+	raw, err := dpop.NewProof(jwt.SigningMethodES256, newKey(t), "GET", uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := dpop.Verify(raw, "GET", uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bound := jwt.MapClaims{"cnf": map[string]interface{}{"jkt": proof.Thumbprint}}
+	if err = proof.VerifyBinding(bound); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	other := jwt.MapClaims{"cnf": map[string]interface{}{"jkt": "other"}}
+	if err = proof.VerifyBinding(other); !errors.Is(err, dpop.ErrBindingMismatch) {
+		t.Errorf("Expected ErrBindingMismatch, got %v", err)
+	}
+	if err = proof.VerifyBinding(&jwt.AccessTokenClaims{}); !errors.Is(err, dpop.ErrMissingConfirmation) {
+		t.Errorf("Expected ErrMissingConfirmation, got %v", err)
+	}
+}
@@ -3,7 +3,7 @@ package jwt
 import (
 	"crypto"
 	"crypto/ecdsa"
-	"crypto/rand"
+	"encoding/asn1"
 	"math/big"
 )
 
@@ -18,6 +18,31 @@ type SigningMethodECDSA struct {
 	Hash      crypto.Hash
 	KeySize   int
 	CurveBits int
+
+	// Deterministic, if set, derives the nonce of each signature from the
+	// private key and the message as described in RFC 6979, rather than from
+	// RandReader, so that the same claims signed with the same key yield the
+	// same token. It applies to *ecdsa.PrivateKey keys only; a crypto.Signer
+	// chooses its own nonces. To sign deterministically, copy a method:
+	//
+	//	method := *jwt.SigningMethodES256
+	//	method.Deterministic = true
+	//	token := jwt.NewWithClaims(&method, claims)
+	//
+	// Built with Go 1.24 or later, nonces are computed by crypto/ecdsa in
+	// constant time. Before, and for curves crypto/ecdsa does not implement,
+	// they are computed with math/big, which is not constant time and may leak
+	// the key to an attacker able to time many signatures; leave Deterministic
+	// unset there unless signing happens where it cannot be timed.
+	Deterministic bool
+
+	// Strict, if set, makes Verify reject signatures which are not in
+	// canonical form: those whose s is in the upper half of the curve order
+	// (high-S), whose r or s is not reduced modulo the order, or whose
+	// base64url encoding has non-zero padding bits. Signatures made by Sign
+	// are always canonical. To verify strictly, register a copy of the method
+	// with a SigningMethodRegistry passed to WithSigningMethods.
+	Strict bool
 }
 
 // Specific instances for EC256 and company
@@ -29,19 +54,19 @@ var (
 
 func init() {
 	// ES256
-	SigningMethodES256 = &SigningMethodECDSA{"ES256", crypto.SHA256, 32, 256}
+	SigningMethodES256 = &SigningMethodECDSA{Name: "ES256", Hash: crypto.SHA256, KeySize: 32, CurveBits: 256}
 	RegisterSigningMethod(SigningMethodES256.Alg(), func() SigningMethod {
 		return SigningMethodES256
 	})
 
 	// ES384
-	SigningMethodES384 = &SigningMethodECDSA{"ES384", crypto.SHA384, 48, 384}
+	SigningMethodES384 = &SigningMethodECDSA{Name: "ES384", Hash: crypto.SHA384, KeySize: 48, CurveBits: 384}
 	RegisterSigningMethod(SigningMethodES384.Alg(), func() SigningMethod {
 		return SigningMethodES384
 	})
 
 	// ES512
-	SigningMethodES512 = &SigningMethodECDSA{"ES512", crypto.SHA512, 66, 521}
+	SigningMethodES512 = &SigningMethodECDSA{Name: "ES512", Hash: crypto.SHA512, KeySize: 66, CurveBits: 521}
 	RegisterSigningMethod(SigningMethodES512.Alg(), func() SigningMethod {
 		return SigningMethodES512
 	})
@@ -80,6 +105,12 @@ func (m *SigningMethodECDSA) Verify(signingString, signature string, key interfa
 	r := big.NewInt(0).SetBytes(sig[:m.KeySize])
 	s := big.NewInt(0).SetBytes(sig[m.KeySize:])
 
+	if m.Strict && !canonicalECDSASignature(signature, sig, r, s, ecdsaKey.Curve.Params().N) {
+		return &SignatureVerificationError{
+			Algorithm: m.Name,
+		}
+	}
+
 	// Create hasher
 	if !m.Hash.Available() {
 		return ErrHashUnavailable
@@ -102,9 +133,21 @@ func (m *SigningMethodECDSA) Verify(signingString, signature string, key interfa
 func (m *SigningMethodECDSA) Sign(signingString string, key interface{}) (string, error) {
 	// Get the key
 	var ecdsaKey *ecdsa.PrivateKey
+	var signer crypto.Signer
+	var pub *ecdsa.PublicKey
 	switch k := key.(type) {
 	case *ecdsa.PrivateKey:
 		ecdsaKey = k
+	case crypto.Signer:
+		var ok bool
+		pub, ok = k.Public().(*ecdsa.PublicKey)
+		if !ok {
+			return "", ErrInvalidKeyType
+		}
+		if m.CurveBits != pub.Curve.Params().BitSize {
+			return "", ErrInvalidKey
+		}
+		signer = k
 	default:
 		return "", ErrInvalidKeyType
 	}
@@ -117,28 +160,74 @@ func (m *SigningMethodECDSA) Sign(signingString string, key interface{}) (string
 	hasher := m.Hash.New()
 	hasher.Write([]byte(signingString))
 
+	// Sign with an opaque signer, such as a key held by a KMS or HSM, which
+	// returns the ASN.1 encoding of r and s
+	if signer != nil {
+		der, err := signer.Sign(RandReader, hasher.Sum(nil), m.Hash)
+		if err != nil {
+			return "", err
+		}
+		var sig struct{ R, S *big.Int }
+		if rest, err := asn1.Unmarshal(der, &sig); err != nil || len(rest) != 0 {
+			return "", ErrSignatureInvalid
+		}
+		return EncodeSegment(ecdsaSignature(sig.R, lowS(sig.S, pub.Curve.Params().N), m.CurveBits)), nil
+	}
+
+	if m.Deterministic {
+		if m.CurveBits != ecdsaKey.Curve.Params().BitSize {
+			return "", ErrInvalidKey
+		}
+		r, s := signDeterministic(ecdsaKey, m.Hash, hasher.Sum(nil))
+		return EncodeSegment(ecdsaSignature(r, lowS(s, ecdsaKey.Curve.Params().N), m.CurveBits)), nil
+	}
+
 	// Sign the string and return r, s
-	if r, s, err := ecdsa.Sign(rand.Reader, ecdsaKey, hasher.Sum(nil)); err == nil {
+	if r, s, err := ecdsa.Sign(RandReader, ecdsaKey, hasher.Sum(nil)); err == nil {
 		curveBits := ecdsaKey.Curve.Params().BitSize
 
 		if m.CurveBits != curveBits {
 			return "", ErrInvalidKey
 		}
 
-		keyBytes := curveBits / 8
-		if curveBits%8 > 0 {
-			keyBytes += 1
-		}
-
-		// We serialize the outputs (r and s) into big-endian byte arrays
-		// padded with zeros on the left to make sure the sizes work out.
-		// Output must be 2*keyBytes long.
-		out := make([]byte, 2*keyBytes)
-		r.FillBytes(out[0:keyBytes]) // r is assigned to the first half of output.
-		s.FillBytes(out[keyBytes:])  // s is assigned to the second half of output.
-
-		return EncodeSegment(out), nil
+		return EncodeSegment(ecdsaSignature(r, lowS(s, ecdsaKey.Curve.Params().N), curveBits)), nil
 	} else {
 		return "", err
 	}
 }
+
+// ecdsaSignature serializes r and s into big-endian byte arrays padded with
+// zeros on the left to the size of the curve, concatenated.
+func ecdsaSignature(r, s *big.Int, curveBits int) []byte {
+	keyBytes := curveBits / 8
+	if curveBits%8 > 0 {
+		keyBytes += 1
+	}
+	out := make([]byte, 2*keyBytes)
+	r.FillBytes(out[0:keyBytes]) // r is assigned to the first half of output.
+	s.FillBytes(out[keyBytes:])  // s is assigned to the second half of output.
+	return out
+}
+
+// lowS returns s, or n-s if s is in the upper half of the order n. Both are
+// valid, but systems which require canonical signatures accept only the
+// former.
+func lowS(s, n *big.Int) *big.Int {
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		return new(big.Int).Sub(n, s)
+	}
+	return s
+}
+
+// canonicalECDSASignature reports whether the signature sig, with the
+// components r and s and base64url encoding encoded, is in the canonical
+// form required by SigningMethodECDSA.Strict.
+func canonicalECDSASignature(encoded string, sig []byte, r, s, n *big.Int) bool {
+	if r.Sign() <= 0 || r.Cmp(n) >= 0 || s.Sign() <= 0 || s.Cmp(n) >= 0 {
+		return false
+	}
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		return false
+	}
+	return EncodeSegment(sig) == encoded
+}
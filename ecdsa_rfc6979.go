@@ -0,0 +1,86 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"math/big"
+)
+
+// signDeterministicBig signs digest with priv, deriving the nonce from the key
+// and the digest as described in RFC 6979, section 3.2, with HMAC over hash.
+//
+// It computes with math/big and Curve.ScalarBaseMult, neither of which runs in
+// constant time, so the time taken may leak the nonce and thereby the key to
+// an attacker able to time many signatures. It is only used where crypto/ecdsa
+// cannot sign deterministically: before Go 1.24, and for curves other than
+// P-224, P-256, P-384 and P-521.
+func signDeterministicBig(priv *ecdsa.PrivateKey, hash crypto.Hash, digest []byte) (r, s *big.Int) {
+	c := priv.Curve
+	q := c.Params().N
+	qlen := q.BitLen()
+	rlen := (qlen + 7) / 8
+
+	// bits2int, int2octets and bits2octets of RFC 6979, section 2.3
+	bits2int := func(b []byte) *big.Int {
+		v := new(big.Int).SetBytes(b)
+		if blen := len(b) * 8; blen > qlen {
+			v.Rsh(v, uint(blen-qlen))
+		}
+		return v
+	}
+	int2octets := func(v *big.Int) []byte {
+		out := make([]byte, rlen)
+		v.FillBytes(out)
+		return out
+	}
+	z := bits2int(digest)
+	if z.Cmp(q) >= 0 {
+		z.Sub(z, q)
+	}
+	x := int2octets(priv.D)
+	h := int2octets(z)
+
+	mac := func(key []byte, data ...[]byte) []byte {
+		m := hmac.New(hash.New, key)
+		for _, d := range data {
+			m.Write(d)
+		}
+		return m.Sum(nil)
+	}
+	hlen := hash.Size()
+	v := make([]byte, hlen)
+	for i := range v {
+		v[i] = 0x01
+	}
+	k := make([]byte, hlen)
+	k = mac(k, v, []byte{0x00}, x, h)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, x, h)
+	v = mac(k, v)
+
+	for {
+		var t []byte
+		for len(t)*8 < qlen {
+			v = mac(k, v)
+			t = append(t, v...)
+		}
+		nonce := bits2int(t)
+		if nonce.Sign() > 0 && nonce.Cmp(q) < 0 {
+			// r = (kG).x mod q and s = k⁻¹(z + r·d) mod q
+			rx, _ := c.ScalarBaseMult(int2octets(nonce))
+			r = rx.Mod(rx, q)
+			if r.Sign() != 0 {
+				s = new(big.Int).Mul(r, priv.D)
+				s.Add(s, bits2int(digest))
+				s.Mul(s, new(big.Int).ModInverse(nonce, q))
+				s.Mod(s, q)
+				if s.Sign() != 0 {
+					return r, s
+				}
+			}
+		}
+		k = mac(k, v, []byte{0x00})
+		v = mac(k, v)
+	}
+}
@@ -0,0 +1,26 @@
+//go:build go1.24
+// +build go1.24
+
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"math/big"
+)
+
+// signDeterministic signs digest with priv as described in RFC 6979. Keys on
+// the curves crypto/ecdsa supports are signed by it, in constant time; others
+// fall back to signDeterministicBig.
+func signDeterministic(priv *ecdsa.PrivateKey, hash crypto.Hash, digest []byte) (r, s *big.Int) {
+	der, err := priv.Sign(nil, digest, hash)
+	if err != nil {
+		return signDeterministicBig(priv, hash, digest)
+	}
+	var sig struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil || len(rest) != 0 {
+		return signDeterministicBig(priv, hash, digest)
+	}
+	return sig.R, sig.S
+}
@@ -0,0 +1,17 @@
+//go:build !go1.24
+// +build !go1.24
+
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"math/big"
+)
+
+// signDeterministic signs digest with priv as described in RFC 6979. Before Go
+// 1.24, crypto/ecdsa cannot, so signDeterministicBig is used; see the caveat
+// there.
+func signDeterministic(priv *ecdsa.PrivateKey, hash crypto.Hash, digest []byte) (r, s *big.Int) {
+	return signDeterministicBig(priv, hash, digest)
+}
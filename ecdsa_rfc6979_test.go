@@ -0,0 +1,82 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/hex"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestSigningMethodECDSA_deterministic(t *testing.T) {
+	// RFC 6979, appendix A.2.5: P-256 with SHA-256, message "sample"
+	d, _ := new(big.Int).SetString("C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721", 16)
+	key := &ecdsa.PrivateKey{D: d}
+	key.Curve = elliptic.P256()
+	key.X, key.Y = key.Curve.ScalarBaseMult(d.Bytes())
+
+	method := *jwt.SigningMethodES256
+	method.Deterministic = true
+	sig, err := method.Sign("sample", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := jwt.DecodeSegment(sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The s of the vector is high; Sign returns its low-S counterpart.
+	s, _ := new(big.Int).SetString("F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8", 16)
+	s.Sub(key.Curve.Params().N, s)
+	want := "EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716" +
+		strings.ToUpper(hex.EncodeToString(s.FillBytes(make([]byte, 32))))
+	if got := strings.ToUpper(hex.EncodeToString(raw)); got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+	if err = method.Verify("sample", sig, &key.PublicKey); err != nil {
+		t.Errorf("Error verifying the signature: %v", err)
+	}
+}
+
+func TestSigningMethodECDSA_deterministicTokens(t *testing.T) {
+	for _, tt := range []struct {
+		method *jwt.SigningMethodECDSA
+		key    string
+	}{
+		{jwt.SigningMethodES256, "test/ec256-private.pem"},
+		{jwt.SigningMethodES384, "test/ec384-private.pem"},
+		{jwt.SigningMethodES512, "test/ec512-private.pem"},
+	} {
+		pemKey, _ := ioutil.ReadFile(tt.key)
+		key, err := jwt.ParseECPrivateKeyFromPEM(pemKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		method := *tt.method
+		method.Deterministic = true
+		claims := jwt.MapClaims{"sub": "a"}
+
+		first, err := jwt.NewWithClaims(&method, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("[%v] Error signing: %v", method.Name, err)
+		}
+		second, _ := jwt.NewWithClaims(&method, claims).SignedString(key)
+		if first != second {
+			t.Errorf("[%v] Deterministic signatures differ: %v != %v", method.Name, first, second)
+		}
+		if _, err = jwt.Parse(first, func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil }); err != nil {
+			t.Errorf("[%v] Error verifying: %v", method.Name, err)
+		}
+
+		// The default stays randomized.
+		third, _ := jwt.NewWithClaims(tt.method, claims).SignedString(key)
+		fourth, _ := jwt.NewWithClaims(tt.method, claims).SignedString(key)
+		if third == fourth {
+			t.Errorf("[%v] Randomized signatures are identical", method.Name)
+		}
+	}
+}
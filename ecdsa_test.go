@@ -3,6 +3,7 @@ package jwt_test
 import (
 	"crypto/ecdsa"
 	"io/ioutil"
+	"math/big"
 	"strings"
 	"testing"
 
@@ -106,6 +107,54 @@ func TestECDSASign(t *testing.T) {
 	}
 }
 
+func TestECDSASign_lowS(t *testing.T) {
+	key, _ := ioutil.ReadFile("test/ec256-private.pem")
+	ecdsaKey, err := jwt.ParseECPrivateKeyFromPEM(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := ecdsaKey.Curve.Params().N
+	half := new(big.Int).Rsh(n, 1)
+
+	strict := *jwt.SigningMethodES256
+	strict.Strict = true
+	for i := 0; i < 32; i++ {
+		sig, err := jwt.SigningMethodES256.Sign("payload", ecdsaKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, _ := jwt.DecodeSegment(sig)
+		s := new(big.Int).SetBytes(raw[32:])
+		if s.Cmp(half) > 0 {
+			t.Fatalf("Sign produced a high-S signature")
+		}
+		if err = strict.Verify("payload", sig, &ecdsaKey.PublicKey); err != nil {
+			t.Fatalf("Strict verification of a low-S signature failed: %v", err)
+		}
+
+		// The high-S twin verifies, but not strictly.
+		s.Sub(n, s)
+		high := jwt.EncodeSegment(append(raw[:32:32], s.FillBytes(make([]byte, 32))...))
+		if err = jwt.SigningMethodES256.Verify("payload", high, &ecdsaKey.PublicKey); err != nil {
+			t.Errorf("Verification of a high-S signature failed: %v", err)
+		}
+		if err = strict.Verify("payload", high, &ecdsaKey.PublicKey); err == nil {
+			t.Errorf("Strict verification accepted a high-S signature")
+		}
+	}
+
+	// A signature whose base64url encoding has non-zero padding bits.
+	sig, _ := jwt.SigningMethodES256.Sign("payload", ecdsaKey)
+	last := strings.IndexByte("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_", sig[len(sig)-1])
+	noncanonical := sig[:len(sig)-1] + string("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"[last|1])
+	if err = jwt.SigningMethodES256.Verify("payload", noncanonical, &ecdsaKey.PublicKey); err != nil {
+		t.Errorf("Verification of a non-canonical encoding failed: %v", err)
+	}
+	if err = strict.Verify("payload", noncanonical, &ecdsaKey.PublicKey); err == nil {
+		t.Errorf("Strict verification accepted a non-canonical encoding")
+	}
+}
+
 func BenchmarkECDSAParsing(b *testing.B) {
 	for _, data := range ecdsaTestData {
 		key, _ := ioutil.ReadFile(data.keys["private"])
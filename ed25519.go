@@ -3,7 +3,6 @@ package jwt
 import (
 	"crypto"
 	"crypto/ed25519"
-	"crypto/rand"
 )
 
 var ()
@@ -73,7 +72,7 @@ func (m *SigningMethodEd25519) Sign(signingString string, key interface{}) (stri
 
 	// Sign the string and return the encoded result
 	// ed25519 performs a two-pass hash as part of its algorithm. Therefore, we need to pass a non-prehashed message into the Sign function, as indicated by crypto.Hash(0)
-	sig, err := ed25519Key.Sign(rand.Reader, []byte(signingString), crypto.Hash(0))
+	sig, err := ed25519Key.Sign(RandReader, []byte(signingString), crypto.Hash(0))
 	if err != nil {
 		return "", err
 	}
@@ -19,6 +19,56 @@ var ValidationErrorFormat = func(errs []error) string {
 	return str
 }
 
+// JoinErrors returns an error wrapping errs, discarding nil errors, or nil if
+// there are none. The returned error matches every error it wraps with
+// errors.Is and errors.As, and exposes them with an Unwrap() []error method.
+// Its message is formatted with ValidationErrorFormat.
+//
+// All claim validation failures of a token are reported this way, so callers
+// can enumerate every problem rather than only the first.
+func JoinErrors(errs ...error) error {
+	var j joinError
+	for _, err := range errs {
+		if err != nil {
+			j = append(j, err)
+		}
+	}
+	if len(j) == 0 {
+		return nil
+	}
+	return j
+}
+
+type joinError []error
+
+func (j joinError) Error() string {
+	return ValidationErrorFormat(j)
+}
+
+func (j joinError) Unwrap() []error {
+	return j
+}
+
+// Is supports errors.Is on Go versions predating Unwrap() []error.
+func (j joinError) Is(target error) bool {
+	for _, err := range j {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As supports errors.As on Go versions predating Unwrap() []error.
+func (j joinError) As(target interface{}) bool {
+	for _, err := range j {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 // Error constants
 var (
 	ErrMalformedToken              = errors.New("jwt: token is malformed")
@@ -35,6 +85,29 @@ var (
 	ErrMissingKeyFunc              = errors.New("jwt: KeyFunc not provided")
 	ErrSignatureInvalid            = errors.New("jwt: signature is invalid")
 	ErrKeyFuncError                = errors.New("jwt: KeyFunc returned an error")
+	ErrInsufficientScope           = errors.New("jwt: the token has insufficient scope")
+	ErrTokenNestingTooDeep         = errors.New("jwt: token nesting exceeds the maximum depth")
+	ErrMissingDecrypter            = errors.New("jwt: Decrypter not provided")
+	ErrUnknownKeyID                = errors.New("jwt: no key is registered for the key ID")
+	ErrKeyAlgorithmMismatch        = errors.New("jwt: the token algorithm does not match the key")
+	ErrMissingValidMethods         = errors.New("jwt: no valid signing methods are configured")
+	ErrTokenInvalidIssuer          = errors.New("jwt: the token has an invalid issuer")
+	ErrTokenInvalidAudience        = errors.New("jwt: the token has an invalid audience")
+	ErrTokenInvalidAuthorizedParty = errors.New("jwt: the token has an invalid authorized party")
+	ErrTokenRequiredClaimMissing   = errors.New("jwt: the token is missing a required claim")
+	ErrTokenTooOld                 = errors.New("jwt: the token was issued too long ago")
+	ErrTokenOutsideIssuanceWindow  = errors.New("jwt: the token was issued outside the accepted window")
+	ErrTokenInsufficientACR        = errors.New("jwt: the token has an insufficient authentication context class")
+	ErrTokenInsufficientAMR        = errors.New("jwt: the token lacks a required authentication method")
+	ErrTokenAuthTooOld             = errors.New("jwt: the end-user authenticated too long ago")
+	ErrSessionInvalid              = errors.New("jwt: the session of the token is no longer valid")
+	ErrInvalidClaimType            = errors.New("jwt: a claim has an invalid type")
+	ErrTokenTooLarge               = errors.New("jwt: the token exceeds the maximum size")
+	ErrInvalidSegments             = errors.New("jwt: the token does not have a non-empty header and claims segment")
+	ErrTokenInvalidType            = errors.New("jwt: the token has an invalid type")
+	ErrTokenRevoked                = errors.New("jwt: the token has been revoked")
+	ErrTokenReplayed               = errors.New("jwt: the token has already been used")
+	ErrTokenUnsigned               = errors.New("jwt: the encrypted token does not enclose a signed token")
 )
 
 type KeyFuncError struct {
@@ -96,6 +169,22 @@ func (err MalformedTokenError) Unwrap() error {
 	return ErrMalformedToken
 }
 
+// segmentsError reports a token rejected by its shape alone, before any of it
+// is decoded. It matches both ErrInvalidSegments and ErrMalformedToken.
+type segmentsError string
+
+func (err segmentsError) Error() string {
+	return MalformedTokenError(err).Error()
+}
+
+func (err segmentsError) Unwrap() error {
+	return ErrInvalidSegments
+}
+
+func (err segmentsError) Is(target error) bool {
+	return target == ErrMalformedToken
+}
+
 type UnregisteredSigningMethodError struct {
 	Alg string
 }
@@ -155,15 +244,23 @@ func (err *UsedBeforeIssuedError) Unwrap() error {
 	return ErrTokenUsedBeforeIssued
 }
 
+// ExpiredError is returned, wrapping ErrTokenExpired, when a token is used
+// after its "exp" claim. Use errors.As to retrieve it, for example to tell a
+// client how stale its token is or to allow a grace period for refreshing it.
 type ExpiredError struct {
-	ExpiredAt   time.Time
-	AttemptedAt time.Time
+	ExpiredAt   time.Time // The expiration time of the token
+	AttemptedAt time.Time // The time the token was validated
 }
 
 func (err *ExpiredError) Delta() time.Duration {
 	return err.AttemptedAt.Sub(err.ExpiredAt)
 }
 
+// ExpiredBy returns how long ago the token expired.
+func (err *ExpiredError) ExpiredBy() time.Duration {
+	return err.Delta()
+}
+
 func (err *ExpiredError) Error() string {
 	return fmt.Sprintf("token is expired by %v", err.Delta())
 }
@@ -171,6 +268,62 @@ func (err *ExpiredError) Unwrap() error {
 	return ErrTokenExpired
 }
 
+// InsufficientScopeError is returned when a token is valid but does not carry
+// every scope required to access a resource.
+type InsufficientScopeError struct {
+	Required []string // The scopes required by the resource
+	Missing  []string // The required scopes the token did not carry
+}
+
+func (err *InsufficientScopeError) Error() string {
+	return ErrInsufficientScope.Error() + ` (missing "` + strings.Join(err.Missing, " ") + `")`
+}
+
+func (err *InsufficientScopeError) Unwrap() error {
+	return ErrInsufficientScope
+}
+
+// ValidationError describes a claim which failed validation. Err is the
+// underlying error, such as an *ExpiredError or ErrTokenInvalidIssuer, so
+// ValidationError works with errors.Is and errors.As for the sentinels and
+// error types it wraps.
+type ValidationError struct {
+	Err      error         // The underlying error
+	Claim    string        // The name of the claim which failed, such as "exp"
+	Expected interface{}   // The accepted values, if the claim is compared to configured values
+	Actual   interface{}   // The value of the claim in the token, if present
+	Delta    time.Duration // How far outside the accepted time range the claim is, for "exp", "nbf" and "iat"
+}
+
+func (err *ValidationError) Error() string {
+	switch {
+	case err.Expected != nil:
+		return fmt.Sprintf("%v (%s: expected %v, got %v)", err.Err, err.Claim, err.Expected, redact(err.Claim, err.Actual))
+	case err.Actual == nil && err.Claim != "":
+		return fmt.Sprintf("%v (%s)", err.Err, err.Claim)
+	}
+	return err.Err.Error()
+}
+
+func (err *ValidationError) Unwrap() error {
+	return err.Err
+}
+
+func newExpiredError(exp, now time.Time) error {
+	e := &ExpiredError{ExpiredAt: exp, AttemptedAt: now}
+	return &ValidationError{Err: e, Claim: "exp", Actual: exp, Delta: e.Delta()}
+}
+
+func newUsedBeforeIssuedError(iat, now time.Time) error {
+	e := &UsedBeforeIssuedError{IssuedAt: iat, AttemptedAt: now}
+	return &ValidationError{Err: e, Claim: "iat", Actual: iat, Delta: e.Delta()}
+}
+
+func newNotYetValidError(nbf, now time.Time) error {
+	e := &NotYetValidError{ValidAt: nbf, AttemptedAt: now}
+	return &ValidationError{Err: e, Claim: "nbf", Actual: nbf, Delta: -e.Delta()}
+}
+
 // The errors that might occur when parsing and validating a token
 const (
 // ValidationErrorMalformed        uint32 = 1 << iota // Token is malformed
@@ -0,0 +1,82 @@
+package jwt_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestValidationError(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	err := jwt.MapClaims{"exp": float64(now.Add(-time.Minute).Unix())}.Valid()
+	var verr *jwt.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected a *ValidationError. Got: %v", err)
+	}
+	if verr.Claim != "exp" || verr.Delta != time.Minute {
+		t.Errorf("Unexpected validation error details: %+v", verr)
+	}
+	var expired *jwt.ExpiredError
+	if !errors.Is(err, jwt.ErrTokenExpired) || !errors.As(err, &expired) {
+		t.Errorf("Expected the validation error to wrap ExpiredError. Got: %v", err)
+	}
+
+	v := &jwt.Validator{Policy: jwt.Policy{Issuers: []string{"https://issuer.example.com"}}}
+	err = v.Validate(&jwt.Token{Claims: jwt.MapClaims{"iss": "https://evil.example.com"}})
+	if !errors.As(err, &verr) || verr.Claim != "iss" || verr.Actual != "https://evil.example.com" {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	if !strings.Contains(verr.Error(), `expected [https://issuer.example.com], got https://evil.example.com`) {
+		t.Errorf("Unexpected error message: %v", verr.Error())
+	}
+
+	defer func(p *jwt.RedactionPolicy) { jwt.Redaction = p }(jwt.Redaction)
+	jwt.Redaction = &jwt.RedactionPolicy{Deny: []string{"iss"}}
+	if strings.Contains(verr.Error(), "evil") {
+		t.Errorf("Expected the issuer to be redacted. Got: %v", verr.Error())
+	}
+}
+
+func TestJoinErrors(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	err := jwt.MapClaims{
+		"exp": float64(now.Add(-time.Minute).Unix()),
+		"nbf": float64(now.Add(time.Minute).Unix()),
+	}.Valid()
+	if !errors.Is(err, jwt.ErrTokenExpired) || !errors.Is(err, jwt.ErrTokenNotYetValid) {
+		t.Errorf("Expected the error to match both sentinels. Got: %v", err)
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 2 {
+		t.Errorf("Expected two wrapped errors. Got: %v", err)
+	}
+
+	if jwt.JoinErrors(nil, nil) != nil {
+		t.Error("Expected nil when joining only nil errors")
+	}
+}
+
+func TestExpiredError(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	exp := now.Add(-90 * time.Second)
+	err := jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(exp)}.Valid()
+	var expired *jwt.ExpiredError
+	if !errors.As(err, &expired) {
+		t.Fatalf("Expected an *ExpiredError. Got: %v", err)
+	}
+	if !expired.ExpiredAt.Equal(exp) || expired.ExpiredBy() != 90*time.Second {
+		t.Errorf("Unexpected expiry details: %+v", expired)
+	}
+}
@@ -0,0 +1,22 @@
+package jwt
+
+// SignatureSizer is implemented by signing methods whose signatures are
+// larger than those of the algorithms of RFC 7518, such as experimental
+// post-quantum algorithms. SignatureSize returns the largest size of a
+// signature in bytes, before base64url encoding.
+//
+// Parser.ParseReader raises its default limit on the size of a token by the
+// encoded size of the largest signature of the methods it resolves, so that
+// registering such a method does not require setting MaxTokenSize.
+type SignatureSizer interface {
+	SignatureSize() int
+}
+
+// HeaderSigningMethod is implemented by signing methods which require header
+// parameters beyond "alg" in the tokens they sign, such as the parameters of
+// a composite algorithm. New and NewWithClaims add the parameters returned by
+// SigningHeader to the header of a new token.
+type HeaderSigningMethod interface {
+	SigningMethod
+	SigningHeader() map[string]interface{}
+}
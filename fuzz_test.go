@@ -0,0 +1,77 @@
+//go:build go1.18
+// +build go1.18
+
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwttest"
+)
+
+var fuzzKey = []byte("fuzz")
+
+// fuzzSeeds adds a valid token and its malformed variants to the corpus of f.
+func fuzzSeeds(f *testing.F) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "fuzz", "aud": []string{"a", "b"}}).SignedString(fuzzKey)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(token)
+	for _, s := range jwttest.Mutate(token) {
+		f.Add(s)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, s string) {
+		token, err := jwt.NewParser(jwt.WithValidMethods("HS256")).Parse(s, func(*jwt.Token) (interface{}, error) {
+			return fuzzKey, nil
+		})
+		if err == nil && !token.Valid {
+			t.Errorf("Parse(%q) returned an invalid token without an error", s)
+		}
+	})
+}
+
+func FuzzParseUnverified(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, s string) {
+		jwt.NewParser().ParseUnverified(s, jwt.MapClaims{})
+		jwt.NewParser().ParseUnverified(s, &jwt.RegisteredClaims{})
+	})
+}
+
+func FuzzParseNested(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, s string) {
+		inner, _, err := jwt.NewParser().ParseNested(s, jwt.MapClaims{}, func(*jwt.Token) (interface{}, error) {
+			return fuzzKey, nil
+		})
+		if err == nil && !inner.Valid {
+			t.Errorf("ParseNested(%q) returned an invalid token without an error", s)
+		}
+	})
+}
+
+func FuzzParseJSON(f *testing.F) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "fuzz"})
+	s, err := token.SignedJSONString(fuzzKey, fuzzKey)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(s)
+	f.Add(`{"payload":"","signatures":[{}]}`)
+	f.Add(`{"payload":"e30","protected":"e30","signatures":[]}`)
+	f.Fuzz(func(t *testing.T, s string) {
+		keyFunc := func(*jwt.Token) (interface{}, error) { return fuzzKey, nil }
+		if token, err := jwt.NewParser().ParseJSON(s, jwt.MapClaims{}, keyFunc); err == nil && !token.Valid {
+			t.Errorf("ParseJSON(%q) returned an invalid token without an error", s)
+		}
+		if token, err := jwt.NewParser().ParseJSONAny(s, jwt.MapClaims{}, keyFunc); err == nil && !token.Valid {
+			t.Errorf("ParseJSONAny(%q) returned an invalid token without an error", s)
+		}
+	})
+}
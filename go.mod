@@ -1,5 +1,3 @@
 module github.com/chanced/go-jwt/v4
 
 go 1.15
-
-require github.com/hashicorp/go-multierror v1.1.1
@@ -6,7 +6,8 @@ import (
 )
 
 // SigningMethodHMAC implements the HMAC-SHA family of signing methods.
-// Expects key type of []byte for both signing and validation
+// Expects key type of []byte, or a SecretProvider such as a SecretSet, for
+// both signing and validation
 type SigningMethodHMAC struct {
 	Name string
 	Hash crypto.Hash
@@ -46,7 +47,7 @@ func (m *SigningMethodHMAC) Alg() string {
 // Verify implements token verification for the SigningMethod. Returns nil if the signature is valid.
 func (m *SigningMethodHMAC) Verify(signingString, signature string, key interface{}) error {
 	// Verify the key is the right type
-	keyBytes, ok := key.([]byte)
+	secrets, ok := hmacSecrets(key)
 	if !ok {
 		return ErrInvalidKeyType
 	}
@@ -64,23 +65,29 @@ func (m *SigningMethodHMAC) Verify(signingString, signature string, key interfac
 
 	// This signing method is symmetric, so we validate the signature
 	// by reproducing the signature from the signing string and key, then
-	// comparing that against the provided signature.
-	hasher := hmac.New(m.Hash.New, keyBytes)
-	hasher.Write([]byte(signingString))
-	if !hmac.Equal(sig, hasher.Sum(nil)) {
-		return &SignatureVerificationError{
-			Algorithm: "HMAC",
+	// comparing that against the provided signature. Each secret of a
+	// SecretProvider is tried in turn.
+	for _, secret := range secrets {
+		hasher := hmac.New(m.Hash.New, secret)
+		hasher.Write([]byte(signingString))
+		if hmac.Equal(sig, hasher.Sum(nil)) {
+			// No validation errors.  Signature is good.
+			return nil
 		}
 	}
-
-	// No validation errors.  Signature is good.
-	return nil
+	return &SignatureVerificationError{
+		Algorithm: "HMAC",
+	}
 }
 
 // Sign implements token signing for the SigningMethod.
-// Key must be []byte
+// Key must be []byte, or a SecretProvider, whose first secret is used
 func (m *SigningMethodHMAC) Sign(signingString string, key interface{}) (string, error) {
-	if keyBytes, ok := key.([]byte); ok {
+	if secrets, ok := hmacSecrets(key); ok {
+		if len(secrets) == 0 {
+			return "", ErrInvalidKey
+		}
+		keyBytes := secrets[0]
 		if !m.Hash.Available() {
 			return "", ErrHashUnavailable
 		}
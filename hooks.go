@@ -0,0 +1,29 @@
+package jwt
+
+import "context"
+
+// Hooks receive events for instrumentation, such as metrics or logging. Any
+// hook may be nil.
+type Hooks struct {
+	// OnValidationFailure is called when a Validator rejects a token.
+	OnValidationFailure func(token *Token, err error)
+
+	// OnDryRunFailure is called when the candidate policy of
+	// Validator.DryRun rejects a token. activeErr is the result of the
+	// active policy, so that tokens which would newly be rejected
+	// (activeErr == nil) can be told apart from those already rejected.
+	OnDryRunFailure func(token *Token, candidateErr, activeErr error)
+
+	// OnCacheEvent is called by in-memory stores, such as MemoryBlocklist,
+	// for every lookup and eviction. cache names the kind of store.
+	OnCacheEvent func(cache string, event CacheEvent)
+
+	// OnParse is called once a Parser has parsed a token, successfully or
+	// not, with the context passed to ParseWithContext. Set it with
+	// WithParserHooks.
+	OnParse func(ctx context.Context, e ParseEvent)
+
+	// OnSign is called once a Signer has signed a token, or failed to. Set it
+	// with Signer.WithHooks.
+	OnSign func(e SignEvent)
+}
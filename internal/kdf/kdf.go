@@ -0,0 +1,184 @@
+// Package kdf implements the password-based key derivation functions used to
+// decrypt PKCS #8 private keys: PBKDF2 (RFC 8018) and scrypt (RFC 7914). They
+// are kept here so the jwt package depends on the standard library alone.
+package kdf
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+// PBKDF2 derives a key of keyLen bytes from password and salt with iter
+// iterations of HMAC over h.
+func PBKDF2(password, salt []byte, iter, keyLen int, h func() hash.Hash) []byte {
+	prf := hmac.New(h, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+
+	var buf [4]byte
+	dk := make([]byte, 0, blocks*hashLen)
+	u := make([]byte, hashLen)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(buf[:], uint32(block))
+		prf.Write(buf[:4])
+		dk = prf.Sum(dk)
+		t := dk[len(dk)-hashLen:]
+		copy(u, t)
+
+		for n := 2; n <= iter; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = u[:0]
+			u = prf.Sum(u)
+			for i := range u {
+				t[i] ^= u[i]
+			}
+		}
+	}
+	return dk[:keyLen]
+}
+
+// ErrScryptParameters is returned by Scrypt for parameters outside the
+// bounds of RFC 7914.
+var ErrScryptParameters = errors.New("kdf: invalid scrypt parameters")
+
+// Scrypt derives a key of keyLen bytes from password and salt with the CPU
+// and memory cost N, which must be a power of two greater than one, the block
+// size r and the parallelization p.
+func Scrypt(password, salt []byte, N, r, p, keyLen int) ([]byte, error) {
+	const maxInt = int(^uint(0) >> 1)
+	if N <= 1 || N&(N-1) != 0 || r <= 0 || p <= 0 || keyLen <= 0 {
+		return nil, ErrScryptParameters
+	}
+	if uint64(r)*uint64(p) >= 1<<30 || r > maxInt/128/p || r > maxInt/256 || N > maxInt/128/r {
+		return nil, ErrScryptParameters
+	}
+
+	xy := make([]uint32, 64*r)
+	v := make([]uint32, 32*N*r)
+	b := PBKDF2(password, salt, 1, p*128*r, sha256.New)
+
+	for i := 0; i < p; i++ {
+		smix(b[i*128*r:], r, N, v, xy)
+	}
+	return PBKDF2(password, b, 1, keyLen, sha256.New), nil
+}
+
+// smix is the scryptROMix function of RFC 7914, section 5, applied in place
+// to the 128*r byte block b.
+func smix(b []byte, r, N int, v, xy []uint32) {
+	var tmp [16]uint32
+	R := 32 * r
+	x := xy
+	y := xy[R:]
+
+	j := 0
+	for i := 0; i < R; i++ {
+		x[i] = binary.LittleEndian.Uint32(b[j:])
+		j += 4
+	}
+	for i := 0; i < N; i += 2 {
+		copy(v[i*R:], x)
+		blockMix(&tmp, x, y, r)
+
+		copy(v[(i+1)*R:], y)
+		blockMix(&tmp, y, x, r)
+	}
+	for i := 0; i < N; i += 2 {
+		k := int(integer(x, r) & uint64(N-1))
+		blockXOR(x, v[k*R:], R)
+		blockMix(&tmp, x, y, r)
+
+		k = int(integer(y, r) & uint64(N-1))
+		blockXOR(y, v[k*R:], R)
+		blockMix(&tmp, y, x, r)
+	}
+	j = 0
+	for _, w := range x[:R] {
+		binary.LittleEndian.PutUint32(b[j:], w)
+		j += 4
+	}
+}
+
+// blockMix is the scryptBlockMix function of RFC 7914, section 4, reading
+// in and writing out.
+func blockMix(tmp *[16]uint32, in, out []uint32, r int) {
+	copy(tmp[:], in[(2*r-1)*16:])
+	for i := 0; i < 2*r; i += 2 {
+		salsaXOR(tmp, in[i*16:], out[i*8:])
+		salsaXOR(tmp, in[i*16+16:], out[i*8+r*16:])
+	}
+}
+
+func blockXOR(dst, src []uint32, n int) {
+	for i, w := range src[:n] {
+		dst[i] ^= w
+	}
+}
+
+// integer returns the little endian integer made of the first 64 bits of the
+// last 64 byte block of b.
+func integer(b []uint32, r int) uint64 {
+	j := (2*r - 1) * 16
+	return uint64(b[j]) | uint64(b[j+1])<<32
+}
+
+// salsaXOR sets tmp to Salsa20/8(tmp XOR in) and copies the result to out.
+func salsaXOR(tmp *[16]uint32, in, out []uint32) {
+	var x [16]uint32
+	for i := range x {
+		tmp[i] ^= in[i]
+		x[i] = tmp[i]
+	}
+	for i := 0; i < 8; i += 2 {
+		x[4] ^= bits.RotateLeft32(x[0]+x[12], 7)
+		x[8] ^= bits.RotateLeft32(x[4]+x[0], 9)
+		x[12] ^= bits.RotateLeft32(x[8]+x[4], 13)
+		x[0] ^= bits.RotateLeft32(x[12]+x[8], 18)
+
+		x[9] ^= bits.RotateLeft32(x[5]+x[1], 7)
+		x[13] ^= bits.RotateLeft32(x[9]+x[5], 9)
+		x[1] ^= bits.RotateLeft32(x[13]+x[9], 13)
+		x[5] ^= bits.RotateLeft32(x[1]+x[13], 18)
+
+		x[14] ^= bits.RotateLeft32(x[10]+x[6], 7)
+		x[2] ^= bits.RotateLeft32(x[14]+x[10], 9)
+		x[6] ^= bits.RotateLeft32(x[2]+x[14], 13)
+		x[10] ^= bits.RotateLeft32(x[6]+x[2], 18)
+
+		x[3] ^= bits.RotateLeft32(x[15]+x[11], 7)
+		x[7] ^= bits.RotateLeft32(x[3]+x[15], 9)
+		x[11] ^= bits.RotateLeft32(x[7]+x[3], 13)
+		x[15] ^= bits.RotateLeft32(x[11]+x[7], 18)
+
+		x[1] ^= bits.RotateLeft32(x[0]+x[3], 7)
+		x[2] ^= bits.RotateLeft32(x[1]+x[0], 9)
+		x[3] ^= bits.RotateLeft32(x[2]+x[1], 13)
+		x[0] ^= bits.RotateLeft32(x[3]+x[2], 18)
+
+		x[6] ^= bits.RotateLeft32(x[5]+x[4], 7)
+		x[7] ^= bits.RotateLeft32(x[6]+x[5], 9)
+		x[4] ^= bits.RotateLeft32(x[7]+x[6], 13)
+		x[5] ^= bits.RotateLeft32(x[4]+x[7], 18)
+
+		x[11] ^= bits.RotateLeft32(x[10]+x[9], 7)
+		x[8] ^= bits.RotateLeft32(x[11]+x[10], 9)
+		x[9] ^= bits.RotateLeft32(x[8]+x[11], 13)
+		x[10] ^= bits.RotateLeft32(x[9]+x[8], 18)
+
+		x[12] ^= bits.RotateLeft32(x[15]+x[14], 7)
+		x[13] ^= bits.RotateLeft32(x[12]+x[15], 9)
+		x[14] ^= bits.RotateLeft32(x[13]+x[12], 13)
+		x[15] ^= bits.RotateLeft32(x[14]+x[13], 18)
+	}
+	for i := range x {
+		tmp[i] += x[i]
+		out[i] = tmp[i]
+	}
+}
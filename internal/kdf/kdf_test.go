@@ -0,0 +1,70 @@
+package kdf_test
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4/internal/kdf"
+)
+
+func TestPBKDF2(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		salt     string
+		iter     int
+		keyLen   int
+		h        func() hash.Hash
+		want     string
+	}{
+		// RFC 6070
+		{"sha1/1", "password", "salt", 1, 20, sha1.New, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+		{"sha1/4096", "password", "salt", 4096, 20, sha1.New, "4b007901b765489abead49d926f721d065a429c1"},
+		{"sha1/long", "passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096, 25, sha1.New, "3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038"},
+		{"sha256/1", "password", "salt", 1, 32, sha256.New, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := kdf.PBKDF2([]byte(tt.password), []byte(tt.salt), tt.iter, tt.keyLen, tt.h)
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("PBKDF2() = %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrypt(t *testing.T) {
+	// RFC 7914, section 12
+	tests := []struct {
+		name     string
+		password string
+		salt     string
+		N, r, p  int
+		want     string
+	}{
+		{"empty", "", "", 16, 1, 1, "77d6576238657b203b19ca42c18a0497f16b4844e3074ae8dfdffa3fede21442fcd0069ded0948f8326a753a0fc81f17e8d3e0fb2e0d3628cf35e20c38d18906"},
+		{"password", "password", "NaCl", 1024, 8, 16, "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b3731622eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := kdf.Scrypt([]byte(tt.password), []byte(tt.salt), tt.N, tt.r, tt.p, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("Scrypt() = %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrypt_parameters(t *testing.T) {
+	for _, N := range []int{0, 1, 3, 1000} {
+		if _, err := kdf.Scrypt(nil, nil, N, 1, 1, 32); err != kdf.ErrScryptParameters {
+			t.Errorf("Scrypt(N=%d) error = %v, want ErrScryptParameters", N, err)
+		}
+	}
+}
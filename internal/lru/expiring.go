@@ -0,0 +1,70 @@
+package lru
+
+import "time"
+
+// minSweep is the fewest additions between two sweeps of an Expiring cache.
+const minSweep = 64
+
+// Expiring is a Cache whose entries expire at a time, or never if it is zero.
+// Expired entries are removed as they are looked up, and by a sweep of the
+// whole cache once as many entries have been added since the last sweep as it
+// held then, so that Add takes amortized constant time. MaxEntries bounds the
+// size in between. The zero value is an unbounded, empty cache.
+type Expiring struct {
+	Cache
+
+	added int // since the last sweep
+	swept int // entries left by the last sweep
+}
+
+type item struct {
+	value interface{}
+	until time.Time
+}
+
+func (i item) expired(now time.Time) bool {
+	return !i.until.IsZero() && now.After(i.until)
+}
+
+// Lookup returns the value and expiry of key and marks it as recently used,
+// unless it has expired by now, in which case it is removed.
+func (c *Expiring) Lookup(key string, now time.Time) (interface{}, time.Time, bool) {
+	v, ok := c.Cache.Get(key)
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	i := v.(item)
+	if i.expired(now) {
+		c.Cache.Remove(key)
+		return nil, time.Time{}, false
+	}
+	return i.value, i.until, true
+}
+
+// Until returns the expiry of key as Lookup does.
+func (c *Expiring) Until(key string, now time.Time) (time.Time, bool) {
+	_, until, ok := c.Lookup(key, now)
+	return until, ok
+}
+
+// Set sets the value and expiry of key, sweeping out expired entries if enough
+// have been added since the last sweep.
+func (c *Expiring) Set(key string, value interface{}, until, now time.Time) {
+	if c.added++; c.added >= minSweep && c.added >= c.swept {
+		c.Sweep(now)
+	}
+	c.Cache.Add(key, item{value: value, until: until})
+}
+
+// AddUntil sets the expiry of key, without a value.
+func (c *Expiring) AddUntil(key string, until, now time.Time) {
+	c.Set(key, nil, until, now)
+}
+
+// Sweep removes the entries which have expired by now.
+func (c *Expiring) Sweep(now time.Time) {
+	c.RemoveFunc(func(_ string, v interface{}) bool {
+		return v.(item).expired(now)
+	})
+	c.added, c.swept = 0, c.Len()
+}
@@ -0,0 +1,84 @@
+// Package lru implements the least-recently-used cache shared by the
+// in-memory stores of this module.
+package lru
+
+import "container/list"
+
+// Cache is a map which, once it holds MaxEntries entries, evicts the least
+// recently used entry to make room for a new one. It is not safe for
+// concurrent use. The zero value is an unbounded, empty cache.
+type Cache struct {
+	MaxEntries int                                 // Optional. Zero means no limit
+	OnEvict    func(key string, value interface{}) // Optional. Called for entries evicted to make room
+
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type entry struct {
+	key   string
+	value interface{}
+}
+
+// Get returns the value of key and marks it as recently used.
+func (c *Cache) Get(key string) (interface{}, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	return e.Value.(*entry).value, true
+}
+
+// Add sets the value of key, marks it as recently used and evicts the least
+// recently used entries beyond MaxEntries.
+func (c *Cache) Add(key string, value interface{}) {
+	if c.items == nil {
+		c.ll, c.items = list.New(), make(map[string]*list.Element)
+	}
+	if e, ok := c.items[key]; ok {
+		c.ll.MoveToFront(e)
+		e.Value.(*entry).value = value
+		return
+	}
+	c.items[key] = c.ll.PushFront(&entry{key, value})
+	for c.MaxEntries > 0 && c.ll.Len() > c.MaxEntries {
+		e := c.ll.Back()
+		c.remove(e)
+		if c.OnEvict != nil {
+			c.OnEvict(e.Value.(*entry).key, e.Value.(*entry).value)
+		}
+	}
+}
+
+// Remove removes key.
+func (c *Cache) Remove(key string) {
+	if e, ok := c.items[key]; ok {
+		c.remove(e)
+	}
+}
+
+// RemoveFunc removes the entries for which f returns true. OnEvict is not
+// called for them.
+func (c *Cache) RemoveFunc(f func(key string, value interface{}) bool) {
+	if c.ll == nil {
+		return
+	}
+	for e := c.ll.Front(); e != nil; {
+		next := e.Next()
+		if en := e.Value.(*entry); f(en.key, en.value) {
+			c.remove(e)
+		}
+		e = next
+	}
+}
+
+// Len returns the number of entries.
+func (c *Cache) Len() int {
+	return len(c.items)
+}
+
+func (c *Cache) remove(e *list.Element) {
+	c.ll.Remove(e)
+	delete(c.items, e.Value.(*entry).key)
+}
@@ -0,0 +1,77 @@
+package lru_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4/internal/lru"
+)
+
+func TestCache(t *testing.T) {
+	var evicted []string
+	c := &lru.Cache{MaxEntries: 2, OnEvict: func(key string, _ interface{}) { evicted = append(evicted, key) }}
+	c.Add("a", 1)
+	c.Add("b", 2)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %v, %v", v, ok)
+	}
+	c.Add("c", 3) // evicts b, the least recently used
+	if _, ok := c.Get("b"); ok {
+		t.Error("b was not evicted")
+	}
+	c.Add("a", 4)
+	c.Add("d", 5) // evicts c
+	if !reflect.DeepEqual(evicted, []string{"b", "c"}) {
+		t.Errorf("evicted %q", evicted)
+	}
+	if v, _ := c.Get("a"); v != 4 {
+		t.Errorf("Get(a) = %v, want 4", v)
+	}
+
+	c.RemoveFunc(func(key string, _ interface{}) bool { return key == "a" })
+	c.Remove("missing")
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", c.Len())
+	}
+	if len(evicted) != 2 {
+		t.Errorf("RemoveFunc called OnEvict")
+	}
+}
+
+func TestExpiring(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	var c lru.Expiring
+	c.AddUntil("a", now.Add(time.Minute), now)
+	c.AddUntil("b", now.Add(time.Hour), now)
+	if until, ok := c.Until("a", now); !ok || !until.Equal(now.Add(time.Minute)) {
+		t.Errorf("Until(a) = %v, %v", until, ok)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.Until("a", now); ok {
+		t.Error("a did not expire")
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d, want 1 once a expired", c.Len())
+	}
+
+	// Entries which are never looked up again are swept out as others are added.
+	for i := 0; i < 200; i++ {
+		c.AddUntil(strconv.Itoa(i), now.Add(time.Second), now)
+		now = now.Add(time.Minute)
+	}
+	if c.Len() > 130 {
+		t.Errorf("Len() = %d, expired entries were not swept", c.Len())
+	}
+	if _, ok := c.Until("b", now); ok {
+		t.Error("b did not expire")
+	}
+
+	c.Set("forever", 1, time.Time{}, now)
+	c.Sweep(now.Add(time.Hour))
+	if v, _, ok := c.Lookup("forever", now.Add(time.Hour)); !ok || v != 1 {
+		t.Errorf("Lookup(forever) = %v, %v", v, ok)
+	}
+}
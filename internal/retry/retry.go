@@ -0,0 +1,58 @@
+// Package retry retries calls to remote signing services with exponential
+// backoff. It is shared by the KMS adapter modules.
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Defaults applied to zero fields of Do's parameters.
+const (
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 2 * time.Second
+)
+
+// Do calls call until it succeeds, returns an error for which retryable is
+// false, or maxAttempts calls have been made, sleeping between attempts for a
+// backoff which doubles from initial up to max. observe, if not nil, is called
+// after every attempt with its number, starting at 1, duration and error. The
+// error of the last attempt is returned, or ctx.Err() if ctx is done while
+// waiting.
+func Do(ctx context.Context, maxAttempts int, initial, max time.Duration,
+	retryable func(error) bool, observe func(attempt int, d time.Duration, err error),
+	call func(ctx context.Context) error) error {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	if initial <= 0 {
+		initial = DefaultInitialBackoff
+	}
+	if max <= 0 {
+		max = DefaultMaxBackoff
+	}
+
+	backoff := initial
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := call(ctx)
+		if observe != nil {
+			observe(attempt, time.Since(start), err)
+		}
+		if err == nil || attempt >= maxAttempts || !retryable(err) {
+			return err
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+		if backoff *= 2; backoff > max {
+			backoff = max
+		}
+	}
+}
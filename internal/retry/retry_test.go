@@ -0,0 +1,53 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4/internal/retry"
+)
+
+var errTransient = errors.New("transient")
+
+func TestDo(t *testing.T) {
+	retryable := func(err error) bool { return errors.Is(err, errTransient) }
+
+	var attempts []int
+	calls := 0
+	err := retry.Do(context.Background(), 3, time.Millisecond, time.Millisecond, retryable,
+		func(attempt int, d time.Duration, err error) { attempts = append(attempts, attempt) },
+		func(context.Context) error {
+			if calls++; calls < 2 {
+				return errTransient
+			}
+			return nil
+		})
+	if err != nil || calls != 2 || len(attempts) != 2 {
+		t.Errorf("Do() = %v after %d calls, observed %v", err, calls, attempts)
+	}
+
+	calls = 0
+	err = retry.Do(context.Background(), 3, time.Millisecond, time.Millisecond, retryable, nil,
+		func(context.Context) error { calls++; return errTransient })
+	if !errors.Is(err, errTransient) || calls != 3 {
+		t.Errorf("Do() = %v after %d calls, want errTransient after 3", err, calls)
+	}
+
+	calls = 0
+	permanent := errors.New("permanent")
+	err = retry.Do(context.Background(), 3, time.Millisecond, time.Millisecond, retryable, nil,
+		func(context.Context) error { calls++; return permanent })
+	if err != permanent || calls != 1 {
+		t.Errorf("Do() = %v after %d calls, want permanent after 1", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = retry.Do(ctx, 3, time.Hour, time.Hour, retryable, nil,
+		func(context.Context) error { return errTransient })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Do() = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,10 @@
+// Package introspection is a client of OAuth 2.0 token introspection, as
+// described in https://datatracker.ietf.org/doc/html/rfc7662, for resource
+// servers which accept both JWTs and opaque access tokens.
+//
+// A Client asks the introspection endpoint of the authorization server about
+// a token and maps an active token's response into jwt.MapClaims. NewVerifier
+// combines it with local verification: JWTs are verified with a Keyfunc, and
+// opaque tokens, as well as JWTs whose key cannot be resolved, are
+// introspected, so that a single Verify call serves hybrid deployments.
+package introspection
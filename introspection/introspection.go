@@ -0,0 +1,118 @@
+package introspection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// maxResponseSize bounds the introspection responses read.
+const maxResponseSize = 1 << 20
+
+var (
+	ErrInactive         = errors.New("introspection: the token is not active")
+	ErrMissingEndpoint  = errors.New("introspection: the endpoint is not configured")
+	ErrInvalidResponse  = errors.New("introspection: the response is not a valid introspection response")
+	ErrUnexpectedStatus = errors.New("introspection: the endpoint returned an unexpected status")
+)
+
+// Client calls an introspection endpoint. It authenticates with
+// client_secret_basic if ClientID is set; other client authentication
+// methods, such as private_key_jwt, can be implemented by an HTTPClient
+// which authenticates its requests.
+type Client struct {
+	Endpoint     string       // The URL of the introspection endpoint
+	ClientID     string       // Optional. The client ID the resource server authenticates with
+	ClientSecret string       // Optional. The secret of ClientID
+	HTTPClient   *http.Client // Optional. Defaults to http.DefaultClient
+
+	// TokenTypeHint, if set, is sent as the token_type_hint parameter, such
+	// as "access_token".
+	TokenTypeHint string
+}
+
+// Introspect asks the endpoint about token and returns the members of the
+// response, without "active", as claims. Inactive tokens fail with
+// ErrInactive, and responses other than 200 OK with an error wrapping
+// ErrUnexpectedStatus.
+func (c *Client) Introspect(ctx context.Context, token string) (jwt.MapClaims, error) {
+	if c.Endpoint == "" {
+		return nil, ErrMissingEndpoint
+	}
+	form := url.Values{"token": {token}}
+	if c.TokenTypeHint != "" {
+		form.Set("token_type_hint", c.TokenTypeHint)
+	}
+	req, err := http.NewRequest(http.MethodPost, c.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.ClientID != "" {
+		// RFC 6749, section 2.3.1, encodes the credentials before combining them.
+		req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+
+	claims := jwt.MapClaims{}
+	if err = json.Unmarshal(body, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	active, ok := claims["active"].(bool)
+	if !ok {
+		return nil, ErrInvalidResponse
+	}
+	if !active {
+		return nil, ErrInactive
+	}
+	delete(claims, "active")
+	return claims, nil
+}
+
+// Validate implements jwt.OpaqueValidator with Introspect.
+func (c *Client) Validate(ctx context.Context, token string) (jwt.Claims, error) {
+	claims, err := c.Introspect(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// NewVerifier returns a jwt.Verifier which verifies JWTs locally with parser,
+// which may be nil, and keyFunc, and introspects opaque tokens and JWTs whose
+// key keyFunc cannot supply with c. The claims of introspected tokens are
+// validated as parser validates those of JWTs.
+func NewVerifier(c *Client, parser *jwt.Parser, keyFunc jwt.Keyfunc) *jwt.FallbackVerifier {
+	return &jwt.FallbackVerifier{
+		Parser:                  parser,
+		Keyfunc:                 keyFunc,
+		Opaque:                  c.Validate,
+		FallbackOnUnresolvedKey: true,
+	}
+}
@@ -0,0 +1,115 @@
+package introspection_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/introspection"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, ok := r.BasicAuth(); !ok || id != "rs%3A1" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.PostFormValue("token_type_hint") != "access_token" {
+			t.Errorf("Unexpected token_type_hint %q", r.PostFormValue("token_type_hint"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.PostFormValue("token") {
+		case "opaque", "eyJhbGciOiJIUzI1NiIsImtpZCI6InVua25vd24ifQ.eyJzdWIiOiJqd3QifQ.c2ln":
+			w.Write([]byte(`{"active":true,"sub":"user","scope":"read","exp":` + jwtExp() + `}`))
+		case "expired":
+			w.Write([]byte(`{"active":true,"sub":"user","exp":1}`))
+		case "malformed":
+			w.Write([]byte(`{"sub":"user"}`))
+		default:
+			w.Write([]byte(`{"active":false}`))
+		}
+	}))
+}
+
+func jwtExp() string {
+	return strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+}
+
+func TestClient_Introspect(t *testing.T) {
+	srv := newServer(t)
+	defer srv.Close()
+	c := &introspection.Client{Endpoint: srv.URL, ClientID: "rs:1", ClientSecret: "secret", TokenTypeHint: "access_token"}
+	ctx := context.Background()
+
+	claims, err := c.Introspect(ctx, "opaque")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := claims["active"]; ok || claims["sub"] != "user" || claims["scope"] != "read" {
+		t.Errorf("Unexpected claims: %v", claims)
+	}
+
+	if _, err = c.Introspect(ctx, "revoked"); !errors.Is(err, introspection.ErrInactive) {
+		t.Errorf("Expected ErrInactive, got %v", err)
+	}
+	if _, err = c.Introspect(ctx, "malformed"); !errors.Is(err, introspection.ErrInvalidResponse) {
+		t.Errorf("Expected ErrInvalidResponse, got %v", err)
+	}
+	c.ClientSecret = "wrong"
+	if _, err = c.Introspect(ctx, "opaque"); !errors.Is(err, introspection.ErrUnexpectedStatus) {
+		t.Errorf("Expected ErrUnexpectedStatus, got %v", err)
+	}
+	if _, err = new(introspection.Client).Introspect(ctx, "opaque"); !errors.Is(err, introspection.ErrMissingEndpoint) {
+		t.Errorf("Expected ErrMissingEndpoint, got %v", err)
+	}
+}
+
+func TestNewVerifier(t *testing.T) {
+	srv := newServer(t)
+	defer srv.Close()
+	c := &introspection.Client{Endpoint: srv.URL, ClientID: "rs:1", ClientSecret: "secret", TokenTypeHint: "access_token"}
+
+	key := []byte("secret")
+	local, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "local"}, jwt.WithKeyID("k1")).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		if token.Header["kid"] != "k1" {
+			return nil, jwt.ErrUnknownKeyID
+		}
+		return key, nil
+	}
+	v := introspection.NewVerifier(c, nil, keyFunc)
+	ctx := context.Background()
+
+	tests := []struct {
+		credential string
+		subject    string
+		err        error
+	}{
+		{local, "local", nil},
+		{"opaque", "user", nil},
+		{"eyJhbGciOiJIUzI1NiIsImtpZCI6InVua25vd24ifQ.eyJzdWIiOiJqd3QifQ.c2ln", "user", nil},
+		{"revoked", "", introspection.ErrInactive},
+		{"expired", "", jwt.ErrTokenExpired},
+		{local + "x", "", jwt.ErrSignatureInvalid},
+	}
+	for _, tc := range tests {
+		token, err := v.Verify(ctx, tc.credential)
+		if tc.err != nil {
+			if !errors.Is(err, tc.err) {
+				t.Errorf("%s: expected %v, got %v", tc.credential, tc.err, err)
+			}
+			continue
+		}
+		if err != nil || !token.Valid || token.Claims.(jwt.MapClaims)["sub"] != tc.subject {
+			t.Errorf("%s: unexpected result %v, %v", tc.credential, token, err)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+// Package jar builds and validates the request objects of JWT-Secured
+// Authorization Requests, described in
+// https://datatracker.ietf.org/doc/html/rfc9101, which carry the parameters
+// of an authorization request as the claims of a signed, and optionally
+// encrypted, JWT, passed by value in the "request" parameter or by reference
+// through "request_uri", such as one returned by a Pushed Authorization
+// Request (https://datatracker.ietf.org/doc/html/rfc9126).
+//
+// Clients build request objects with Sign, or SignAndEncrypt to keep the
+// parameters confidential. Authorization servers validate them with Verify,
+// which also decrypts encrypted request objects when configured with
+// WithDecryption.
+package jar
@@ -0,0 +1,227 @@
+package jar
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwe"
+)
+
+// TokenType is the "typ" header of request objects.
+const TokenType = jwt.TypeAuthzRequest
+
+// DefaultTTL is how long request objects made by Sign are valid, unless their
+// "exp" claim is set.
+const DefaultTTL = 5 * time.Minute
+
+var (
+	ErrClientIDMismatch = errors.New(`jar: the "client_id" claim does not match the client_id parameter`)
+	ErrNestedRequest    = errors.New(`jar: the request object contains a "request" or "request_uri" claim`)
+	ErrMissingClientID  = errors.New(`jar: the "client_id" claim is missing`)
+)
+
+// Claims are the claims of a request object: the parameters of the
+// authorization request, and the registered claims of the JWT. Parameters of
+// extensions can be carried by embedding Claims in a custom type.
+type Claims struct {
+	jwt.RegisteredClaims
+
+	ClientID            string     `json:"client_id,omitempty"`
+	ResponseType        string     `json:"response_type,omitempty"`
+	ResponseMode        string     `json:"response_mode,omitempty"`
+	RedirectURI         string     `json:"redirect_uri,omitempty"`
+	Scope               jwt.Scopes `json:"scope,omitempty"`
+	State               string     `json:"state,omitempty"`
+	Nonce               string     `json:"nonce,omitempty"`
+	Prompt              string     `json:"prompt,omitempty"`
+	MaxAge              *int64     `json:"max_age,omitempty"`
+	LoginHint           string     `json:"login_hint,omitempty"`
+	ACRValues           string     `json:"acr_values,omitempty"`
+	CodeChallenge       string     `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string     `json:"code_challenge_method,omitempty"`
+
+	// the `request` and `request_uri` parameters, which request objects
+	// must not contain
+	Request    *string `json:"request,omitempty"`
+	RequestURI *string `json:"request_uri,omitempty"`
+}
+
+// Valid validates the time based claims, and the requirements specific to
+// request objects: "client_id" is required, and "request" and "request_uri"
+// are prohibited, as described in RFC 9101, section 4.
+func (c *Claims) Valid() error {
+	errs := []error{c.RegisteredClaims.Valid()}
+	if c.ClientID == "" {
+		errs = append(errs, &jwt.ValidationError{Err: ErrMissingClientID, Claim: "client_id"})
+	}
+	if c.Request != nil || c.RequestURI != nil {
+		errs = append(errs, ErrNestedRequest)
+	}
+	return jwt.JoinErrors(errs...)
+}
+
+// Policy returns the jwt.Policy request objects sent by the client identified
+// by clientID to the authorization server identified by issuer must satisfy:
+// "iss" must be clientID, "aud" must contain issuer, and "exp" must be
+// present, as profiled by FAPI 2.0 and OpenID Connect.
+func Policy(issuer, clientID string) jwt.Policy {
+	return jwt.Policy{
+		Issuers:           []string{clientID},
+		Audiences:         []string{issuer},
+		RequireExpiration: true,
+	}
+}
+
+// Sign returns a request object carrying claims, sent to the authorization
+// server identified by audience, signed with key using method. Unless they
+// are set, "iss" is set to the client ID, "aud" to audience, "jti" to a new
+// random ID, "iat" to now and "exp" to DefaultTTL later. claims is updated.
+func Sign(claims *Claims, audience string, method jwt.SigningMethod, key interface{}, opts ...jwt.TokenOption) (string, error) {
+	if claims.ClientID == "" {
+		return "", ErrMissingClientID
+	}
+	if claims.Issuer == "" {
+		claims.Issuer = claims.ClientID
+	}
+	if len(claims.Audience) == 0 {
+		claims.Audience = jwt.ClaimStrings{audience}
+	}
+	if claims.ID == "" {
+		id, err := jwt.NewID()
+		if err != nil {
+			return "", err
+		}
+		claims.ID = id
+	}
+	now := jwt.TimeFunc()
+	if claims.IssuedAt == nil {
+		claims.IssuedAt = jwt.NewNumericDate(now)
+	}
+	if claims.ExpiresAt == nil {
+		claims.ExpiresAt = jwt.NewNumericDate(now.Add(DefaultTTL))
+	}
+	opts = append([]jwt.TokenOption{jwt.WithType(TokenType)}, opts...)
+	return jwt.NewWithClaims(method, claims, opts...).SignedString(key)
+}
+
+// SignAndEncrypt signs a request object as Sign does, then encrypts it to
+// the authorization server with encKey, its public or shared key, using the
+// key management algorithm alg and the content encryption algorithm enc, as
+// described in RFC 9101, section 4. header holds additional JWE header
+// parameters, such as "kid", and may be nil.
+func SignAndEncrypt(claims *Claims, audience string, method jwt.SigningMethod, key interface{}, alg, enc string, encKey interface{}, header map[string]interface{}) (string, error) {
+	signed, err := Sign(claims, audience, method, key)
+	if err != nil {
+		return "", err
+	}
+	return jwe.EncryptNested(signed, alg, enc, encKey, header)
+}
+
+// Option configures Verify.
+type Option func(*config)
+
+type config struct {
+	decrypter     jwt.Decrypter
+	allowUntyped  bool
+	parserOptions []jwt.ParserOption
+	policy        func(*jwt.Policy)
+}
+
+// WithDecryption accepts encrypted request objects, decrypting them with the
+// key keyFunc returns.
+func WithDecryption(keyFunc jwe.Keyfunc) Option {
+	return func(c *config) {
+		c.decrypter = &jwe.TokenDecrypter{Keyfunc: keyFunc}
+	}
+}
+
+// WithUntypedRequests accepts request objects without a "typ" header, or with
+// the generic "JWT", from clients predating RFC 9101, which recommends but
+// does not require explicit typing.
+func WithUntypedRequests() Option {
+	return func(c *config) {
+		c.allowUntyped = true
+	}
+}
+
+// WithParserOptions configures the parser of the request object, such as
+// with jwt.WithValidMethods to accept only the algorithm registered for the
+// client as request_object_signing_alg.
+func WithParserOptions(opts ...jwt.ParserOption) Option {
+	return func(c *config) {
+		c.parserOptions = append(c.parserOptions, opts...)
+	}
+}
+
+// WithPolicy amends the Policy request objects are validated against, such as
+// to set Policy.MaxAge or Policy.RequiredClaims.
+func WithPolicy(fn func(p *jwt.Policy)) Option {
+	return func(c *config) {
+		c.policy = fn
+	}
+}
+
+// Verify validates the request object raw, received by the authorization
+// server identified by issuer along with the client_id parameter clientID,
+// verifying its signature with the key keyFunc returns, typically one from
+// the client's registered JWK Set:
+//
+//   - encrypted request objects are decrypted, if WithDecryption is given;
+//   - the "typ" header must be "oauth-authz-req+jwt";
+//   - the signature must verify, with an algorithm other than "none";
+//   - "client_id" must equal clientID;
+//   - "request" and "request_uri" must be absent;
+//   - the claims must satisfy Policy(issuer, clientID).
+//
+// All failures of the claims are reported, combined with jwt.JoinErrors. The
+// parameters of the authorization request are those of the returned claims;
+// those sent outside the request object must be ignored.
+func Verify(raw, issuer, clientID string, keyFunc jwt.Keyfunc, opts ...Option) (*Claims, error) {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	types := []string{TokenType}
+	if c.allowUntyped {
+		types = append(types, "", jwt.TypeJWT)
+	}
+	p := jwt.NewParser(append([]jwt.ParserOption{jwt.WithValidTypes(types...)}, c.parserOptions...)...)
+	p.SkipClaimsValidation = true
+
+	// Request objects must be signed; encrypting them is optional.
+	if strings.Count(raw, ".") == 4 {
+		if c.decrypter == nil {
+			return nil, jwt.ErrMissingDecrypter
+		}
+		header, plaintext, err := c.decrypter.DecryptToken(raw)
+		if err != nil {
+			return nil, err
+		}
+		if cty, _ := header["cty"].(string); strings.TrimPrefix(strings.ToLower(cty), "application/") != "jwt" {
+			return nil, jwe.ErrNotNested
+		}
+		raw = string(plaintext)
+	}
+	claims := new(Claims)
+	token, err := p.ParseWithClaims(raw, claims, keyFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := Policy(issuer, clientID)
+	if c.policy != nil {
+		c.policy(&policy)
+	}
+	v := jwt.Validator{Policy: policy}
+	errs := []error{claims.Valid(), v.Validate(token)}
+	if claims.ClientID != "" && claims.ClientID != clientID {
+		errs = append(errs, &jwt.ValidationError{Err: ErrClientIDMismatch, Claim: "client_id", Expected: clientID, Actual: claims.ClientID})
+	}
+	if err = jwt.JoinErrors(errs...); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
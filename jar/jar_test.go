@@ -0,0 +1,131 @@
+package jar_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jar"
+)
+
+const (
+	issuer   = "https://issuer.example"
+	clientID = "client"
+)
+
+func newKey(t *testing.T) (*ecdsa.PrivateKey, jwt.Keyfunc) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil }
+}
+
+func requestClaims() *jar.Claims {
+	return &jar.Claims{
+		ClientID:     clientID,
+		ResponseType: "code",
+		RedirectURI:  "https://client.example/cb",
+		Scope:        jwt.Scopes{"openid", "profile"},
+		State:        "af0ifjsldkj",
+	}
+}
+
+func TestVerify(t *testing.T) {
+	key, keyFunc := newKey(t)
+	raw, err := jar.Sign(requestClaims(), issuer, jwt.SigningMethodES256, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := jar.Verify(raw, issuer, clientID, keyFunc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if claims.Issuer != clientID || claims.RedirectURI != "https://client.example/cb" || !claims.Scope.HasAll("openid", "profile") {
+		t.Errorf("Verify() = %+v", claims)
+	}
+
+	if _, err = jar.Verify(raw, issuer, "other", keyFunc); !errors.Is(err, jar.ErrClientIDMismatch) {
+		t.Errorf("Expected ErrClientIDMismatch, got %v", err)
+	}
+	if _, err = jar.Verify(raw, "https://other.example", clientID, keyFunc); !errors.Is(err, jwt.ErrTokenInvalidAudience) {
+		t.Errorf("Expected ErrTokenInvalidAudience, got %v", err)
+	}
+}
+
+func TestVerify_claims(t *testing.T) {
+	key, keyFunc := newKey(t)
+	uri := "urn:example:request"
+
+	tests := []struct {
+		name   string
+		modify func(c *jar.Claims)
+		want   error
+	}{
+		{"request_uri", func(c *jar.Claims) { c.RequestURI = &uri }, jar.ErrNestedRequest},
+		{"issuer", func(c *jar.Claims) { c.Issuer = "other" }, jwt.ErrTokenInvalidIssuer},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			claims := requestClaims()
+			tc.modify(claims)
+			raw, err := jar.Sign(claims, issuer, jwt.SigningMethodES256, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = jar.Verify(raw, issuer, clientID, keyFunc); !errors.Is(err, tc.want) {
+				t.Errorf("Expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestVerify_untyped(t *testing.T) {
+	key, keyFunc := newKey(t)
+	claims := requestClaims()
+	claims.Issuer = clientID
+	claims.Audience = jwt.ClaimStrings{issuer}
+	claims.ExpiresAt = jwt.NewNumericDate(jwt.TimeFunc().Add(jar.DefaultTTL))
+	raw, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = jar.Verify(raw, issuer, clientID, keyFunc); !errors.Is(err, jwt.ErrTokenInvalidType) {
+		t.Errorf("Expected ErrTokenInvalidType, got %v", err)
+	}
+	if _, err = jar.Verify(raw, issuer, clientID, keyFunc, jar.WithUntypedRequests()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestSignAndEncrypt(t *testing.T) {
+	key, keyFunc := newKey(t)
+	encKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := jar.SignAndEncrypt(requestClaims(), issuer, jwt.SigningMethodES256, key, "RSA-OAEP-256", "A256GCM", &encKey.PublicKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decryptKey := func(map[string]interface{}) (interface{}, error) { return encKey, nil }
+	claims, err := jar.Verify(raw, issuer, clientID, keyFunc, jar.WithDecryption(decryptKey))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if claims.State != "af0ifjsldkj" {
+		t.Errorf("State = %v", claims.State)
+	}
+
+	if _, err = jar.Verify(raw, issuer, clientID, keyFunc); !errors.Is(err, jwt.ErrMissingDecrypter) {
+		t.Errorf("Expected ErrMissingDecrypter, got %v", err)
+	}
+}
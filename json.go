@@ -0,0 +1,47 @@
+package jwt
+
+import "encoding/json"
+
+// JSONMarshalFunc encodes v as JSON, like json.Marshal.
+type JSONMarshalFunc func(v interface{}) ([]byte, error)
+
+// JSONUnmarshalFunc decodes JSON into v, like json.Unmarshal.
+type JSONUnmarshalFunc func(data []byte, v interface{}) error
+
+var (
+	jsonMarshal   JSONMarshalFunc   = json.Marshal
+	jsonUnmarshal JSONUnmarshalFunc = json.Unmarshal
+	customJSON    bool
+)
+
+// SetJSONProvider replaces encoding/json for encoding and decoding the
+// header and claims of tokens, for example with a faster drop-in
+// replacement:
+//
+//	jwt.SetJSONProvider(jsoniter.ConfigCompatibleWithStandardLibrary.Marshal,
+//		jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal)
+//
+// Passing nil restores encoding/json. The replacement must honor the
+// encoding/json struct tags and the json.Marshaler and json.Unmarshaler
+// interfaces. SetJSONProvider is not safe for concurrent use with the rest of
+// the package; call it during initialization. Parser.UseJSONNumber always
+// decodes with encoding/json.
+func SetJSONProvider(marshal JSONMarshalFunc, unmarshal JSONUnmarshalFunc) {
+	customJSON = marshal != nil || unmarshal != nil
+	if marshal == nil {
+		marshal = json.Marshal
+	}
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+	jsonMarshal, jsonUnmarshal = marshal, unmarshal
+}
+
+// unmarshalJSON decodes with the Parser's JSONUnmarshal, or else the package
+// provider.
+func (p *Parser) unmarshalJSON(data []byte, v interface{}) error {
+	if p.JSONUnmarshal != nil {
+		return p.JSONUnmarshal(data, v)
+	}
+	return jsonUnmarshal(data, v)
+}
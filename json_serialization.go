@@ -0,0 +1,259 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// JSONSignature is one signature of a JWS in the JSON serialization, as
+// described in https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.1
+type JSONSignature struct {
+	Protected string                 `json:"protected,omitempty"` // The base64url encoded protected header
+	Header    map[string]interface{} `json:"header,omitempty"`    // The unprotected header
+	Signature string                 `json:"signature"`           // The base64url encoded signature
+}
+
+// jsonSerialization covers both the general and the flattened syntax.
+type jsonSerialization struct {
+	Payload    string                 `json:"payload"`
+	Signatures []JSONSignature        `json:"signatures,omitempty"`
+	Protected  string                 `json:"protected,omitempty"`
+	Header     map[string]interface{} `json:"header,omitempty"`
+	Signature  string                 `json:"signature,omitempty"`
+}
+
+// SignedJSONString retrieves the token signed in the JWS JSON serialization.
+// With a single key the flattened syntax is produced; with several keys the
+// general syntax is produced, carrying one signature per key, each made with
+// the token's Method and Header.
+// See https://datatracker.ietf.org/doc/html/rfc7515#section-7.2
+func (t *Token) SignedJSONString(keys ...interface{}) (string, error) {
+	if len(keys) == 0 {
+		return "", errors.New("jwt: at least one key is required")
+	}
+	headerJSON, err := jsonMarshal(t.Header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := t.marshalClaims()
+	if err != nil {
+		return "", err
+	}
+	protected := EncodeSegment(headerJSON)
+	out := jsonSerialization{Payload: EncodeSegment(claimsJSON)}
+
+	for _, key := range keys {
+		sig, err := t.Method.Sign(protected+"."+out.Payload, key)
+		if err != nil {
+			return "", err
+		}
+		out.Signatures = append(out.Signatures, JSONSignature{Protected: protected, Signature: sig})
+	}
+	if len(out.Signatures) == 1 {
+		out.Protected, out.Signature = protected, out.Signatures[0].Signature
+		out.Signatures = nil
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// JSONSigner is one of the signatures produced by Token.SignedJSONStringWith.
+type JSONSigner struct {
+	Method SigningMethod          // The signing method. The "alg" header is set to match
+	Key    interface{}            // The signing key
+	Header map[string]interface{} // Optional. Protected header members, such as "kid", merged over the token's Header
+}
+
+// SignedJSONStringWith retrieves the token signed in the general JWS JSON
+// serialization with one signature per signer. Unlike SignedJSONString each
+// signature may use a different algorithm and header, which allows a token to
+// be verified by services trusting either an old or a new key during a key
+// rotation. The token's own Method is not used.
+func (t *Token) SignedJSONStringWith(signers ...JSONSigner) (string, error) {
+	if len(signers) == 0 {
+		return "", errors.New("jwt: at least one signer is required")
+	}
+	claimsJSON, err := t.marshalClaims()
+	if err != nil {
+		return "", err
+	}
+	out := jsonSerialization{Payload: EncodeSegment(claimsJSON)}
+
+	for _, s := range signers {
+		header := make(map[string]interface{}, len(t.Header)+len(s.Header)+1)
+		for k, v := range t.Header {
+			header[k] = v
+		}
+		for k, v := range s.Header {
+			header[k] = v
+		}
+		header["alg"] = s.Method.Alg()
+		headerJSON, err := jsonMarshal(header)
+		if err != nil {
+			return "", err
+		}
+		protected := EncodeSegment(headerJSON)
+		sig, err := s.Method.Sign(protected+"."+out.Payload, s.Key)
+		if err != nil {
+			return "", err
+		}
+		out.Signatures = append(out.Signatures, JSONSignature{Protected: protected, Signature: sig})
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ParseJSON parses, validates, and returns a token in either syntax of the
+// JWS JSON serialization. keyFunc is called once per signature; the token it
+// receives carries that signature's protected and unprotected header members.
+// Every signature must validate. The returned token carries the header and
+// signature of the first.
+func (p *Parser) ParseJSON(data string, claims Claims, keyFunc Keyfunc) (*Token, error) {
+	return p.observe(context.Background(), data, func(stage *string) (*Token, error) {
+		return p.parseJSON(data, claims, keyFunc, false, stage)
+	})
+}
+
+// ParseJSONAny is like ParseJSON, but succeeds if any one signature validates.
+// Signatures using a method that is not registered or not in ValidMethods, and
+// those for which keyFunc returns an error, are skipped. This allows a
+// verifier to accept tokens signed by several keys while it only trusts some
+// of them. The returned token carries the header and signature which
+// validated; if none did, the error of the first signature is returned.
+func (p *Parser) ParseJSONAny(data string, claims Claims, keyFunc Keyfunc) (*Token, error) {
+	return p.observe(context.Background(), data, func(stage *string) (*Token, error) {
+		return p.parseJSON(data, claims, keyFunc, true, stage)
+	})
+}
+
+func (p *Parser) parseJSON(data string, claims Claims, keyFunc Keyfunc, anyOf bool, stage *string) (*Token, error) {
+	*stage = stageSplit
+	var in jsonSerialization
+	if err := json.Unmarshal([]byte(data), &in); err != nil {
+		return nil, MalformedTokenError(err.Error())
+	}
+	signatures := in.Signatures
+	if in.Signature != "" || in.Protected != "" {
+		if len(signatures) != 0 {
+			return nil, MalformedTokenError("token mixes the general and flattened syntax")
+		}
+		signatures = []JSONSignature{{Protected: in.Protected, Header: in.Header, Signature: in.Signature}}
+	}
+	if len(signatures) == 0 {
+		return nil, MalformedTokenError("token contains no signatures")
+	}
+
+	*stage = stageClaimsDecode
+	claimBytes, err := DecodeSegment(in.Payload)
+	if err != nil {
+		return nil, MalformedTokenError(err.Error())
+	}
+	if err = p.decodeClaims(claimBytes, claims); err != nil {
+		return nil, err
+	}
+
+	*stage = stageSignature
+	var first *Token
+	if anyOf {
+		var firstErr error
+		for _, s := range signatures {
+			token, err := p.parseJSONSignature(data, s, claims)
+			if err == nil {
+				err = p.verifyJSONSignature(token, s.Protected+"."+in.Payload, keyFunc)
+			}
+			if err == nil {
+				first = token
+				break
+			}
+			if firstErr == nil {
+				first, firstErr = token, err
+			}
+		}
+		if !first.Valid {
+			return first, firstErr
+		}
+	} else {
+		for _, s := range signatures {
+			token, err := p.parseJSONSignature(data, s, claims)
+			if err != nil {
+				return token, err
+			}
+			if first == nil {
+				first = token
+			}
+			if err = p.verifyJSONSignature(token, s.Protected+"."+in.Payload, keyFunc); err != nil {
+				return token, err
+			}
+		}
+	}
+
+	// Validate Claims
+	ctx := context.Background()
+	*stage = stageClaimsValidation
+	if err := p.validateClaims(ctx, first); err != nil {
+		first.Valid = false
+		return first, err
+	}
+	*stage = stageRevocation
+	if err := p.checkVerified(ctx, first); err != nil {
+		first.Valid = false
+		return first, err
+	}
+	return first, nil
+}
+
+// parseJSONSignature builds the Token for a single signature, merging the
+// protected and unprotected header members.
+func (p *Parser) parseJSONSignature(raw string, s JSONSignature, claims Claims) (*Token, error) {
+	token := &Token{Raw: raw, Claims: claims, Signature: s.Signature, Header: map[string]interface{}{}}
+	if s.Protected != "" {
+		headerBytes, err := DecodeSegment(s.Protected)
+		if err != nil {
+			return token, MalformedTokenError(err.Error())
+		}
+		if err = p.unmarshalJSON(headerBytes, &token.Header); err != nil {
+			return token, MalformedTokenError(err.Error())
+		}
+	}
+	for k, v := range s.Header {
+		if _, ok := token.Header[k]; ok {
+			return token, MalformedTokenError(`header parameter "` + k + `" is both protected and unprotected`)
+		}
+		token.Header[k] = v
+	}
+	if err := p.lookupMethod(token); err != nil {
+		return token, err
+	}
+	return token, nil
+}
+
+func (p *Parser) verifyJSONSignature(token *Token, signingString string, keyFunc Keyfunc) error {
+	if err := p.verifyMethod(token); err != nil {
+		return err
+	}
+	if err := p.verifyType(token); err != nil {
+		return err
+	}
+	if keyFunc == nil {
+		return ErrMissingKeyFunc
+	}
+	key, err := keyFunc(token)
+	if err != nil {
+		return &KeyFuncError{Err: err}
+	}
+	if err = p.verifySignature(token, signingString, key); err != nil {
+		token.Valid = false
+		return err
+	}
+	token.Valid = true
+	return nil
+}
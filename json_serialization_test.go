@@ -0,0 +1,155 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/test"
+)
+
+func TestToken_SignedJSONString(t *testing.T) {
+	keyA := []byte("key a")
+	keyB := []byte("key b")
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"})
+
+	var tests = []struct {
+		name       string
+		signWith   [][]byte
+		verifyWith map[string][]byte
+		flattened  bool
+		err        error
+	}{
+		{"flattened", [][]byte{keyA}, nil, true, nil},
+		{"general", [][]byte{keyA, keyB}, nil, false, nil},
+		{"general with invalid signature", [][]byte{keyA, []byte("other")}, nil, false, jwt.ErrSignatureInvalid},
+	}
+
+	for _, data := range tests {
+		keys := make([]interface{}, len(data.signWith))
+		for i, k := range data.signWith {
+			keys[i] = k
+		}
+		signed, err := token.SignedJSONString(keys...)
+		if err != nil {
+			t.Errorf("[%v] Error signing token: %v", data.name, err)
+			continue
+		}
+
+		var raw map[string]interface{}
+		if err = json.Unmarshal([]byte(signed), &raw); err != nil {
+			t.Errorf("[%v] Signed token is not JSON: %v", data.name, err)
+			continue
+		}
+		if _, ok := raw["signature"]; ok != data.flattened {
+			t.Errorf("[%v] Unexpected syntax: %v", data.name, signed)
+		}
+
+		i := 0
+		parsed, err := new(jwt.Parser).ParseJSON(signed, jwt.MapClaims{}, func(*jwt.Token) (interface{}, error) {
+			// Signatures are verified in order, so the keys line up with keyA then keyB.
+			k := [][]byte{keyA, keyB}[i]
+			i++
+			return k, nil
+		})
+		if data.err != nil {
+			if !errors.Is(err, data.err) {
+				t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%v] Error parsing token: %v", data.name, err)
+			continue
+		}
+		if !parsed.Valid || parsed.Claims.(jwt.MapClaims)["foo"] != "bar" {
+			t.Errorf("[%v] Unexpected token: %v", data.name, parsed.Claims)
+		}
+	}
+}
+
+func TestParser_ParseJSON_unprotectedHeader(t *testing.T) {
+	key := []byte("key a")
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"}).SignedJSONString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]interface{}
+	json.Unmarshal([]byte(signed), &raw)
+
+	raw["header"] = map[string]interface{}{"kid": "a"}
+	withKid, _ := json.Marshal(raw)
+	parsed, err := new(jwt.Parser).ParseJSON(string(withKid), jwt.MapClaims{}, func(t *jwt.Token) (interface{}, error) {
+		if t.Header["kid"] != "a" {
+			return nil, errors.New("unexpected kid")
+		}
+		return key, nil
+	})
+	if err != nil || !parsed.Valid {
+		t.Errorf("Error parsing token with unprotected header: %v", err)
+	}
+
+	raw["header"] = map[string]interface{}{"alg": "none"}
+	conflicting, _ := json.Marshal(raw)
+	if _, err = new(jwt.Parser).ParseJSON(string(conflicting), jwt.MapClaims{}, nil); !errors.Is(err, jwt.ErrMalformedToken) {
+		t.Errorf("Expected ErrMalformedToken for duplicate header parameter. Got: %v", err)
+	}
+}
+
+func TestParser_ParseJSONAny(t *testing.T) {
+	oldKey := []byte("old key")
+	newKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"})
+
+	signed, err := token.SignedJSONStringWith(
+		jwt.JSONSigner{Method: jwt.SigningMethodHS256, Key: oldKey, Header: map[string]interface{}{"kid": "old"}},
+		jwt.JSONSigner{Method: jwt.SigningMethodRS256, Key: newKey, Header: map[string]interface{}{"kid": "new"}},
+	)
+	if err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+
+	var tests = []struct {
+		name    string
+		methods []string
+		keys    map[string]interface{}
+		kid     string
+		err     error
+	}{
+		{"trusts old key", nil, map[string]interface{}{"old": oldKey}, "old", nil},
+		{"trusts new key", nil, map[string]interface{}{"new": &newKey.PublicKey}, "new", nil},
+		{"old method not allowed", []string{"RS256"}, map[string]interface{}{"old": oldKey, "new": &newKey.PublicKey}, "new", nil},
+		{"no trusted key", nil, map[string]interface{}{}, "old", jwt.ErrKeyFuncError},
+		{"wrong key", nil, map[string]interface{}{"old": []byte("other")}, "old", jwt.ErrSignatureInvalid},
+	}
+
+	for _, data := range tests {
+		p := &jwt.Parser{ValidMethods: data.methods}
+		parsed, err := p.ParseJSONAny(signed, jwt.MapClaims{}, func(t *jwt.Token) (interface{}, error) {
+			if key, ok := data.keys[t.Header["kid"].(string)]; ok {
+				return key, nil
+			}
+			return nil, errors.New("unknown kid")
+		})
+		if data.err != nil {
+			if !errors.Is(err, data.err) {
+				t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%v] Error parsing token: %v", data.name, err)
+			continue
+		}
+		if !parsed.Valid || parsed.Header["kid"] != data.kid {
+			t.Errorf("[%v] Expected valid token verified by %v. Got: %v", data.name, data.kid, parsed.Header)
+		}
+	}
+
+	if _, err = new(jwt.Parser).ParseJSON(signed, jwt.MapClaims{}, func(*jwt.Token) (interface{}, error) {
+		return oldKey, nil
+	}); err == nil {
+		t.Errorf("Expected ParseJSON to require every signature to validate")
+	}
+}
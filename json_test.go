@@ -0,0 +1,44 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestSetJSONProvider(t *testing.T) {
+	var marshals, unmarshals int
+	jwt.SetJSONProvider(
+		func(v interface{}) ([]byte, error) { marshals++; return json.Marshal(v) },
+		func(data []byte, v interface{}) error { unmarshals++; return json.Unmarshal(data, v) },
+	)
+	defer jwt.SetJSONProvider(nil, nil)
+
+	key := []byte("secret")
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if marshals != 2 {
+		t.Errorf("Expected the header and claims to be encoded by the provider. Got %v calls", marshals)
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err = jwt.ParseWithClaims(signed, claims, func(*jwt.Token) (interface{}, error) { return key, nil }); err != nil {
+		t.Fatalf("Error parsing token: %v", err)
+	}
+	if unmarshals != 2 || claims["sub"] != "user" {
+		t.Errorf("Expected the header and claims to be decoded by the provider. Got %v calls, claims %v", unmarshals, claims)
+	}
+
+	var parserCalls int
+	p := &jwt.Parser{JSONUnmarshal: func(data []byte, v interface{}) error { parserCalls++; return json.Unmarshal(data, v) }}
+	registered := new(jwt.RegisteredClaims)
+	if _, err = p.ParseWithClaims(signed, registered, func(*jwt.Token) (interface{}, error) { return key, nil }); err != nil {
+		t.Fatalf("Error parsing token: %v", err)
+	}
+	if parserCalls != 2 || registered.Subject != "user" {
+		t.Errorf("Expected the Parser's JSONUnmarshal to be used. Got %v calls, claims %v", parserCalls, registered)
+	}
+}
@@ -0,0 +1,178 @@
+package jwe
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"hash"
+	"io"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// Content encryption algorithms, as registered in
+// https://datatracker.ietf.org/doc/html/rfc7518#section-5.1
+const (
+	EncA128CBCHS256 = "A128CBC-HS256"
+	EncA256CBCHS512 = "A256CBC-HS512"
+	EncA128GCM      = "A128GCM"
+	EncA256GCM      = "A256GCM"
+)
+
+// contentCipher implements one of the "enc" algorithms.
+type contentCipher interface {
+	keySize() int
+	encrypt(cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error)
+	decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error)
+}
+
+func getContentCipher(enc string) contentCipher {
+	switch enc {
+	case EncA128CBCHS256:
+		return &cbcHMAC{size: 32, hash: sha256.New, tagSize: 16}
+	case EncA256CBCHS512:
+		return &cbcHMAC{size: 64, hash: sha512.New, tagSize: 32}
+	case EncA128GCM:
+		return &aesGCM{size: 16}
+	case EncA256GCM:
+		return &aesGCM{size: 32}
+	}
+	return nil
+}
+
+// cbcHMAC implements AES_CBC_HMAC_SHA2 as described in
+// https://datatracker.ietf.org/doc/html/rfc7518#section-5.2
+type cbcHMAC struct {
+	size    int
+	hash    func() hash.Hash
+	tagSize int
+}
+
+func (c *cbcHMAC) keySize() int {
+	return c.size
+}
+
+func (c *cbcHMAC) encrypt(cek, plaintext, aad []byte) ([]byte, []byte, []byte, error) {
+	if len(cek) != c.size {
+		return nil, nil, nil, ErrInvalidKeySize
+	}
+	macKey, encKey := cek[:c.size/2], cek[c.size/2:]
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err = io.ReadFull(jwt.RandReader, iv); err != nil {
+		return nil, nil, nil, err
+	}
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext := make([]byte, len(plaintext), len(plaintext)+padding)
+	copy(ciphertext, plaintext)
+	ciphertext = append(ciphertext, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	return iv, ciphertext, c.tag(macKey, aad, iv, ciphertext), nil
+}
+
+func (c *cbcHMAC) decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	if len(cek) != c.size {
+		return nil, ErrInvalidKeySize
+	}
+	macKey, encKey := cek[:c.size/2], cek[c.size/2:]
+	if subtle.ConstantTimeCompare(tag, c.tag(macKey, aad, iv, ciphertext)) != 1 {
+		return nil, ErrDecryption
+	}
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrDecryption
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(plaintext) {
+		return nil, ErrDecryption
+	}
+	for _, b := range plaintext[len(plaintext)-padding:] {
+		if int(b) != padding {
+			return nil, ErrDecryption
+		}
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+func (c *cbcHMAC) tag(macKey, aad, iv, ciphertext []byte) []byte {
+	al := make([]byte, 8)
+	binary.BigEndian.PutUint64(al, uint64(len(aad))*8)
+
+	mac := hmac.New(c.hash, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	mac.Write(al)
+	return mac.Sum(nil)[:c.tagSize]
+}
+
+// aesGCM implements AES GCM as described in
+// https://datatracker.ietf.org/doc/html/rfc7518#section-5.3
+type aesGCM struct {
+	size int
+}
+
+func (c *aesGCM) keySize() int {
+	return c.size
+}
+
+func (c *aesGCM) aead(cek []byte) (cipher.AEAD, error) {
+	if len(cek) != c.size {
+		return nil, ErrInvalidKeySize
+	}
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (c *aesGCM) encrypt(cek, plaintext, aad []byte) ([]byte, []byte, []byte, error) {
+	aead, err := c.aead(cek)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	iv := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(jwt.RandReader, iv); err != nil {
+		return nil, nil, nil, err
+	}
+	sealed := aead.Seal(nil, iv, plaintext, aad)
+	split := len(sealed) - aead.Overhead()
+	return iv, sealed[:split], sealed[split:], nil
+}
+
+func (c *aesGCM) decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	aead, err := c.aead(cek)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aead.NonceSize() || len(tag) != aead.Overhead() {
+		return nil, ErrDecryption
+	}
+	sealed := make([]byte, 0, len(ciphertext)+len(tag))
+	sealed = append(sealed, ciphertext...)
+	sealed = append(sealed, tag...)
+	plaintext, err := aead.Open(nil, iv, sealed, aad)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return plaintext, nil
+}
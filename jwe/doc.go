@@ -0,0 +1,11 @@
+// Package jwe implements the compact serialization of JSON Web Encryption
+// as described in https://datatracker.ietf.org/doc/html/rfc7516.
+//
+// The key management algorithms RSA-OAEP, RSA-OAEP-256, ECDH-ES (directly or
+// with AES key wrapping), A128KW, A256KW and dir are supported, as are the
+// content encryption algorithms A128CBC-HS256, A256CBC-HS512, A128GCM and
+// A256GCM.
+//
+// Signed-then-encrypted (nested) tokens are produced with EncryptNested and
+// consumed with ParseNested, which hands the decrypted JWT to a jwt.Parser.
+package jwe
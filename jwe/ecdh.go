@@ -0,0 +1,132 @@
+package jwe
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+var errInvalidEphemeralKey = errors.New(`jwe: "epk" header is not a valid ephemeral public key`)
+
+// deriveECDHES performs the key agreement described in
+// https://datatracker.ietf.org/doc/html/rfc7518#section-4.6.2
+func deriveECDHES(algID string, keySize int, priv *ecdsa.PrivateKey, pub *ecdsa.PublicKey, apu, apv []byte) ([]byte, error) {
+	if priv.Curve.Params().Name != pub.Curve.Params().Name {
+		return nil, errInvalidEphemeralKey
+	}
+	z, err := sharedSecret(priv, pub)
+	if err != nil {
+		return nil, err
+	}
+	return concatKDF(z, algID, apu, apv, keySize), nil
+}
+
+// concatKDF implements the Concat KDF from NIST SP 800-56A with SHA-256, using
+// the OtherInfo layout required by RFC 7518.
+func concatKDF(z []byte, algID string, apu, apv []byte, keySize int) []byte {
+	var otherInfo []byte
+	otherInfo = appendLengthPrefixed(otherInfo, []byte(algID))
+	otherInfo = appendLengthPrefixed(otherInfo, apu)
+	otherInfo = appendLengthPrefixed(otherInfo, apv)
+	otherInfo = appendUint32(otherInfo, uint32(keySize*8))
+
+	out := make([]byte, 0, keySize+sha256.Size)
+	for counter := uint32(1); len(out) < keySize; counter++ {
+		h := sha256.New()
+		h.Write(appendUint32(nil, counter))
+		h.Write(z)
+		h.Write(otherInfo)
+		out = h.Sum(out)
+	}
+	return out[:keySize]
+}
+
+func appendLengthPrefixed(b, data []byte) []byte {
+	return append(appendUint32(b, uint32(len(data))), data...)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func curveSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
+
+func curveByName(name string) elliptic.Curve {
+	switch name {
+	case "P-256":
+		return elliptic.P256()
+	case "P-384":
+		return elliptic.P384()
+	case "P-521":
+		return elliptic.P521()
+	}
+	return nil
+}
+
+// ephemeralKeyHeader renders the public part of key as the "epk" header value.
+func ephemeralKeyHeader(key *ecdsa.PublicKey) map[string]interface{} {
+	size := curveSize(key.Curve)
+	x := make([]byte, size)
+	y := make([]byte, size)
+	key.X.FillBytes(x)
+	key.Y.FillBytes(y)
+	return map[string]interface{}{
+		"kty": "EC",
+		"crv": key.Curve.Params().Name,
+		"x":   base64.RawURLEncoding.EncodeToString(x),
+		"y":   base64.RawURLEncoding.EncodeToString(y),
+	}
+}
+
+// parseEphemeralKey parses the "epk" header value.
+func parseEphemeralKey(v interface{}) (*ecdsa.PublicKey, error) {
+	epk, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errInvalidEphemeralKey
+	}
+	if kty, _ := epk["kty"].(string); kty != "EC" {
+		return nil, errInvalidEphemeralKey
+	}
+	crv, _ := epk["crv"].(string)
+	curve := curveByName(crv)
+	if curve == nil {
+		return nil, errInvalidEphemeralKey
+	}
+	xs, _ := epk["x"].(string)
+	ys, _ := epk["y"].(string)
+	x, err := base64.RawURLEncoding.DecodeString(xs)
+	if err != nil || len(x) != curveSize(curve) {
+		return nil, errInvalidEphemeralKey
+	}
+	y, err := base64.RawURLEncoding.DecodeString(ys)
+	if err != nil || len(y) != curveSize(curve) {
+		return nil, errInvalidEphemeralKey
+	}
+	pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if !curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, errInvalidEphemeralKey
+	}
+	return pub, nil
+}
+
+// decodeHeaderBytes decodes an optional base64url encoded header parameter,
+// such as "apu" and "apv".
+func decodeHeaderBytes(header map[string]interface{}, name string) ([]byte, error) {
+	v, ok := header[name]
+	if !ok {
+		return nil, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return nil, errors.New(`jwe: "` + name + `" header must be a string`)
+	}
+	return base64.RawURLEncoding.DecodeString(s)
+}
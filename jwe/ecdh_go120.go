@@ -0,0 +1,21 @@
+//go:build go1.20
+// +build go1.20
+
+package jwe
+
+import "crypto/ecdsa"
+
+// sharedSecret computes the ECDH shared secret of priv and pub with
+// crypto/ecdh, which runs in constant time and rejects points not on the
+// curve, preventing invalid curve attacks.
+func sharedSecret(priv *ecdsa.PrivateKey, pub *ecdsa.PublicKey) ([]byte, error) {
+	k, err := priv.ECDH()
+	if err != nil {
+		return nil, err
+	}
+	p, err := pub.ECDH()
+	if err != nil {
+		return nil, errInvalidEphemeralKey
+	}
+	return k.ECDH(p)
+}
@@ -0,0 +1,20 @@
+//go:build !go1.20
+// +build !go1.20
+
+package jwe
+
+import "crypto/ecdsa"
+
+// sharedSecret computes the ECDH shared secret of priv and pub. Before Go
+// 1.20 there is no crypto/ecdh, so it falls back to Curve.ScalarMult, which is
+// only constant time for P-256.
+func sharedSecret(priv *ecdsa.PrivateKey, pub *ecdsa.PublicKey) ([]byte, error) {
+	// Reject points not on the curve to prevent invalid curve attacks.
+	if !priv.Curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, errInvalidEphemeralKey
+	}
+	x, _ := priv.Curve.ScalarMult(pub.X, pub.Y, priv.D.Bytes())
+	z := make([]byte, curveSize(priv.Curve))
+	x.FillBytes(z)
+	return z, nil
+}
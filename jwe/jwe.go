@@ -0,0 +1,203 @@
+package jwe
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// Error constants
+var (
+	ErrMalformed              = errors.New("jwe: token is malformed")
+	ErrDecryption             = errors.New("jwe: decryption failed")
+	ErrInvalidKeySize         = errors.New("jwe: key is of invalid size")
+	ErrUnsupportedAlgorithm   = errors.New("jwe: algorithm is not supported")
+	ErrInvalidAlgorithm       = errors.New("jwe: algorithm is not allowed")
+	ErrCompressionUnsupported = errors.New(`jwe: "zip" header is not supported`)
+	ErrNotNested              = errors.New(`jwe: content type is not "JWT"`)
+)
+
+// UnsupportedAlgorithmError is returned when the "alg" or "enc" value of a
+// token is not implemented by this package.
+type UnsupportedAlgorithmError struct {
+	Alg string
+}
+
+func (err *UnsupportedAlgorithmError) Error() string {
+	return `jwe: algorithm "` + err.Alg + `" is not supported`
+}
+
+func (err *UnsupportedAlgorithmError) Unwrap() error {
+	return ErrUnsupportedAlgorithm
+}
+
+// InvalidAlgorithmError is returned when the "alg" or "enc" value of a token
+// is not in the set allowed by a Decrypter.
+type InvalidAlgorithmError struct {
+	Alg string
+}
+
+func (err *InvalidAlgorithmError) Error() string {
+	return `jwe: algorithm "` + err.Alg + `" is not allowed`
+}
+
+func (err *InvalidAlgorithmError) Unwrap() error {
+	return ErrInvalidAlgorithm
+}
+
+type malformedError string
+
+func (err malformedError) Error() string {
+	return ErrMalformed.Error() + "\n\t" + string(err)
+}
+
+func (err malformedError) Unwrap() error {
+	return ErrMalformed
+}
+
+// Keyfunc supplies the key used to decrypt a token. It receives the token's
+// protected header, allowing "kid" or "alg" to be used to select the key.
+type Keyfunc func(header map[string]interface{}) (interface{}, error)
+
+// Message is a decrypted JWE.
+type Message struct {
+	Raw       string                 // The raw compact token
+	Header    map[string]interface{} // The protected header
+	Plaintext []byte                 // The decrypted content
+}
+
+// Encrypt encrypts plaintext and returns the compact serialization. alg is the
+// key management algorithm, enc the content encryption algorithm. The type of
+// key depends on alg:
+//
+//	RSA-OAEP, RSA-OAEP-256:                     *rsa.PublicKey
+//	ECDH-ES, ECDH-ES+A128KW, ECDH-ES+A256KW:    *ecdsa.PublicKey
+//	A128KW, A256KW, dir:                        []byte
+//
+// Additional protected header parameters (such as "kid" or "cty") may be passed
+// in header, which may be nil.
+func Encrypt(plaintext []byte, alg, enc string, key interface{}, header map[string]interface{}) (string, error) {
+	cc := getContentCipher(enc)
+	if cc == nil {
+		return "", &UnsupportedAlgorithmError{Alg: enc}
+	}
+
+	h := make(map[string]interface{}, len(header)+3)
+	for k, v := range header {
+		h[k] = v
+	}
+	h["alg"] = alg
+	h["enc"] = enc
+
+	cek, encryptedKey, err := encryptKey(alg, enc, cc.keySize(), key, h)
+	if err != nil {
+		return "", err
+	}
+
+	headerJSON, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	protected := base64.RawURLEncoding.EncodeToString(headerJSON)
+
+	iv, ciphertext, tag, err := cc.encrypt(cek, plaintext, []byte(protected))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		protected,
+		base64.RawURLEncoding.EncodeToString(encryptedKey),
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(ciphertext),
+		base64.RawURLEncoding.EncodeToString(tag),
+	}, "."), nil
+}
+
+// Decrypter decrypts compact JWE tokens.
+type Decrypter struct {
+	ValidAlgorithms  []string // If populated, only these key management algorithms will be accepted
+	ValidEncryptions []string // If populated, only these content encryption algorithms will be accepted
+}
+
+// Decrypt decrypts a compact serialized token using the default Decrypter.
+func Decrypt(compact string, keyFunc Keyfunc) (*Message, error) {
+	return new(Decrypter).Decrypt(compact, keyFunc)
+}
+
+// Decrypt decrypts a compact serialized token. keyFunc receives the protected
+// header and should return the key for decryption.
+func (d *Decrypter) Decrypt(compact string, keyFunc Keyfunc) (*Message, error) {
+	parts := strings.Split(compact, ".")
+	if len(parts) != 5 {
+		return nil, malformedError("token contains an invalid number of segments")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, malformedError(err.Error())
+	}
+	msg := &Message{Raw: compact}
+	if err = json.Unmarshal(headerJSON, &msg.Header); err != nil {
+		return nil, malformedError(err.Error())
+	}
+
+	var segments [4][]byte
+	for i := range segments {
+		if segments[i], err = base64.RawURLEncoding.DecodeString(parts[i+1]); err != nil {
+			return nil, malformedError(err.Error())
+		}
+	}
+	encryptedKey, iv, ciphertext, tag := segments[0], segments[1], segments[2], segments[3]
+
+	alg, _ := msg.Header["alg"].(string)
+	enc, _ := msg.Header["enc"].(string)
+	if alg == "" || enc == "" {
+		return nil, malformedError(`"alg" and "enc" headers are required`)
+	}
+	if _, ok := msg.Header["zip"]; ok {
+		return nil, ErrCompressionUnsupported
+	}
+	if !allowed(d.ValidAlgorithms, alg) {
+		return nil, &InvalidAlgorithmError{Alg: alg}
+	}
+	if !allowed(d.ValidEncryptions, enc) {
+		return nil, &InvalidAlgorithmError{Alg: enc}
+	}
+	cc := getContentCipher(enc)
+	if cc == nil {
+		return nil, &UnsupportedAlgorithmError{Alg: enc}
+	}
+
+	if keyFunc == nil {
+		return nil, jwt.ErrMissingKeyFunc
+	}
+	key, err := keyFunc(msg.Header)
+	if err != nil {
+		return nil, &jwt.KeyFuncError{Err: err}
+	}
+
+	cek, err := decryptKey(alg, enc, cc.keySize(), key, msg.Header, encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Plaintext, err = cc.decrypt(cek, iv, ciphertext, tag, []byte(parts[0])); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func allowed(valid []string, alg string) bool {
+	if valid == nil {
+		return true
+	}
+	for _, v := range valid {
+		if v == alg {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package jwe_test
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwe"
+	"github.com/chanced/go-jwt/v4/test"
+)
+
+func loadECKey(t *testing.T) *ecdsa.PrivateKey {
+	data, err := ioutil.ReadFile("../test/ec256-private.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	rsaKey := test.LoadRSAPrivateKeyFromDisk("../test/sample_key")
+	ecKey := loadECKey(t)
+	kek128, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	kek256, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+
+	var tests = []struct {
+		name       string
+		alg        string
+		enc        string
+		encryptKey interface{}
+		decryptKey interface{}
+	}{
+		{"RSA-OAEP", jwe.AlgRSAOAEP, jwe.EncA128CBCHS256, &rsaKey.PublicKey, rsaKey},
+		{"RSA-OAEP-256", jwe.AlgRSAOAEP256, jwe.EncA256GCM, &rsaKey.PublicKey, rsaKey},
+		{"ECDH-ES", jwe.AlgECDHES, jwe.EncA256GCM, &ecKey.PublicKey, ecKey},
+		{"ECDH-ES+A128KW", jwe.AlgECDHESA128KW, jwe.EncA128CBCHS256, &ecKey.PublicKey, ecKey},
+		{"ECDH-ES+A256KW", jwe.AlgECDHESA256KW, jwe.EncA256CBCHS512, &ecKey.PublicKey, ecKey},
+		{"A128KW", jwe.AlgA128KW, jwe.EncA128GCM, kek128, kek128},
+		{"A256KW", jwe.AlgA256KW, jwe.EncA256GCM, kek256, kek256},
+		{"dir", jwe.AlgDirect, jwe.EncA256GCM, kek256, kek256},
+	}
+
+	plaintext := []byte("Live long and prosper.")
+	for _, data := range tests {
+		compact, err := jwe.Encrypt(plaintext, data.alg, data.enc, data.encryptKey, map[string]interface{}{"kid": "1"})
+		if err != nil {
+			t.Errorf("[%v] Error encrypting: %v", data.name, err)
+			continue
+		}
+		msg, err := jwe.Decrypt(compact, func(map[string]interface{}) (interface{}, error) {
+			return data.decryptKey, nil
+		})
+		if err != nil {
+			t.Errorf("[%v] Error decrypting: %v", data.name, err)
+			continue
+		}
+		if string(msg.Plaintext) != string(plaintext) {
+			t.Errorf("[%v] Plaintext mismatch. Got: %s", data.name, msg.Plaintext)
+		}
+		if msg.Header["kid"] != "1" || msg.Header["alg"] != data.alg || msg.Header["enc"] != data.enc {
+			t.Errorf("[%v] Header mismatch. Got: %v", data.name, msg.Header)
+		}
+	}
+}
+
+// Test vector from https://datatracker.ietf.org/doc/html/rfc7516#appendix-A.3
+func TestDecrypt_RFC7516A3(t *testing.T) {
+	compact := "eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0." +
+		"6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ." +
+		"AxY8DCtDaGlsbGljb3RoZQ." +
+		"KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY." +
+		"U0m_YmjN04DJvceFICbCVQ"
+	key, _ := jwt.DecodeSegment("GawgguFyGrWKav7AX4VKUg")
+
+	msg, err := jwe.Decrypt(compact, func(map[string]interface{}) (interface{}, error) { return key, nil })
+	if err != nil {
+		t.Fatalf("Error decrypting: %v", err)
+	}
+	if string(msg.Plaintext) != "Live long and prosper." {
+		t.Errorf("Plaintext mismatch. Got: %s", msg.Plaintext)
+	}
+
+	tampered := compact[:len(compact)-1] + "A"
+	if _, err = jwe.Decrypt(tampered, func(map[string]interface{}) (interface{}, error) { return key, nil }); !errors.Is(err, jwe.ErrDecryption) {
+		t.Errorf("Expected ErrDecryption for tampered tag. Got: %v", err)
+	}
+}
+
+func TestDecrypter_ValidAlgorithms(t *testing.T) {
+	key, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	compact, err := jwe.Encrypt([]byte("x"), jwe.AlgA128KW, jwe.EncA128GCM, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &jwe.Decrypter{ValidAlgorithms: []string{jwe.AlgRSAOAEP256}}
+	_, err = d.Decrypt(compact, func(map[string]interface{}) (interface{}, error) { return key, nil })
+	if !errors.Is(err, jwe.ErrInvalidAlgorithm) {
+		t.Errorf("Expected ErrInvalidAlgorithm. Got: %v", err)
+	}
+}
+
+func TestParseNested(t *testing.T) {
+	signingKey := test.LoadRSAPrivateKeyFromDisk("../test/sample_key")
+	encryptionKey := loadECKey(t)
+
+	signed := test.MakeSampleToken(jwt.MapClaims{"foo": "bar"}, signingKey)
+	compact, err := jwe.EncryptNested(signed, jwe.AlgECDHESA256KW, jwe.EncA256GCM, &encryptionKey.PublicKey, nil)
+	if err != nil {
+		t.Fatalf("Error encrypting: %v", err)
+	}
+
+	token, err := jwe.ParseNested(compact,
+		func(map[string]interface{}) (interface{}, error) { return encryptionKey, nil },
+		nil,
+		jwt.MapClaims{},
+		func(*jwt.Token) (interface{}, error) { return &signingKey.PublicKey, nil },
+	)
+	if err != nil {
+		t.Fatalf("Error parsing nested token: %v", err)
+	}
+	if !token.Valid || token.Claims.(jwt.MapClaims)["foo"] != "bar" {
+		t.Errorf("Unexpected inner token: %v", token.Claims)
+	}
+
+	notNested, _ := jwe.Encrypt([]byte(signed), jwe.AlgECDHES, jwe.EncA256GCM, &encryptionKey.PublicKey, nil)
+	_, err = jwe.ParseNested(notNested,
+		func(map[string]interface{}) (interface{}, error) { return encryptionKey, nil },
+		nil, jwt.MapClaims{}, nil)
+	if !errors.Is(err, jwe.ErrNotNested) {
+		t.Errorf("Expected ErrNotNested. Got: %v", err)
+	}
+}
+
+func TestParser_ParseNested_encrypted(t *testing.T) {
+	signingKey := test.LoadRSAPrivateKeyFromDisk("../test/sample_key")
+	encryptionKey := loadECKey(t)
+
+	signed := test.MakeSampleToken(jwt.MapClaims{"foo": "bar"}, signingKey)
+	compact, err := jwe.EncryptNested(signed, jwe.AlgECDHESA128KW, jwe.EncA128CBCHS256, &encryptionKey.PublicKey, nil)
+	if err != nil {
+		t.Fatalf("Error encrypting: %v", err)
+	}
+
+	p := &jwt.Parser{Decrypter: &jwe.TokenDecrypter{
+		Keyfunc: func(map[string]interface{}) (interface{}, error) { return encryptionKey, nil },
+	}}
+	token, outer, err := p.ParseNested(compact, jwt.MapClaims{}, func(*jwt.Token) (interface{}, error) {
+		return &signingKey.PublicKey, nil
+	})
+	if err != nil {
+		t.Fatalf("Error parsing nested token: %v", err)
+	}
+	if len(outer) != 1 || outer[0].Header["alg"] != jwe.AlgECDHESA128KW {
+		t.Errorf("Unexpected enclosing tokens: %v", outer)
+	}
+	if !token.Valid || token.Claims.(jwt.MapClaims)["foo"] != "bar" {
+		t.Errorf("Unexpected inner token: %v", token.Claims)
+	}
+}
+
+func TestParser_ParseNested_unsigned(t *testing.T) {
+	encryptionKey := loadECKey(t)
+	compact, err := jwe.Encrypt([]byte(`{"foo":"bar"}`), jwe.AlgECDHESA128KW, jwe.EncA128CBCHS256, &encryptionKey.PublicKey, nil)
+	if err != nil {
+		t.Fatalf("Error encrypting: %v", err)
+	}
+	decrypter := &jwe.TokenDecrypter{
+		Keyfunc: func(map[string]interface{}) (interface{}, error) { return encryptionKey, nil },
+	}
+	keyFunc := func(*jwt.Token) (interface{}, error) {
+		t.Error("Expected keyFunc not to be called")
+		return nil, nil
+	}
+
+	p := &jwt.Parser{Decrypter: decrypter}
+	if token, _, err := p.ParseNested(compact, jwt.MapClaims{}, keyFunc); !errors.Is(err, jwt.ErrTokenUnsigned) {
+		t.Errorf("Expected %v. Got: %v", jwt.ErrTokenUnsigned, err)
+	} else if token != nil && token.Valid {
+		t.Error("Expected the token to be invalid")
+	}
+
+	p = jwt.NewParser(jwt.WithInsecureUnsignedEncryptedTokens())
+	p.Decrypter = decrypter
+	token, _, err := p.ParseNested(compact, jwt.MapClaims{}, keyFunc)
+	if err != nil {
+		t.Fatalf("Error parsing unsigned token: %v", err)
+	}
+	if token.Valid || !token.Unsigned {
+		t.Errorf("Expected an unsigned, invalid token. Got: valid %v, unsigned %v", token.Valid, token.Unsigned)
+	}
+	if token.Claims.(jwt.MapClaims)["foo"] != "bar" {
+		t.Errorf("Unexpected claims: %v", token.Claims)
+	}
+}
@@ -0,0 +1,195 @@
+package jwe
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"hash"
+	"io"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// Key management algorithms, as registered in
+// https://datatracker.ietf.org/doc/html/rfc7518#section-4.1
+const (
+	AlgRSAOAEP      = "RSA-OAEP"
+	AlgRSAOAEP256   = "RSA-OAEP-256"
+	AlgECDHES       = "ECDH-ES"
+	AlgECDHESA128KW = "ECDH-ES+A128KW"
+	AlgECDHESA256KW = "ECDH-ES+A256KW"
+	AlgA128KW       = "A128KW"
+	AlgA256KW       = "A256KW"
+	AlgDirect       = "dir"
+)
+
+// encryptKey produces the content encryption key (CEK) and its encrypted form
+// for alg. Header parameters required by the algorithm (such as "epk") are
+// added to header.
+func encryptKey(alg, enc string, cekSize int, key interface{}, header map[string]interface{}) (cek, encryptedKey []byte, err error) {
+	switch alg {
+	case AlgRSAOAEP, AlgRSAOAEP256:
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, nil, jwt.ErrInvalidKeyType
+		}
+		if cek, err = randomBytes(cekSize); err != nil {
+			return nil, nil, err
+		}
+		encryptedKey, err = rsa.EncryptOAEP(oaepHash(alg), jwt.RandReader, pub, cek, nil)
+		return cek, encryptedKey, err
+
+	case AlgA128KW, AlgA256KW:
+		kek, ok := key.([]byte)
+		if !ok {
+			return nil, nil, jwt.ErrInvalidKeyType
+		}
+		if len(kek) != keyWrapSize(alg) {
+			return nil, nil, ErrInvalidKeySize
+		}
+		if cek, err = randomBytes(cekSize); err != nil {
+			return nil, nil, err
+		}
+		encryptedKey, err = keyWrap(kek, cek)
+		return cek, encryptedKey, err
+
+	case AlgECDHES, AlgECDHESA128KW, AlgECDHESA256KW:
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, nil, jwt.ErrInvalidKeyType
+		}
+		ephemeral, err := ecdsa.GenerateKey(pub.Curve, jwt.RandReader)
+		if err != nil {
+			return nil, nil, err
+		}
+		apu, err := decodeHeaderBytes(header, "apu")
+		if err != nil {
+			return nil, nil, err
+		}
+		apv, err := decodeHeaderBytes(header, "apv")
+		if err != nil {
+			return nil, nil, err
+		}
+		header["epk"] = ephemeralKeyHeader(&ephemeral.PublicKey)
+
+		if alg == AlgECDHES {
+			cek, err = deriveECDHES(enc, cekSize, ephemeral, pub, apu, apv)
+			return cek, nil, err
+		}
+		kek, err := deriveECDHES(alg, keyWrapSize(alg), ephemeral, pub, apu, apv)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cek, err = randomBytes(cekSize); err != nil {
+			return nil, nil, err
+		}
+		encryptedKey, err = keyWrap(kek, cek)
+		return cek, encryptedKey, err
+
+	case AlgDirect:
+		cek, ok := key.([]byte)
+		if !ok {
+			return nil, nil, jwt.ErrInvalidKeyType
+		}
+		if len(cek) != cekSize {
+			return nil, nil, ErrInvalidKeySize
+		}
+		return cek, nil, nil
+	}
+	return nil, nil, &UnsupportedAlgorithmError{Alg: alg}
+}
+
+// decryptKey recovers the content encryption key for alg.
+func decryptKey(alg, enc string, cekSize int, key interface{}, header map[string]interface{}, encryptedKey []byte) ([]byte, error) {
+	switch alg {
+	case AlgRSAOAEP, AlgRSAOAEP256:
+		priv, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, jwt.ErrInvalidKeyType
+		}
+		cek, err := rsa.DecryptOAEP(oaepHash(alg), jwt.RandReader, priv, encryptedKey, nil)
+		if err != nil || len(cek) != cekSize {
+			// Continue with a random key so that a failure to decrypt the
+			// key is indistinguishable from a failure to decrypt the content.
+			// See https://datatracker.ietf.org/doc/html/rfc7516#section-11.5
+			return randomBytes(cekSize)
+		}
+		return cek, nil
+
+	case AlgA128KW, AlgA256KW:
+		kek, ok := key.([]byte)
+		if !ok {
+			return nil, jwt.ErrInvalidKeyType
+		}
+		if len(kek) != keyWrapSize(alg) {
+			return nil, ErrInvalidKeySize
+		}
+		return keyUnwrap(kek, encryptedKey)
+
+	case AlgECDHES, AlgECDHESA128KW, AlgECDHESA256KW:
+		priv, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, jwt.ErrInvalidKeyType
+		}
+		epk, err := parseEphemeralKey(header["epk"])
+		if err != nil {
+			return nil, err
+		}
+		apu, err := decodeHeaderBytes(header, "apu")
+		if err != nil {
+			return nil, err
+		}
+		apv, err := decodeHeaderBytes(header, "apv")
+		if err != nil {
+			return nil, err
+		}
+		if alg == AlgECDHES {
+			if len(encryptedKey) != 0 {
+				return nil, ErrDecryption
+			}
+			return deriveECDHES(enc, cekSize, priv, epk, apu, apv)
+		}
+		kek, err := deriveECDHES(alg, keyWrapSize(alg), priv, epk, apu, apv)
+		if err != nil {
+			return nil, err
+		}
+		return keyUnwrap(kek, encryptedKey)
+
+	case AlgDirect:
+		cek, ok := key.([]byte)
+		if !ok {
+			return nil, jwt.ErrInvalidKeyType
+		}
+		if len(encryptedKey) != 0 {
+			return nil, ErrDecryption
+		}
+		return cek, nil
+	}
+	return nil, &UnsupportedAlgorithmError{Alg: alg}
+}
+
+func oaepHash(alg string) hash.Hash {
+	if alg == AlgRSAOAEP256 {
+		return sha256.New()
+	}
+	return sha1.New()
+}
+
+func keyWrapSize(alg string) int {
+	switch alg {
+	case AlgA128KW, AlgECDHESA128KW:
+		return 16
+	case AlgA256KW, AlgECDHESA256KW:
+		return 32
+	}
+	return 0
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(jwt.RandReader, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,95 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+var keyWrapDefaultIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+// keyWrap wraps cek with kek using the AES Key Wrap algorithm described in
+// https://datatracker.ietf.org/doc/html/rfc3394#section-2.2.1
+func keyWrap(kek, cek []byte) ([]byte, error) {
+	if len(cek)%8 != 0 || len(cek) < 16 {
+		return nil, errors.New("jwe: key to wrap must be a multiple of 64 bits")
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(cek) / 8
+	r := make([][]byte, n)
+	for i := range r {
+		r[i] = make([]byte, 8)
+		copy(r[i], cek[i*8:])
+	}
+
+	buf := make([]byte, 16)
+	a := make([]byte, 8)
+	copy(a, keyWrapDefaultIV)
+	for j := 0; j <= 5; j++ {
+		for i := 0; i < n; i++ {
+			copy(buf, a)
+			copy(buf[8:], r[i])
+			block.Encrypt(buf, buf)
+
+			t := uint64(n*j + i + 1)
+			binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(buf[:8])^t)
+			copy(r[i], buf[8:])
+		}
+	}
+
+	out := make([]byte, 0, (n+1)*8)
+	out = append(out, a...)
+	for i := range r {
+		out = append(out, r[i]...)
+	}
+	return out, nil
+}
+
+// keyUnwrap reverses keyWrap as described in
+// https://datatracker.ietf.org/doc/html/rfc3394#section-2.2.2
+func keyUnwrap(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped)%8 != 0 || len(wrapped) < 24 {
+		return nil, ErrDecryption
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(wrapped)/8 - 1
+	r := make([][]byte, n)
+	for i := range r {
+		r[i] = make([]byte, 8)
+		copy(r[i], wrapped[(i+1)*8:])
+	}
+
+	buf := make([]byte, 16)
+	a := make([]byte, 8)
+	copy(a, wrapped[:8])
+	for j := 5; j >= 0; j-- {
+		for i := n - 1; i >= 0; i-- {
+			t := uint64(n*j + i + 1)
+			binary.BigEndian.PutUint64(buf, binary.BigEndian.Uint64(a)^t)
+			copy(buf[8:], r[i])
+			block.Decrypt(buf, buf)
+
+			copy(a, buf[:8])
+			copy(r[i], buf[8:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(a, keyWrapDefaultIV) != 1 {
+		return nil, ErrDecryption
+	}
+
+	out := make([]byte, 0, n*8)
+	for i := range r {
+		out = append(out, r[i]...)
+	}
+	return out, nil
+}
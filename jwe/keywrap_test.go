@@ -0,0 +1,54 @@
+package jwe
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// Test vectors from https://datatracker.ietf.org/doc/html/rfc3394#section-4
+func TestKeyWrap(t *testing.T) {
+	var tests = []struct {
+		name    string
+		kek     string
+		key     string
+		wrapped string
+	}{
+		{
+			"128 bit key with 128 bit KEK",
+			"000102030405060708090A0B0C0D0E0F",
+			"00112233445566778899AABBCCDDEEFF",
+			"1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5",
+		},
+		{
+			"256 bit key with 256 bit KEK",
+			"000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			"00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F",
+			"28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21",
+		},
+	}
+
+	for _, data := range tests {
+		kek, _ := hex.DecodeString(data.kek)
+		key, _ := hex.DecodeString(data.key)
+		expected, _ := hex.DecodeString(data.wrapped)
+
+		wrapped, err := keyWrap(kek, key)
+		if err != nil {
+			t.Errorf("[%v] Error wrapping key: %v", data.name, err)
+			continue
+		}
+		if !bytes.Equal(wrapped, expected) {
+			t.Errorf("[%v] Wrapped key mismatch. Got: %X", data.name, wrapped)
+		}
+
+		unwrapped, err := keyUnwrap(kek, wrapped)
+		if err != nil {
+			t.Errorf("[%v] Error unwrapping key: %v", data.name, err)
+			continue
+		}
+		if !bytes.Equal(unwrapped, key) {
+			t.Errorf("[%v] Unwrapped key mismatch. Got: %X", data.name, unwrapped)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package jwe
+
+import (
+	"strings"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// EncryptNested encrypts a signed JWT, producing a nested token as described in
+// https://datatracker.ietf.org/doc/html/rfc7519#section-5.2. The "cty" header
+// is set to "JWT".
+func EncryptNested(signed string, alg, enc string, key interface{}, header map[string]interface{}) (string, error) {
+	h := make(map[string]interface{}, len(header)+1)
+	for k, v := range header {
+		h[k] = v
+	}
+	h["cty"] = "JWT"
+	return Encrypt([]byte(signed), alg, enc, key, h)
+}
+
+// ParseNested decrypts a nested token and parses, validates, and returns the
+// signed JWT it contains using parser. decryptKeyFunc supplies the decryption
+// key, keyFunc the key for verifying the inner signature. If parser is nil, the
+// default jwt.Parser is used.
+func (d *Decrypter) ParseNested(compact string, decryptKeyFunc Keyfunc, parser *jwt.Parser, claims jwt.Claims, keyFunc jwt.Keyfunc) (*jwt.Token, error) {
+	msg, err := d.Decrypt(compact, decryptKeyFunc)
+	if err != nil {
+		return nil, err
+	}
+	if cty, _ := msg.Header["cty"].(string); !strings.EqualFold(cty, "JWT") {
+		return nil, ErrNotNested
+	}
+	if parser == nil {
+		parser = new(jwt.Parser)
+	}
+	return parser.ParseWithClaims(string(msg.Plaintext), claims, keyFunc)
+}
+
+// ParseNested decrypts and parses a nested token using the default Decrypter.
+func ParseNested(compact string, decryptKeyFunc Keyfunc, parser *jwt.Parser, claims jwt.Claims, keyFunc jwt.Keyfunc) (*jwt.Token, error) {
+	return new(Decrypter).ParseNested(compact, decryptKeyFunc, parser, claims, keyFunc)
+}
+
+// TokenDecrypter adapts a Decrypter to jwt.Decrypter so that encrypted tokens
+// can be unwrapped by jwt.Parser.ParseNested.
+type TokenDecrypter struct {
+	Decrypter *Decrypter // Optional. Defaults to a Decrypter accepting all algorithms
+	Keyfunc   Keyfunc    // Supplies the decryption key
+}
+
+// DecryptToken implements jwt.Decrypter.
+func (d *TokenDecrypter) DecryptToken(compact string) (map[string]interface{}, []byte, error) {
+	dec := d.Decrypter
+	if dec == nil {
+		dec = new(Decrypter)
+	}
+	msg, err := dec.Decrypt(compact, d.Keyfunc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg.Header, msg.Plaintext, nil
+}
@@ -0,0 +1,12 @@
+// Package jwk implements JSON Web Keys and JSON Web Key Sets as described in
+// https://datatracker.ietf.org/doc/html/rfc7517.
+//
+// A Set is typically parsed from an issuer's JWKS document and its Keyfunc
+// passed to jwt.Parse. Keys are indexed by key ID and thumbprint, and are only
+// converted into crypto keys when first used, so that sets of thousands of
+// keys remain cheap to load.
+//
+// Remote fetches a Set over HTTP. It is excluded by the jwt_nonet build tag,
+// so that Sets can be parsed without depending on net/http in environments
+// such as WebAssembly or TinyGo.
+package jwk
@@ -0,0 +1,238 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// Error constants
+var (
+	ErrUnsupportedKeyType = errors.New("jwk: unsupported key type")
+	ErrInvalidKey         = errors.New("jwk: key is invalid")
+)
+
+// Key is a JSON Web Key holding a public or symmetric key. Private key members
+// are not supported.
+type Key struct {
+	KeyType   string   `json:"kty"`
+	Use       string   `json:"use,omitempty"`
+	KeyOps    []string `json:"key_ops,omitempty"`
+	Algorithm string   `json:"alg,omitempty"`
+	KeyID     string   `json:"kid,omitempty"`
+	X5c       []string `json:"x5c,omitempty"`
+	X5t       string   `json:"x5t,omitempty"`
+	X5tS256   string   `json:"x5t#S256,omitempty"`
+
+	Curve string `json:"crv,omitempty"` // EC and OKP
+	X     string `json:"x,omitempty"`   // EC and OKP
+	Y     string `json:"y,omitempty"`   // EC
+	N     string `json:"n,omitempty"`   // RSA
+	E     string `json:"e,omitempty"`   // RSA
+	K     string `json:"k,omitempty"`   // oct
+
+	// Issuer is the issuer whose tokens the key may verify. It is set from
+	// Set.Issuer by Merge and is not serialized. If empty, the key may
+	// verify tokens of any issuer.
+	Issuer string `json:"-"`
+
+	once sync.Once
+	key  interface{}
+	err  error
+}
+
+// NewKey creates a Key from an *rsa.PublicKey, *ecdsa.PublicKey,
+// ed25519.PublicKey or []byte. Private keys are reduced to their public half.
+func NewKey(key interface{}) (*Key, error) {
+	if s, ok := key.(crypto.Signer); ok {
+		key = s.Public()
+	}
+	k := &Key{}
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		k.KeyType = "RSA"
+		k.N = jwt.EncodeSegment(pub.N.Bytes())
+		k.E = jwt.EncodeSegment(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		k.KeyType = "EC"
+		k.Curve = pub.Curve.Params().Name
+		k.X = jwt.EncodeSegment(pad(pub.X.Bytes(), size))
+		k.Y = jwt.EncodeSegment(pad(pub.Y.Bytes(), size))
+	case ed25519.PublicKey:
+		k.KeyType = "OKP"
+		k.Curve = "Ed25519"
+		k.X = jwt.EncodeSegment(pub)
+	case []byte:
+		k.KeyType = "oct"
+		k.K = jwt.EncodeSegment(pub)
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+	k.once.Do(func() { k.key = key })
+	return k, nil
+}
+
+// Materialize returns the crypto key held by k: an *rsa.PublicKey,
+// *ecdsa.PublicKey, ed25519.PublicKey or []byte. The key is decoded on the
+// first call and cached.
+func (k *Key) Materialize() (interface{}, error) {
+	k.once.Do(func() {
+		k.key, k.err = k.decode()
+	})
+	return k.key, k.err
+}
+
+func (k *Key) decode() (interface{}, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil || !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, ErrInvalidKey
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, ErrUnsupportedKeyType
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, ErrInvalidKey
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, ErrUnsupportedKeyType
+		}
+		x, err := jwt.DecodeSegment(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, ErrInvalidKey
+		}
+		return ed25519.PublicKey(x), nil
+	case "oct":
+		b, err := jwt.DecodeSegment(k.K)
+		if err != nil {
+			return nil, ErrInvalidKey
+		}
+		return b, nil
+	}
+	return nil, ErrUnsupportedKeyType
+}
+
+// Thumbprint computes the base64url encoded JWK thumbprint of the key, as
+// described in https://datatracker.ietf.org/doc/html/rfc7638, using hash. It
+// is computed from the key's members without materializing the key.
+func (k *Key) Thumbprint(hash crypto.Hash) (string, error) {
+	if !hash.Available() {
+		return "", jwt.ErrHashUnavailable
+	}
+	var members interface{}
+	switch k.KeyType {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.KeyType, k.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{k.Curve, k.KeyType, k.X, k.Y}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Curve, k.KeyType, k.X}
+	case "oct":
+		members = struct {
+			K   string `json:"k"`
+			Kty string `json:"kty"`
+		}{k.K, k.KeyType}
+	default:
+		return "", ErrUnsupportedKeyType
+	}
+	b, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	h := hash.New()
+	h.Write(b)
+	return jwt.EncodeSegment(h.Sum(nil)), nil
+}
+
+// clone returns a copy of the key's members, without the materialized key.
+func (k *Key) clone() *Key {
+	return &Key{
+		KeyType:   k.KeyType,
+		Use:       k.Use,
+		KeyOps:    k.KeyOps,
+		Algorithm: k.Algorithm,
+		KeyID:     k.KeyID,
+		X5c:       k.X5c,
+		X5t:       k.X5t,
+		X5tS256:   k.X5tS256,
+		Curve:     k.Curve,
+		X:         k.X,
+		Y:         k.Y,
+		N:         k.N,
+		E:         k.E,
+		K:         k.K,
+		Issuer:    k.Issuer,
+	}
+}
+
+// usableFor reports whether the key may verify tokens signed with alg.
+func (k *Key) usableFor(alg string) bool {
+	if k.Use != "" && k.Use != "sig" {
+		return false
+	}
+	return k.Algorithm == "" || k.Algorithm == alg
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := jwt.DecodeSegment(s)
+	if err != nil || len(b) == 0 {
+		return nil, ErrInvalidKey
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func pad(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+	return padded
+}
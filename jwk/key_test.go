@@ -0,0 +1,94 @@
+package jwk_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwk"
+	"github.com/chanced/go-jwt/v4/test"
+)
+
+func TestNewKey(t *testing.T) {
+	rsaKey := test.LoadRSAPrivateKeyFromDisk("../test/sample_key")
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	edKey, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	var tests = []struct {
+		name string
+		key  interface{}
+		pub  interface{}
+	}{
+		{"RSA", rsaKey, &rsaKey.PublicKey},
+		{"EC", ecKey, &ecKey.PublicKey},
+		{"OKP", edKey, edKey},
+		{"oct", []byte("secret"), []byte("secret")},
+	}
+
+	for _, data := range tests {
+		k, err := jwk.NewKey(data.key)
+		if err != nil {
+			t.Errorf("[%v] Error creating key: %v", data.name, err)
+			continue
+		}
+		b, err := json.Marshal(k)
+		if err != nil {
+			t.Errorf("[%v] Error marshaling key: %v", data.name, err)
+			continue
+		}
+		var parsed jwk.Key
+		if err = json.Unmarshal(b, &parsed); err != nil {
+			t.Errorf("[%v] Error unmarshaling key: %v", data.name, err)
+			continue
+		}
+		pub, err := parsed.Materialize()
+		if err != nil {
+			t.Errorf("[%v] Error materializing key: %v", data.name, err)
+			continue
+		}
+		if ed, ok := data.pub.(ed25519.PrivateKey); ok {
+			data.pub = ed.Public()
+		}
+		if !reflect.DeepEqual(pub, data.pub) {
+			t.Errorf("[%v] Materialized key mismatch", data.name)
+		}
+
+		expected, _ := jwt.Thumbprint(data.key, crypto.SHA256)
+		if tp, err := parsed.Thumbprint(crypto.SHA256); err != nil || tp != jwt.EncodeSegment(expected) {
+			t.Errorf("[%v] Thumbprint mismatch: %v", data.name, err)
+		}
+	}
+
+	if _, err := jwk.NewKey("not a key"); err != jwk.ErrUnsupportedKeyType {
+		t.Errorf("Expected ErrUnsupportedKeyType. Got: %v", err)
+	}
+}
+
+func TestKey_Materialize_invalid(t *testing.T) {
+	var tests = []struct {
+		name string
+		raw  string
+	}{
+		{"unknown kty", `{"kty":"XYZ"}`},
+		{"unknown curve", `{"kty":"EC","crv":"P-999","x":"AQ","y":"AQ"}`},
+		{"point not on curve", `{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}`},
+		{"short Ed25519 key", `{"kty":"OKP","crv":"Ed25519","x":"AQ"}`},
+		{"missing modulus", `{"kty":"RSA","e":"AQAB"}`},
+	}
+
+	for _, data := range tests {
+		var k jwk.Key
+		if err := json.Unmarshal([]byte(data.raw), &k); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := k.Materialize(); err == nil {
+			t.Errorf("[%v] Expected error", data.name)
+		}
+	}
+}
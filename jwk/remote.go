@@ -0,0 +1,366 @@
+//go:build !jwt_nonet
+// +build !jwt_nonet
+
+package jwk
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// Defaults for Remote.
+const (
+	DefaultRefreshInterval    = time.Hour
+	DefaultMinRefreshInterval = time.Minute
+	DefaultHTTPTimeout        = 10 * time.Second
+)
+
+// defaultClient is used by Remotes without an HTTPClient. Unlike
+// http.DefaultClient, it gives up on an issuer which does not respond.
+var defaultClient = &http.Client{Timeout: DefaultHTTPTimeout}
+
+// ErrRemoteStarted is returned by Remote.Start if the Remote is already
+// refreshing in the background.
+var ErrRemoteStarted = errors.New("jwk: remote already started")
+
+// maxSetSize bounds the size of key set documents read by Remote.
+const maxSetSize = 4 << 20
+
+// Remote is a key set fetched from a URL, such as an issuer's jwks_uri. The
+// set is fetched on first use and refreshed once it is older than
+// RefreshInterval, or when a token names an unknown key ID and the set is older
+// than MinRefreshInterval.
+//
+// Keys which disappear from a refreshed set are still honored for GracePeriod,
+// so that tokens signed with a key the issuer retired abruptly keep verifying
+// until they expire. Retired keys are only used for tokens naming them by key
+// ID.
+//
+// Remote refreshes lazily, as keys are requested. Start begins refreshing it
+// in the background every RefreshInterval instead, so that requests never wait
+// on the issuer; Remote implements jwt.Component.
+//
+// Concurrent refreshes share one fetch, which is made without blocking
+// callers served from the current set. If a refresh fails, the last set
+// fetched stays in use, and lazy refreshes are not attempted again for
+// MinRefreshInterval.
+//
+// A Remote is safe for concurrent use.
+type Remote struct {
+	URL                string
+	HTTPClient         *http.Client  // Optional. Defaults to a client with a timeout of DefaultHTTPTimeout
+	RefreshInterval    time.Duration // Optional. Defaults to DefaultRefreshInterval
+	MinRefreshInterval time.Duration // Optional. Defaults to DefaultMinRefreshInterval
+	GracePeriod        time.Duration // Optional. How long retired keys are honored; zero disables
+
+	// OnGraceKeyUsed, if set, is called whenever a retired key is used to
+	// verify a token.
+	OnGraceKeyUsed func(kid string)
+
+	mu          sync.Mutex
+	set         *Set
+	retired     map[string]retiredKey // by thumbprint
+	fetchedAt   time.Time             // of the last successful refresh
+	attemptedAt time.Time             // of the last refresh, successful or not
+	err         error                 // of the last refresh
+	inflight    *refreshCall
+	stats       RemoteStats
+
+	lifecycle sync.Mutex
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// refreshCall is a fetch of the key set in progress, which is shared by the
+// refreshes requested while it runs.
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+type retiredKey struct {
+	key       *Key
+	removedAt time.Time
+}
+
+// RemoteStats are counters describing the activity of a Remote.
+type RemoteStats struct {
+	Refreshes     uint64    // Successful fetches of the key set
+	RefreshErrors uint64    // Failed fetches of the key set
+	LastRefresh   time.Time // Time of the last successful fetch
+	KeysInGrace   int       // Retired keys currently honored
+	GraceHits     uint64    // Tokens resolved to a retired key
+}
+
+// Stats returns a snapshot of the counters of r.
+func (r *Remote) Stats() RemoteStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// Set returns the current key set, fetching it if it has not been fetched or
+// is older than RefreshInterval. If a refresh fails but a set was fetched
+// before, the previous set is returned. If no set has been fetched, the error
+// of the last attempt is returned until MinRefreshInterval has passed.
+func (r *Remote) Set(ctx context.Context) (*Set, error) {
+	interval := r.RefreshInterval
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	r.mu.Lock()
+	due := r.dueLocked(interval)
+	set, err := r.set, r.err
+	r.mu.Unlock()
+	if due {
+		err = r.Refresh(ctx)
+		r.mu.Lock()
+		set = r.set
+		r.mu.Unlock()
+	}
+	if set == nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// dueLocked reports whether the set is older than interval, unless a refresh
+// was attempted less than MinRefreshInterval ago.
+func (r *Remote) dueLocked(interval time.Duration) bool {
+	now := jwt.TimeFunc()
+	if r.set != nil && now.Sub(r.fetchedAt) < interval {
+		return false
+	}
+	min := r.MinRefreshInterval
+	if min <= 0 {
+		min = DefaultMinRefreshInterval
+	}
+	return r.attemptedAt.IsZero() || now.Sub(r.attemptedAt) >= min
+}
+
+// Refresh fetches the key set now. If a refresh is already in progress, its
+// result is waited for instead.
+func (r *Remote) Refresh(ctx context.Context) error {
+	r.mu.Lock()
+	if c := r.inflight; c != nil {
+		r.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	c := &refreshCall{done: make(chan struct{})}
+	r.inflight = c
+	r.mu.Unlock()
+
+	set, err := r.fetch(ctx)
+
+	r.mu.Lock()
+	r.attemptedAt, r.err = jwt.TimeFunc(), err
+	if err != nil {
+		r.stats.RefreshErrors++
+	} else {
+		r.installLocked(set)
+	}
+	r.inflight = nil
+	r.mu.Unlock()
+
+	c.err = err
+	close(c.done)
+	return err
+}
+
+// Start fetches the key set in the background now and every RefreshInterval
+// after, until Close is called or ctx is done. Failed refreshes are counted in
+// Stats; the previous set stays in use.
+func (r *Remote) Start(ctx context.Context) error {
+	r.lifecycle.Lock()
+	defer r.lifecycle.Unlock()
+	if r.done != nil {
+		return ErrRemoteStarted
+	}
+	interval := r.RefreshInterval
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+	go r.run(ctx, interval, r.done)
+	return nil
+}
+
+func (r *Remote) run(ctx context.Context, interval time.Duration, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_ = r.Refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops the background refreshes begun by Start and waits for any
+// refresh in flight to finish. The current set stays available.
+func (r *Remote) Close() error {
+	r.lifecycle.Lock()
+	defer r.lifecycle.Unlock()
+	if r.done == nil {
+		return nil
+	}
+	r.cancel()
+	<-r.done
+	r.cancel, r.done = nil, nil
+	return nil
+}
+
+// installLocked replaces the set with one just fetched, retiring the keys it
+// no longer holds.
+func (r *Remote) installLocked(set *Set) {
+	now := jwt.TimeFunc()
+
+	current := make(map[string]bool, len(set.Keys))
+	for _, k := range set.Keys {
+		if tp, err := k.Thumbprint(crypto.SHA256); err == nil {
+			current[tp] = true
+		}
+	}
+	if r.retired == nil {
+		r.retired = make(map[string]retiredKey)
+	}
+	if r.set != nil && r.GracePeriod > 0 {
+		for _, k := range r.set.Keys {
+			tp, err := k.Thumbprint(crypto.SHA256)
+			if err != nil || current[tp] {
+				continue
+			}
+			if _, ok := r.retired[tp]; !ok {
+				r.retired[tp] = retiredKey{key: k, removedAt: now}
+			}
+		}
+	}
+	for tp := range current {
+		delete(r.retired, tp)
+	}
+
+	r.set, r.fetchedAt = set, now
+	r.stats.Refreshes++
+	r.stats.LastRefresh = now
+	r.graceSetLocked(now)
+}
+
+// graceSetLocked drops retired keys whose grace period has passed and returns
+// a set of the remaining ones.
+func (r *Remote) graceSetLocked(now time.Time) *Set {
+	grace := &Set{}
+	for tp, rk := range r.retired {
+		if now.Sub(rk.removedAt) >= r.GracePeriod {
+			delete(r.retired, tp)
+			continue
+		}
+		grace.Keys = append(grace.Keys, rk.key)
+	}
+	r.stats.KeysInGrace = len(grace.Keys)
+	return grace
+}
+
+func (r *Remote) fetch(ctx context.Context) (*Set, error) {
+	req, err := http.NewRequest(http.MethodGet, r.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/jwk-set+json, application/json")
+	client := r.HTTPClient
+	if client == nil {
+		client = defaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwk: fetching %s: unexpected status %s", r.URL, res.Status)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, maxSetSize))
+	if err != nil {
+		return nil, err
+	}
+	return Parse(body)
+}
+
+// Keyfunc is a jwt.Keyfunc resolving keys from the remote set, as described
+// by Set.Keyfunc, and from the keys in their grace period. Fetches it makes
+// are only bounded by the timeout of the HTTPClient; see KeyfuncContext.
+func (r *Remote) Keyfunc(token *jwt.Token) (interface{}, error) {
+	return r.keyfunc(context.Background(), token)
+}
+
+// KeyfuncContext returns a jwt.Keyfunc like Keyfunc, whose fetches are
+// canceled with ctx, such as the context of the request presenting the token.
+func (r *Remote) KeyfuncContext(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		return r.keyfunc(ctx, token)
+	}
+}
+
+func (r *Remote) keyfunc(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	set, err := r.Set(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, err := set.Keyfunc(token)
+	if !errors.Is(err, jwt.ErrUnknownKeyID) {
+		return key, err
+	}
+
+	// The issuer may have published a new key since the last refresh.
+	min := r.MinRefreshInterval
+	if min <= 0 {
+		min = DefaultMinRefreshInterval
+	}
+	r.mu.Lock()
+	due := r.dueLocked(min)
+	r.mu.Unlock()
+	if due && r.Refresh(ctx) == nil {
+		r.mu.Lock()
+		set = r.set
+		r.mu.Unlock()
+	}
+	r.mu.Lock()
+	grace := r.graceSetLocked(jwt.TimeFunc())
+	r.mu.Unlock()
+
+	if key, err = set.Keyfunc(token); !errors.Is(err, jwt.ErrUnknownKeyID) {
+		return key, err
+	}
+	if _, hasKid := token.Header["kid"]; !hasKid || len(grace.Keys) == 0 {
+		return nil, err
+	}
+	if key, gerr := grace.Keyfunc(token); gerr == nil {
+		kid, _ := token.Header["kid"].(string)
+		r.mu.Lock()
+		r.stats.GraceHits++
+		r.mu.Unlock()
+		if r.OnGraceKeyUsed != nil {
+			r.OnGraceKeyUsed(kid)
+		}
+		return key, nil
+	}
+	return nil, err
+}
@@ -0,0 +1,208 @@
+//go:build !jwt_nonet
+// +build !jwt_nonet
+
+package jwk_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwk"
+)
+
+func TestRemote_gracePeriod(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	set, keys := newSet(t, 3)
+	var mu sync.Mutex
+	published := &jwk.Set{Keys: set.Keys[:2]}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		json.NewEncoder(w).Encode(published)
+	}))
+	defer srv.Close()
+
+	var graceUsed []string
+	remote := &jwk.Remote{
+		URL:            srv.URL,
+		GracePeriod:    10 * time.Minute,
+		OnGraceKeyUsed: func(kid string) { graceUsed = append(graceUsed, kid) },
+	}
+	sign := func(i int) string {
+		token := jwt.New(jwt.SigningMethodES256)
+		token.Header["kid"] = set.Keys[i].KeyID
+		s, err := token.SignedString(keys[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	if _, err := jwt.Parse(sign(0), remote.Keyfunc); err != nil {
+		t.Fatalf("Error verifying with initial set: %v", err)
+	}
+
+	// The issuer abruptly replaces key-0 with key-2.
+	mu.Lock()
+	published = &jwk.Set{Keys: set.Keys[1:]}
+	mu.Unlock()
+	if err := remote.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := jwt.Parse(sign(2), remote.Keyfunc); err != nil {
+		t.Errorf("Error verifying with new key: %v", err)
+	}
+	if _, err := jwt.Parse(sign(0), remote.Keyfunc); err != nil {
+		t.Errorf("Error verifying with retired key in grace period: %v", err)
+	}
+	stats := remote.Stats()
+	if stats.GraceHits != 1 || stats.KeysInGrace != 1 || len(graceUsed) != 1 || graceUsed[0] != "key-0" {
+		t.Errorf("Unexpected grace metrics: %+v, %v", stats, graceUsed)
+	}
+
+	now = now.Add(11 * time.Minute)
+	if _, err := jwt.Parse(sign(0), remote.Keyfunc); !errors.Is(err, jwt.ErrUnknownKeyID) {
+		t.Errorf("Expected ErrUnknownKeyID after grace period. Got: %v", err)
+	}
+	if stats = remote.Stats(); stats.KeysInGrace != 0 || stats.Refreshes != 3 {
+		t.Errorf("Unexpected metrics after grace period: %+v", stats)
+	}
+}
+
+func TestRemote_unknownKeyIDRefresh(t *testing.T) {
+	set, keys := newSet(t, 2)
+	var mu sync.Mutex
+	published := &jwk.Set{Keys: set.Keys[:1]}
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		json.NewEncoder(w).Encode(published)
+	}))
+	defer srv.Close()
+
+	remote := &jwk.Remote{URL: srv.URL, MinRefreshInterval: time.Nanosecond}
+	if _, err := remote.Set(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	published = set
+	mu.Unlock()
+
+	token := jwt.New(jwt.SigningMethodES256)
+	token.Header["kid"] = "key-1"
+	signed, _ := token.SignedString(keys[1])
+	if _, err := jwt.Parse(signed, remote.Keyfunc); err != nil {
+		t.Errorf("Expected refresh on unknown key ID. Got: %v", err)
+	}
+	if fetches != 2 {
+		t.Errorf("Expected 2 fetches. Got: %v", fetches)
+	}
+}
+
+func TestRemote_refreshFailure(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	set, keys := newSet(t, 1)
+	var mu sync.Mutex
+	failing, fetches := false, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	remote := &jwk.Remote{URL: srv.URL, RefreshInterval: time.Minute, MinRefreshInterval: time.Minute}
+	if _, err := remote.Set(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	failing = true
+	mu.Unlock()
+
+	token := jwt.New(jwt.SigningMethodES256)
+	token.Header["kid"] = set.Keys[0].KeyID
+	signed, _ := token.SignedString(keys[0])
+	now = now.Add(2 * time.Minute)
+	for i := 0; i < 5; i++ {
+		if _, err := jwt.Parse(signed, remote.Keyfunc); err != nil {
+			t.Errorf("Expected the last set to be served. Got: %v", err)
+		}
+	}
+	if fetches != 2 {
+		t.Errorf("Expected 2 fetches. Got: %v", fetches)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := remote.Set(context.Background()); err != nil {
+		t.Errorf("Expected the last set to be served. Got: %v", err)
+	}
+	if stats := remote.Stats(); fetches != 3 || stats.RefreshErrors != 2 || stats.Refreshes != 1 {
+		t.Errorf("Unexpected metrics after %v fetches: %+v", fetches, stats)
+	}
+}
+
+func TestRemote_Start(t *testing.T) {
+	set, _ := newSet(t, 1)
+	fetched := make(chan struct{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+		select {
+		case fetched <- struct{}{}:
+		default:
+		}
+	}))
+	defer srv.Close()
+
+	remote := &jwk.Remote{URL: srv.URL, RefreshInterval: time.Millisecond}
+	var rt jwt.Runtime
+	if err := rt.Add(remote); err != nil {
+		t.Fatal(err)
+	}
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Start(context.Background()); !errors.Is(err, jwk.ErrRemoteStarted) {
+		t.Errorf("second Start: got %v, want %v", err, jwk.ErrRemoteStarted)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-fetched:
+		case <-time.After(5 * time.Second):
+			t.Fatal("key set not refreshed in the background")
+		}
+	}
+	if err := rt.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	refreshes := remote.Stats().Refreshes
+	time.Sleep(20 * time.Millisecond)
+	if got := remote.Stats().Refreshes; got != refreshes {
+		t.Errorf("refreshed %d times after Close", got-refreshes)
+	}
+	if _, err := remote.Set(context.Background()); err != nil {
+		t.Errorf("Set after Close: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package jwk
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// Set is a JSON Web Key Set. Lookups are served from indexes by key ID and
+// SHA-256 thumbprint which are built on first use; a Set must not be modified
+// once it has been used.
+type Set struct {
+	Keys []*Key `json:"keys"`
+
+	// Issuer is the issuer which published the set. It is not serialized;
+	// Merge records it on each key so that keys of a merged set only verify
+	// tokens of the issuer they came from.
+	Issuer string `json:"-"`
+
+	once         sync.Once
+	byKeyID      map[string][]*Key
+	byThumbprint map[string]*Key
+}
+
+// Parse parses a JSON Web Key Set. Keys are not materialized until used.
+func Parse(data []byte) (*Set, error) {
+	s := &Set{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("jwk: invalid key set: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Set) index() {
+	s.once.Do(func() {
+		s.byKeyID = make(map[string][]*Key, len(s.Keys))
+		s.byThumbprint = make(map[string]*Key, len(s.Keys))
+		for _, k := range s.Keys {
+			s.byKeyID[k.KeyID] = append(s.byKeyID[k.KeyID], k)
+			if tp, err := k.Thumbprint(crypto.SHA256); err == nil {
+				if _, ok := s.byThumbprint[tp]; !ok {
+					s.byThumbprint[tp] = k
+				}
+			}
+		}
+	})
+}
+
+// LookupKeyID returns the keys with the key ID kid.
+func (s *Set) LookupKeyID(kid string) []*Key {
+	s.index()
+	return s.byKeyID[kid]
+}
+
+// LookupThumbprint returns the key whose base64url encoded SHA-256
+// thumbprint is tp, or nil.
+func (s *Set) LookupThumbprint(tp string) *Key {
+	s.index()
+	return s.byThumbprint[tp]
+}
+
+// Merge combines sets into a single set, such as for a verifier trusting
+// several issuers. Each key records the Issuer of the set it came from, unless
+// it already records one, and the Keyfunc of the merged set only uses a key
+// for tokens whose "iss" claim matches it. The keys of sets are copied; sets
+// are not modified.
+func Merge(sets ...*Set) *Set {
+	merged := &Set{}
+	for _, set := range sets {
+		for _, k := range set.Keys {
+			c := k.clone()
+			if c.Issuer == "" {
+				c.Issuer = set.Issuer
+			}
+			merged.Keys = append(merged.Keys, c)
+		}
+	}
+	return merged
+}
+
+// Keyfunc is a jwt.Keyfunc selecting the verification key by the token's
+// "kid" header. Keys whose "use" is not "sig", or whose "alg" differs from the
+// token's, are not considered, nor are keys recording an Issuer other than the
+// token's "iss" claim. If several keys remain, or the token has no "kid", a
+// *jwt.VerificationKeySet of the candidates is returned.
+func (s *Set) Keyfunc(token *jwt.Token) (interface{}, error) {
+	alg, _ := token.Header["alg"].(string)
+	kid, hasKid := token.Header["kid"].(string)
+	iss, checkIssuer := "", false
+
+	candidates := s.Keys
+	if hasKid {
+		candidates = s.LookupKeyID(kid)
+	}
+
+	var keys []interface{}
+	for _, k := range candidates {
+		if !k.usableFor(alg) {
+			continue
+		}
+		if k.Issuer != "" {
+			if !checkIssuer {
+				iss, checkIssuer = issuer(token.Claims), true
+			}
+			if k.Issuer != iss {
+				continue
+			}
+		}
+		key, err := k.Materialize()
+		if err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	switch len(keys) {
+	case 0:
+		return nil, fmt.Errorf("%w: %q", jwt.ErrUnknownKeyID, kid)
+	case 1:
+		return keys[0], nil
+	}
+	return jwt.NewVerificationKeySet(keys...), nil
+}
+
+// issuer returns the "iss" claim of claims.
+func issuer(claims jwt.Claims) string {
+	switch c := claims.(type) {
+	case jwt.MapClaims:
+		iss, _ := c["iss"].(string)
+		return iss
+	case jwt.ClaimsGetter:
+		iss, _ := c.GetIssuer()
+		return iss
+	}
+	// Other claims types are inspected through their JSON encoding.
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return ""
+	}
+	var c struct {
+		Issuer string `json:"iss"`
+	}
+	json.Unmarshal(b, &c)
+	return c.Issuer
+}
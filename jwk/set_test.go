@@ -0,0 +1,178 @@
+package jwk_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwk"
+)
+
+// newSet generates a set of n EC keys with key IDs "key-0" through
+// "key-<n-1>", along with the private keys.
+func newSet(tb testing.TB, n int) (*jwk.Set, []*ecdsa.PrivateKey) {
+	tb.Helper()
+	set := &jwk.Set{}
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		k, err := jwk.NewKey(priv)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		k.KeyID = fmt.Sprintf("key-%d", i)
+		k.Algorithm = "ES256"
+		set.Keys = append(set.Keys, k)
+		keys[i] = priv
+	}
+
+	// Round trip through JSON so that keys are materialized lazily.
+	b, err := json.Marshal(set)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if set, err = jwk.Parse(b); err != nil {
+		tb.Fatal(err)
+	}
+	return set, keys
+}
+
+func TestSet_Keyfunc(t *testing.T) {
+	set, keys := newSet(t, 3)
+
+	var tests = []struct {
+		name   string
+		method jwt.SigningMethod
+		kid    string
+		key    interface{}
+		err    error
+	}{
+		{"matching kid", jwt.SigningMethodES256, "key-1", keys[1], nil},
+		{"no kid", jwt.SigningMethodES256, "", keys[2], nil},
+		{"unknown kid", jwt.SigningMethodES256, "key-9", keys[0], jwt.ErrUnknownKeyID},
+		{"alg mismatch", jwt.SigningMethodHS256, "key-0", []byte("secret"), jwt.ErrUnknownKeyID},
+		{"wrong key for kid", jwt.SigningMethodES256, "key-0", keys[1], jwt.ErrSignatureInvalid},
+	}
+
+	for _, data := range tests {
+		token := jwt.New(data.method)
+		if data.kid != "" {
+			token.Header["kid"] = data.kid
+		}
+		signed, err := token.SignedString(data.key)
+		if err != nil {
+			t.Errorf("[%v] Error signing token: %v", data.name, err)
+			continue
+		}
+		_, err = jwt.Parse(signed, set.Keyfunc)
+		if data.err == nil && err != nil {
+			t.Errorf("[%v] Error parsing token: %v", data.name, err)
+		} else if !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+}
+
+func TestSet_LookupThumbprint(t *testing.T) {
+	set, keys := newSet(t, 2)
+	tp, _ := jwt.Thumbprint(keys[1], crypto.SHA256)
+	if k := set.LookupThumbprint(jwt.EncodeSegment(tp)); k == nil || k.KeyID != "key-1" {
+		t.Errorf("Expected key-1. Got: %v", k)
+	}
+	if k := set.LookupThumbprint("unknown"); k != nil {
+		t.Errorf("Expected no key. Got: %v", k)
+	}
+}
+
+func BenchmarkSet_Parse_1k(b *testing.B) {
+	set, _ := newSet(b, 1000)
+	data, _ := json.Marshal(set)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jwk.Parse(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSet_Keyfunc_1k(b *testing.B) {
+	set, _ := newSet(b, 1000)
+	token := jwt.New(jwt.SigningMethodES256)
+	token.Header["kid"] = "key-999"
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := set.Keyfunc(token); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSet_LookupThumbprint_1k(b *testing.B) {
+	set, keys := newSet(b, 1000)
+	raw, _ := jwt.Thumbprint(keys[999], crypto.SHA256)
+	tp := jwt.EncodeSegment(raw)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if set.LookupThumbprint(tp) == nil {
+			b.Fatal("key not found")
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a, keysA := newSet(t, 1)
+	a.Issuer = "https://a.example.com"
+	b, keysB := newSet(t, 1)
+	b.Issuer = "https://b.example.com"
+	// Both issuers publish a key with the ID "key-0".
+	merged := jwk.Merge(a, b)
+
+	var tests = []struct {
+		name   string
+		claims jwt.Claims
+		key    interface{}
+		err    error
+	}{
+		{"issuer a", jwt.MapClaims{"iss": a.Issuer}, keysA[0], nil},
+		{"issuer b", &jwt.RegisteredClaims{Issuer: b.Issuer}, keysB[0], nil},
+		{"key of other issuer", jwt.MapClaims{"iss": a.Issuer}, keysB[0], jwt.ErrSignatureInvalid},
+		{"unknown issuer", jwt.MapClaims{"iss": "https://c.example.com"}, keysA[0], jwt.ErrUnknownKeyID},
+		{"no issuer", jwt.MapClaims{}, keysA[0], jwt.ErrUnknownKeyID},
+	}
+
+	for _, data := range tests {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, data.claims)
+		token.Header["kid"] = "key-0"
+		signed, err := token.SignedString(data.key)
+		if err != nil {
+			t.Errorf("[%v] Error signing token: %v", data.name, err)
+			continue
+		}
+		var claims jwt.Claims = jwt.MapClaims{}
+		if _, ok := data.claims.(*jwt.RegisteredClaims); ok {
+			claims = &jwt.RegisteredClaims{}
+		}
+		_, err = jwt.ParseWithClaims(signed, claims, merged.Keyfunc)
+		if data.err == nil && err != nil {
+			t.Errorf("[%v] Error parsing token: %v", data.name, err)
+		} else if !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+
+	if a.Keys[0].Issuer != "" {
+		t.Errorf("Merge modified the keys of its arguments")
+	}
+}
@@ -0,0 +1,36 @@
+package jwtgrpc
+
+import (
+	"context"
+)
+
+// TokenCredentials implement credentials.PerRPCCredentials, attaching a bearer
+// token to the "authorization" metadata of outgoing calls.
+type TokenCredentials struct {
+	// Token returns the token for a call, such as from a cache of tokens
+	// minted for the called service.
+	Token func(ctx context.Context) (string, error)
+
+	// AllowInsecure permits sending the token over connections without
+	// transport security. It should only be set for tests.
+	AllowInsecure bool
+}
+
+// StaticToken returns TokenCredentials which always send token.
+func StaticToken(token string) *TokenCredentials {
+	return &TokenCredentials{Token: func(context.Context) (string, error) { return token, nil }}
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (c *TokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := c.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials.
+func (c *TokenCredentials) RequireTransportSecurity() bool {
+	return !c.AllowInsecure
+}
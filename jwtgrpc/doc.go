@@ -0,0 +1,11 @@
+// Package jwtgrpc provides gRPC interceptors authenticating calls with a JWT
+// carried in the "authorization" metadata, and call credentials attaching a
+// token to outgoing calls.
+//
+// The verified token is stored in the call's context with jwt.NewContext,
+// where handlers read it with jwt.FromContext. A Propagator forwards a subset
+// of its claims to the services called in turn.
+//
+// jwtgrpc is a separate module so that users of the jwt package do not depend
+// on gRPC.
+package jwtgrpc
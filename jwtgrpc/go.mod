@@ -0,0 +1,10 @@
+module github.com/chanced/go-jwt/v4/jwtgrpc
+
+go 1.15
+
+require (
+	github.com/chanced/go-jwt/v4 v4.0.0
+	google.golang.org/grpc v1.44.0
+)
+
+replace github.com/chanced/go-jwt/v4 => ../
@@ -0,0 +1,155 @@
+package jwtgrpc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// IdentityMetadataKey is the metadata carrying identities propagated by a
+// Propagator, unless its MetadataKey is set.
+const IdentityMetadataKey = "x-jwt-identity"
+
+// DefaultIdentityTTL is the lifetime of the internal tokens minted by a
+// Propagator when its TTL is unset.
+const DefaultIdentityTTL = time.Minute
+
+// ErrNoIdentity is returned by ProjectedClaims when the incoming metadata
+// carries no propagated identity.
+var ErrNoIdentity = errors.New("jwtgrpc: no propagated identity in metadata")
+
+// Propagator forwards the identity of the caller of a service to the services
+// it calls in turn. A subset of the claims of the verified token in the
+// context, stored there by the server interceptors, is projected into the
+// metadata of outgoing calls.
+//
+// With a Signer, the projection is signed as a short-lived internal token,
+// which the receiving service verifies with server interceptors whose
+// MetadataKey is IdentityMetadataKey. Without one, the claims are sent as
+// unsigned JSON, read with ProjectedClaims; this is only appropriate where the
+// network between services is trusted.
+type Propagator struct {
+	Claims      []string      // Optional. The names of the claims projected. Defaults to "sub"
+	Signer      *jwt.Signer   // Optional. Signs the projected claims as an internal token
+	TTL         time.Duration // Optional. Lifetime of signed projections. Defaults to DefaultIdentityTTL
+	MetadataKey string        // Optional. Defaults to IdentityMetadataKey
+}
+
+func (p *Propagator) metadataKey() string {
+	if p.MetadataKey != "" {
+		return p.MetadataKey
+	}
+	return IdentityMetadataKey
+}
+
+// Project returns the projection of the claims of token sent as metadata. A
+// signed projection expires after TTL, or with token if that is sooner.
+func (p *Propagator) Project(token *jwt.Token) (string, error) {
+	all, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		b, err := json.Marshal(token.Claims)
+		if err != nil {
+			return "", err
+		}
+		if err = json.Unmarshal(b, &all); err != nil {
+			return "", err
+		}
+	}
+	names := p.Claims
+	if len(names) == 0 {
+		names = []string{"sub"}
+	}
+	claims := make(jwt.MapClaims, len(names)+2)
+	for _, name := range names {
+		if v, ok := all[name]; ok {
+			claims[name] = v
+		}
+	}
+
+	if p.Signer == nil {
+		b, err := json.Marshal(claims)
+		if err != nil {
+			return "", err
+		}
+		return base64.RawURLEncoding.EncodeToString(b), nil
+	}
+	ttl := p.TTL
+	if ttl <= 0 {
+		ttl = DefaultIdentityTTL
+	}
+	now := jwt.TimeFunc()
+	exp := now.Add(ttl)
+	if t, ok := all.ExpiresAt().(time.Time); ok && t.Before(exp) {
+		exp = t
+	}
+	claims["iat"], claims["exp"] = jwt.NewNumericDate(now), jwt.NewNumericDate(exp)
+	return p.Signer.Sign(claims)
+}
+
+// OutgoingContext returns a copy of ctx whose outgoing metadata carries the
+// projection of the verified token in ctx. If ctx carries no token, it is
+// returned unchanged.
+func (p *Propagator) OutgoingContext(ctx context.Context) (context.Context, error) {
+	token, ok := jwt.FromContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+	v, err := p.Project(token)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.AppendToOutgoingContext(ctx, p.metadataKey(), v), nil
+}
+
+// UnaryClientInterceptor returns a client interceptor propagating the
+// identity of unary calls.
+func (p *Propagator) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, err := p.OutgoingContext(ctx)
+		if err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor propagating the
+// identity of streaming calls.
+func (p *Propagator) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := p.OutgoingContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// ProjectedClaims returns the unsigned claims propagated in the incoming
+// metadata of ctx under key, or IdentityMetadataKey if key is empty. The
+// claims are not verified in any way.
+func ProjectedClaims(ctx context.Context, key string) (jwt.MapClaims, error) {
+	if key == "" {
+		key = IdentityMetadataKey
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(key)
+	if len(values) == 0 {
+		return nil, ErrNoIdentity
+	}
+	b, err := base64.RawURLEncoding.DecodeString(values[0])
+	if err != nil {
+		return nil, err
+	}
+	var claims jwt.MapClaims
+	if err = json.Unmarshal(b, &claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
@@ -0,0 +1,71 @@
+package jwtgrpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwtgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var internalKey = []byte("internal secret")
+
+func incoming(t *testing.T, p *jwtgrpc.Propagator, token *jwt.Token) context.Context {
+	ctx, err := p.OutgoingContext(jwt.NewContext(context.Background(), token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestPropagator_signed(t *testing.T) {
+	signer, err := jwt.NewSigner(jwt.SigningMethodHS256, internalKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &jwtgrpc.Propagator{Claims: []string{"sub", "scope"}, Signer: signer}
+	exp := time.Now().Add(time.Hour).Unix()
+	token := &jwt.Token{Claims: jwt.MapClaims{"sub": "user", "scope": "read", "email": "user@example.com", "exp": float64(exp)}}
+	ctx := incoming(t, p, token)
+
+	var claims jwt.MapClaims
+	interceptor := jwtgrpc.UnaryServerInterceptor(jwtgrpc.Options{
+		Keyfunc:     func(*jwt.Token) (interface{}, error) { return internalKey, nil },
+		MetadataKey: jwtgrpc.IdentityMetadataKey,
+	})
+	_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		token, _ := jwt.FromContext(ctx)
+		claims = token.Claims.(jwt.MapClaims)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims["sub"] != "user" || claims["scope"] != "read" || claims["email"] != nil {
+		t.Errorf("unexpected claims %v", claims)
+	}
+	if e := int64(claims["exp"].(float64)); e > time.Now().Add(jwtgrpc.DefaultIdentityTTL).Unix() {
+		t.Errorf("exp %v exceeds DefaultIdentityTTL", e)
+	}
+}
+
+func TestProjectedClaims(t *testing.T) {
+	p := &jwtgrpc.Propagator{}
+	ctx := incoming(t, p, &jwt.Token{Claims: &jwt.RegisteredClaims{Subject: "user", Issuer: "issuer"}})
+	claims, err := jwtgrpc.ProjectedClaims(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claims) != 1 || claims["sub"] != "user" {
+		t.Errorf("unexpected claims %v", claims)
+	}
+
+	if _, err = jwtgrpc.ProjectedClaims(context.Background(), ""); !errors.Is(err, jwtgrpc.ErrNoIdentity) {
+		t.Errorf("got %v, want %v", err, jwtgrpc.ErrNoIdentity)
+	}
+}
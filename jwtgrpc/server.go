@@ -0,0 +1,108 @@
+package jwtgrpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chanced/go-jwt/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Options configure the server interceptors.
+type Options struct {
+	// Verifier verifies the token. If nil, tokens are parsed with Parser,
+	// NewClaims and Keyfunc.
+	Verifier jwt.Verifier
+
+	Parser    *jwt.Parser       // Optional. Defaults to a zero jwt.Parser
+	Keyfunc   jwt.Keyfunc       // Supplies the verification key, unless Verifier is set
+	NewClaims func() jwt.Claims // Optional. Returns the Claims to parse into. Defaults to jwt.MapClaims
+
+	// MetadataKey is the metadata carrying the token. Defaults to
+	// "authorization". Services receiving identities propagated with a
+	// Propagator set it to IdentityMetadataKey.
+	MetadataKey string
+
+	// Skip, if set, exempts the methods for which it returns true, such as
+	// health checks, from authentication. It receives the full method name.
+	Skip func(fullMethod string) bool
+}
+
+func (o *Options) verifier() jwt.Verifier {
+	if o.Verifier != nil {
+		return o.Verifier
+	}
+	return &jwt.FallbackVerifier{Parser: o.Parser, Keyfunc: o.Keyfunc, NewClaims: o.NewClaims}
+}
+
+func (o *Options) metadataKey() string {
+	if o.MetadataKey != "" {
+		return o.MetadataKey
+	}
+	return "authorization"
+}
+
+// authenticate verifies the bearer token in the incoming metadata of ctx and
+// returns a context carrying it. Failures are reported with the
+// Unauthenticated code.
+func authenticate(ctx context.Context, v jwt.Verifier, key string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(key)
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing "+key+" metadata")
+	}
+	credential := values[0]
+	if len(credential) > 6 && strings.EqualFold(credential[:7], "bearer ") {
+		credential = credential[7:]
+	}
+	token, err := v.Verify(ctx, credential)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, jwt.ToOAuthError(err).Description)
+	}
+	return jwt.NewContext(ctx, token), nil
+}
+
+// UnaryServerInterceptor returns a server interceptor authenticating unary
+// calls.
+func UnaryServerInterceptor(opts Options) grpc.UnaryServerInterceptor {
+	v := opts.verifier()
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if opts.Skip != nil && opts.Skip(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx, v, opts.metadataKey())
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor authenticating
+// streaming calls.
+func StreamServerInterceptor(opts Options) grpc.StreamServerInterceptor {
+	v := opts.verifier()
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if opts.Skip != nil && opts.Skip(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticate(ss.Context(), v, opts.metadataKey())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream overrides the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
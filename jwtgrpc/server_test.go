@@ -0,0 +1,110 @@
+package jwtgrpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwtgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var key = []byte("secret")
+
+// subjectHealthServer reports SERVING only to callers whose token has the
+// subject "user".
+type subjectHealthServer struct {
+	*health.Server
+}
+
+func (s subjectHealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	token, ok := jwt.FromContext(ctx)
+	if !ok || token.Claims.(jwt.MapClaims)["sub"] != "user" {
+		return nil, status.Error(codes.PermissionDenied, "unexpected caller")
+	}
+	return s.Server.Check(ctx, req)
+}
+
+func dial(t *testing.T, creds *jwtgrpc.TokenCredentials) healthpb.HealthClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(jwtgrpc.UnaryServerInterceptor(jwtgrpc.Options{
+		Keyfunc: func(*jwt.Token) (interface{}, error) { return key, nil },
+	})))
+	healthpb.RegisterHealthServer(srv, subjectHealthServer{health.NewServer()})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	opts := []grpc.DialOption{
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+	}
+	if creds != nil {
+		opts = append(opts, grpc.WithPerRPCCredentials(creds))
+	}
+	conn, err := grpc.Dial("bufnet", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	valid, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString(key)
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString([]byte("other"))
+
+	var tests = []struct {
+		name  string
+		token string
+		code  codes.Code
+	}{
+		{"valid token", valid, codes.OK},
+		{"forged token", forged, codes.Unauthenticated},
+		{"no token", "", codes.Unauthenticated},
+	}
+
+	for _, data := range tests {
+		var creds *jwtgrpc.TokenCredentials
+		if data.token != "" {
+			creds = jwtgrpc.StaticToken(data.token)
+			creds.AllowInsecure = true
+		}
+		_, err := dial(t, creds).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		if code := status.Code(err); code != data.code {
+			t.Errorf("[%v] Expected code %v. Got: %v", data.name, data.code, err)
+		}
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := jwtgrpc.StreamServerInterceptor(jwtgrpc.Options{
+		Keyfunc: func(*jwt.Token) (interface{}, error) { return key, nil },
+		Skip:    func(method string) bool { return method == "/public" },
+	})
+	called := false
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		called = true
+		return nil
+	}
+
+	err := interceptor(nil, &fakeStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/private"}, handler)
+	if status.Code(err) != codes.Unauthenticated || called {
+		t.Errorf("Expected Unauthenticated. Got: %v", err)
+	}
+	if err = interceptor(nil, &fakeStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/public"}, handler); err != nil || !called {
+		t.Errorf("Expected skipped method to be called. Got: %v", err)
+	}
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
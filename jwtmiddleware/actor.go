@@ -0,0 +1,62 @@
+package jwtmiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/request"
+)
+
+// ActorOptions configure the verification of actor tokens, for requests which
+// carry both the token of an end-user, the subject, and that of the service
+// acting on their behalf, such as in separate headers. The actor token is
+// verified independently, with its own keys and Policy, and stored in the
+// request context with jwt.NewActorContext.
+type ActorOptions struct {
+	// Verifier verifies the actor token. If nil, tokens are parsed with
+	// Parser, NewClaims and Keyfunc.
+	Verifier jwt.Verifier
+
+	Parser    *jwt.Parser       // Optional. Defaults to a zero jwt.Parser
+	Keyfunc   jwt.Keyfunc       // Supplies the verification key, unless Verifier is set
+	NewClaims func() jwt.Claims // Optional. Returns the Claims to parse into. Defaults to jwt.MapClaims
+
+	// Extractor extracts the actor token from the request, for example
+	// request.HeaderExtractor{"X-Actor-Token"}. Required.
+	Extractor request.Extractor
+
+	// Optional passes requests carrying no actor token on to the next handler
+	// with only the subject token in the context.
+	Optional bool
+}
+
+// ActorTokenError is reported to Options.ErrorHandler when a request is
+// rejected because of its actor token, rather than its subject token.
+type ActorTokenError struct {
+	Err error
+}
+
+func (e *ActorTokenError) Error() string {
+	return "jwtmiddleware: actor token: " + e.Err.Error()
+}
+
+func (e *ActorTokenError) Unwrap() error {
+	return e.Err
+}
+
+func (o *ActorOptions) verify(ctx context.Context, r *http.Request) (context.Context, error) {
+	credential, err := o.Extractor.ExtractToken(r)
+	if errors.Is(err, request.ErrNoTokenInRequest) && o.Optional {
+		return ctx, nil
+	}
+	if err != nil {
+		return ctx, &ActorTokenError{Err: err}
+	}
+	token, err := newVerifier(o.Verifier, o.Parser, o.Keyfunc, o.NewClaims).Verify(ctx, credential)
+	if err != nil {
+		return ctx, &ActorTokenError{Err: err}
+	}
+	return jwt.NewActorContext(ctx, token), nil
+}
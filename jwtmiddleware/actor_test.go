@@ -0,0 +1,76 @@
+package jwtmiddleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwtmiddleware"
+	"github.com/chanced/go-jwt/v4/request"
+)
+
+func TestNew_actor(t *testing.T) {
+	userKey, serviceKey := []byte("user secret"), []byte("service secret")
+	user, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString(userKey)
+	service, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "service"}).SignedString(serviceKey)
+
+	var tests = []struct {
+		name     string
+		optional bool
+		actor    string
+		status   int
+		subject  string
+		actorSub string
+	}{
+		{"both tokens", false, service, http.StatusOK, "user", "service"},
+		{"actor signed with the user key", false, user, http.StatusUnauthorized, "", ""},
+		{"no actor token", false, "", http.StatusUnauthorized, "", ""},
+		{"no actor token, optional", true, "", http.StatusOK, "user", ""},
+	}
+
+	for _, data := range tests {
+		var subject, actor string
+		var handlerErr error
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token, ok := jwt.FromContext(r.Context()); ok {
+				subject, _ = token.Claims.(jwt.MapClaims)["sub"].(string)
+			}
+			if token, ok := jwt.ActorFromContext(r.Context()); ok {
+				actor, _ = token.Claims.(jwt.MapClaims)["sub"].(string)
+			}
+		})
+		handler := jwtmiddleware.New(jwtmiddleware.Options{
+			Keyfunc: func(*jwt.Token) (interface{}, error) { return userKey, nil },
+			Actor: &jwtmiddleware.ActorOptions{
+				Keyfunc:   func(*jwt.Token) (interface{}, error) { return serviceKey, nil },
+				Extractor: request.HeaderExtractor{"X-Actor-Token"},
+				Optional:  data.optional,
+			},
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				handlerErr = err
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+		})(next)
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "Bearer "+user)
+		if data.actor != "" {
+			r.Header.Set("X-Actor-Token", data.actor)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v. Got: %v", data.name, data.status, w.Code)
+		}
+		if subject != data.subject || actor != data.actorSub {
+			t.Errorf("[%v] Expected subject %q and actor %q. Got: %q and %q", data.name, data.subject, data.actorSub, subject, actor)
+		}
+		var actorErr *jwtmiddleware.ActorTokenError
+		if (data.status != http.StatusOK) != errors.As(handlerErr, &actorErr) {
+			t.Errorf("[%v] Unexpected error %v", data.name, handlerErr)
+		}
+	}
+}
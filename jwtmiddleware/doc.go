@@ -0,0 +1,13 @@
+// Package jwtmiddleware provides net/http middleware for authorizing requests
+// carrying a JWT.
+//
+// The middleware returned by New extracts and verifies the token presented
+// with a request and stores it in the request context, where handlers read it
+// with jwt.FromContext. Authorization middleware such as RequireScopes and
+// RequireAnyScope reads the token from the context as well; it must be placed
+// behind the middleware that performs the verification.
+//
+// Requests made by a service on behalf of an end-user may carry two tokens.
+// With Options.Actor set, the service's token is verified as well and stored
+// separately, where handlers read it with jwt.ActorFromContext.
+package jwtmiddleware
@@ -0,0 +1,30 @@
+package jwtmiddleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// writeError responds with the RFC 6750 translation of err, including the
+// WWW-Authenticate challenge.
+func writeError(w http.ResponseWriter, err error, realm string) {
+	oerr := jwt.ToOAuthError(err)
+	w.Header().Set("WWW-Authenticate", oerr.WWWAuthenticate(realm))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(oerr.StatusCode)
+	json.NewEncoder(w).Encode(oerr)
+}
+
+// writeChallenge responds 401 with a bare challenge, which is the correct
+// response to a request carrying no credentials at all.
+// See https://datatracker.ietf.org/doc/html/rfc6750#section-3.1
+func writeChallenge(w http.ResponseWriter, realm string) {
+	challenge := "Bearer"
+	if realm != "" {
+		challenge = (&jwt.OAuthError{}).WWWAuthenticate(realm)
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
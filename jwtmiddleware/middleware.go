@@ -0,0 +1,112 @@
+package jwtmiddleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/request"
+)
+
+// Options configure the middleware returned by New.
+type Options struct {
+	// Verifier verifies the extracted token. If nil, tokens are parsed with
+	// Parser, NewClaims and Keyfunc.
+	Verifier jwt.Verifier
+
+	Parser    *jwt.Parser       // Optional. Defaults to a zero jwt.Parser
+	Keyfunc   jwt.Keyfunc       // Supplies the verification key, unless Verifier is set
+	NewClaims func() jwt.Claims // Optional. Returns the Claims to parse into. Defaults to jwt.MapClaims
+
+	// Extractor extracts the token from the request. Defaults to
+	// request.AuthorizationHeaderExtractor.
+	Extractor request.Extractor
+
+	// Realm is included in the WWW-Authenticate challenge of error responses.
+	Realm string
+
+	// CredentialsOptional passes requests carrying no token on to the next
+	// handler without a token in the context. Requests carrying an invalid
+	// token are still rejected.
+	CredentialsOptional bool
+
+	// Sessions, if set, is consulted with the "sid" claim of every valid
+	// token, so that tokens outlive neither a logout nor the revocation of
+	// their session at the server. Tokens of inactive sessions are rejected
+	// with jwt.ErrSessionInvalid.
+	Sessions SessionStore
+
+	// Actor, if set, configures the verification of a second token carried
+	// by each request: that of the service calling on behalf of the end-user
+	// whose token is verified as configured above.
+	Actor *ActorOptions
+
+	// RequireSession rejects tokens without a "sid" claim when Sessions is
+	// set. Otherwise such tokens are accepted without consulting Sessions.
+	RequireSession bool
+
+	// ErrorHandler writes the response for requests which are rejected. err
+	// is request.ErrNoTokenInRequest if the request carried no token.
+	// Defaults to responding as described in
+	// https://datatracker.ietf.org/doc/html/rfc6750#section-3
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// New returns middleware which extracts and verifies the token presented with
+// each request and, if it is valid, stores it in the request context with
+// jwt.NewContext before calling the next handler.
+func New(opts Options) func(http.Handler) http.Handler {
+	verifier := newVerifier(opts.Verifier, opts.Parser, opts.Keyfunc, opts.NewClaims)
+	extractor := opts.Extractor
+	if extractor == nil {
+		extractor = request.AuthorizationHeaderExtractor
+	}
+	onError := opts.ErrorHandler
+	if onError == nil {
+		onError = func(w http.ResponseWriter, r *http.Request, err error) {
+			if errors.Is(err, request.ErrNoTokenInRequest) {
+				writeChallenge(w, opts.Realm)
+				return
+			}
+			writeError(w, err, opts.Realm)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			credential, err := extractor.ExtractToken(r)
+			if err != nil {
+				if errors.Is(err, request.ErrNoTokenInRequest) && opts.CredentialsOptional {
+					next.ServeHTTP(w, r)
+					return
+				}
+				onError(w, r, err)
+				return
+			}
+
+			token, err := verifier.Verify(r.Context(), credential)
+			if err == nil && opts.Sessions != nil {
+				err = checkSession(r.Context(), opts.Sessions, token, opts.RequireSession)
+			}
+			if err != nil {
+				onError(w, r, err)
+				return
+			}
+			ctx := jwt.NewContext(r.Context(), token)
+			if opts.Actor != nil {
+				if ctx, err = opts.Actor.verify(ctx, r); err != nil {
+					onError(w, r, err)
+					return
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func newVerifier(verifier jwt.Verifier, parser *jwt.Parser, keyfunc jwt.Keyfunc, newClaims func() jwt.Claims) jwt.Verifier {
+	if verifier != nil {
+		return verifier
+	}
+	return &jwt.FallbackVerifier{Parser: parser, Keyfunc: keyfunc, NewClaims: newClaims}
+}
@@ -0,0 +1,76 @@
+package jwtmiddleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwtmiddleware"
+)
+
+func TestNew(t *testing.T) {
+	key := []byte("secret")
+	valid, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString(key)
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString([]byte("other"))
+
+	var tests = []struct {
+		name     string
+		optional bool
+		header   string
+		status   int
+		subject  string
+	}{
+		{"valid token", false, "Bearer " + valid, http.StatusOK, "user"},
+		{"invalid token", false, "Bearer " + forged, http.StatusUnauthorized, ""},
+		{"no token", false, "", http.StatusUnauthorized, ""},
+		{"no token, optional", true, "", http.StatusOK, ""},
+		{"invalid token, optional", true, "Bearer " + forged, http.StatusUnauthorized, ""},
+	}
+
+	for _, data := range tests {
+		var subject string
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token, ok := jwt.FromContext(r.Context()); ok {
+				subject, _ = token.Claims.(jwt.MapClaims)["sub"].(string)
+			}
+		})
+		handler := jwtmiddleware.New(jwtmiddleware.Options{
+			Keyfunc:             func(*jwt.Token) (interface{}, error) { return key, nil },
+			Realm:               "api",
+			CredentialsOptional: data.optional,
+		})(next)
+
+		r := httptest.NewRequest("GET", "/", nil)
+		if data.header != "" {
+			r.Header.Set("Authorization", data.header)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v. Got: %v", data.name, data.status, w.Code)
+		}
+		if subject != data.subject {
+			t.Errorf("[%v] Expected subject %q. Got: %q", data.name, data.subject, subject)
+		}
+		if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("[%v] Expected a WWW-Authenticate challenge", data.name)
+		}
+	}
+}
+
+func TestNew_errorHandler(t *testing.T) {
+	handler := jwtmiddleware.New(jwtmiddleware.Options{
+		Keyfunc: func(*jwt.Token) (interface{}, error) { return []byte("secret"), nil },
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			w.WriteHeader(http.StatusTeapot)
+		},
+	})(http.NotFoundHandler())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusTeapot {
+		t.Errorf("Expected custom error handler to respond. Got: %v", w.Code)
+	}
+}
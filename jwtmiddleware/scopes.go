@@ -0,0 +1,70 @@
+package jwtmiddleware
+
+import (
+	"net/http"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// RequireScopes returns middleware which only passes requests on to the next
+// handler if the verified token in the request context carries every one of
+// scopes. The token's claims must implement jwt.ScopesGetter.
+//
+// Requests without a token are answered with 401. Requests whose token lacks a
+// scope are answered with 403 and an insufficient_scope challenge naming the
+// required scopes, as described in
+// https://datatracker.ietf.org/doc/html/rfc6750#section-3.1
+func RequireScopes(scopes ...string) func(http.Handler) http.Handler {
+	return RequireScopesWithRealm("", scopes...)
+}
+
+// RequireScopesWithRealm is like RequireScopes but includes realm in the
+// WWW-Authenticate challenge.
+func RequireScopesWithRealm(realm string, scopes ...string) func(http.Handler) http.Handler {
+	return requireScopes(realm, func(granted jwt.Scopes) error {
+		return granted.Verify(scopes...)
+	})
+}
+
+// RequireAnyScope returns middleware which only passes requests on to the
+// next handler if the verified token in the request context carries at least
+// one of scopes, answering other requests as RequireScopes does.
+func RequireAnyScope(scopes ...string) func(http.Handler) http.Handler {
+	return RequireAnyScopeWithRealm("", scopes...)
+}
+
+// RequireAnyScopeWithRealm is like RequireAnyScope but includes realm in the
+// WWW-Authenticate challenge.
+func RequireAnyScopeWithRealm(realm string, scopes ...string) func(http.Handler) http.Handler {
+	return requireScopes(realm, func(granted jwt.Scopes) error {
+		return granted.VerifyAny(scopes...)
+	})
+}
+
+// requireScopes returns middleware which passes requests on to the next
+// handler if verify accepts the scopes of their token.
+func requireScopes(realm string, verify func(jwt.Scopes) error) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := jwt.FromContext(r.Context())
+			if !ok {
+				writeChallenge(w, realm)
+				return
+			}
+
+			var granted jwt.Scopes
+			if sg, ok := token.Claims.(jwt.ScopesGetter); ok {
+				var err error
+				if granted, err = sg.GetScopes(); err != nil {
+					writeError(w, err, realm)
+					return
+				}
+			}
+			if err := verify(granted); err != nil {
+				writeError(w, err, realm)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
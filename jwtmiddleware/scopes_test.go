@@ -0,0 +1,70 @@
+package jwtmiddleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwtmiddleware"
+)
+
+func TestRequireScopes(t *testing.T) {
+	var tests = []struct {
+		name      string
+		claims    jwt.Claims
+		status    int
+		challenge string
+	}{
+		{"no token", nil, http.StatusUnauthorized, `Bearer realm="api"`},
+		{"all scopes", jwt.MapClaims{"scope": "read:users write:users"}, http.StatusOK, ""},
+		{"missing scope", jwt.MapClaims{"scope": "read:users"}, http.StatusForbidden, `Bearer realm="api", error="insufficient_scope", error_description="The access token does not carry the required scope", scope="read:users write:users"`},
+		{"no scope claim", jwt.MapClaims{}, http.StatusForbidden, `Bearer realm="api", error="insufficient_scope", error_description="The access token does not carry the required scope", scope="read:users write:users"`},
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := jwtmiddleware.RequireScopesWithRealm("api", "read:users", "write:users")(ok)
+
+	for _, data := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		if data.claims != nil {
+			r = r.WithContext(jwt.NewContext(r.Context(), &jwt.Token{Claims: data.claims, Valid: true}))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v. Got: %v", data.name, data.status, w.Code)
+		}
+		if got := w.Header().Get("WWW-Authenticate"); got != data.challenge {
+			t.Errorf("[%v] Challenge mismatch.\nExpecting: %v\nGot: %v", data.name, data.challenge, got)
+		}
+	}
+}
+
+func TestRequireAnyScope(t *testing.T) {
+	var tests = []struct {
+		name   string
+		claims jwt.Claims
+		status int
+	}{
+		{"one scope", jwt.MapClaims{"scope": "write:users"}, http.StatusOK},
+		{"both scopes", jwt.MapClaims{"scope": "read:users write:users"}, http.StatusOK},
+		{"other scope", jwt.MapClaims{"scope": "read:orders"}, http.StatusForbidden},
+		{"no scope claim", jwt.MapClaims{}, http.StatusForbidden},
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := jwtmiddleware.RequireAnyScope("read:users", "write:users")(ok)
+
+	for _, data := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r = r.WithContext(jwt.NewContext(r.Context(), &jwt.Token{Claims: data.claims, Valid: true}))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v. Got: %v", data.name, data.status, w.Code)
+		}
+	}
+}
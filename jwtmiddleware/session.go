@@ -0,0 +1,46 @@
+package jwtmiddleware
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// SessionStore reports whether the server-side sessions tokens belong to are
+// still active, bridging stateless tokens and session revocation. The
+// logout.Sessions type implements it for sessions ended by OpenID Connect
+// back-channel logout.
+type SessionStore interface {
+	ActiveSession(ctx context.Context, sid string) (bool, error)
+}
+
+// sessionClaims is implemented by claims carrying a "sid" claim, such as
+// jwt.RegisteredClaims and jwt.MapClaims.
+type sessionClaims interface {
+	GetSessionID() (string, error)
+}
+
+func checkSession(ctx context.Context, store SessionStore, token *jwt.Token, required bool) error {
+	var sid string
+	if c, ok := token.Claims.(sessionClaims); ok {
+		var err error
+		if sid, err = c.GetSessionID(); err != nil {
+			return &jwt.ValidationError{Err: jwt.ErrSessionInvalid, Claim: "sid"}
+		}
+	}
+	if sid == "" {
+		if required {
+			return &jwt.ValidationError{Err: jwt.ErrTokenRequiredClaimMissing, Claim: "sid"}
+		}
+		return nil
+	}
+	active, err := store.ActiveSession(ctx, sid)
+	if err != nil {
+		return fmt.Errorf("jwtmiddleware: checking the session: %w", err)
+	}
+	if !active {
+		return &jwt.ValidationError{Err: jwt.ErrSessionInvalid, Claim: "sid"}
+	}
+	return nil
+}
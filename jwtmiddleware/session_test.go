@@ -0,0 +1,57 @@
+package jwtmiddleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwtmiddleware"
+)
+
+type sessionStore map[string]bool
+
+func (s sessionStore) ActiveSession(_ context.Context, sid string) (bool, error) {
+	return s[sid], nil
+}
+
+func TestNew_sessions(t *testing.T) {
+	key := []byte("secret")
+	sign := func(claims jwt.MapClaims) string {
+		s, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		return s
+	}
+	store := sessionStore{"active": true, "ended": false}
+
+	var tests = []struct {
+		name     string
+		token    string
+		required bool
+		status   int
+	}{
+		{"active session", sign(jwt.MapClaims{"sid": "active"}), false, http.StatusOK},
+		{"ended session", sign(jwt.MapClaims{"sid": "ended"}), false, http.StatusUnauthorized},
+		{"unknown session", sign(jwt.MapClaims{"sid": "unknown"}), false, http.StatusUnauthorized},
+		{"malformed session", sign(jwt.MapClaims{"sid": 1}), false, http.StatusUnauthorized},
+		{"no session", sign(jwt.MapClaims{}), false, http.StatusOK},
+		{"no session, required", sign(jwt.MapClaims{}), true, http.StatusUnauthorized},
+	}
+
+	for _, data := range tests {
+		handler := jwtmiddleware.New(jwtmiddleware.Options{
+			Keyfunc:        func(*jwt.Token) (interface{}, error) { return key, nil },
+			Sessions:       store,
+			RequireSession: data.required,
+		})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "Bearer "+data.token)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v. Got: %v", data.name, data.status, w.Code)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Package jwtotel reports the parsing and signing of tokens, and the events
+// of in-memory caches, to OpenTelemetry, through jwt.Hooks.
+//
+// Durations are recorded in the histograms "jwt.parse.duration" and
+// "jwt.sign.duration", in seconds, labelled with the algorithm and, for
+// failures, the jwt.FailureCategory of the error, so that spikes of invalid
+// signatures or expired tokens can be alerted on. The algorithm of a parsed
+// token is one of the registered signing methods, or jwt.UnknownAlgorithm, so
+// that tokens presented by anyone cannot create new series. Every operation
+// is also traced as a span, a child of the span in the context passed to
+// jwt.Parser.ParseWithContext, which also carries the key ID.
+//
+// It is a separate module, so that the jwt package does not depend on
+// OpenTelemetry.
+package jwtotel
@@ -0,0 +1,128 @@
+package jwtotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the meter and tracer.
+const ScopeName = "github.com/chanced/go-jwt/v4/jwtotel"
+
+// Attribute keys.
+const (
+	AlgorithmKey = attribute.Key("jwt.algorithm")
+	KeyIDKey     = attribute.Key("jwt.key_id")
+	FailureKey   = attribute.Key("jwt.failure")
+	CacheKey     = attribute.Key("jwt.cache")
+	EventKey     = attribute.Key("jwt.cache.event")
+)
+
+// Option configures NewHooks.
+type Option func(*config)
+
+type config struct {
+	meterProvider  metric.MeterProvider
+	tracerProvider trace.TracerProvider
+}
+
+// WithMeterProvider sets the MeterProvider, which defaults to the global one.
+func WithMeterProvider(p metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = p
+	}
+}
+
+// WithTracerProvider sets the TracerProvider, which defaults to the global
+// one.
+func WithTracerProvider(p trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = p
+	}
+}
+
+type instruments struct {
+	tracer        trace.Tracer
+	parseDuration metric.Float64Histogram
+	signDuration  metric.Float64Histogram
+	cacheEvents   metric.Int64Counter
+}
+
+// NewHooks returns hooks recording to OpenTelemetry: OnParse, OnSign and
+// OnCacheEvent are set. Set them on a Parser with jwt.WithParserHooks, on a
+// Signer with Signer.WithHooks, and on in-memory stores through their Hooks
+// field.
+func NewHooks(opts ...Option) (jwt.Hooks, error) {
+	c := config{meterProvider: otel.GetMeterProvider(), tracerProvider: otel.GetTracerProvider()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	meter := c.meterProvider.Meter(ScopeName)
+	i := &instruments{tracer: c.tracerProvider.Tracer(ScopeName)}
+	var err error
+	if i.parseDuration, err = meter.Float64Histogram("jwt.parse.duration",
+		metric.WithDescription("Duration of parsing and verifying tokens"), metric.WithUnit("s")); err != nil {
+		return jwt.Hooks{}, err
+	}
+	if i.signDuration, err = meter.Float64Histogram("jwt.sign.duration",
+		metric.WithDescription("Duration of signing tokens"), metric.WithUnit("s")); err != nil {
+		return jwt.Hooks{}, err
+	}
+	if i.cacheEvents, err = meter.Int64Counter("jwt.cache.events",
+		metric.WithDescription("Lookups and evictions of in-memory caches")); err != nil {
+		return jwt.Hooks{}, err
+	}
+	return jwt.Hooks{OnParse: i.onParse, OnSign: i.onSign, OnCacheEvent: i.onCacheEvent}, nil
+}
+
+// maxKeyIDSize bounds the key IDs recorded on spans, which are chosen by
+// whoever presented the token.
+const maxKeyIDSize = 64
+
+func (i *instruments) onParse(ctx context.Context, e jwt.ParseEvent) {
+	attrs := outcome(e.Algorithm, e.Failure)
+	// The key ID is left off the metric, as every distinct value would be a
+	// new series.
+	i.parseDuration.Record(ctx, e.Duration.Seconds(), metric.WithAttributes(attrs...))
+	if kid := e.KeyID; kid != "" {
+		if len(kid) > maxKeyIDSize {
+			kid = kid[:maxKeyIDSize]
+		}
+		attrs = append(attrs, KeyIDKey.String(kid))
+	}
+	_, span := i.tracer.Start(ctx, "jwt.Parse", trace.WithTimestamp(e.Start), trace.WithAttributes(attrs...))
+	endSpan(span, e.Err, e.Start.Add(e.Duration))
+}
+
+func (i *instruments) onSign(e jwt.SignEvent) {
+	ctx := context.Background()
+	attrs := outcome(e.Algorithm, e.Failure)
+	i.signDuration.Record(ctx, e.Duration.Seconds(), metric.WithAttributes(attrs...))
+	_, span := i.tracer.Start(ctx, "jwt.Sign", trace.WithTimestamp(e.Start), trace.WithAttributes(attrs...))
+	endSpan(span, e.Err, e.Start.Add(e.Duration))
+}
+
+func (i *instruments) onCacheEvent(cache string, event jwt.CacheEvent) {
+	i.cacheEvents.Add(context.Background(), 1, metric.WithAttributes(CacheKey.String(cache), EventKey.String(event.String())))
+}
+
+func outcome(alg, failure string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{AlgorithmKey.String(alg)}
+	if failure != "" {
+		attrs = append(attrs, FailureKey.String(failure))
+	}
+	return attrs
+}
+
+func endSpan(span trace.Span, err error, end time.Time) {
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(end))
+}
@@ -0,0 +1,85 @@
+package jwtotel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwtotel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewHooks(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	spans := tracetest.NewSpanRecorder()
+	hooks, err := jwtotel.NewHooks(
+		jwtotel.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		jwtotel.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := []byte("secret")
+	signer, err := jwt.NewSigner(jwt.SigningMethodHS256, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := signer.WithHooks(hooks).Sign(jwt.MapClaims{"sub": "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := jwt.NewParser(jwt.WithParserHooks(hooks))
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+	if _, err = p.ParseWithContext(context.Background(), signed, jwt.MapClaims{}, keyFunc); err != nil {
+		t.Fatal(err)
+	}
+	p.ParseWithContext(context.Background(), signed+"x", jwt.MapClaims{}, keyFunc)
+	(&jwt.MemoryBlocklist{Hooks: hooks}).Blocked(context.Background(), "jti")
+
+	ended := spans.Ended()
+	if len(ended) != 3 || ended[0].Name() != "jwt.Sign" || ended[1].Name() != "jwt.Parse" {
+		t.Fatalf("Unexpected spans: %v", ended)
+	}
+	if ended[1].Status().Code == codes.Error || ended[2].Status().Code != codes.Error {
+		t.Errorf("Unexpected span statuses: %v, %v", ended[1].Status(), ended[2].Status())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err = reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]map[attribute.Distinct]uint64{}
+	var cacheEvents int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				counts[m.Name] = map[attribute.Distinct]uint64{}
+				for _, dp := range data.DataPoints {
+					counts[m.Name][dp.Attributes.Equivalent()] = dp.Count
+				}
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					cacheEvents += dp.Value
+				}
+			}
+		}
+	}
+	failed := attribute.NewSet(jwtotel.AlgorithmKey.String("HS256"), jwtotel.FailureKey.String(jwt.FailureSignature))
+	ok := attribute.NewSet(jwtotel.AlgorithmKey.String("HS256"))
+	if parse := counts["jwt.parse.duration"]; parse[ok.Equivalent()] != 1 || parse[failed.Equivalent()] != 1 {
+		t.Errorf("Unexpected parse durations: %v", parse)
+	}
+	if sign := counts["jwt.sign.duration"]; sign[ok.Equivalent()] != 1 {
+		t.Errorf("Unexpected sign durations: %v", sign)
+	}
+	if cacheEvents != 1 {
+		t.Errorf("Expected 1 cache event, got %d", cacheEvents)
+	}
+}
@@ -0,0 +1,114 @@
+package jwttest
+
+import (
+	"crypto"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// Issuer issues tokens signed with an ephemeral key, for tests of code which
+// verifies them. Its methods panic on failure, which only happens if the
+// claims cannot be encoded.
+type Issuer struct {
+	Method    jwt.SigningMethod // The signing method
+	Key       interface{}       // The key to sign with
+	PublicKey interface{}       // The key to verify with. For HMAC methods it is Key
+	KeyID     string            // The "kid" header of minted tokens, the SHA-256 thumbprint of the key
+	JWK       []byte            // The public JWK of the key, or the secret itself for HMAC methods
+}
+
+// NewIssuer returns an Issuer with a fresh key for method, generated by
+// jwt.GenerateKey. It panics if method is not registered.
+func NewIssuer(method jwt.SigningMethod) *Issuer {
+	key, jwk, err := jwt.GenerateKey(method.Alg())
+	if err != nil {
+		panic(err)
+	}
+	pub := key
+	if signer, ok := key.(crypto.Signer); ok {
+		pub = signer.Public()
+	}
+	var header struct {
+		KeyID string `json:"kid"`
+	}
+	if err = json.Unmarshal(jwk, &header); err != nil {
+		panic(err)
+	}
+	return &Issuer{Method: method, Key: key, PublicKey: pub, KeyID: header.KeyID, JWK: jwk}
+}
+
+// Mint returns a token carrying claims, signed with the Issuer's key.
+func (i *Issuer) Mint(claims jwt.Claims) string {
+	s, err := jwt.NewWithClaims(i.Method, claims, jwt.WithKeyID(i.KeyID)).SignedString(i.Key)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// MintExpired returns a token like Mint, but which expired an hour ago, per
+// jwt.TimeFunc. Its "iat" and "nbf" claims, if any, are moved before then.
+func (i *Issuer) MintExpired(claims jwt.Claims) string {
+	m := toMap(claims)
+	now := jwt.TimeFunc()
+	m["exp"] = jwt.NewNumericDate(now.Add(-time.Hour))
+	for _, name := range []string{"iat", "nbf"} {
+		if _, ok := m[name]; ok {
+			m[name] = jwt.NewNumericDate(now.Add(-2 * time.Hour))
+		}
+	}
+	return i.Mint(m)
+}
+
+// MintTampered returns a token like Mint, whose claims were altered after it
+// was signed, by adding a "tampered" claim. It is well formed, but its
+// signature does not verify.
+func (i *Issuer) MintTampered(claims jwt.Claims) string {
+	parts := strings.Split(i.Mint(claims), ".")
+	m := toMap(claims)
+	m["tampered"] = true
+	payload, err := json.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	parts[1] = jwt.EncodeSegment(payload)
+	return strings.Join(parts, ".")
+}
+
+// Keyfunc is a jwt.Keyfunc returning the Issuer's verification key.
+func (i *Issuer) Keyfunc(*jwt.Token) (interface{}, error) {
+	return i.PublicKey, nil
+}
+
+// JWKS returns a JWK Set holding the Issuer's JWK.
+func (i *Issuer) JWKS() []byte {
+	return []byte(`{"keys":[` + string(i.JWK) + `]}`)
+}
+
+// NewJWKSServer starts an httptest.Server serving the Issuer's JWKS at every
+// path. The caller must close it.
+func (i *Issuer) NewJWKSServer() *httptest.Server {
+	jwks := i.JWKS()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jwks)
+	}))
+}
+
+// toMap returns claims as a jwt.MapClaims, by way of their JSON encoding.
+func toMap(claims jwt.Claims) jwt.MapClaims {
+	b, err := json.Marshal(claims)
+	if err != nil {
+		panic(err)
+	}
+	m := jwt.MapClaims{}
+	if err = json.Unmarshal(b, &m); err != nil {
+		panic(err)
+	}
+	return m
+}
@@ -0,0 +1,66 @@
+package jwttest_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwk"
+	"github.com/chanced/go-jwt/v4/jwttest"
+)
+
+func TestIssuer(t *testing.T) {
+	methods := []jwt.SigningMethod{jwt.SigningMethodHS256, jwt.SigningMethodES256, jwt.SigningMethodEdDSA, jwt.SigningMethodRS256}
+	for _, method := range methods {
+		t.Run(method.Alg(), func(t *testing.T) {
+			iss := jwttest.NewIssuer(method)
+			claims := jwt.MapClaims{"sub": "subject", "iat": jwt.NewNumericDate(time.Now())}
+
+			token, err := jwt.Parse(iss.Mint(claims), iss.Keyfunc)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if token.Header["kid"] != iss.KeyID || token.Method != method {
+				t.Errorf("Unexpected header: %v", token.Header)
+			}
+
+			if _, err = jwt.Parse(iss.MintExpired(claims), iss.Keyfunc); !errors.Is(err, jwt.ErrTokenExpired) {
+				t.Errorf("Expected ErrTokenExpired, got %v", err)
+			}
+			if _, err = jwt.Parse(iss.MintTampered(claims), iss.Keyfunc); !errors.Is(err, jwt.ErrSignatureInvalid) {
+				t.Errorf("Expected ErrSignatureInvalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestIssuer_NewJWKSServer(t *testing.T) {
+	iss := jwttest.NewIssuer(jwt.SigningMethodES256)
+	srv := iss.NewJWKSServer()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := jwk.Parse(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err = jwt.Parse(iss.Mint(jwt.MapClaims{"sub": "subject"}), set.Keyfunc); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	other := jwttest.NewIssuer(jwt.SigningMethodES256)
+	if _, err = jwt.Parse(other.Mint(jwt.MapClaims{"sub": "subject"}), set.Keyfunc); err == nil {
+		t.Error("Expected a token of another issuer to be rejected")
+	}
+}
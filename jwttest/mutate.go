@@ -0,0 +1,101 @@
+package jwttest
+
+import (
+	"strings"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// HugeHeaderSize is the size of the oversized header in the variants of
+// Mutate, in bytes before encoding.
+const HugeHeaderSize = 1 << 20
+
+// Mutate returns malformed variants of token, a JWT in the compact
+// serialization, for fuzzing and testing the handling of hostile input:
+// truncated and missing segments, stray separators and padding, invalid
+// base64url and UTF-8, headers and claims which are not JSON objects or hold
+// claims of the wrong type, a "none" algorithm, and a header of
+// HugeHeaderSize bytes. The variants are the same for the same token, and
+// suit f.Add as the seed corpus of a fuzz target:
+//
+//	for _, s := range jwttest.Mutate(token) {
+//		f.Add(s)
+//	}
+//
+// Services should reject every variant without panicking. Parsers accepting
+// line breaks in segments, as jwt.Parser does unless StrictDecoding is set,
+// accept the variants adding one after the signature.
+func Mutate(token string) []string {
+	parts := strings.Split(token, ".")
+	for len(parts) < 3 {
+		parts = append(parts, "")
+	}
+	header, claims, sig := parts[0], parts[1], parts[2]
+	join := func(segments ...string) string { return strings.Join(segments, ".") }
+	enc := func(s string) string { return jwt.EncodeSegment([]byte(s)) }
+
+	variants := []string{
+		// Truncated and missing segments
+		"",
+		".",
+		"..",
+		"...",
+		header,
+		join(header, claims),
+		join(header, claims, ""),
+		join(header, "", sig),
+		join("", claims, sig),
+		join(header[:len(header)/2], claims, sig),
+		join(header, claims[:len(claims)/2], sig),
+		join(header, claims, sig[:len(sig)/2]),
+		token[:len(token)/2],
+		token[:len(token)-len(token)/8],
+
+		// Stray separators, padding and whitespace
+		token + ".",
+		"." + token,
+		join(header, claims, sig, sig),
+		join(header, claims, sig, "", ""),
+		join(header+"==", claims, sig),
+		join(header, claims+"=", sig+"=="),
+		" " + token,
+		token + "\n",
+		join(header, claims+"\n", sig),
+		"Bearer " + token,
+
+		// Invalid base64url and UTF-8
+		join(header+"*", claims, sig),
+		join(header, "!"+claims, sig),
+		join(header, claims, sig+"+/"),
+		join(header, claims, "\xff\xfe"),
+		join("\xff"+header, claims, sig),
+		join(header, claims+"\xc3\x28", sig),
+		join(enc(`{"alg":"HS256","kid":"\xff\xfe"}`), claims, sig),
+		join(header, enc(`{"sub":"\xc3\x28"}`), sig),
+		join(header, enc("\xff\xfe\xfd"), sig),
+
+		// Headers and claims which are not JSON objects
+		join(enc(`null`), claims, sig),
+		join(enc(`[]`), claims, sig),
+		join(enc(`"HS256"`), claims, sig),
+		join(enc(`{"alg":"HS256"`), claims, sig),
+		join(enc(`{}`), claims, sig),
+		join(header, enc(`null`), sig),
+		join(header, enc(`[1,2,3]`), sig),
+		join(header, enc(`{"sub":`), sig),
+		join(header, enc(strings.Repeat("[", 10000)), sig),
+
+		// Headers and claims of the wrong type
+		join(enc(`{"alg":256}`), claims, sig),
+		join(enc(`{"alg":"none"}`), claims, ""),
+		join(enc(`{"alg":"HS256","crit":["exp"]}`), claims, sig),
+		join(enc(`{"alg":"HS256","alg":"none"}`), claims, sig),
+		join(header, enc(`{"exp":"tomorrow","nbf":true,"iat":[]}`), sig),
+		join(header, enc(`{"exp":1e400,"aud":{"a":1}}`), sig),
+		join(header, enc(`{"exp":-9223372036854775809,"iss":1}`), sig),
+
+		// An oversized header
+		join(enc(`{"alg":"HS256","x":"`+strings.Repeat("x", HugeHeaderSize)+`"}`), claims, sig),
+	}
+	return variants
+}
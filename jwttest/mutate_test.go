@@ -0,0 +1,39 @@
+package jwttest_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwttest"
+)
+
+func TestMutate(t *testing.T) {
+	key := []byte("secret")
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "subject"}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	variants := jwttest.Mutate(token)
+	if !reflect.DeepEqual(variants, jwttest.Mutate(token)) {
+		t.Error("variants of the same token differ")
+	}
+	// Line breaks and padding are only rejected by a strict parser.
+	parser := jwt.NewParser(jwt.WithValidMethods("HS256"), jwt.WithStrictDecoding())
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+	for i, s := range variants {
+		if s == token {
+			t.Errorf("variant %d is the token itself", i)
+		}
+		if _, err := parser.Parse(s, keyFunc); err == nil {
+			t.Errorf("variant %d was accepted: %.80q", i, s)
+		}
+	}
+}
+
+func TestMutate_empty(t *testing.T) {
+	if len(jwttest.Mutate("")) == 0 {
+		t.Error("no variants of the empty token")
+	}
+}
@@ -0,0 +1,64 @@
+// Package jwttest provides helpers for testing code which issues or verifies
+// tokens.
+package jwttest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// DeterministicReader is an io.Reader which produces a stream of bytes fully
+// determined by its seed: the SHA-256 digests of the seed followed by a block
+// counter. It must only be used in tests.
+//
+// Since Go 1.26, crypto/rsa, crypto/ecdsa and crypto/ecdh do not read from it
+// unless GODEBUG=cryptocustomrand=1 is set; see jwt.RandReader. To make their
+// keys and signatures reproducible, use testing/cryptotest.SetGlobalRandom.
+type DeterministicReader struct {
+	mu      sync.Mutex
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+// NewDeterministicReader returns a DeterministicReader for seed.
+func NewDeterministicReader(seed []byte) *DeterministicReader {
+	return &DeterministicReader{seed: append([]byte(nil), seed...)}
+}
+
+// Read fills p and never fails.
+func (r *DeterministicReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			h := sha256.New()
+			h.Write(r.seed)
+			var ctr [8]byte
+			binary.BigEndian.PutUint64(ctr[:], r.counter)
+			h.Write(ctr[:])
+			r.buf = h.Sum(nil)
+			r.counter++
+		}
+		c := copy(p[n:], r.buf)
+		r.buf = r.buf[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// SetRandReader replaces jwt.RandReader with r for the duration of the test,
+// restoring the previous reader when it finishes. Tests using it must not run
+// in parallel.
+func SetRandReader(tb testing.TB, r io.Reader) {
+	tb.Helper()
+	prev := jwt.RandReader
+	jwt.RandReader = r
+	tb.Cleanup(func() { jwt.RandReader = prev })
+}
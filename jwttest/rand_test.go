@@ -0,0 +1,53 @@
+package jwttest_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwttest"
+)
+
+func TestDeterministicReader(t *testing.T) {
+	a, b := make([]byte, 100), make([]byte, 100)
+	if _, err := io.ReadFull(jwttest.NewDeterministicReader([]byte("seed")), a); err != nil {
+		t.Fatal(err)
+	}
+	r := jwttest.NewDeterministicReader([]byte("seed"))
+	// Read in uneven chunks; the stream must not depend on them.
+	if _, err := io.ReadFull(r, b[:7]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(r, b[7:]); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Error("streams with the same seed differ")
+	}
+
+	c := make([]byte, 100)
+	io.ReadFull(jwttest.NewDeterministicReader([]byte("other")), c)
+	if bytes.Equal(a, c) {
+		t.Error("streams with different seeds are equal")
+	}
+}
+
+func TestSetRandReader(t *testing.T) {
+	prev := jwt.RandReader
+	t.Run("inner", func(t *testing.T) {
+		jwttest.SetRandReader(t, jwttest.NewDeterministicReader([]byte("seed")))
+		first, err := jwt.NewID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		jwttest.SetRandReader(t, jwttest.NewDeterministicReader([]byte("seed")))
+		second, _ := jwt.NewID()
+		if first != second {
+			t.Errorf("NewID() = %q, then %q; want equal", first, second)
+		}
+	})
+	if jwt.RandReader != prev {
+		t.Error("RandReader was not restored")
+	}
+}
@@ -0,0 +1,100 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"sync"
+)
+
+// KeyRegistry holds verification keys registered by key ID and algorithm.
+// Its Keyfunc selects the key named by a token's "kid" header and rejects
+// tokens whose "alg" header differs from the algorithm the key was registered
+// for, which prevents a key from being used with an algorithm it was not
+// intended for.
+//
+// The zero value is ready to use. A KeyRegistry is safe for concurrent use.
+type KeyRegistry struct {
+	mu   sync.RWMutex
+	keys map[string]registeredKey
+}
+
+type registeredKey struct {
+	method SigningMethod
+	key    interface{}
+}
+
+// Register adds key under kid for use with the signing method alg, replacing
+// any key previously registered under kid. Private keys are reduced to their
+// public half. An error is returned if alg is not registered or key is not of
+// a type alg verifies with.
+func (r *KeyRegistry) Register(kid, alg string, key interface{}) error {
+	method := GetSigningMethod(alg)
+	if method == nil {
+		return &UnregisteredSigningMethodError{Alg: alg}
+	}
+	key = publicKey(key)
+	if err := checkKeyType(method, key); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.keys == nil {
+		r.keys = make(map[string]registeredKey)
+	}
+	r.keys[kid] = registeredKey{method: method, key: key}
+	return nil
+}
+
+// Remove removes the key registered under kid, if any.
+func (r *KeyRegistry) Remove(kid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.keys, kid)
+}
+
+// Keyfunc is a Keyfunc returning the key registered under the token's "kid"
+// header. It fails with ErrUnknownKeyID if no key is registered for the kid,
+// and with ErrKeyAlgorithmMismatch if the token's algorithm differs from the
+// key's.
+func (r *KeyRegistry) Keyfunc(token *Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	r.mu.RLock()
+	k, ok := r.keys[kid]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, truncateHeader(kid))
+	}
+	if token.Method == nil || token.Method.Alg() != k.method.Alg() {
+		return nil, ErrKeyAlgorithmMismatch
+	}
+	return k.key, nil
+}
+
+// checkKeyType checks that key is a verification key for method.
+func checkKeyType(method SigningMethod, key interface{}) error {
+	ok := false
+	switch m := method.(type) {
+	case *SigningMethodHMAC:
+		_, ok = key.([]byte)
+	case *SigningMethodRSA, *SigningMethodRSAPSS:
+		_, ok = key.(*rsa.PublicKey)
+	case *SigningMethodECDSA:
+		var k *ecdsa.PublicKey
+		if k, ok = key.(*ecdsa.PublicKey); ok {
+			ok = k.Curve.Params().BitSize == m.CurveBits
+		}
+	case *SigningMethodEd25519:
+		_, ok = key.(ed25519.PublicKey)
+	default:
+		// Custom signing methods verify the key type themselves
+		ok = true
+	}
+	if !ok {
+		return ErrKeyAlgorithmMismatch
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/test"
+)
+
+func TestKeyRegistry(t *testing.T) {
+	rsaKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	hmacKey := []byte("secret")
+
+	var r jwt.KeyRegistry
+	if err := r.Register("rsa", "RS256", rsaKey); err != nil {
+		t.Fatalf("Error registering key: %v", err)
+	}
+	if err := r.Register("hmac", "HS256", hmacKey); err != nil {
+		t.Fatalf("Error registering key: %v", err)
+	}
+	if err := r.Register("bad", "ES256", hmacKey); !errors.Is(err, jwt.ErrKeyAlgorithmMismatch) {
+		t.Errorf("Expected ErrKeyAlgorithmMismatch registering key. Got: %v", err)
+	}
+	if err := r.Register("bad", "XX999", hmacKey); !errors.Is(err, jwt.ErrUnregisteredSigningMethod) {
+		t.Errorf("Expected ErrUnregisteredSigningMethod registering key. Got: %v", err)
+	}
+
+	var tests = []struct {
+		name   string
+		method jwt.SigningMethod
+		kid    string
+		key    interface{}
+		err    error
+	}{
+		{"rsa", jwt.SigningMethodRS256, "rsa", rsaKey, nil},
+		{"hmac", jwt.SigningMethodHS256, "hmac", hmacKey, nil},
+		{"unknown kid", jwt.SigningMethodHS256, "other", hmacKey, jwt.ErrUnknownKeyID},
+		{"algorithm mismatch", jwt.SigningMethodHS384, "hmac", hmacKey, jwt.ErrKeyAlgorithmMismatch},
+	}
+
+	for _, data := range tests {
+		token := jwt.New(data.method)
+		token.Header["kid"] = data.kid
+		signed, err := token.SignedString(data.key)
+		if err != nil {
+			t.Errorf("[%v] Error signing token: %v", data.name, err)
+			continue
+		}
+		_, err = jwt.Parse(signed, r.Keyfunc)
+		if data.err == nil && err != nil {
+			t.Errorf("[%v] Error parsing token: %v", data.name, err)
+		} else if !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+
+	r.Remove("hmac")
+	signed, _ := jwt.New(jwt.SigningMethodHS256).SignedString(hmacKey)
+	if _, err := jwt.Parse(signed, r.Keyfunc); !errors.Is(err, jwt.ErrUnknownKeyID) {
+		t.Errorf("Expected ErrUnknownKeyID after removal. Got: %v", err)
+	}
+}
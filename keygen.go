@@ -0,0 +1,135 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
+)
+
+// KeyGenOption configures GenerateKey and GenerateKeyPair.
+type KeyGenOption func(*keyGenConfig)
+
+type keyGenConfig struct {
+	rsaBits int
+}
+
+// WithRSAKeySize sets the modulus size in bits of generated RSA keys,
+// overriding the size chosen for the algorithm.
+func WithRSAKeySize(bits int) KeyGenOption {
+	return func(c *keyGenConfig) {
+		c.rsaBits = bits
+	}
+}
+
+// rsaKeySizes are the modulus sizes of RSA keys generated for each hash size,
+// following the security strength of the hash.
+var rsaKeySizes = map[crypto.Hash]int{
+	crypto.SHA256: 2048,
+	crypto.SHA384: 3072,
+	crypto.SHA512: 4096,
+}
+
+// GenerateKey generates a fresh signing key for the registered algorithm alg,
+// read from RandReader, along with its public JWK. RSA keys are 2048, 3072 or
+// 4096 bits for the SHA-256, SHA-384 and SHA-512 variants, unless set with
+// WithRSAKeySize; ECDSA keys use the curve of the algorithm; HMAC secrets are
+// as long as the output of the hash; EdDSA keys are Ed25519 keys.
+//
+// The key is an *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey or
+// []byte, suitable for signing with the method of alg. The JWK carries "alg",
+// "use" and a "kid" set to the base64url SHA-256 thumbprint of the key. For
+// HMAC algorithms, which have no public key, it holds the secret itself.
+func GenerateKey(alg string, opts ...KeyGenOption) (key interface{}, jwk []byte, err error) {
+	method := GetSigningMethod(alg)
+	if method == nil {
+		return nil, nil, &UnregisteredSigningMethodError{Alg: alg}
+	}
+	if key, err = generateKey(method, opts); err != nil {
+		return nil, nil, err
+	}
+	if jwk, err = publicJWK(key, alg); err != nil {
+		return nil, nil, err
+	}
+	return key, jwk, nil
+}
+
+// GenerateKeyPair generates a fresh key pair for method, as described for
+// GenerateKey, returning the key to sign with and the key to verify with. It
+// lets test suites and development environments sign and verify tokens
+// without checking sample keys into a repository:
+//
+//	signKey, verifyKey, err := jwt.GenerateKeyPair(jwt.SigningMethodES256)
+//
+// The verification key is the *rsa.PublicKey, *ecdsa.PublicKey or
+// ed25519.PublicKey of the signing key; for HMAC methods both are the same
+// secret.
+func GenerateKeyPair(method SigningMethod, opts ...KeyGenOption) (signKey, verifyKey interface{}, err error) {
+	if signKey, err = generateKey(method, opts); err != nil {
+		return nil, nil, err
+	}
+	return signKey, publicKey(signKey), nil
+}
+
+func generateKey(method SigningMethod, opts []KeyGenOption) (interface{}, error) {
+	var c keyGenConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	switch m := method.(type) {
+	case *SigningMethodHMAC:
+		secret := make([]byte, m.Hash.Size())
+		if _, err := io.ReadFull(RandReader, secret); err != nil {
+			return nil, err
+		}
+		return secret, nil
+	case *SigningMethodRSA:
+		return generateRSAKey(m.Hash, c.rsaBits)
+	case *SigningMethodRSAPSS:
+		return generateRSAKey(m.Hash, c.rsaBits)
+	case *SigningMethodECDSA:
+		var curve elliptic.Curve
+		switch m.CurveBits {
+		case 256:
+			curve = elliptic.P256()
+		case 384:
+			curve = elliptic.P384()
+		case 521:
+			curve = elliptic.P521()
+		default:
+			return nil, ErrInvalidKeyType
+		}
+		return ecdsa.GenerateKey(curve, RandReader)
+	case *SigningMethodEd25519:
+		_, key, err := ed25519.GenerateKey(RandReader)
+		return key, err
+	}
+	return nil, ErrInvalidKeyType
+}
+
+func generateRSAKey(hash crypto.Hash, bits int) (*rsa.PrivateKey, error) {
+	if bits <= 0 {
+		if bits = rsaKeySizes[hash]; bits == 0 {
+			bits = 2048
+		}
+	}
+	return rsa.GenerateKey(RandReader, bits)
+}
+
+// publicJWK encodes the public half of key as a JWK for alg.
+func publicJWK(key interface{}, alg string) ([]byte, error) {
+	members, err := jwkMembers(publicKey(key))
+	if err != nil {
+		return nil, err
+	}
+	tp, err := Thumbprint(key, crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	members["alg"], members["use"], members["kid"] = alg, "sig", EncodeSegment(tp)
+	return json.Marshal(members)
+}
@@ -0,0 +1,87 @@
+package jwt_test
+
+import (
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwk"
+)
+
+func TestGenerateKey(t *testing.T) {
+	for _, alg := range []string{"HS256", "HS512", "RS256", "PS256", "ES256", "ES384", "ES512", "EdDSA"} {
+		key, jwkJSON, err := jwt.GenerateKey(alg)
+		if err != nil {
+			t.Errorf("[%v] Unexpected error: %v", alg, err)
+			continue
+		}
+		method := jwt.GetSigningMethod(alg)
+		token, err := jwt.NewWithClaims(method, jwt.MapClaims{"sub": "a"}).SignedString(key)
+		if err != nil {
+			t.Errorf("[%v] Error signing with the generated key: %v", alg, err)
+			continue
+		}
+
+		set, err := jwk.Parse([]byte(`{"keys":[` + string(jwkJSON) + `]}`))
+		if err != nil {
+			t.Errorf("[%v] Error parsing the JWK %s: %v", alg, jwkJSON, err)
+			continue
+		}
+		if k := set.Keys[0]; k.Algorithm != alg || k.Use != "sig" || k.KeyID == "" {
+			t.Errorf("[%v] Unexpected JWK members: %s", alg, jwkJSON)
+		}
+		if _, err = jwt.Parse(token, set.Keyfunc); err != nil {
+			t.Errorf("[%v] Error verifying with the JWK: %v", alg, err)
+		}
+	}
+}
+
+func TestGenerateKey_sizes(t *testing.T) {
+	key, _, err := jwt.GenerateKey("RS256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bits := key.(*rsa.PrivateKey).N.BitLen(); bits != 2048 {
+		t.Errorf("RS256 key is %d bits, want 2048", bits)
+	}
+	key, _, err = jwt.GenerateKey("RS512", jwt.WithRSAKeySize(1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bits := key.(*rsa.PrivateKey).N.BitLen(); bits != 1024 {
+		t.Errorf("RS512 key with WithRSAKeySize(1024) is %d bits", bits)
+	}
+	key, _, _ = jwt.GenerateKey("HS384")
+	if n := len(key.([]byte)); n != 48 {
+		t.Errorf("HS384 secret is %d bytes, want 48", n)
+	}
+	if _, _, err = jwt.GenerateKey("XX256"); !errors.Is(err, jwt.ErrUnregisteredSigningMethod) {
+		t.Errorf("expected ErrUnregisteredSigningMethod, got %v", err)
+	}
+}
+
+func TestGenerateKeyPair(t *testing.T) {
+	methods := []jwt.SigningMethod{
+		jwt.SigningMethodHS256, jwt.SigningMethodRS256, jwt.SigningMethodPS384,
+		jwt.SigningMethodES256, jwt.SigningMethodES512, jwt.SigningMethodEdDSA,
+	}
+	for _, method := range methods {
+		signKey, verifyKey, err := jwt.GenerateKeyPair(method)
+		if err != nil {
+			t.Errorf("[%v] Unexpected error: %v", method.Alg(), err)
+			continue
+		}
+		token, err := jwt.NewWithClaims(method, jwt.MapClaims{"sub": "a"}).SignedString(signKey)
+		if err != nil {
+			t.Errorf("[%v] Error signing with the generated key: %v", method.Alg(), err)
+			continue
+		}
+		if _, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return verifyKey, nil }); err != nil {
+			t.Errorf("[%v] Error verifying with the generated key: %v", method.Alg(), err)
+		}
+	}
+	if _, _, err := jwt.GenerateKeyPair(jwt.SigningMethodNone); !errors.Is(err, jwt.ErrInvalidKeyType) {
+		t.Errorf("expected ErrInvalidKeyType, got %v", err)
+	}
+}
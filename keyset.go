@@ -0,0 +1,117 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+)
+
+// VerificationKeySet holds several verification keys, optionally identified
+// by key ID. It may be returned as the key from a Keyfunc: the signature is
+// first verified with the keys whose ID matches the token's "kid" header, and
+// then with every other key. This allows tokens signed with either the old or
+// the new key to be accepted while a signing key is rotated.
+//
+// A VerificationKeySet must not be modified while it is in use.
+type VerificationKeySet struct {
+	Keys []VerificationKey
+}
+
+// VerificationKey is a member of a VerificationKeySet.
+type VerificationKey struct {
+	KeyID string      // Optional. Matched against the "kid" header
+	Key   interface{} // The verification key
+}
+
+// NewVerificationKeySet creates a VerificationKeySet from keys without IDs.
+func NewVerificationKeySet(keys ...interface{}) *VerificationKeySet {
+	s := &VerificationKeySet{}
+	for _, key := range keys {
+		s.Add("", key)
+	}
+	return s
+}
+
+// Add adds key, identified by kid, to the set. kid may be empty.
+func (s *VerificationKeySet) Add(kid string, key interface{}) {
+	s.Keys = append(s.Keys, VerificationKey{KeyID: kid, Key: key})
+}
+
+// verify verifies the signature with each key in turn, those matching the
+// "kid" header first, until one succeeds.
+func (s *VerificationKeySet) verify(token *Token, signingString string) error {
+	kid, _ := token.Header["kid"].(string)
+	ordered := make([]VerificationKey, 0, len(s.Keys))
+	for _, k := range s.Keys {
+		if kid != "" && k.KeyID == kid {
+			ordered = append(ordered, k)
+		}
+	}
+	for _, k := range s.Keys {
+		if kid == "" || k.KeyID != kid {
+			ordered = append(ordered, k)
+		}
+	}
+
+	err := ErrInvalidKeyType
+	for _, k := range ordered {
+		verr := token.Method.Verify(signingString, token.Signature, k.Key)
+		if verr == nil {
+			return nil
+		}
+		// Report a signature failure over keys not usable with the method.
+		if !errors.Is(verr, ErrInvalidKeyType) {
+			err = verr
+		}
+	}
+	return err
+}
+
+// verifySignature verifies the token's signature with key, which may be a
+// *VerificationKeySet.
+func verifySignature(token *Token, signingString string, key interface{}) error {
+	if set, ok := key.(*VerificationKeySet); ok {
+		return set.verify(token, signingString)
+	}
+	return token.Method.Verify(signingString, token.Signature, key)
+}
+
+// checkKeyConfusion rejects the use of a public key as an HMAC secret, which
+// would let anyone holding the public key forge tokens by changing "alg" from
+// an asymmetric algorithm to HMAC. Keys are checked in every form a Keyfunc
+// serving several algorithms might return them: PEM, DER or SSH encoded.
+func checkKeyConfusion(method SigningMethod, key interface{}) error {
+	if _, ok := method.(*SigningMethodHMAC); !ok {
+		return nil
+	}
+	keys := []interface{}{key}
+	if set, ok := key.(*VerificationKeySet); ok {
+		keys = keys[:0]
+		for _, k := range set.Keys {
+			keys = append(keys, k.Key)
+		}
+	}
+	for _, k := range keys {
+		secrets, _ := hmacSecrets(k)
+		for _, b := range secrets {
+			if isEncodedPublicKey(b) {
+				return ErrKeyAlgorithmMismatch
+			}
+		}
+	}
+	return nil
+}
+
+func isEncodedPublicKey(b []byte) bool {
+	trimmed := bytes.TrimSpace(b)
+	for _, prefix := range []string{"-----BEGIN ", "ssh-rsa ", "ssh-ed25519 ", "ecdsa-sha2-"} {
+		if bytes.HasPrefix(trimmed, []byte(prefix)) {
+			return true
+		}
+	}
+	if _, err := x509.ParsePKIXPublicKey(b); err == nil {
+		return true
+	}
+	_, err := x509.ParsePKCS1PublicKey(b)
+	return err == nil
+}
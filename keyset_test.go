@@ -0,0 +1,59 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/test"
+)
+
+func TestVerificationKeySet(t *testing.T) {
+	rsaKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	hmacKey := []byte("current")
+
+	set := jwt.NewVerificationKeySet([]byte("retired"))
+	set.Add("rsa", &rsaKey.PublicKey)
+	set.Add("hmac", hmacKey)
+
+	var tests = []struct {
+		name   string
+		method jwt.SigningMethod
+		kid    string
+		key    interface{}
+		err    error
+	}{
+		{"matching kid", jwt.SigningMethodHS256, "hmac", hmacKey, nil},
+		{"stale kid", jwt.SigningMethodHS256, "rsa", hmacKey, nil},
+		{"no kid", jwt.SigningMethodRS256, "", rsaKey, nil},
+		{"unknown key", jwt.SigningMethodHS256, "hmac", []byte("unknown"), jwt.ErrSignatureInvalid},
+	}
+
+	for _, data := range tests {
+		token := jwt.NewWithClaims(data.method, jwt.MapClaims{"foo": "bar"})
+		if data.kid != "" {
+			token.Header["kid"] = data.kid
+		}
+		signed, err := token.SignedString(data.key)
+		if err != nil {
+			t.Errorf("[%v] Error signing token: %v", data.name, err)
+			continue
+		}
+		parsed, err := jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return set, nil })
+		if data.err != nil {
+			if !errors.Is(err, data.err) {
+				t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+			}
+			continue
+		}
+		if err != nil || !parsed.Valid {
+			t.Errorf("[%v] Error verifying token: %v", data.name, err)
+		}
+	}
+
+	unusable := jwt.NewVerificationKeySet("not a key")
+	signed, _ := jwt.New(jwt.SigningMethodHS256).SignedString(hmacKey)
+	if _, err := jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return unusable, nil }); !errors.Is(err, jwt.ErrInvalidKeyType) {
+		t.Errorf("Expected ErrInvalidKeyType. Got: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"strconv"
+	"sync"
+)
+
+// KeyIDStrategy derives the "kid" (key ID) of a key. Using the same strategy
+// when signing and when publishing verification keys ensures issuers and
+// verifiers agree on identifiers without maintaining them by hand.
+type KeyIDStrategy interface {
+	KeyID(key interface{}) (string, error)
+}
+
+// KeyIDFunc is an adapter to allow the use of ordinary functions as a
+// KeyIDStrategy.
+type KeyIDFunc func(key interface{}) (string, error)
+
+// KeyID calls f(key).
+func (f KeyIDFunc) KeyID(key interface{}) (string, error) {
+	return f(key)
+}
+
+// ThumbprintKeyID derives the key ID as the base64url encoded SHA-256 JWK
+// thumbprint of the key, as described in
+// https://datatracker.ietf.org/doc/html/rfc7638#section-3
+var ThumbprintKeyID KeyIDStrategy = KeyIDFunc(func(key interface{}) (string, error) {
+	tp, err := Thumbprint(key, crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+	return EncodeSegment(tp), nil
+})
+
+// ThumbprintURIKeyID derives the key ID as the JWK thumbprint URI of the key,
+// as described in https://datatracker.ietf.org/doc/html/rfc9278, using
+// SHA-256: "urn:ietf:params:oauth:jwk-thumbprint:sha-256:" followed by the
+// value of ThumbprintKeyID. Unlike a bare thumbprint, the URI names the hash
+// it was computed with.
+var ThumbprintURIKeyID KeyIDStrategy = KeyIDFunc(func(key interface{}) (string, error) {
+	return ThumbprintURI(key, crypto.SHA256)
+})
+
+// thumbprintURIHashes are the names, in the IANA "Named Information Hash
+// Algorithm" registry, of the hashes a thumbprint URI may be computed with.
+var thumbprintURIHashes = map[crypto.Hash]string{
+	crypto.SHA256: "sha-256",
+	crypto.SHA384: "sha-384",
+	crypto.SHA512: "sha-512",
+}
+
+// ThumbprintURI returns the JWK thumbprint URI of key, as described in
+// https://datatracker.ietf.org/doc/html/rfc9278, computed with hash, which is
+// SHA-256, SHA-384 or SHA-512.
+func ThumbprintURI(key interface{}, hash crypto.Hash) (string, error) {
+	name, ok := thumbprintURIHashes[hash]
+	if !ok {
+		return "", ErrHashUnavailable
+	}
+	tp, err := Thumbprint(key, hash)
+	if err != nil {
+		return "", err
+	}
+	return "urn:ietf:params:oauth:jwk-thumbprint:" + name + ":" + EncodeSegment(tp), nil
+}
+
+// SPKIKeyID returns a KeyIDStrategy which derives the key ID as the hex encoded
+// SHA-256 digest of the DER-encoded SubjectPublicKeyInfo of the key, truncated
+// to n bytes. n is clamped to the range [1, 32]. Symmetric keys are not
+// supported.
+func SPKIKeyID(n int) KeyIDStrategy {
+	if n <= 0 || n > sha256.Size {
+		n = sha256.Size
+	}
+	return KeyIDFunc(func(key interface{}) (string, error) {
+		sum, err := spkiDigest(key)
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(sum[:n]), nil
+	})
+}
+
+func spkiDigest(key interface{}) ([sha256.Size]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey(key))
+	if err != nil {
+		return [sha256.Size]byte{}, ErrInvalidKeyType
+	}
+	return sha256.Sum256(der), nil
+}
+
+// SequentialKeyID assigns key IDs of the form Prefix followed by an
+// incrementing counter, starting at 1. Each distinct key is assigned an ID
+// once; asking again for the same key, or its public half, returns the same
+// ID. Because the IDs depend on the order in which keys are seen, a
+// SequentialKeyID must be shared by everything that needs to agree on them.
+//
+// The zero value is ready to use. A SequentialKeyID is safe for concurrent use.
+type SequentialKeyID struct {
+	Prefix string
+
+	mu   sync.Mutex
+	next int
+	ids  map[string]string
+}
+
+// KeyID implements KeyIDStrategy.
+func (s *SequentialKeyID) KeyID(key interface{}) (string, error) {
+	tp, err := Thumbprint(key, crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ids == nil {
+		s.ids = make(map[string]string)
+	}
+	if id, ok := s.ids[string(tp)]; ok {
+		return id, nil
+	}
+	s.next++
+	id := s.Prefix + strconv.Itoa(s.next)
+	s.ids[string(tp)] = id
+	return id, nil
+}
+
+// SetKeyID sets the "kid" header of the token to the key ID strategy derives
+// from key. The signing key may be passed directly; strategies derive the ID
+// from its public half so that it matches the published verification key.
+func (t *Token) SetKeyID(strategy KeyIDStrategy, key interface{}) error {
+	kid, err := strategy.KeyID(key)
+	if err != nil {
+		return err
+	}
+	t.Header["kid"] = kid
+	return nil
+}
@@ -0,0 +1,127 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/test"
+)
+
+// Example key from https://datatracker.ietf.org/doc/html/rfc7638#section-3.1
+func rfc7638Key(t *testing.T) *rsa.PublicKey {
+	n, err := jwt.DecodeSegment("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+}
+
+func TestThumbprint(t *testing.T) {
+	tp, err := jwt.Thumbprint(rfc7638Key(t), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := jwt.EncodeSegment(tp); got != "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs" {
+		t.Errorf("Thumbprint mismatch. Got: %v", got)
+	}
+
+	if _, err = jwt.Thumbprint("not a key", crypto.SHA256); err != jwt.ErrInvalidKeyType {
+		t.Errorf("Expected ErrInvalidKeyType. Got: %v", err)
+	}
+}
+
+func TestKeyIDStrategies(t *testing.T) {
+	priv := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	pub := test.LoadRSAPublicKeyFromDisk("test/sample_key.pub")
+
+	var tests = []struct {
+		name     string
+		strategy jwt.KeyIDStrategy
+		length   int
+	}{
+		{"thumbprint", jwt.ThumbprintKeyID, 43},
+		{"spki", jwt.SPKIKeyID(8), 16},
+		{"sequential", &jwt.SequentialKeyID{Prefix: "key-"}, 5},
+	}
+
+	for _, data := range tests {
+		privID, err := data.strategy.KeyID(priv)
+		if err != nil {
+			t.Errorf("[%v] Error deriving key ID: %v", data.name, err)
+			continue
+		}
+		pubID, err := data.strategy.KeyID(pub)
+		if err != nil {
+			t.Errorf("[%v] Error deriving key ID: %v", data.name, err)
+			continue
+		}
+		if privID != pubID {
+			t.Errorf("[%v] Private and public key IDs differ: %v != %v", data.name, privID, pubID)
+		}
+		if len(privID) != data.length {
+			t.Errorf("[%v] Unexpected key ID %q", data.name, privID)
+		}
+	}
+}
+
+func TestSequentialKeyID(t *testing.T) {
+	s := &jwt.SequentialKeyID{Prefix: "k"}
+	first, _ := s.KeyID([]byte("first"))
+	second, _ := s.KeyID([]byte("second"))
+	again, _ := s.KeyID([]byte("first"))
+	if first != "k1" || second != "k2" || again != "k1" {
+		t.Errorf("Unexpected sequence: %v, %v, %v", first, second, again)
+	}
+}
+
+func TestToken_SetKeyID(t *testing.T) {
+	priv := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	token := jwt.New(jwt.SigningMethodRS256)
+	if err := token.SetKeyID(jwt.ThumbprintKeyID, priv); err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := jwt.ThumbprintKeyID.KeyID(&priv.PublicKey)
+	if token.Header["kid"] != expected {
+		t.Errorf("Expected kid %v. Got: %v", expected, token.Header["kid"])
+	}
+}
+
+func TestThumbprintURI(t *testing.T) {
+	// https://datatracker.ietf.org/doc/html/rfc9278#section-3
+	uri, err := jwt.ThumbprintURI(rfc7638Key(t), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "urn:ietf:params:oauth:jwk-thumbprint:sha-256:NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"; uri != want {
+		t.Errorf("ThumbprintURI mismatch. Got: %v", uri)
+	}
+	if uri, _ = jwt.ThumbprintURI(rfc7638Key(t), crypto.SHA512); !strings.HasPrefix(uri, "urn:ietf:params:oauth:jwk-thumbprint:sha-512:") {
+		t.Errorf("Unexpected SHA-512 thumbprint URI: %v", uri)
+	}
+	if _, err = jwt.ThumbprintURI(rfc7638Key(t), crypto.SHA1); err != jwt.ErrHashUnavailable {
+		t.Errorf("Expected ErrHashUnavailable. Got: %v", err)
+	}
+}
+
+func TestToken_KeyIDStrategy(t *testing.T) {
+	priv := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "a"})
+	token.KeyIDStrategy = jwt.ThumbprintURIKeyID
+	signed, err := token.SignedString(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return &priv.PublicKey, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := jwt.ThumbprintURI(&priv.PublicKey, crypto.SHA256)
+	if parsed.Header["kid"] != expected {
+		t.Errorf("Expected kid %v. Got: %v", expected, parsed.Header["kid"])
+	}
+}
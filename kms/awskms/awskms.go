@@ -0,0 +1,207 @@
+package awskms
+
+import (
+	"context"
+	"crypto"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/chanced/go-jwt/v4"
+)
+
+// DefaultTimeout bounds each call to KMS when Key.Timeout is unset.
+const DefaultTimeout = 5 * time.Second
+
+// ErrMalformedSignature is returned when KMS returns an ECDSA signature which
+// is not a valid ASN.1 encoding.
+var ErrMalformedSignature = errors.New("awskms: malformed ECDSA signature")
+
+// Client is the subset of the KMS client used by the signing methods. It is
+// implemented by *kms.Client.
+type Client interface {
+	Sign(ctx context.Context, params *kms.SignInput, optFns ...func(*kms.Options)) (*kms.SignOutput, error)
+	Verify(ctx context.Context, params *kms.VerifyInput, optFns ...func(*kms.Options)) (*kms.VerifyOutput, error)
+}
+
+// Key identifies a KMS key, by key ID, ARN or alias, and the client used to
+// reach it.
+type Key struct {
+	Client  Client
+	KeyID   string
+	Timeout time.Duration // Optional. Bounds each call to KMS; defaults to DefaultTimeout
+
+	// GrantTokens are passed to KMS with every call.
+	GrantTokens []string
+}
+
+func (k *Key) context() (context.Context, context.CancelFunc) {
+	timeout := k.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// SigningMethod signs and verifies tokens with a KMS key. It implements
+// jwt.SigningMethod.
+type SigningMethod struct {
+	local     jwt.SigningMethod // Verifies with public keys
+	spec      types.SigningAlgorithmSpec
+	hash      crypto.Hash
+	curveBits int // For ECDSA, the size of the curve; zero otherwise
+}
+
+// The signing methods, named after the JWS algorithms.
+var (
+	SigningMethodRS256 = &SigningMethod{jwt.SigningMethodRS256, types.SigningAlgorithmSpecRsassaPkcs1V15Sha256, crypto.SHA256, 0}
+	SigningMethodRS384 = &SigningMethod{jwt.SigningMethodRS384, types.SigningAlgorithmSpecRsassaPkcs1V15Sha384, crypto.SHA384, 0}
+	SigningMethodRS512 = &SigningMethod{jwt.SigningMethodRS512, types.SigningAlgorithmSpecRsassaPkcs1V15Sha512, crypto.SHA512, 0}
+	SigningMethodPS256 = &SigningMethod{jwt.SigningMethodPS256, types.SigningAlgorithmSpecRsassaPssSha256, crypto.SHA256, 0}
+	SigningMethodPS384 = &SigningMethod{jwt.SigningMethodPS384, types.SigningAlgorithmSpecRsassaPssSha384, crypto.SHA384, 0}
+	SigningMethodPS512 = &SigningMethod{jwt.SigningMethodPS512, types.SigningAlgorithmSpecRsassaPssSha512, crypto.SHA512, 0}
+	SigningMethodES256 = &SigningMethod{jwt.SigningMethodES256, types.SigningAlgorithmSpecEcdsaSha256, crypto.SHA256, 256}
+	SigningMethodES384 = &SigningMethod{jwt.SigningMethodES384, types.SigningAlgorithmSpecEcdsaSha384, crypto.SHA384, 384}
+	SigningMethodES512 = &SigningMethod{jwt.SigningMethodES512, types.SigningAlgorithmSpecEcdsaSha512, crypto.SHA512, 521}
+)
+
+var methods = []*SigningMethod{
+	SigningMethodRS256, SigningMethodRS384, SigningMethodRS512,
+	SigningMethodPS256, SigningMethodPS384, SigningMethodPS512,
+	SigningMethodES256, SigningMethodES384, SigningMethodES512,
+}
+
+// Algorithm returns the JWS algorithm of the KMS signing algorithm spec, such
+// as "RS256" for RSASSA_PKCS1_V1_5_SHA_256, or "" if there is none.
+func Algorithm(spec types.SigningAlgorithmSpec) string {
+	for _, m := range methods {
+		if m.spec == spec {
+			return m.Alg()
+		}
+	}
+	return ""
+}
+
+// Registry returns a registry of the signing methods, for use with
+// jwt.WithSigningMethods.
+func Registry() *jwt.SigningMethodRegistry {
+	r := jwt.NewSigningMethodRegistry()
+	for _, m := range methods {
+		m := m
+		r.Register(m.Alg(), func() jwt.SigningMethod { return m })
+	}
+	return r
+}
+
+// Alg returns the JWS algorithm of the method.
+func (m *SigningMethod) Alg() string {
+	return m.local.Alg()
+}
+
+// Spec returns the KMS signing algorithm of the method.
+func (m *SigningMethod) Spec() types.SigningAlgorithmSpec {
+	return m.spec
+}
+
+// Sign signs signingString with key, which must be a *Key.
+func (m *SigningMethod) Sign(signingString string, key interface{}) (string, error) {
+	k, ok := key.(*Key)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	ctx, cancel := k.context()
+	defer cancel()
+
+	out, err := k.Client.Sign(ctx, &kms.SignInput{
+		KeyId:            &k.KeyID,
+		Message:          m.digest(signingString),
+		MessageType:      types.MessageTypeDigest,
+		SigningAlgorithm: m.spec,
+		GrantTokens:      k.GrantTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+	sig := out.Signature
+	if m.curveBits > 0 {
+		if sig, err = fromASN1(sig, m.curveBits); err != nil {
+			return "", err
+		}
+	}
+	return jwt.EncodeSegment(sig), nil
+}
+
+// Verify verifies signature with key. If key is a *Key, the signature is
+// verified by KMS; otherwise it is verified locally, as by the standard method
+// of the same algorithm.
+func (m *SigningMethod) Verify(signingString, signature string, key interface{}) error {
+	k, ok := key.(*Key)
+	if !ok {
+		return m.local.Verify(signingString, signature, key)
+	}
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+	if m.curveBits > 0 {
+		if sig, err = toASN1(sig, m.curveBits); err != nil {
+			return err
+		}
+	}
+	ctx, cancel := k.context()
+	defer cancel()
+
+	out, err := k.Client.Verify(ctx, &kms.VerifyInput{
+		KeyId:            &k.KeyID,
+		Message:          m.digest(signingString),
+		MessageType:      types.MessageTypeDigest,
+		Signature:        sig,
+		SigningAlgorithm: m.spec,
+		GrantTokens:      k.GrantTokens,
+	})
+	var invalid *types.KMSInvalidSignatureException
+	if errors.As(err, &invalid) || (err == nil && !out.SignatureValid) {
+		return jwt.ErrSignatureInvalid
+	}
+	return err
+}
+
+func (m *SigningMethod) digest(signingString string) []byte {
+	h := m.hash.New()
+	h.Write([]byte(signingString))
+	return h.Sum(nil)
+}
+
+type ecdsaSignature struct{ R, S *big.Int }
+
+// fromASN1 converts an ASN.1 encoded ECDSA signature to the concatenation of
+// r and s, each padded to the size of the curve.
+func fromASN1(der []byte, curveBits int) ([]byte, error) {
+	var sig ecdsaSignature
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil || len(rest) != 0 {
+		return nil, ErrMalformedSignature
+	}
+	size := (curveBits + 7) / 8
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 || len(sig.R.Bytes()) > size || len(sig.S.Bytes()) > size {
+		return nil, ErrMalformedSignature
+	}
+	out := make([]byte, 2*size)
+	sig.R.FillBytes(out[:size])
+	sig.S.FillBytes(out[size:])
+	return out, nil
+}
+
+// toASN1 converts a JWS ECDSA signature to its ASN.1 encoding.
+func toASN1(sig []byte, curveBits int) ([]byte, error) {
+	size := (curveBits + 7) / 8
+	if len(sig) != 2*size {
+		return nil, jwt.ErrSignatureInvalid
+	}
+	return asn1.Marshal(ecdsaSignature{
+		R: new(big.Int).SetBytes(sig[:size]),
+		S: new(big.Int).SetBytes(sig[size:]),
+	})
+}
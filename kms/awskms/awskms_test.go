@@ -0,0 +1,126 @@
+package awskms_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/kms/awskms"
+)
+
+// fakeKMS signs and verifies digests with local keys, as KMS does.
+type fakeKMS struct {
+	keys map[string]crypto.Signer
+}
+
+func (f *fakeKMS) opts(spec types.SigningAlgorithmSpec) crypto.SignerOpts {
+	switch spec {
+	case types.SigningAlgorithmSpecRsassaPssSha256:
+		return &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}
+	case types.SigningAlgorithmSpecEcdsaSha384:
+		return crypto.SHA384
+	}
+	return crypto.SHA256
+}
+
+func (f *fakeKMS) Sign(ctx context.Context, in *kms.SignInput, _ ...func(*kms.Options)) (*kms.SignOutput, error) {
+	key, ok := f.keys[*in.KeyId]
+	if !ok || in.MessageType != types.MessageTypeDigest {
+		return nil, &types.NotFoundException{}
+	}
+	sig, err := key.Sign(rand.Reader, in.Message, f.opts(in.SigningAlgorithm))
+	if err != nil {
+		return nil, err
+	}
+	return &kms.SignOutput{Signature: sig, SigningAlgorithm: in.SigningAlgorithm}, nil
+}
+
+func (f *fakeKMS) Verify(ctx context.Context, in *kms.VerifyInput, _ ...func(*kms.Options)) (*kms.VerifyOutput, error) {
+	key, ok := f.keys[*in.KeyId]
+	if !ok {
+		return nil, &types.NotFoundException{}
+	}
+	var valid bool
+	switch pub := key.Public().(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(pub, in.Message, in.Signature)
+	case *rsa.PublicKey:
+		if opts, ok := f.opts(in.SigningAlgorithm).(*rsa.PSSOptions); ok {
+			valid = rsa.VerifyPSS(pub, crypto.SHA256, in.Message, in.Signature, opts) == nil
+		} else {
+			valid = rsa.VerifyPKCS1v15(pub, crypto.SHA256, in.Message, in.Signature) == nil
+		}
+	}
+	if !valid {
+		return nil, &types.KMSInvalidSignatureException{}
+	}
+	return &kms.VerifyOutput{SignatureValid: true}, nil
+}
+
+func TestSigningMethod(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	client := &fakeKMS{keys: map[string]crypto.Signer{"rsa": rsaKey, "p256": p256, "p384": p384}}
+
+	var tests = []struct {
+		method *awskms.SigningMethod
+		keyID  string
+		public crypto.PublicKey
+	}{
+		{awskms.SigningMethodRS256, "rsa", &rsaKey.PublicKey},
+		{awskms.SigningMethodPS256, "rsa", &rsaKey.PublicKey},
+		{awskms.SigningMethodES256, "p256", &p256.PublicKey},
+		{awskms.SigningMethodES384, "p384", &p384.PublicKey},
+	}
+	for _, data := range tests {
+		name := data.method.Alg()
+		key := &awskms.Key{Client: client, KeyID: data.keyID}
+		token, err := jwt.NewWithClaims(data.method, jwt.MapClaims{"sub": "a"}).SignedString(key)
+		if err != nil {
+			t.Errorf("[%v] Error signing: %v", name, err)
+			continue
+		}
+
+		// Verified locally by the standard method.
+		if _, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return data.public, nil }); err != nil {
+			t.Errorf("[%v] Error verifying locally: %v", name, err)
+		}
+
+		// Verified by KMS.
+		parser := jwt.NewParser(jwt.WithSigningMethods(awskms.Registry()))
+		keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+		if _, err = parser.Parse(token, keyFunc); err != nil {
+			t.Errorf("[%v] Error verifying with KMS: %v", name, err)
+		}
+		if _, err = parser.Parse(token[:len(token)-4]+"AAAA", keyFunc); !errors.Is(err, jwt.ErrSignatureInvalid) {
+			t.Errorf("[%v] Expected ErrSignatureInvalid for a tampered token, got %v", name, err)
+		}
+	}
+}
+
+func TestAlgorithm(t *testing.T) {
+	if alg := awskms.Algorithm(types.SigningAlgorithmSpecRsassaPkcs1V15Sha256); alg != "RS256" {
+		t.Errorf("Algorithm(RSASSA_PKCS1_V1_5_SHA_256) = %q", alg)
+	}
+	if alg := awskms.Algorithm(types.SigningAlgorithmSpecEcdsaSha256); alg != "ES256" {
+		t.Errorf("Algorithm(ECDSA_SHA_256) = %q", alg)
+	}
+	if alg := awskms.Algorithm(types.SigningAlgorithmSpecSm2dsa); alg != "" {
+		t.Errorf("Algorithm(SM2DSA) = %q", alg)
+	}
+}
+
+func TestSign_invalidKey(t *testing.T) {
+	if _, err := awskms.SigningMethodRS256.Sign("a.b", []byte("secret")); !errors.Is(err, jwt.ErrInvalidKeyType) {
+		t.Errorf("expected ErrInvalidKeyType, got %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+// Package awskms provides signing methods backed by keys held in AWS Key
+// Management Service, so that tokens can be signed without the private key
+// ever leaving KMS.
+//
+// The methods are used with a *Key, which names the KMS key and the client
+// calling the Sign and Verify APIs:
+//
+//	key := &awskms.Key{Client: kms.NewFromConfig(cfg), KeyID: "alias/token-signing"}
+//	s, err := jwt.NewWithClaims(awskms.SigningMethodES256, claims).SignedString(key)
+//
+// The KMS signing algorithms are mapped to JWS algorithms, such as
+// RSASSA_PKCS1_V1_5_SHA_256 to RS256 and ECDSA_SHA_256 to ES256, and ECDSA
+// signatures are converted between the ASN.1 encoding used by KMS and the
+// fixed-size encoding used by JWS. Tokens are verified by KMS when the key is
+// a *Key, and locally when it is a public key, so that verifiers need not have
+// access to KMS. A Parser resolves these methods in place of the standard ones
+// when set up with jwt.WithSigningMethods(awskms.Registry()).
+//
+// awskms is a separate module so that users of the jwt package do not depend
+// on the AWS SDK.
+package awskms
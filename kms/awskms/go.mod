@@ -0,0 +1,17 @@
+module github.com/chanced/go-jwt/v4/kms/awskms
+
+go 1.22
+
+require (
+	github.com/aws/aws-sdk-go-v2/service/kms v1.38.3
+	github.com/chanced/go-jwt/v4 v4.0.0
+)
+
+require (
+	github.com/aws/aws-sdk-go-v2 v1.36.3 // indirect
+	github.com/aws/aws-sdk-go-v2/internal/configsources v1.3.34 // indirect
+	github.com/aws/aws-sdk-go-v2/internal/endpoints/v2 v2.6.34 // indirect
+	github.com/aws/smithy-go v1.22.2 // indirect
+)
+
+replace github.com/chanced/go-jwt/v4 => ../../
@@ -0,0 +1,21 @@
+// Package gcpkms provides signing methods backed by asymmetric signing keys
+// held in Google Cloud KMS, so that tokens can be signed without the private
+// key ever leaving KMS.
+//
+// The methods are used with a *Key, which names the crypto key version and the
+// client calling the AsymmetricSign API:
+//
+//	client, err := kms.NewKeyManagementClient(ctx)
+//	key := &gcpkms.Key{Client: client, Name: "projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1"}
+//	s, err := jwt.NewWithClaims(gcpkms.SigningMethodES256, claims).SignedString(key)
+//
+// Cloud KMS has no asymmetric verify API, so tokens are verified locally with
+// the public key of the version, fetched once with GetPublicKey. Verifiers
+// which hold the public key need not use this package at all.
+//
+// Calls failing with a transient gRPC status are retried, and every attempt is
+// reported to Key.Hooks, so that latency and error rates can be recorded.
+//
+// gcpkms is a separate module so that users of the jwt package do not depend
+// on the Google Cloud SDK.
+package gcpkms
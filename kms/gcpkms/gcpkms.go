@@ -0,0 +1,284 @@
+package gcpkms
+
+import (
+	"context"
+	"crypto"
+	"encoding/asn1"
+	"errors"
+	"hash/crc32"
+	"math/big"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/internal/retry"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// DefaultTimeout bounds each call to KMS when Key.Timeout is unset.
+const DefaultTimeout = 5 * time.Second
+
+var (
+	// ErrCorrupted is returned when the integrity checksums of a request or
+	// response do not match, as described in
+	// https://cloud.google.com/kms/docs/data-integrity-guidelines
+	ErrCorrupted = errors.New("gcpkms: request or response corrupted in transit")
+
+	// ErrMalformedSignature is returned when KMS returns an ECDSA signature
+	// which is not a valid ASN.1 encoding.
+	ErrMalformedSignature = errors.New("gcpkms: malformed ECDSA signature")
+)
+
+// Client is the subset of the Cloud KMS client used by the signing methods. It
+// is implemented by *kms.KeyManagementClient.
+type Client interface {
+	AsymmetricSign(ctx context.Context, req *kmspb.AsymmetricSignRequest, opts ...gax.CallOption) (*kmspb.AsymmetricSignResponse, error)
+	GetPublicKey(ctx context.Context, req *kmspb.GetPublicKeyRequest, opts ...gax.CallOption) (*kmspb.PublicKey, error)
+}
+
+// RetryPolicy controls the retry of calls failing with a transient error. Zero
+// fields take the defaults of three attempts and a backoff doubling from
+// 100ms up to 2s.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Call describes an attempted call to KMS.
+type Call struct {
+	Operation string // "AsymmetricSign" or "GetPublicKey"
+	Name      string // The crypto key version
+	Attempt   int    // Starting at 1
+	Duration  time.Duration
+	Err       error
+}
+
+// Hooks are called on events of a Key. Fields may be nil.
+type Hooks struct {
+	OnCall func(Call) // Called after every attempted call, for latency and error metrics
+}
+
+// Key identifies a Cloud KMS crypto key version and the client used to reach
+// it. A Key is safe for concurrent use.
+type Key struct {
+	Client  Client
+	Name    string        // The resource name of the crypto key version
+	Timeout time.Duration // Optional. Bounds each attempt; defaults to DefaultTimeout
+	Retry   RetryPolicy
+	Hooks   Hooks
+
+	mu     sync.Mutex
+	public crypto.PublicKey
+}
+
+// call calls fn under the retry policy, reporting every attempt to the hooks.
+func (k *Key) call(op string, fn func(ctx context.Context) error) error {
+	timeout := k.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	var observe func(int, time.Duration, error)
+	if k.Hooks.OnCall != nil {
+		observe = func(attempt int, d time.Duration, err error) {
+			k.Hooks.OnCall(Call{Operation: op, Name: k.Name, Attempt: attempt, Duration: d, Err: err})
+		}
+	}
+	return retry.Do(context.Background(), k.Retry.MaxAttempts, k.Retry.InitialBackoff, k.Retry.MaxBackoff,
+		retryable, observe, func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return fn(ctx)
+		})
+}
+
+func retryable(err error) bool {
+	if errors.Is(err, ErrCorrupted) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal:
+		return true
+	}
+	return false
+}
+
+// PublicKey returns the public key of the version, fetched from KMS on the
+// first call.
+func (k *Key) PublicKey() (crypto.PublicKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.public != nil {
+		return k.public, nil
+	}
+	var resp *kmspb.PublicKey
+	err := k.call("GetPublicKey", func(ctx context.Context) (err error) {
+		resp, err = k.Client.GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{Name: k.Name})
+		if err == nil && resp.PemCrc32C != nil && int64(crc32c([]byte(resp.Pem))) != resp.PemCrc32C.Value {
+			err = ErrCorrupted
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	pub, err := parsePublicKey([]byte(resp.Pem))
+	if err != nil {
+		return nil, err
+	}
+	k.public = pub
+	return pub, nil
+}
+
+func parsePublicKey(pem []byte) (crypto.PublicKey, error) {
+	if pub, err := jwt.ParseRSAPublicKeyFromPEM(pem); err == nil {
+		return pub, nil
+	}
+	return jwt.ParseECPublicKeyFromPEM(pem)
+}
+
+// SigningMethod signs tokens with a Cloud KMS key version. It implements
+// jwt.SigningMethod.
+type SigningMethod struct {
+	local     jwt.SigningMethod // Verifies with public keys
+	hash      crypto.Hash
+	curveBits int // For ECDSA, the size of the curve; zero otherwise
+}
+
+// The signing methods, named after the JWS algorithms. Cloud KMS supports no
+// other JWS algorithms with digests.
+var (
+	SigningMethodRS256 = &SigningMethod{jwt.SigningMethodRS256, crypto.SHA256, 0}
+	SigningMethodRS512 = &SigningMethod{jwt.SigningMethodRS512, crypto.SHA512, 0}
+	SigningMethodPS256 = &SigningMethod{jwt.SigningMethodPS256, crypto.SHA256, 0}
+	SigningMethodPS512 = &SigningMethod{jwt.SigningMethodPS512, crypto.SHA512, 0}
+	SigningMethodES256 = &SigningMethod{jwt.SigningMethodES256, crypto.SHA256, 256}
+	SigningMethodES384 = &SigningMethod{jwt.SigningMethodES384, crypto.SHA384, 384}
+)
+
+var methods = []*SigningMethod{
+	SigningMethodRS256, SigningMethodRS512, SigningMethodPS256, SigningMethodPS512,
+	SigningMethodES256, SigningMethodES384,
+}
+
+var algorithms = map[kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm]*SigningMethod{
+	kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_2048_SHA256: SigningMethodRS256,
+	kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_3072_SHA256: SigningMethodRS256,
+	kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_4096_SHA256: SigningMethodRS256,
+	kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_4096_SHA512: SigningMethodRS512,
+	kmspb.CryptoKeyVersion_RSA_SIGN_PSS_2048_SHA256:   SigningMethodPS256,
+	kmspb.CryptoKeyVersion_RSA_SIGN_PSS_3072_SHA256:   SigningMethodPS256,
+	kmspb.CryptoKeyVersion_RSA_SIGN_PSS_4096_SHA256:   SigningMethodPS256,
+	kmspb.CryptoKeyVersion_RSA_SIGN_PSS_4096_SHA512:   SigningMethodPS512,
+	kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256:        SigningMethodES256,
+	kmspb.CryptoKeyVersion_EC_SIGN_P384_SHA384:        SigningMethodES384,
+}
+
+// MethodFor returns the signing method for keys of the Cloud KMS algorithm,
+// such as SigningMethodRS256 for RSA_SIGN_PKCS1_2048_SHA256, or nil if there
+// is none.
+func MethodFor(alg kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm) *SigningMethod {
+	return algorithms[alg]
+}
+
+// Registry returns a registry of the signing methods, for use with
+// jwt.WithSigningMethods.
+func Registry() *jwt.SigningMethodRegistry {
+	r := jwt.NewSigningMethodRegistry()
+	for _, m := range methods {
+		m := m
+		r.Register(m.Alg(), func() jwt.SigningMethod { return m })
+	}
+	return r
+}
+
+// Alg returns the JWS algorithm of the method.
+func (m *SigningMethod) Alg() string {
+	return m.local.Alg()
+}
+
+// Sign signs signingString with key, which must be a *Key.
+func (m *SigningMethod) Sign(signingString string, key interface{}) (string, error) {
+	k, ok := key.(*Key)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+
+	h := m.hash.New()
+	h.Write([]byte(signingString))
+	digest := &kmspb.Digest{}
+	switch sum := h.Sum(nil); m.hash {
+	case crypto.SHA256:
+		digest.Digest = &kmspb.Digest_Sha256{Sha256: sum}
+	case crypto.SHA384:
+		digest.Digest = &kmspb.Digest_Sha384{Sha384: sum}
+	case crypto.SHA512:
+		digest.Digest = &kmspb.Digest_Sha512{Sha512: sum}
+	}
+	req := &kmspb.AsymmetricSignRequest{
+		Name:         k.Name,
+		Digest:       digest,
+		DigestCrc32C: wrapperspb.Int64(int64(crc32c(h.Sum(nil)))),
+	}
+
+	var resp *kmspb.AsymmetricSignResponse
+	err := k.call("AsymmetricSign", func(ctx context.Context) (err error) {
+		resp, err = k.Client.AsymmetricSign(ctx, req)
+		if err == nil && (!resp.VerifiedDigestCrc32C || resp.SignatureCrc32C == nil ||
+			int64(crc32c(resp.Signature)) != resp.SignatureCrc32C.Value) {
+			err = ErrCorrupted
+		}
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sig := resp.Signature
+	if m.curveBits > 0 {
+		if sig, err = fromASN1(sig, m.curveBits); err != nil {
+			return "", err
+		}
+	}
+	return jwt.EncodeSegment(sig), nil
+}
+
+// Verify verifies signature with key. If key is a *Key, its public key is
+// used; otherwise key is used as by the standard method of the same algorithm.
+func (m *SigningMethod) Verify(signingString, signature string, key interface{}) error {
+	if k, ok := key.(*Key); ok {
+		pub, err := k.PublicKey()
+		if err != nil {
+			return err
+		}
+		key = pub
+	}
+	return m.local.Verify(signingString, signature, key)
+}
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+func crc32c(b []byte) uint32 {
+	return crc32.Checksum(b, castagnoli)
+}
+
+// fromASN1 converts an ASN.1 encoded ECDSA signature to the concatenation of
+// r and s, each padded to the size of the curve.
+func fromASN1(der []byte, curveBits int) ([]byte, error) {
+	var sig struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil || len(rest) != 0 {
+		return nil, ErrMalformedSignature
+	}
+	size := (curveBits + 7) / 8
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 || len(sig.R.Bytes()) > size || len(sig.S.Bytes()) > size {
+		return nil, ErrMalformedSignature
+	}
+	out := make([]byte, 2*size)
+	sig.R.FillBytes(out[:size])
+	sig.S.FillBytes(out[size:])
+	return out, nil
+}
@@ -0,0 +1,159 @@
+package gcpkms_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"hash/crc32"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/kms/gcpkms"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func crc(b []byte) *wrapperspb.Int64Value {
+	return wrapperspb.Int64(int64(crc32.Checksum(b, crc32.MakeTable(crc32.Castagnoli))))
+}
+
+// fakeKMS signs digests with local keys, as Cloud KMS does. The first
+// failures calls fail with codes.Unavailable.
+type fakeKMS struct {
+	keys     map[string]crypto.Signer
+	failures int
+	corrupt  bool
+}
+
+func (f *fakeKMS) AsymmetricSign(ctx context.Context, req *kmspb.AsymmetricSignRequest, _ ...gax.CallOption) (*kmspb.AsymmetricSignResponse, error) {
+	if f.failures > 0 {
+		f.failures--
+		return nil, status.Error(codes.Unavailable, "unavailable")
+	}
+	key, ok := f.keys[req.Name]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	var digest []byte
+	var opts crypto.SignerOpts
+	switch d := req.Digest.Digest.(type) {
+	case *kmspb.Digest_Sha256:
+		digest, opts = d.Sha256, crypto.SHA256
+	case *kmspb.Digest_Sha384:
+		digest, opts = d.Sha384, crypto.SHA384
+	}
+	if _, ok := key.(*rsa.PrivateKey); ok && req.Name == "pss" {
+		opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}
+	}
+	sig, err := key.Sign(rand.Reader, digest, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp := &kmspb.AsymmetricSignResponse{
+		Signature:            sig,
+		SignatureCrc32C:      crc(sig),
+		VerifiedDigestCrc32C: req.DigestCrc32C.Value == crc(digest).Value,
+		Name:                 req.Name,
+	}
+	if f.corrupt {
+		resp.Signature = append([]byte{0}, sig...)
+	}
+	return resp, nil
+}
+
+func (f *fakeKMS) GetPublicKey(ctx context.Context, req *kmspb.GetPublicKeyRequest, _ ...gax.CallOption) (*kmspb.PublicKey, error) {
+	key, ok := f.keys[req.Name]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	p := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	return &kmspb.PublicKey{Pem: p, PemCrc32C: crc([]byte(p))}, nil
+}
+
+func TestSigningMethod(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	client := &fakeKMS{keys: map[string]crypto.Signer{"rsa": rsaKey, "pss": rsaKey, "p256": p256, "p384": p384}}
+
+	var tests = []struct {
+		method *gcpkms.SigningMethod
+		name   string
+		public crypto.PublicKey
+	}{
+		{gcpkms.SigningMethodRS256, "rsa", &rsaKey.PublicKey},
+		{gcpkms.SigningMethodPS256, "pss", &rsaKey.PublicKey},
+		{gcpkms.SigningMethodES256, "p256", &p256.PublicKey},
+		{gcpkms.SigningMethodES384, "p384", &p384.PublicKey},
+	}
+	for _, data := range tests {
+		alg := data.method.Alg()
+		key := &gcpkms.Key{Client: client, Name: data.name}
+		token, err := jwt.NewWithClaims(data.method, jwt.MapClaims{"sub": "a"}).SignedString(key)
+		if err != nil {
+			t.Errorf("[%v] Error signing: %v", alg, err)
+			continue
+		}
+		if _, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return data.public, nil }); err != nil {
+			t.Errorf("[%v] Error verifying locally: %v", alg, err)
+		}
+		parser := jwt.NewParser(jwt.WithSigningMethods(gcpkms.Registry()))
+		if _, err = parser.Parse(token, func(*jwt.Token) (interface{}, error) { return key, nil }); err != nil {
+			t.Errorf("[%v] Error verifying with the fetched public key: %v", alg, err)
+		}
+	}
+}
+
+func TestKey_retry(t *testing.T) {
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	client := &fakeKMS{keys: map[string]crypto.Signer{"p256": p256}, failures: 2}
+	var calls []gcpkms.Call
+	key := &gcpkms.Key{
+		Client: client,
+		Name:   "p256",
+		Retry:  gcpkms.RetryPolicy{InitialBackoff: time.Millisecond},
+		Hooks:  gcpkms.Hooks{OnCall: func(c gcpkms.Call) { calls = append(calls, c) }},
+	}
+	if _, err := gcpkms.SigningMethodES256.Sign("a.b", key); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(calls) != 3 || calls[0].Err == nil || calls[2].Err != nil || calls[2].Attempt != 3 || calls[2].Operation != "AsymmetricSign" {
+		t.Errorf("Unexpected calls: %+v", calls)
+	}
+
+	client.failures = 5
+	if _, err := gcpkms.SigningMethodES256.Sign("a.b", key); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable after exhausting retries, got %v", err)
+	}
+
+	client.failures, client.corrupt = 0, true
+	if _, err := gcpkms.SigningMethodES256.Sign("a.b", key); !errors.Is(err, gcpkms.ErrCorrupted) {
+		t.Errorf("Expected ErrCorrupted, got %v", err)
+	}
+}
+
+func TestMethodFor(t *testing.T) {
+	if m := gcpkms.MethodFor(kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_3072_SHA256); m != gcpkms.SigningMethodRS256 {
+		t.Errorf("MethodFor(RSA_SIGN_PKCS1_3072_SHA256) = %v", m)
+	}
+	if m := gcpkms.MethodFor(kmspb.CryptoKeyVersion_EC_SIGN_P384_SHA384); m != gcpkms.SigningMethodES384 {
+		t.Errorf("MethodFor(EC_SIGN_P384_SHA384) = %v", m)
+	}
+	if m := gcpkms.MethodFor(kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256); m != nil {
+		t.Errorf("MethodFor(EC_SIGN_SECP256K1_SHA256) = %v", m)
+	}
+}
@@ -0,0 +1,23 @@
+module github.com/chanced/go-jwt/v4/kms/gcpkms
+
+go 1.24.0
+
+require (
+	github.com/chanced/go-jwt/v4 v4.0.0
+	github.com/googleapis/gax-go/v2 v2.15.0
+	google.golang.org/grpc v1.74.2
+	google.golang.org/protobuf v1.36.7
+)
+
+require (
+	cloud.google.com/go/kms v1.23.2
+	cloud.google.com/go/longrunning v0.6.7 // indirect
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+	google.golang.org/api v0.247.0 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20250818200422-3122310a409c // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250811230008-5f3141c8851a // indirect
+)
+
+replace github.com/chanced/go-jwt/v4 => ../../
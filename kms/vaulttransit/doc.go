@@ -0,0 +1,22 @@
+// Package vaulttransit provides signing methods backed by keys held in the
+// transit secrets engine of HashiCorp Vault, so that tokens can be signed
+// without the private key ever leaving Vault.
+//
+// The methods are used with a *Key, which names the transit key and the client
+// calling the sign endpoint:
+//
+//	client, err := api.NewClient(api.DefaultConfig())
+//	key := &vaulttransit.Key{Client: client.Logical(), Name: "token-signing"}
+//	s, err := jwt.NewWithClaims(vaulttransit.SigningMethodES256, claims).SignedString(key)
+//
+// Tokens are verified locally with the public keys of every version of the
+// transit key, read from Vault when first needed and again, at most once per
+// MinRefreshInterval, when a signature does not verify, so that tokens signed
+// before and after a rotation of the key are accepted.
+//
+// Calls failing with a server error are retried, and every attempt is reported
+// to Key.Hooks, so that latency and error rates can be recorded.
+//
+// vaulttransit is a separate module so that users of the jwt package do not
+// depend on the Vault API client.
+package vaulttransit
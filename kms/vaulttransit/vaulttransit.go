@@ -0,0 +1,319 @@
+package vaulttransit
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/internal/retry"
+	"github.com/hashicorp/vault/api"
+)
+
+// Defaults for Key.
+const (
+	DefaultMount              = "transit"
+	DefaultTimeout            = 5 * time.Second
+	DefaultMinRefreshInterval = time.Minute
+)
+
+// ErrUnexpectedResponse is returned when Vault responds without the expected
+// data.
+var ErrUnexpectedResponse = errors.New("vaulttransit: unexpected response from Vault")
+
+// Client is the subset of the Vault client used by the signing methods. It is
+// implemented by *api.Logical.
+type Client interface {
+	ReadWithContext(ctx context.Context, path string) (*api.Secret, error)
+	WriteWithContext(ctx context.Context, path string, data map[string]interface{}) (*api.Secret, error)
+}
+
+// RetryPolicy controls the retry of calls failing with a server error. Zero
+// fields take the defaults of three attempts and a backoff doubling from
+// 100ms up to 2s.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Call describes an attempted call to Vault.
+type Call struct {
+	Operation string // "sign" or "keys"
+	Name      string // The transit key
+	Attempt   int    // Starting at 1
+	Duration  time.Duration
+	Err       error
+}
+
+// Hooks are called on events of a Key. Fields may be nil.
+type Hooks struct {
+	OnCall func(Call) // Called after every attempted call, for latency and error metrics
+}
+
+// Key identifies a transit key and the client used to reach it. A Key is safe
+// for concurrent use.
+type Key struct {
+	Client  Client
+	Mount   string        // Optional. The path of the transit engine; defaults to DefaultMount
+	Name    string        // The name of the transit key
+	Version int           // Optional. The version signing tokens; defaults to the latest
+	Timeout time.Duration // Optional. Bounds each attempt; defaults to DefaultTimeout
+	Retry   RetryPolicy
+	Hooks   Hooks
+
+	// MinRefreshInterval is the minimum time between reads of the public
+	// keys prompted by signatures which do not verify. Defaults to
+	// DefaultMinRefreshInterval.
+	MinRefreshInterval time.Duration
+
+	mu        sync.Mutex
+	public    *jwt.VerificationKeySet
+	fetchedAt time.Time
+}
+
+func (k *Key) path(op string) string {
+	mount := k.Mount
+	if mount == "" {
+		mount = DefaultMount
+	}
+	return strings.Trim(mount, "/") + "/" + op + "/" + k.Name
+}
+
+// call calls fn under the retry policy, reporting every attempt to the hooks.
+func (k *Key) call(op string, fn func(ctx context.Context) error) error {
+	timeout := k.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	var observe func(int, time.Duration, error)
+	if k.Hooks.OnCall != nil {
+		observe = func(attempt int, d time.Duration, err error) {
+			k.Hooks.OnCall(Call{Operation: op, Name: k.Name, Attempt: attempt, Duration: d, Err: err})
+		}
+	}
+	return retry.Do(context.Background(), k.Retry.MaxAttempts, k.Retry.InitialBackoff, k.Retry.MaxBackoff,
+		retryable, observe, func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return fn(ctx)
+		})
+}
+
+func retryable(err error) bool {
+	var resp *api.ResponseError
+	if errors.As(err, &resp) {
+		return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// PublicKeys returns the public keys of every version of the transit key,
+// identified by version number, read from Vault on the first call.
+func (k *Key) PublicKeys() (*jwt.VerificationKeySet, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.public != nil {
+		return k.public, nil
+	}
+	return k.fetchLocked()
+}
+
+// refresh reads the public keys again, unless they were read within
+// MinRefreshInterval. It reports whether they were.
+func (k *Key) refresh() bool {
+	interval := k.MinRefreshInterval
+	if interval <= 0 {
+		interval = DefaultMinRefreshInterval
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if time.Since(k.fetchedAt) < interval {
+		return false
+	}
+	_, err := k.fetchLocked()
+	return err == nil
+}
+
+func (k *Key) fetchLocked() (*jwt.VerificationKeySet, error) {
+	var secret *api.Secret
+	err := k.call("keys", func(ctx context.Context) (err error) {
+		secret, err = k.Client.ReadWithContext(ctx, k.path("keys"))
+		return err
+	})
+	k.fetchedAt = time.Now()
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, ErrUnexpectedResponse
+	}
+	versions, _ := secret.Data["keys"].(map[string]interface{})
+	set := new(jwt.VerificationKeySet)
+	for version, v := range versions {
+		info, _ := v.(map[string]interface{})
+		pem, _ := info["public_key"].(string)
+		if pem == "" {
+			continue
+		}
+		pub, err := parsePublicKey([]byte(pem))
+		if err != nil {
+			return nil, fmt.Errorf("vaulttransit: version %s: %w", version, err)
+		}
+		set.Add(version, pub)
+	}
+	if len(set.Keys) == 0 {
+		return nil, ErrUnexpectedResponse
+	}
+	k.public = set
+	return set, nil
+}
+
+func parsePublicKey(pem []byte) (crypto.PublicKey, error) {
+	if pub, err := jwt.ParseRSAPublicKeyFromPEM(pem); err == nil {
+		return pub, nil
+	}
+	if pub, err := jwt.ParseECPublicKeyFromPEM(pem); err == nil {
+		return pub, nil
+	}
+	return jwt.ParseEdPublicKeyFromPEM(pem)
+}
+
+// SigningMethod signs tokens with a transit key. It implements
+// jwt.SigningMethod.
+type SigningMethod struct {
+	local     jwt.SigningMethod // Verifies with public keys
+	hash      crypto.Hash
+	hashName  string // The transit hash_algorithm
+	algorithm string // The transit signature_algorithm, for RSA keys
+	ecdsa     bool
+}
+
+// The signing methods, named after the JWS algorithms.
+var (
+	SigningMethodRS256 = &SigningMethod{jwt.SigningMethodRS256, crypto.SHA256, "sha2-256", "pkcs1v15", false}
+	SigningMethodRS384 = &SigningMethod{jwt.SigningMethodRS384, crypto.SHA384, "sha2-384", "pkcs1v15", false}
+	SigningMethodRS512 = &SigningMethod{jwt.SigningMethodRS512, crypto.SHA512, "sha2-512", "pkcs1v15", false}
+	SigningMethodPS256 = &SigningMethod{jwt.SigningMethodPS256, crypto.SHA256, "sha2-256", "pss", false}
+	SigningMethodPS384 = &SigningMethod{jwt.SigningMethodPS384, crypto.SHA384, "sha2-384", "pss", false}
+	SigningMethodPS512 = &SigningMethod{jwt.SigningMethodPS512, crypto.SHA512, "sha2-512", "pss", false}
+	SigningMethodES256 = &SigningMethod{jwt.SigningMethodES256, crypto.SHA256, "sha2-256", "", true}
+	SigningMethodES384 = &SigningMethod{jwt.SigningMethodES384, crypto.SHA384, "sha2-384", "", true}
+	SigningMethodES512 = &SigningMethod{jwt.SigningMethodES512, crypto.SHA512, "sha2-512", "", true}
+)
+
+var methods = []*SigningMethod{
+	SigningMethodRS256, SigningMethodRS384, SigningMethodRS512,
+	SigningMethodPS256, SigningMethodPS384, SigningMethodPS512,
+	SigningMethodES256, SigningMethodES384, SigningMethodES512,
+}
+
+// Registry returns a registry of the signing methods, for use with
+// jwt.WithSigningMethods.
+func Registry() *jwt.SigningMethodRegistry {
+	r := jwt.NewSigningMethodRegistry()
+	for _, m := range methods {
+		m := m
+		r.Register(m.Alg(), func() jwt.SigningMethod { return m })
+	}
+	return r
+}
+
+// Alg returns the JWS algorithm of the method.
+func (m *SigningMethod) Alg() string {
+	return m.local.Alg()
+}
+
+// Sign signs signingString with key, which must be a *Key. ECDSA signatures
+// are requested in the JWS encoding.
+func (m *SigningMethod) Sign(signingString string, key interface{}) (string, error) {
+	k, ok := key.(*Key)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+
+	h := m.hash.New()
+	h.Write([]byte(signingString))
+	data := map[string]interface{}{
+		"input":     base64.StdEncoding.EncodeToString(h.Sum(nil)),
+		"prehashed": true,
+	}
+	if m.ecdsa {
+		data["marshaling_algorithm"] = "jws"
+	} else {
+		data["signature_algorithm"] = m.algorithm
+		if m.algorithm == "pss" {
+			data["salt_length"] = "hash"
+		}
+	}
+	if k.Version > 0 {
+		data["key_version"] = k.Version
+	}
+
+	var secret *api.Secret
+	err := k.call("sign", func(ctx context.Context) (err error) {
+		secret, err = k.Client.WriteWithContext(ctx, k.path("sign")+"/"+m.hashName, data)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	if secret == nil {
+		return "", ErrUnexpectedResponse
+	}
+
+	// The signature is prefixed with "vault:v<version>:".
+	sig, _ := secret.Data["signature"].(string)
+	parts := strings.SplitN(sig, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" {
+		return "", ErrUnexpectedResponse
+	}
+	if m.ecdsa {
+		return strings.TrimRight(parts[2], "="), nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrUnexpectedResponse
+	}
+	return jwt.EncodeSegment(raw), nil
+}
+
+// Verify verifies signature with key. If key is a *Key, the public keys of all
+// its versions are tried; otherwise key is used as by the standard method of
+// the same algorithm.
+func (m *SigningMethod) Verify(signingString, signature string, key interface{}) error {
+	k, ok := key.(*Key)
+	if !ok {
+		return m.local.Verify(signingString, signature, key)
+	}
+	set, err := k.PublicKeys()
+	if err != nil {
+		return err
+	}
+	if err = m.verifySet(signingString, signature, set); err != nil && k.refresh() {
+		if set, err = k.PublicKeys(); err == nil {
+			err = m.verifySet(signingString, signature, set)
+		}
+	}
+	return err
+}
+
+func (m *SigningMethod) verifySet(signingString, signature string, set *jwt.VerificationKeySet) error {
+	err := jwt.ErrInvalidKeyType
+	for _, vk := range set.Keys {
+		verr := m.local.Verify(signingString, signature, vk.Key)
+		if verr == nil {
+			return nil
+		}
+		if !errors.Is(verr, jwt.ErrInvalidKeyType) {
+			err = verr
+		}
+	}
+	return err
+}
@@ -0,0 +1,174 @@
+package vaulttransit_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/kms/vaulttransit"
+	"github.com/hashicorp/vault/api"
+)
+
+// fakeVault implements the transit sign and keys endpoints with local keys,
+// the last of which is the latest version. The first failures calls fail with
+// a server error.
+type fakeVault struct {
+	versions []crypto.Signer
+	failures int
+	reads    int
+}
+
+func (f *fakeVault) ReadWithContext(ctx context.Context, path string) (*api.Secret, error) {
+	f.reads++
+	keys := map[string]interface{}{}
+	for i, key := range f.versions {
+		der, _ := x509.MarshalPKIXPublicKey(key.Public())
+		keys[strconv.Itoa(i+1)] = map[string]interface{}{
+			"public_key": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		}
+	}
+	return &api.Secret{Data: map[string]interface{}{"keys": keys}}, nil
+}
+
+func (f *fakeVault) WriteWithContext(ctx context.Context, path string, data map[string]interface{}) (*api.Secret, error) {
+	if f.failures > 0 {
+		f.failures--
+		return nil, &api.ResponseError{StatusCode: 503}
+	}
+	if !strings.HasPrefix(path, "transit/sign/signing/") || data["prehashed"] != true {
+		return nil, &api.ResponseError{StatusCode: 400}
+	}
+	digest, _ := base64.StdEncoding.DecodeString(data["input"].(string))
+	version := len(f.versions)
+	key := f.versions[version-1]
+
+	var encoded string
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]byte, 64)
+		r.FillBytes(out[:32])
+		s.FillBytes(out[32:])
+		encoded = base64.RawURLEncoding.EncodeToString(out)
+	case *rsa.PrivateKey:
+		var sig []byte
+		var err error
+		if data["signature_algorithm"] == "pss" {
+			sig, err = rsa.SignPSS(rand.Reader, k, crypto.SHA256, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest)
+		}
+		if err != nil {
+			return nil, err
+		}
+		encoded = base64.StdEncoding.EncodeToString(sig)
+	}
+	return &api.Secret{Data: map[string]interface{}{"signature": "vault:v" + strconv.Itoa(version) + ":" + encoded}}, nil
+}
+
+func TestSigningMethod(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	var tests = []struct {
+		method *vaulttransit.SigningMethod
+		key    crypto.Signer
+	}{
+		{vaulttransit.SigningMethodRS256, rsaKey},
+		{vaulttransit.SigningMethodPS256, rsaKey},
+		{vaulttransit.SigningMethodES256, p256},
+	}
+	for _, data := range tests {
+		alg := data.method.Alg()
+		key := &vaulttransit.Key{Client: &fakeVault{versions: []crypto.Signer{data.key}}, Name: "signing"}
+		token, err := jwt.NewWithClaims(data.method, jwt.MapClaims{"sub": "a"}).SignedString(key)
+		if err != nil {
+			t.Errorf("[%v] Error signing: %v", alg, err)
+			continue
+		}
+		if _, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return data.key.Public(), nil }); err != nil {
+			t.Errorf("[%v] Error verifying locally: %v", alg, err)
+		}
+		parser := jwt.NewParser(jwt.WithSigningMethods(vaulttransit.Registry()))
+		if _, err = parser.Parse(token, func(*jwt.Token) (interface{}, error) { return key, nil }); err != nil {
+			t.Errorf("[%v] Error verifying with the public keys: %v", alg, err)
+		}
+	}
+}
+
+func TestKey_rotation(t *testing.T) {
+	v1, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	v2, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	vault := &fakeVault{versions: []crypto.Signer{v1}}
+	key := &vaulttransit.Key{Client: vault, Name: "signing", MinRefreshInterval: time.Nanosecond}
+	parser := jwt.NewParser(jwt.WithSigningMethods(vaulttransit.Registry()))
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+
+	old, err := jwt.NewWithClaims(vaulttransit.SigningMethodES256, jwt.MapClaims{}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = parser.Parse(old, keyFunc); err != nil {
+		t.Fatalf("Error verifying: %v", err)
+	}
+
+	// Rotate the key: tokens of either version must verify.
+	vault.versions = append(vault.versions, v2)
+	current, err := jwt.NewWithClaims(vaulttransit.SigningMethodES256, jwt.MapClaims{}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, token := range []string{current, old} {
+		if _, err = parser.Parse(token, keyFunc); err != nil {
+			t.Errorf("Error verifying after rotation: %v", err)
+		}
+	}
+	if vault.reads != 2 {
+		t.Errorf("keys read %d times, want 2", vault.reads)
+	}
+
+	forged := current[:strings.LastIndex(current, ".")+1] + jwt.EncodeSegment(make([]byte, 64))
+	if _, err = parser.Parse(forged, keyFunc); !errors.Is(err, jwt.ErrSignatureInvalid) {
+		t.Errorf("Expected ErrSignatureInvalid, got %v", err)
+	}
+}
+
+func TestKey_retry(t *testing.T) {
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	vault := &fakeVault{versions: []crypto.Signer{p256}, failures: 2}
+	var calls []vaulttransit.Call
+	key := &vaulttransit.Key{
+		Client: vault,
+		Name:   "signing",
+		Retry:  vaulttransit.RetryPolicy{InitialBackoff: time.Millisecond},
+		Hooks:  vaulttransit.Hooks{OnCall: func(c vaulttransit.Call) { calls = append(calls, c) }},
+	}
+	if _, err := vaulttransit.SigningMethodES256.Sign("a.b", key); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(calls) != 3 || calls[0].Err == nil || calls[2].Err != nil || calls[2].Operation != "sign" {
+		t.Errorf("Unexpected calls: %+v", calls)
+	}
+
+	vault.failures = 5
+	var resp *api.ResponseError
+	if _, err := vaulttransit.SigningMethodES256.Sign("a.b", key); !errors.As(err, &resp) || resp.StatusCode != 503 {
+		t.Errorf("Expected a 503 error after exhausting retries, got %v", err)
+	}
+}
+
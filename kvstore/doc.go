@@ -0,0 +1,9 @@
+// Package kvstore defines the key-value Store used for server-side token
+// state, such as revocations and refresh token lineage, so that the backing
+// database can be chosen by the application.
+//
+// Memory is an in-process implementation suitable for a single instance and
+// for tests. Blocklist and ReplayDetector adapt any Store to jwt.Blocklist and
+// jwt.ReplayDetector; see the Redis example for a Store shared between
+// instances.
+package kvstore
@@ -0,0 +1,65 @@
+package kvstore_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/kvstore"
+)
+
+// redisClient is the part of a Redis client used by redisStore, in the form
+// of the Do method offered by most clients.
+type redisClient interface {
+	Do(ctx context.Context, args ...interface{}) (interface{}, error)
+}
+
+// redisStore is a kvstore.Store and kvstore.Adder kept in Redis, so that
+// every instance of a service shares revocations and seen identifiers.
+type redisStore struct {
+	client redisClient
+}
+
+func (s redisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	v, err := s.client.Do(ctx, "GET", key)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, kvstore.ErrNotFound
+	}
+	return v.([]byte), nil
+}
+
+func (s redisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []interface{}{"SET", key, value}
+	if ttl > 0 {
+		args = append(args, "PX", ttl.Milliseconds())
+	}
+	_, err := s.client.Do(ctx, args...)
+	return err
+}
+
+func (s redisStore) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	v, err := s.client.Do(ctx, "SET", key, value, "NX", "PX", ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	return v != nil, nil
+}
+
+func (s redisStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.Do(ctx, "DEL", key)
+	return err
+}
+
+func ExampleReplayDetector_redis() {
+	var client redisClient // such as a connection pool of a Redis client library
+
+	v := jwt.NewValidator(jwt.WithReplayDetection(&kvstore.ReplayDetector{
+		Store:  redisStore{client},
+		Prefix: "jti:",
+	}, 5*time.Minute))
+	p := jwt.NewParser()
+	p.Validator = v
+}
@@ -0,0 +1,88 @@
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// ErrNotFound is returned by Store.Get for keys which are absent or expired.
+var ErrNotFound = errors.New("kvstore: key not found")
+
+// Store is a key-value store with expiring entries. Implementations must be
+// safe for concurrent use.
+type Store interface {
+	// Get returns the value of key, or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key. The entry expires after ttl, or never if
+	// ttl is 0.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes key. Deleting an absent key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Adder is implemented by Stores which can set a key only if it is absent, in
+// a single atomic step, such as with the Redis command SET NX.
+type Adder interface {
+	// Add stores value under key, expiring after ttl, unless key is already
+	// present. It reports whether the value was stored.
+	Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+}
+
+// Blocklist is a jwt.Blocklist kept in a Store.
+type Blocklist struct {
+	Store  Store
+	Prefix string // Optional. Prepended to the keys of the Store, to share it with other uses
+}
+
+// Block implements jwt.Blocklist.
+func (b *Blocklist) Block(ctx context.Context, key string, until time.Time) error {
+	ttl := until.Sub(jwt.TimeFunc())
+	if ttl <= 0 {
+		return nil
+	}
+	return b.Store.Set(ctx, b.Prefix+key, []byte{1}, ttl)
+}
+
+// Blocked implements jwt.Blocklist.
+func (b *Blocklist) Blocked(ctx context.Context, key string) (bool, error) {
+	_, err := b.Store.Get(ctx, b.Prefix+key)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, ErrNotFound):
+		return false, nil
+	}
+	return false, err
+}
+
+// ReplayDetector is a jwt.ReplayDetector kept in a Store. If the Store
+// implements Adder, identifiers are recorded atomically; otherwise two
+// instances presented the same token at once may both accept it.
+type ReplayDetector struct {
+	Store  Store
+	Prefix string // Optional. Prepended to the keys of the Store, to share it with other uses
+}
+
+// Seen implements jwt.ReplayDetector.
+func (d *ReplayDetector) Seen(ctx context.Context, id string, until time.Time) (bool, error) {
+	ttl := until.Sub(jwt.TimeFunc())
+	if ttl <= 0 {
+		ttl = time.Second
+	}
+	key := d.Prefix + id
+	if a, ok := d.Store.(Adder); ok {
+		added, err := a.Add(ctx, key, []byte{1}, ttl)
+		return !added, err
+	}
+	_, err := d.Store.Get(ctx, key)
+	switch {
+	case err == nil:
+		return true, nil
+	case !errors.Is(err, ErrNotFound):
+		return false, err
+	}
+	return false, d.Store.Set(ctx, key, []byte{1}, ttl)
+}
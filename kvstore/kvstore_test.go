@@ -0,0 +1,46 @@
+package kvstore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/kvstore"
+)
+
+func TestBlocklist(t *testing.T) {
+	ctx := context.Background()
+	var bl jwt.Blocklist = &kvstore.Blocklist{Store: new(kvstore.Memory), Prefix: "revoked:"}
+
+	_ = bl.Block(ctx, "a", jwt.TimeFunc().Add(time.Hour))
+	_ = bl.Block(ctx, "b", jwt.TimeFunc().Add(-time.Hour))
+
+	if blocked, err := bl.Blocked(ctx, "a"); err != nil || !blocked {
+		t.Errorf("Expected a to be blocked. Got: %v, %v", blocked, err)
+	}
+	if blocked, err := bl.Blocked(ctx, "b"); err != nil || blocked {
+		t.Errorf("Expected b not to be blocked. Got: %v, %v", blocked, err)
+	}
+}
+
+func TestReplayDetector(t *testing.T) {
+	ctx := context.Background()
+	until := jwt.TimeFunc().Add(time.Minute)
+	stores := []kvstore.Store{new(kvstore.Memory), getSetOnly{new(kvstore.Memory)}}
+	for _, store := range stores {
+		var d jwt.ReplayDetector = &kvstore.ReplayDetector{Store: store, Prefix: "jti:"}
+		if seen, err := d.Seen(ctx, "a", until); err != nil || seen {
+			t.Errorf("%T: Seen(a) = %v, %v, want false", store, seen, err)
+		}
+		if seen, err := d.Seen(ctx, "a", until); err != nil || !seen {
+			t.Errorf("%T: Seen(a) = %v, %v, want true", store, seen, err)
+		}
+		if _, err := store.Get(ctx, "jti:a"); err != nil {
+			t.Errorf("%T: expected the prefixed key to be set: %v", store, err)
+		}
+	}
+}
+
+// getSetOnly hides the Adder implementation of a Store.
+type getSetOnly struct{ kvstore.Store }
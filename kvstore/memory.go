@@ -0,0 +1,107 @@
+package kvstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/internal/lru"
+)
+
+// Memory is a Store held in memory. Expired entries are removed as they are
+// looked up, and swept out from time to time as new ones are set. The zero
+// value is ready to use.
+//
+// If MaxEntries is set, the least recently used entries are evicted once it is
+// reached.
+type Memory struct {
+	MaxEntries int       // Optional. Zero means no limit
+	Hooks      jwt.Hooks // Optional. OnCacheEvent is reported to as "kvstore"
+
+	mu      sync.Mutex
+	entries lru.Expiring
+	stats   jwt.CacheStats
+}
+
+// Get implements Store.
+func (m *Memory) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	v, _, ok := m.entries.Lookup(key, jwt.TimeFunc())
+	event := jwt.CacheMiss
+	if ok {
+		event = jwt.CacheHit
+	}
+	m.stats.Count(event)
+	m.mu.Unlock()
+	m.Hooks.ReportCacheEvent("kvstore", event)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), v.([]byte)...), nil
+}
+
+// Set implements Store.
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	evictions := m.set(key, value, ttl)
+	m.mu.Unlock()
+	m.reportEvictions(evictions)
+	return nil
+}
+
+// Add implements Adder.
+func (m *Memory) Add(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	if _, ok := m.entries.Until(key, jwt.TimeFunc()); ok {
+		m.mu.Unlock()
+		return false, nil
+	}
+	evictions := m.set(key, value, ttl)
+	m.mu.Unlock()
+	m.reportEvictions(evictions)
+	return true, nil
+}
+
+// set sets key, returning the number of entries evicted to make room.
+func (m *Memory) set(key string, value []byte, ttl time.Duration) uint64 {
+	now := jwt.TimeFunc()
+	var until time.Time // zero for entries which do not expire
+	if ttl > 0 {
+		until = now.Add(ttl)
+	}
+	m.entries.MaxEntries = m.MaxEntries
+	m.entries.OnEvict = m.evicted
+	evictions := m.stats.Evictions
+	m.entries.Set(key, append([]byte(nil), value...), until, now)
+	return m.stats.Evictions - evictions
+}
+
+func (m *Memory) evicted(string, interface{}) {
+	m.stats.Count(jwt.CacheEviction)
+}
+
+// reportEvictions reports n evictions to the OnCacheEvent hook, once the lock
+// is released.
+func (m *Memory) reportEvictions(n uint64) {
+	for ; n > 0; n-- {
+		m.Hooks.ReportCacheEvent("kvstore", jwt.CacheEviction)
+	}
+}
+
+// Delete implements Store.
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries.Remove(key)
+	return nil
+}
+
+// Stats returns a snapshot of the counters of m.
+func (m *Memory) Stats() jwt.CacheStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.stats
+	s.Entries = m.entries.Len()
+	return s
+}
@@ -0,0 +1,67 @@
+package kvstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/kvstore"
+)
+
+func TestMemory(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	ctx := context.Background()
+	var m kvstore.Memory
+	_ = m.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = m.Set(ctx, "b", []byte("2"), 0)
+
+	if v, err := m.Get(ctx, "a"); err != nil || string(v) != "1" {
+		t.Errorf("Unexpected value of a: %s, %v", v, err)
+	}
+	if _, err := m.Get(ctx, "c"); !errors.Is(err, kvstore.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound. Got: %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := m.Get(ctx, "a"); !errors.Is(err, kvstore.ErrNotFound) {
+		t.Errorf("Expected a to have expired. Got: %v", err)
+	}
+	if v, err := m.Get(ctx, "b"); err != nil || string(v) != "2" {
+		t.Errorf("Unexpected value of b: %s, %v", v, err)
+	}
+
+	_ = m.Delete(ctx, "b")
+	if _, err := m.Get(ctx, "b"); !errors.Is(err, kvstore.ErrNotFound) {
+		t.Errorf("Expected b to be deleted. Got: %v", err)
+	}
+}
+
+func TestMemory_MaxEntries(t *testing.T) {
+	ctx := context.Background()
+	var evictions int
+	m := &kvstore.Memory{
+		MaxEntries: 2,
+		Hooks: jwt.Hooks{OnCacheEvent: func(cache string, e jwt.CacheEvent) {
+			if cache == "kvstore" && e == jwt.CacheEviction {
+				evictions++
+			}
+		}},
+	}
+	_ = m.Set(ctx, "a", []byte("1"), 0)
+	_ = m.Set(ctx, "b", []byte("2"), 0)
+	_, _ = m.Get(ctx, "a")
+	_ = m.Set(ctx, "c", []byte("3"), 0)
+
+	if _, err := m.Get(ctx, "b"); !errors.Is(err, kvstore.ErrNotFound) {
+		t.Errorf("Expected b to be evicted. Got: %v", err)
+	}
+	want := jwt.CacheStats{Entries: 2, Hits: 1, Misses: 1, Evictions: 1}
+	if got := m.Stats(); got != want || evictions != 1 {
+		t.Errorf("Stats() = %+v, want %+v; %d evictions reported", got, want, evictions)
+	}
+}
@@ -0,0 +1,151 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultSweepInterval is how often the in-memory stores which implement
+// Component sweep out expired entries when their SweepInterval is unset.
+const DefaultSweepInterval = time.Minute
+
+var (
+	// ErrRuntimeStarted is returned by Runtime.Start and Runtime.Add once the
+	// Runtime has been started.
+	ErrRuntimeStarted = errors.New("jwt: runtime already started")
+
+	// ErrComponentStarted is returned by the Start method of a Component of
+	// this package which is already running.
+	ErrComponentStarted = errors.New("jwt: component already started")
+)
+
+// Component is implemented by types which run work in the background, such
+// as jwk.Remote refreshing its key set, and VerificationCache,
+// MemoryReplayDetector and MemoryBlocklist sweeping out expired entries.
+// Start launches the background work and returns once it is running; Close
+// stops it and waits for it to exit.
+type Component interface {
+	Start(ctx context.Context) error
+	Close() error
+}
+
+// Runtime starts and stops a group of Components together, so that a service
+// can bring up all of its background goroutines on startup and tear them down
+// on shutdown, or at the end of a test.
+//
+// Components are started in the order they were added and closed in reverse.
+// The zero value is ready to use. A Runtime is safe for concurrent use.
+type Runtime struct {
+	mu         sync.Mutex
+	components []Component
+	started    []Component
+	running    bool
+}
+
+// NewRuntime returns a Runtime managing components.
+func NewRuntime(components ...Component) *Runtime {
+	return &Runtime{components: components}
+}
+
+// Add adds components to r. Components cannot be added to a running Runtime.
+func (r *Runtime) Add(components ...Component) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running {
+		return ErrRuntimeStarted
+	}
+	r.components = append(r.components, components...)
+	return nil
+}
+
+// Start starts every component in order. If one fails to start, those already
+// started are closed and the error is returned, joined with any errors from
+// closing them.
+func (r *Runtime) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running {
+		return ErrRuntimeStarted
+	}
+	for _, c := range r.components {
+		if err := c.Start(ctx); err != nil {
+			return JoinErrors(err, r.closeLocked())
+		}
+		r.started = append(r.started, c)
+	}
+	r.running = true
+	return nil
+}
+
+// Close closes the started components in reverse order and returns their
+// errors joined. Closing a Runtime which is not running does nothing. A closed
+// Runtime may be started again.
+func (r *Runtime) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.running = false
+	return r.closeLocked()
+}
+
+// sweeper runs the periodic sweeps of the in-memory stores implementing
+// Component.
+type sweeper struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// start calls sweep every interval, or DefaultSweepInterval, in the
+// background until close is called or ctx is done.
+func (s *sweeper) start(ctx context.Context, interval time.Duration, sweep func()) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		return ErrComponentStarted
+	}
+	if interval <= 0 {
+		interval = DefaultSweepInterval
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	go func(done chan struct{}) {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sweep()
+			}
+		}
+	}(s.done)
+	return nil
+}
+
+// close stops the sweeps begun by start and waits for any in progress.
+func (s *sweeper) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done == nil {
+		return nil
+	}
+	s.cancel()
+	<-s.done
+	s.cancel, s.done = nil, nil
+	return nil
+}
+
+func (r *Runtime) closeLocked() error {
+	var errs []error
+	for i := len(r.started) - 1; i >= 0; i-- {
+		if err := r.started[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	r.started = nil
+	return JoinErrors(errs...)
+}
@@ -0,0 +1,111 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+type component struct {
+	name     string
+	log      *[]string
+	startErr error
+	closeErr error
+}
+
+func (c *component) Start(ctx context.Context) error {
+	*c.log = append(*c.log, "start "+c.name)
+	return c.startErr
+}
+
+func (c *component) Close() error {
+	*c.log = append(*c.log, "close "+c.name)
+	return c.closeErr
+}
+
+func TestRuntime(t *testing.T) {
+	var log []string
+	errClose := errors.New("close failed")
+	rt := jwt.NewRuntime(&component{name: "a", log: &log}, &component{name: "b", log: &log, closeErr: errClose})
+	if err := rt.Add(&component{name: "c", log: &log}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := rt.Start(context.Background()); !errors.Is(err, jwt.ErrRuntimeStarted) {
+		t.Errorf("second Start: got %v, want %v", err, jwt.ErrRuntimeStarted)
+	}
+	if err := rt.Add(&component{name: "d", log: &log}); !errors.Is(err, jwt.ErrRuntimeStarted) {
+		t.Errorf("Add while running: got %v, want %v", err, jwt.ErrRuntimeStarted)
+	}
+	if err := rt.Close(); !errors.Is(err, errClose) {
+		t.Errorf("Close: got %v, want %v", err, errClose)
+	}
+	if err := rt.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+
+	want := []string{"start a", "start b", "start c", "close c", "close b", "close a"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("got %q, want %q", log, want)
+	}
+}
+
+func TestRuntime_startFailure(t *testing.T) {
+	var log []string
+	errStart := errors.New("start failed")
+	rt := jwt.NewRuntime(
+		&component{name: "a", log: &log},
+		&component{name: "b", log: &log, startErr: errStart},
+		&component{name: "c", log: &log},
+	)
+	if err := rt.Start(context.Background()); !errors.Is(err, errStart) {
+		t.Fatalf("got %v, want %v", err, errStart)
+	}
+	want := []string{"start a", "start b", "close a"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("got %q, want %q", log, want)
+	}
+	if err := rt.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
+
+func TestRuntime_stores(t *testing.T) {
+	ctx := context.Background()
+	blocklist := &jwt.MemoryBlocklist{SweepInterval: time.Millisecond}
+	replay := &jwt.MemoryReplayDetector{SweepInterval: time.Millisecond}
+	cache := &jwt.VerificationCache{SweepInterval: time.Millisecond}
+	rt := jwt.NewRuntime(blocklist, replay, cache)
+	if err := rt.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Start(ctx); !errors.Is(err, jwt.ErrComponentStarted) {
+		t.Errorf("second Start: got %v, want %v", err, jwt.ErrComponentStarted)
+	}
+
+	// Entries which lapse without being looked up again are swept out.
+	lapsed := time.Now().Add(-time.Minute)
+	if err := blocklist.Block(ctx, "a", lapsed); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := replay.Seen(ctx, "a", lapsed); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for blocklist.Stats().Entries != 0 || replay.Stats().Entries != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expired entries not swept: %+v, %+v", blocklist.Stats(), replay.Stats())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := rt.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
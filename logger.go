@@ -0,0 +1,66 @@
+package jwt
+
+// Logger receives debug messages from a Parser, as a message followed by
+// alternating keys and values. *slog.Logger implements it, and other
+// structured loggers can be adapted with a small wrapper.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+}
+
+// Stages of parsing, logged as "stage" with the failures of a Parser.
+const (
+	stageSplit            = "segment_split"     // Checking the size and segments of the token
+	stageHeader           = "header_decode"     // Decoding the header
+	stageClaimsDecode     = "claims_decode"     // Decoding the claims
+	stageMethod           = "signing_method"    // Resolving and checking the algorithm and type
+	stageKeyfunc          = "keyfunc"           // Obtaining the key from the Keyfunc
+	stageClaimsValidation = "claims_validation" // Validating the claims
+	stageSignature        = "signature"         // Verifying the signature
+	stageRevocation       = "revocation"        // Checking for replay and revocation
+)
+
+// WithLogger makes the Parser log, at debug level, why each token it rejects
+// failed: the stage of parsing, the error, and the "alg", "kid" and "typ"
+// headers if they could be decoded. The token itself is never logged.
+func WithLogger(l Logger) ParserOption {
+	return func(p *Parser) {
+		p.Logger = l
+	}
+}
+
+// maxLoggedHeaderSize bounds the header values logged, which are chosen by
+// whoever presented the token.
+const maxLoggedHeaderSize = 64
+
+// maxLoggedErrorSize bounds the error text logged, which may quote values
+// from the token.
+const maxLoggedErrorSize = 256
+
+// logFailure logs the failure of tokenString at stage.
+func (p *Parser) logFailure(stage, tokenString string, token *Token, err error) {
+	if p.Logger == nil {
+		return
+	}
+	kv := []interface{}{"stage", stage, "error", truncate(err.Error(), maxLoggedErrorSize), "token_length", len(tokenString)}
+	if token != nil {
+		for _, name := range [...]string{"alg", "kid", "typ"} {
+			if v, ok := token.Header[name].(string); ok {
+				kv = append(kv, name, truncateHeader(v))
+			}
+		}
+	}
+	p.Logger.Debug("jwt: token rejected", kv...)
+}
+
+// truncateHeader cuts a header value to maxLoggedHeaderSize bytes.
+func truncateHeader(v string) string {
+	return truncate(v, maxLoggedHeaderSize)
+}
+
+// truncate cuts s to n bytes.
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,107 @@
+package jwt_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+type recordingLogger struct {
+	entries []map[string]interface{}
+}
+
+func (l *recordingLogger) Debug(msg string, kv ...interface{}) {
+	e := map[string]interface{}{"msg": msg}
+	for i := 0; i+1 < len(kv); i += 2 {
+		e[kv[i].(string)] = kv[i+1]
+	}
+	l.entries = append(l.entries, e)
+}
+
+func TestParser_Logger(t *testing.T) {
+	key := []byte("secret")
+	sign := func(claims jwt.Claims) string {
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims, jwt.WithKeyID("k1")).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	valid := sign(jwt.MapClaims{"sub": "a"})
+	parts := strings.Split(valid, ".")
+
+	tests := []struct {
+		token   string
+		keyFunc jwt.Keyfunc
+		stage   string
+	}{
+		{"a.b", nil, "segment_split"},
+		{"!!." + parts[1] + "." + parts[2], nil, "header_decode"},
+		{jwt.EncodeSegment([]byte("{")) + "." + parts[1] + "." + parts[2], nil, "header_decode"},
+		{parts[0] + ".!!." + parts[2], nil, "claims_decode"},
+		{jwt.EncodeSegment([]byte(`{"alg":"XX"}`)) + "." + parts[1] + "." + parts[2], nil, "signing_method"},
+		{valid, func(*jwt.Token) (interface{}, error) { return nil, fmt.Errorf("unknown key") }, "keyfunc"},
+		{sign(jwt.MapClaims{"exp": float64(time.Now().Add(-time.Hour).Unix())}), nil, "claims_validation"},
+		{valid + "x", nil, "signature"},
+	}
+	for _, tc := range tests {
+		l := new(recordingLogger)
+		keyFunc := tc.keyFunc
+		if keyFunc == nil {
+			keyFunc = func(*jwt.Token) (interface{}, error) { return key, nil }
+		}
+		if _, err := jwt.NewParser(jwt.WithLogger(l)).Parse(tc.token, keyFunc); err == nil {
+			t.Errorf("%s: expected an error", tc.stage)
+			continue
+		}
+		if len(l.entries) != 1 {
+			t.Errorf("%s: expected one log entry, got %v", tc.stage, l.entries)
+			continue
+		}
+		e := l.entries[0]
+		if e["stage"] != tc.stage || e["error"] == "" {
+			t.Errorf("%s: unexpected entry %v", tc.stage, e)
+		}
+		if tc.stage == "signature" && (e["alg"] != "HS256" || e["kid"] != "k1") {
+			t.Errorf("%s: expected the headers to be logged, got %v", tc.stage, e)
+		}
+		for k, v := range e {
+			if s, ok := v.(string); ok && k != "msg" && len(tc.token) > 8 && strings.Contains(s, tc.token[:8]) {
+				t.Errorf("%s: the token is logged in %q", tc.stage, k)
+			}
+		}
+	}
+
+	l := new(recordingLogger)
+	p := jwt.NewParser(jwt.WithLogger(l))
+	long := strings.Repeat("k", 10000)
+	var registry jwt.KeyRegistry
+	if _, err := p.Parse(sign(jwt.MapClaims{}), func(*jwt.Token) (interface{}, error) {
+		return nil, fmt.Errorf("unknown key %s", long)
+	}); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if _, err := p.Parse(jwt.EncodeSegment([]byte(`{"alg":"HS256","kid":"`+long+`"}`))+"."+parts[1]+"."+parts[2], registry.Keyfunc); err == nil || len(err.Error()) > 512 {
+		t.Errorf("Expected the key ID to be bounded in the error. Got: %d bytes", len(err.Error()))
+	}
+	for _, e := range l.entries {
+		if len(e["error"].(string)) > 256 {
+			t.Errorf("Expected the logged error to be bounded. Got: %d bytes", len(e["error"].(string)))
+		}
+	}
+
+	l = new(recordingLogger)
+	p = jwt.NewParser(jwt.WithLogger(l))
+	if _, err := p.Parse(valid, func(*jwt.Token) (interface{}, error) { return key, nil }); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := p.ParseUnverified("a.b", jwt.MapClaims{}); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if len(l.entries) != 1 || l.entries[0]["stage"] != "segment_split" {
+		t.Errorf("Expected only the failure to be logged, got %v", l.entries)
+	}
+}
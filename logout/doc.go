@@ -0,0 +1,8 @@
+// Package logout validates OpenID Connect Back-Channel Logout tokens as
+// described in https://openid.net/specs/openid-connect-backchannel-1_0.html.
+//
+// Claims holds and validates the claims of a logout token. Receiver verifies
+// logout tokens posted by an OpenID Provider and revokes the sessions they
+// name in a jwt.Blocklist, which LoggedOut consults when later requests
+// present tokens for those sessions.
+package logout
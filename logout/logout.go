@@ -0,0 +1,91 @@
+package logout
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// TokenType is the "typ" header of explicitly typed logout tokens.
+const TokenType = jwt.TypeLogoutToken
+
+// EventBackchannelLogout is the member of the "events" claim identifying a
+// logout token.
+const EventBackchannelLogout = "http://schemas.openid.net/event/backchannel-logout"
+
+var (
+	ErrInvalidType      = errors.New("logout: the token is not a logout token")
+	ErrInvalidEvents    = errors.New(`logout: the "events" claim does not contain a back-channel logout event`)
+	ErrMissingSubject   = errors.New(`logout: the token contains neither a "sub" nor a "sid" claim`)
+	ErrNonceProhibited  = errors.New(`logout: the token contains a "nonce" claim`)
+	ErrMissingBlocklist = errors.New("logout: Blocklist not provided")
+)
+
+// Claims are the claims of a logout token.
+type Claims struct {
+	jwt.RegisteredClaims
+
+	// the `events` claim, which must contain EventBackchannelLogout
+	Events map[string]json.RawMessage `json:"events,omitempty"`
+
+	// the `nonce` claim, which logout tokens must not contain
+	Nonce json.RawMessage `json:"nonce,omitempty"`
+}
+
+// Valid validates the time based claims, and the requirements specific to
+// logout tokens: "iss", "aud", "iat", "jti" and "events" are required, at
+// least one of "sub" and "sid" must be present, and "nonce" is prohibited.
+// All failures are reported, combined with jwt.JoinErrors.
+func (c *Claims) Valid() error {
+	errs := []error{c.RegisteredClaims.Valid()}
+
+	for name, missing := range map[string]bool{
+		"iss": c.Issuer == "",
+		"aud": len(c.Audience) == 0,
+		"iat": c.IssuedAt == nil,
+		"jti": c.ID == "",
+	} {
+		if missing {
+			errs = append(errs, &jwt.ValidationError{Err: jwt.ErrTokenRequiredClaimMissing, Claim: name})
+		}
+	}
+
+	var event map[string]interface{}
+	if raw, ok := c.Events[EventBackchannelLogout]; !ok || json.Unmarshal(raw, &event) != nil || event == nil {
+		errs = append(errs, ErrInvalidEvents)
+	}
+	if c.Subject == "" && c.SessionID == "" {
+		errs = append(errs, ErrMissingSubject)
+	}
+	if c.Nonce != nil {
+		errs = append(errs, ErrNonceProhibited)
+	}
+	return jwt.JoinErrors(errs...)
+}
+
+// Policy returns the jwt.Policy logout tokens from issuer, sent to the client
+// identified by clientID, must satisfy.
+func Policy(issuer, clientID string) jwt.Policy {
+	return jwt.Policy{
+		Issuers:         []string{issuer},
+		Audiences:       []string{clientID},
+		RequireIssuedAt: true,
+	}
+}
+
+// CheckType checks that the "typ" header of token is "logout+jwt". The
+// comparison is case-insensitive and the "application/" prefix is optional. If
+// allowMissing is true, tokens without a "typ" header are also accepted, for
+// providers which do not type their logout tokens explicitly.
+func CheckType(token *jwt.Token, allowMissing bool) error {
+	typ, ok := token.Header["typ"].(string)
+	if !ok && allowMissing {
+		return nil
+	}
+	if strings.TrimPrefix(strings.ToLower(typ), "application/") != TokenType {
+		return ErrInvalidType
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package logout_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/logout"
+)
+
+func newClaims() *logout.Claims {
+	return &logout.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://op.example.com",
+			Audience:  jwt.ClaimStrings{"client"},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        "bWJq",
+			SessionID: "08a5019c-17e1-4977-8f42-65a12843ea02",
+		},
+		Events: map[string]json.RawMessage{logout.EventBackchannelLogout: json.RawMessage(`{}`)},
+	}
+}
+
+func TestClaims_Valid(t *testing.T) {
+	var tests = []struct {
+		name   string
+		modify func(*logout.Claims)
+		err    error
+	}{
+		{"valid", func(*logout.Claims) {}, nil},
+		{"subject only", func(c *logout.Claims) { c.SessionID, c.Subject = "", "248289761001" }, nil},
+		{"no subject", func(c *logout.Claims) { c.SessionID = "" }, logout.ErrMissingSubject},
+		{"no events", func(c *logout.Claims) { c.Events = nil }, logout.ErrInvalidEvents},
+		{"event not an object", func(c *logout.Claims) {
+			c.Events[logout.EventBackchannelLogout] = json.RawMessage(`"x"`)
+		}, logout.ErrInvalidEvents},
+		{"nonce", func(c *logout.Claims) { c.Nonce = json.RawMessage(`"n-0S6_WzA2Mj"`) }, logout.ErrNonceProhibited},
+		{"no jti", func(c *logout.Claims) { c.ID = "" }, jwt.ErrTokenRequiredClaimMissing},
+	}
+
+	for _, data := range tests {
+		c := newClaims()
+		data.modify(c)
+		err := c.Valid()
+		if data.err == nil && err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		} else if !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+}
+
+func TestCheckType(t *testing.T) {
+	var tests = []struct {
+		typ          interface{}
+		allowMissing bool
+		valid        bool
+	}{
+		{"logout+jwt", false, true},
+		{"application/logout+JWT", false, true},
+		{"JWT", true, false},
+		{nil, false, false},
+		{nil, true, true},
+	}
+
+	for _, data := range tests {
+		token := &jwt.Token{Header: map[string]interface{}{}}
+		if data.typ != nil {
+			token.Header["typ"] = data.typ
+		}
+		if err := logout.CheckType(token, data.allowMissing); (err == nil) != data.valid {
+			t.Errorf("[%v] Unexpected result: %v", data.typ, err)
+		}
+	}
+}
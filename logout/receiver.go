@@ -0,0 +1,129 @@
+package logout
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// DefaultRevocationPeriod is how long a revoked session is kept in the
+// Blocklist when Receiver.RevokeFor is unset.
+const DefaultRevocationPeriod = 24 * time.Hour
+
+// Receiver verifies logout tokens sent by an OpenID Provider. It may be
+// mounted as the client's back-channel logout URI.
+type Receiver struct {
+	Parser           *jwt.Parser   // Optional. Defaults to a zero Parser
+	Keyfunc          jwt.Keyfunc   // Supplies the key for verifying logout tokens, such as the provider's JWKS
+	Issuer           string        // The issuer identifier of the provider
+	ClientID         string        // The client identifier, the expected audience
+	AllowMissingType bool          // Accept logout tokens without a "typ" header
+	Blocklist        jwt.Blocklist // Optional. The sessions named by logout tokens are revoked in it
+	RevokeFor        time.Duration // Optional. How long revocations are kept. Defaults to DefaultRevocationPeriod
+
+	// OnLogout, if set, is called with the claims of every valid logout
+	// token, after the session has been revoked. Logout tokens naming a
+	// subject but no session can only be acted on here. An error fails the
+	// request.
+	OnLogout func(ctx context.Context, claims *Claims) error
+}
+
+// Parse parses and validates a logout token.
+func (r *Receiver) Parse(logoutToken string) (*Claims, error) {
+	p := r.Parser
+	if p == nil {
+		p = new(jwt.Parser)
+	}
+	claims := new(Claims)
+	token, err := p.ParseWithClaims(logoutToken, claims, r.Keyfunc)
+	if err != nil {
+		return nil, err
+	}
+	if err = CheckType(token, r.AllowMissingType); err != nil {
+		return nil, err
+	}
+	v := jwt.Validator{Policy: Policy(r.Issuer, r.ClientID)}
+	if err = v.Validate(token); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Logout parses and validates a logout token, revokes the session it names in
+// the Blocklist and calls OnLogout.
+func (r *Receiver) Logout(ctx context.Context, logoutToken string) (*Claims, error) {
+	claims, err := r.Parse(logoutToken)
+	if err != nil {
+		return nil, err
+	}
+	if r.Blocklist != nil && claims.SessionID != "" {
+		period := r.RevokeFor
+		if period <= 0 {
+			period = DefaultRevocationPeriod
+		}
+		key := SessionKey(claims.Issuer, claims.SessionID)
+		if err = r.Blocklist.Block(ctx, key, jwt.TimeFunc().Add(period)); err != nil {
+			return nil, err
+		}
+	}
+	if r.OnLogout != nil {
+		if err = r.OnLogout(ctx, claims); err != nil {
+			return nil, err
+		}
+	}
+	return claims, nil
+}
+
+// ServeHTTP handles a logout request, the logout token being posted as the
+// "logout_token" form parameter. It responds with 200 OK on success, and with
+// 400 Bad Request and an OAuth error body otherwise.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if _, err := r.Logout(req.Context(), req.PostFormValue("logout_token")); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(&jwt.OAuthError{Code: jwt.OAuthErrorInvalidRequest, Description: err.Error()})
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// SessionKey returns the Blocklist key under which the session sid of issuer
+// is revoked.
+func SessionKey(issuer, sid string) string {
+	return "logout:sid:" + issuer + "#" + sid
+}
+
+// Sessions reports the sessions of an issuer revoked by logout tokens as
+// inactive. It can be used as the session store of jwtmiddleware.Options.
+type Sessions struct {
+	Blocklist jwt.Blocklist // The Blocklist of the Receiver
+	Issuer    string        // The issuer identifier of the provider
+}
+
+// ActiveSession reports whether the session sid has not been logged out.
+func (s *Sessions) ActiveSession(ctx context.Context, sid string) (bool, error) {
+	out, err := LoggedOut(ctx, s.Blocklist, s.Issuer, sid)
+	return !out, err
+}
+
+// LoggedOut reports whether the session sid of issuer has been revoked by a
+// logout token. Call it with the "iss" and "sid" claims of ID or access
+// tokens presented after the session was established.
+func LoggedOut(ctx context.Context, bl jwt.Blocklist, issuer, sid string) (bool, error) {
+	if bl == nil {
+		return false, ErrMissingBlocklist
+	}
+	if sid == "" {
+		return false, nil
+	}
+	return bl.Blocked(ctx, SessionKey(issuer, sid))
+}
@@ -0,0 +1,92 @@
+package logout_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/logout"
+)
+
+var key = []byte("secret")
+
+func sign(t *testing.T, claims *logout.Claims, typ string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["typ"] = typ
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestReceiver(t *testing.T) {
+	var bl jwt.MemoryBlocklist
+	var loggedOut []*logout.Claims
+	r := &logout.Receiver{
+		Keyfunc:   func(*jwt.Token) (interface{}, error) { return key, nil },
+		Issuer:    "https://op.example.com",
+		ClientID:  "client",
+		Blocklist: &bl,
+		OnLogout: func(_ context.Context, c *logout.Claims) error {
+			loggedOut = append(loggedOut, c)
+			return nil
+		},
+	}
+	claims := newClaims()
+
+	var tests = []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"logout token", sign(t, claims, logout.TokenType), http.StatusOK},
+		{"untyped", sign(t, claims, "JWT"), http.StatusBadRequest},
+		{"wrong audience", sign(t, func() *logout.Claims { c := newClaims(); c.Audience = jwt.ClaimStrings{"other"}; return c }(), logout.TokenType), http.StatusBadRequest},
+		{"missing", "", http.StatusBadRequest},
+	}
+
+	for _, data := range tests {
+		form := url.Values{"logout_token": {data.token}}
+		req := httptest.NewRequest(http.MethodPost, "/logout", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v. Got: %v %s", data.name, data.status, w.Code, w.Body)
+		}
+		if w.Header().Get("Cache-Control") != "no-store" {
+			t.Errorf("[%v] Expected Cache-Control: no-store", data.name)
+		}
+	}
+
+	if len(loggedOut) != 1 || loggedOut[0].SessionID != claims.SessionID {
+		t.Errorf("Expected OnLogout to be called once. Got: %v", loggedOut)
+	}
+	ctx := context.Background()
+	if out, err := logout.LoggedOut(ctx, &bl, claims.Issuer, claims.SessionID); err != nil || !out {
+		t.Errorf("Expected the session to be revoked. Got: %v, %v", out, err)
+	}
+	if out, _ := logout.LoggedOut(ctx, &bl, claims.Issuer, "other"); out {
+		t.Error("Expected other sessions not to be revoked")
+	}
+}
+
+func TestSessions(t *testing.T) {
+	ctx := context.Background()
+	var bl jwt.MemoryBlocklist
+	s := &logout.Sessions{Blocklist: &bl, Issuer: "https://op.example.com"}
+	_ = bl.Block(ctx, logout.SessionKey(s.Issuer, "ended"), jwt.TimeFunc().Add(time.Hour))
+
+	if active, err := s.ActiveSession(ctx, "ended"); err != nil || active {
+		t.Errorf("Expected the session to have ended. Got: %v, %v", active, err)
+	}
+	if active, err := s.ActiveSession(ctx, "other"); err != nil || !active {
+		t.Errorf("Expected the session to be active. Got: %v, %v", active, err)
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
-
-	"github.com/hashicorp/go-multierror"
 )
 
 // MapClaims is a claims type that uses the map[string]interface{} for JSON decoding.
@@ -81,23 +79,119 @@ func (m MapClaims) Issuer() interface{} {
 }
 
 func (m MapClaims) Audience() ([]string, error) {
-	var err *multierror.Error
-	var aud []string
-	switch v := m["aud"].(type) {
+	return claimStrings(m["aud"], "aud")
+}
+
+// claimStrings converts the value of a claim which may be a single string or
+// an array of strings. Entries which are not strings are reported.
+func claimStrings(value interface{}, claim string) ([]string, error) {
+	var errs []error
+	var list []string
+	switch v := value.(type) {
 	case string:
-		aud = append(aud, v)
+		list = append(list, v)
 	case []string:
-		aud = v
+		list = v
 	case []interface{}:
 		for _, a := range v {
 			if vs, ok := a.(string); ok {
-				aud = append(aud, vs)
+				list = append(list, vs)
 			} else {
-				err = multierror.Append(err, fmt.Errorf("aud entry [%v] is not a string", a))
+				errs = append(errs, fmt.Errorf("%s entry [%v] is not a string", claim, redact(claim, a)))
 			}
 		}
 	}
-	return aud, err.ErrorOrNil()
+	return list, JoinErrors(errs...)
+}
+
+// GetExpirationTime implements ClaimsGetter.
+func (m MapClaims) GetExpirationTime() (*NumericDate, error) {
+	return m.numericDate("exp")
+}
+
+// GetNotBefore implements ClaimsGetter.
+func (m MapClaims) GetNotBefore() (*NumericDate, error) {
+	return m.numericDate("nbf")
+}
+
+// GetIssuedAt implements ClaimsGetter.
+func (m MapClaims) GetIssuedAt() (*NumericDate, error) {
+	return m.numericDate("iat")
+}
+
+// GetIssuer implements ClaimsGetter.
+func (m MapClaims) GetIssuer() (string, error) {
+	return m.string("iss")
+}
+
+// GetSubject implements ClaimsGetter.
+func (m MapClaims) GetSubject() (string, error) {
+	return m.string("sub")
+}
+
+// GetAudience implements ClaimsGetter.
+func (m MapClaims) GetAudience() (ClaimStrings, error) {
+	switch m["aud"].(type) {
+	case nil, string, []string, []interface{}:
+	default:
+		return nil, m.invalidType("aud")
+	}
+	aud, err := m.Audience()
+	if err != nil {
+		return nil, m.invalidType("aud")
+	}
+	return aud, nil
+}
+
+// numericDate returns the named claim as a NumericDate. As with ExpiresAt,
+// IssuedAt and NotBefore, 0 is treated as absent.
+func (m MapClaims) numericDate(name string) (*NumericDate, error) {
+	var f float64
+	switch v := m[name].(type) {
+	case nil:
+		return nil, nil
+	case float64:
+		f = v
+	case json.Number:
+		var err error
+		if f, err = v.Float64(); err != nil {
+			return nil, m.invalidType(name)
+		}
+	default:
+		return nil, m.invalidType(name)
+	}
+	if f == 0 {
+		return nil, nil
+	}
+	return newNumericDateFromSeconds(f), nil
+}
+
+func (m MapClaims) string(name string) (string, error) {
+	switch v := m[name].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	default:
+		return "", m.invalidType(name)
+	}
+}
+
+func (m MapClaims) invalidType(name string) error {
+	return &ValidationError{Err: ErrInvalidClaimType, Claim: name}
+}
+
+// GetSessionID returns the sid field of the MapClaims, or the empty string if
+// it is unset. It is an error for sid to be anything but a string.
+func (m MapClaims) GetSessionID() (string, error) {
+	switch sid := m["sid"].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return sid, nil
+	default:
+		return "", fmt.Errorf("sid [%v] is not a string", redact("sid", sid))
+	}
 }
 
 // VerifyAudience Compares the aud claim against cmp.
@@ -173,37 +267,38 @@ func (m MapClaims) VerifyIssuer(cmp string, req bool) bool {
 	return false
 }
 
+// VerifySubject compares the sub claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (m MapClaims) VerifySubject(cmp string, req bool) bool {
+	sub, ok := m["sub"]
+	if !ok || sub == nil {
+		return verifyIss("", cmp, req)
+	}
+	str, ok := sub.(string)
+	return ok && verifyIss(str, cmp, req)
+}
+
 // Valid validates time based claims "exp, iat, nbf".
 // There is no accounting for clock skew.
 // As well, if any of the above claims are not in the token, it will still
 // be considered a valid claim.
 func (m MapClaims) Valid() error {
-	result := &multierror.Error{}
-	result.ErrorFormat = ValidationErrorFormat
+	var errs []error
 	now := TimeFunc()
 	nowUnix := now.Unix()
 	exp, _ := m.ExpiresAt().(time.Time)
 	if !m.VerifyExpiresAt(nowUnix, false) {
-		result = multierror.Append(result, &ExpiredError{
-			ExpiredAt:   exp,
-			AttemptedAt: now,
-		})
+		errs = append(errs, newExpiredError(exp, now))
 	}
 	if !m.VerifyIssuedAt(nowUnix, false) {
 		iat, _ := m.IssuedAt().(time.Time)
-		result = multierror.Append(result, &UsedBeforeIssuedError{
-			IssuedAt:    iat,
-			AttemptedAt: now,
-		})
+		errs = append(errs, newUsedBeforeIssuedError(iat, now))
 	}
 	if !m.VerifyNotBefore(nowUnix, false) {
 		nbf, _ := m.NotBefore().(time.Time)
 
-		result = multierror.Append(result, &NotYetValidError{
-			ValidAt:     nbf,
-			AttemptedAt: now,
-		})
+		errs = append(errs, newNotYetValidError(nbf, now))
 	}
-	return result.ErrorOrNil()
+	return JoinErrors(errs...)
 
 }
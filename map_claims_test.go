@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -97,3 +98,27 @@ func TestMapclaimsVerifyExpiresAtInvalidTypeString(t *testing.T) {
 		t.Fatalf("Failed to verify claims, wanted: %v got %v", want, got)
 	}
 }
+
+func TestMapClaims_GetAudience(t *testing.T) {
+	var tests = []struct {
+		claims  MapClaims
+		aud     ClaimStrings
+		invalid bool
+	}{
+		{MapClaims{}, nil, false},
+		{MapClaims{"aud": "api"}, ClaimStrings{"api"}, false},
+		{MapClaims{"aud": []interface{}{"api", "other"}}, ClaimStrings{"api", "other"}, false},
+		{MapClaims{"aud": []interface{}{"api", 1}}, nil, true},
+		{MapClaims{"aud": 1.0}, nil, true},
+	}
+
+	for _, data := range tests {
+		aud, err := data.claims.GetAudience()
+		if (err != nil) != data.invalid || !errors.Is(err, ErrInvalidClaimType) && data.invalid {
+			t.Errorf("[%v] Unexpected error: %v", data.claims, err)
+		}
+		if len(aud) != len(data.aud) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.claims, data.aud, aud)
+		}
+	}
+}
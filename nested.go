@@ -0,0 +1,181 @@
+package jwt
+
+import (
+	"context"
+	"strings"
+)
+
+// DefaultMaxNestingDepth is the number of enclosing tokens ParseNested unwraps
+// when Parser.MaxNestingDepth is unset.
+const DefaultMaxNestingDepth = 2
+
+// Decrypter decrypts a token in the JWE compact serialization, returning its
+// protected header and plaintext. The jwe subpackage provides an implementation.
+type Decrypter interface {
+	DecryptToken(compact string) (header map[string]interface{}, plaintext []byte, err error)
+}
+
+// ParseNested parses, validates, and returns a nested token as described in
+// https://datatracker.ietf.org/doc/html/rfc7519#section-5.2. Enclosing tokens
+// whose "cty" header is "JWT" are verified (JWS) or decrypted (JWE, which
+// requires Parser.Decrypter) and unwrapped until the innermost token is
+// reached, at most Parser.MaxNestingDepth times.
+//
+// keyFunc is consulted for every signed token, enclosing or not, and may use
+// the token's Header to tell them apart. The innermost token is returned along
+// with the enclosing tokens, ordered outermost first. Enclosing tokens do not
+// carry Claims.
+//
+// The innermost token must be signed. An encrypted token whose plaintext is
+// the claims set is rejected with ErrTokenUnsigned unless
+// Parser.AllowUnsignedEncryptedTokens is set.
+func (p *Parser) ParseNested(tokenString string, claims Claims, keyFunc Keyfunc) (inner *Token, outer []*Token, err error) {
+	ctx := context.Background()
+	inner, err = p.observe(ctx, tokenString, func(stage *string) (*Token, error) {
+		var token *Token
+		token, outer, err = p.parseNested(ctx, tokenString, claims, keyFunc, stage)
+		return token, err
+	})
+	return inner, outer, err
+}
+
+func (p *Parser) parseNested(ctx context.Context, tokenString string, claims Claims, keyFunc Keyfunc, stage *string) (inner *Token, outer []*Token, err error) {
+	maxDepth := p.MaxNestingDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxNestingDepth
+	}
+
+	for depth := 0; ; depth++ {
+		*stage = stageSplit
+		if depth > maxDepth {
+			return nil, outer, ErrTokenNestingTooDeep
+		}
+		if p.MaxTokenSize > 0 && len(tokenString) > p.MaxTokenSize {
+			return nil, outer, ErrTokenTooLarge
+		}
+
+		var (
+			token   *Token
+			payload []byte
+		)
+		if strings.Count(tokenString, ".") == 4 {
+			if token, payload, err = p.decryptNested(tokenString); err != nil {
+				return token, outer, err
+			}
+			if !isNestedJWT(token.Header) {
+				// An encrypted, but not nested, token: the plaintext is the
+				// claims set, which nobody has signed.
+				token.Valid = false
+				if !p.AllowUnsignedEncryptedTokens {
+					*stage = stageSignature
+					return token, outer, ErrTokenUnsigned
+				}
+				token.Unsigned = true
+				token.Claims = claims
+				*stage = stageClaimsDecode
+				if err = p.decodeClaims(payload, claims); err != nil {
+					return token, outer, err
+				}
+				*stage = stageClaimsValidation
+				if err = p.validateClaims(ctx, token); err != nil {
+					return token, outer, err
+				}
+				*stage = stageRevocation
+				if err = p.checkVerified(ctx, token); err != nil {
+					return token, outer, err
+				}
+				return token, outer, nil
+			}
+		} else {
+			if token, payload, err = p.parseEnclosing(tokenString); err != nil {
+				return token, outer, err
+			}
+			if token == nil {
+				inner, err = p.parseWithContext(ctx, tokenString, claims, keyFunc, stage)
+				return inner, outer, err
+			}
+			*stage = stageSignature
+			if err = p.verifyEnclosing(token, keyFunc); err != nil {
+				return token, outer, err
+			}
+		}
+		outer = append(outer, token)
+		tokenString = string(payload)
+	}
+}
+
+// decryptNested decrypts an encrypted token with the Parser's Decrypter.
+func (p *Parser) decryptNested(tokenString string) (*Token, []byte, error) {
+	if p.Decrypter == nil {
+		return nil, nil, ErrMissingDecrypter
+	}
+	header, plaintext, err := p.Decrypter.DecryptToken(tokenString)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Decryption proves the content was encrypted for this recipient, which
+	// is all that is relied on for an enclosing token; the enclosed token is
+	// still verified.
+	return &Token{Raw: tokenString, Header: header, Valid: true}, plaintext, nil
+}
+
+// parseEnclosing parses the header of a signed token. If the token encloses
+// another token, the unverified token and its payload are returned; otherwise
+// the returned token is nil. Segments are decoded as by Parse, subject to
+// MaxTokenSize and StrictDecoding.
+func (p *Parser) parseEnclosing(tokenString string) (*Token, []byte, error) {
+	parts, err := p.splitToken(tokenString)
+	if err != nil {
+		return nil, nil, err
+	}
+	headerBytes, err := p.decodeSegment(parts[0])
+	if err != nil {
+		return nil, nil, MalformedTokenError(err.Error())
+	}
+	token := &Token{Raw: tokenString, Signature: parts[2]}
+	if p.allowPadding() {
+		token.Signature = strings.TrimRight(token.Signature, "=")
+	}
+	if err = p.unmarshalJSON(headerBytes, &token.Header); err != nil {
+		return nil, nil, MalformedTokenError(err.Error())
+	}
+	if !isNestedJWT(token.Header) {
+		return nil, nil, nil
+	}
+	payload, err := p.decodeSegment(parts[1])
+	if err != nil {
+		return token, nil, MalformedTokenError(err.Error())
+	}
+	if err = p.lookupMethod(token); err != nil {
+		return token, nil, err
+	}
+	return token, payload, nil
+}
+
+// verifyEnclosing verifies the signature of an enclosing token.
+func (p *Parser) verifyEnclosing(token *Token, keyFunc Keyfunc) error {
+	if err := p.verifyMethod(token); err != nil {
+		return err
+	}
+	if keyFunc == nil {
+		return ErrMissingKeyFunc
+	}
+	key, err := keyFunc(token)
+	if err != nil {
+		return &KeyFuncError{Err: err}
+	}
+	signingString := token.Raw[:strings.LastIndex(token.Raw, ".")]
+	if err = p.verifySignature(token, signingString, key); err != nil {
+		return err
+	}
+	token.Valid = true
+	return nil
+}
+
+// isNestedJWT reports whether the "cty" header marks the payload as a JWT. The
+// comparison is case-insensitive and the "application/" prefix is optional.
+// See https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.10
+func isNestedJWT(header map[string]interface{}) bool {
+	cty, _ := header["cty"].(string)
+	return strings.TrimPrefix(strings.ToLower(cty), "application/") == "jwt"
+}
@@ -0,0 +1,93 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// wrap signs inner as the payload of an enclosing token with "cty": "JWT".
+func wrap(t *testing.T, inner string, key []byte) string {
+	t.Helper()
+	token := jwt.New(jwt.SigningMethodHS256)
+	token.Header["cty"] = "JWT"
+	sstr := jwt.EncodeSegment(mustMarshal(t, token.Header)) + "." + jwt.EncodeSegment([]byte(inner))
+	sig, err := token.Method.Sign(sstr, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sstr + "." + sig
+}
+
+func TestParser_ParseNested(t *testing.T) {
+	key := []byte("nested secret")
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+
+	inner, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The signature of HS256 is 43 characters long, leaving two unused bits.
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	outer := []byte(wrap(t, inner, key))
+	outer[len(outer)-1] = alphabet[strings.IndexByte(alphabet, outer[len(outer)-1])|1]
+
+	var tests = []struct {
+		name     string
+		token    string
+		maxDepth int
+		maxSize  int
+		strict   bool
+		outer    int
+		err      error
+	}{
+		{"not nested", inner, 0, 0, false, 0, nil},
+		{"single", wrap(t, inner, key), 0, 0, false, 1, nil},
+		{"double", wrap(t, wrap(t, inner, key), key), 0, 0, false, 2, nil},
+		{"too deep", wrap(t, wrap(t, inner, key), key), 1, 0, false, 1, jwt.ErrTokenNestingTooDeep},
+		{"bad enclosing signature", wrap(t, inner, []byte("other")), 0, 0, false, 0, jwt.ErrSignatureInvalid},
+		{"encrypted without decrypter", "a.b.c.d.e", 0, 0, false, 0, jwt.ErrMissingDecrypter},
+		{"oversized enclosing token", wrap(t, inner, key), 0, len(inner), false, 0, jwt.ErrTokenTooLarge},
+		{"non-canonical enclosing token", string(outer), 0, 0, false, 1, nil},
+		{"strict non-canonical enclosing token", string(outer), 0, 0, true, 0, jwt.ErrMalformedToken},
+	}
+
+	for _, data := range tests {
+		p := &jwt.Parser{MaxNestingDepth: data.maxDepth, MaxTokenSize: data.maxSize, StrictDecoding: data.strict}
+		token, outer, err := p.ParseNested(data.token, jwt.MapClaims{}, keyFunc)
+		if data.err != nil {
+			if !errors.Is(err, data.err) {
+				t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%v] Error while parsing token: %v", data.name, err)
+			continue
+		}
+		if !token.Valid || token.Claims.(jwt.MapClaims)["foo"] != "bar" {
+			t.Errorf("[%v] Unexpected inner token: %v", data.name, token.Claims)
+		}
+		if len(outer) != data.outer {
+			t.Errorf("[%v] Expected %v enclosing tokens. Got: %v", data.name, data.outer, len(outer))
+		}
+		for _, o := range outer {
+			if !o.Valid || o.Header["cty"] != "JWT" {
+				t.Errorf("[%v] Unexpected enclosing token: %v", data.name, o.Header)
+			}
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -6,6 +6,10 @@ import "errors"
 // but you probably should never use it.
 var SigningMethodNone *signingMethodNone
 
+// UnsafeAllowNoneSignatureType is the only key accepted by SigningMethodNone.
+// A Keyfunc opts in to unsigned tokens by returning it; with any other key
+// they fail with ErrNoneSignatureTypeDisallowed. It should only be returned in
+// tests, and only for tokens whose "alg" header is "none".
 const UnsafeAllowNoneSignatureType unsafeNoneMagicConstant = "none signing method allowed"
 
 // var NoneSignatureTypeDisallowedError error
@@ -0,0 +1,163 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+)
+
+// Error codes defined by https://datatracker.ietf.org/doc/html/rfc6750#section-3.1
+const (
+	OAuthErrorInvalidRequest    = "invalid_request"
+	OAuthErrorInvalidToken      = "invalid_token"
+	OAuthErrorInsufficientScope = "insufficient_scope"
+)
+
+// HTTP status codes used by the translation table. They are declared here
+// rather than pulled from net/http to keep the core package free of it.
+const (
+	statusBadRequest   = 400
+	statusUnauthorized = 401
+	statusForbidden    = 403
+)
+
+// OAuthError is a bearer token error response as described by
+// https://datatracker.ietf.org/doc/html/rfc6750#section-3.
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	URI         string `json:"error_uri,omitempty"`
+	Scope       string `json:"scope,omitempty"` // Space delimited scopes required, set for insufficient_scope
+	StatusCode  int    `json:"-"`               // The HTTP status code the response should be sent with
+}
+
+// WWWAuthenticate renders the value of the WWW-Authenticate response header
+// for the error. realm is omitted if empty.
+func (e *OAuthError) WWWAuthenticate(realm string) string {
+	var params []string
+	if realm != "" {
+		params = append(params, `realm="`+quoteAuthParam(realm)+`"`)
+	}
+	if e.Code != "" {
+		params = append(params, `error="`+e.Code+`"`)
+	}
+	if e.Description != "" {
+		params = append(params, `error_description="`+quoteAuthParam(e.Description)+`"`)
+	}
+	if e.URI != "" {
+		params = append(params, `error_uri="`+quoteAuthParam(e.URI)+`"`)
+	}
+	if e.Scope != "" {
+		params = append(params, `scope="`+quoteAuthParam(e.Scope)+`"`)
+	}
+	if len(params) == 0 {
+		return "Bearer"
+	}
+	return "Bearer " + strings.Join(params, ", ")
+}
+
+// ProblemDetails is an error payload as described by
+// https://datatracker.ietf.org/doc/html/rfc7807. It should be served with the
+// content type "application/problem+json".
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"error,omitempty"` // The RFC 6750 error code
+	Scope    string `json:"scope,omitempty"` // Space delimited scopes required, set for insufficient_scope
+}
+
+type errorTranslation struct {
+	err         error
+	code        string
+	status      int
+	title       string
+	description string
+}
+
+// errorTranslations is consulted in order; the first entry matching the error
+// (by errors.Is) is used.
+var errorTranslations = []errorTranslation{
+	{ErrInsufficientScope, OAuthErrorInsufficientScope, statusForbidden, "Insufficient Scope", "The access token does not carry the required scope"},
+	{ErrTokenExpired, OAuthErrorInvalidToken, statusUnauthorized, "Token Expired", "The access token expired"},
+	{ErrTokenNotYetValid, OAuthErrorInvalidToken, statusUnauthorized, "Token Not Yet Valid", "The access token is not yet valid"},
+	{ErrTokenUsedBeforeIssued, OAuthErrorInvalidToken, statusUnauthorized, "Token Used Before Issued", "The access token was used before it was issued"},
+	{ErrSessionInvalid, OAuthErrorInvalidToken, statusUnauthorized, "Session Ended", "The session of the access token has ended"},
+	{ErrTokenRevoked, OAuthErrorInvalidToken, statusUnauthorized, "Token Revoked", "The access token has been revoked"},
+	{ErrTokenReplayed, OAuthErrorInvalidToken, statusUnauthorized, "Token Replayed", "The token has already been used"},
+	{ErrSignatureInvalid, OAuthErrorInvalidToken, statusUnauthorized, "Invalid Signature", "The access token signature is invalid"},
+	{ErrTokenUnsigned, OAuthErrorInvalidToken, statusUnauthorized, "Invalid Signature", "The access token is not signed"},
+	{ErrTokenContainsBearer, OAuthErrorInvalidRequest, statusBadRequest, "Invalid Request", `The access token must not contain the "Bearer " prefix`},
+	{ErrTokenTooLarge, OAuthErrorInvalidRequest, statusBadRequest, "Token Too Large", "The access token exceeds the maximum size"},
+	{ErrTokenInvalidType, OAuthErrorInvalidToken, statusUnauthorized, "Invalid Token Type", "The token is not an access token"},
+	{ErrMalformedToken, OAuthErrorInvalidToken, statusUnauthorized, "Malformed Token", "The access token is malformed"},
+	{ErrInvalidSigningMethod, OAuthErrorInvalidToken, statusUnauthorized, "Invalid Signing Method", "The access token is signed with a disallowed algorithm"},
+	{ErrUnregisteredSigningMethod, OAuthErrorInvalidToken, statusUnauthorized, "Invalid Signing Method", "The access token is signed with an unsupported algorithm"},
+	{ErrNoneSignatureTypeDisallowed, OAuthErrorInvalidToken, statusUnauthorized, "Invalid Signing Method", "The access token is not signed"},
+}
+
+var defaultErrorTranslation = errorTranslation{
+	code:        OAuthErrorInvalidToken,
+	status:      statusUnauthorized,
+	title:       "Invalid Token",
+	description: "The access token is invalid",
+}
+
+func translateError(err error) errorTranslation {
+	for _, t := range errorTranslations {
+		if errors.Is(err, t.err) {
+			return t
+		}
+	}
+	return defaultErrorTranslation
+}
+
+func requiredScope(err error) string {
+	var scopeErr *InsufficientScopeError
+	if errors.As(err, &scopeErr) {
+		return strings.Join(scopeErr.Required, " ")
+	}
+	return ""
+}
+
+// ToOAuthError translates an error returned from parsing or validating a token
+// into an RFC 6750 error response. Errors that are not recognized are reported as
+// invalid_token. It returns nil if err is nil.
+//
+// The description is deliberately generic so that internal details of err are
+// not disclosed to clients.
+func ToOAuthError(err error) *OAuthError {
+	if err == nil {
+		return nil
+	}
+	t := translateError(err)
+	return &OAuthError{
+		Code:        t.code,
+		Description: t.description,
+		Scope:       requiredScope(err),
+		StatusCode:  t.status,
+	}
+}
+
+// ToProblemDetails translates an error returned from parsing or validating a
+// token into an RFC 7807 problem details payload. Errors that are not recognized
+// are reported as invalid_token. It returns nil if err is nil.
+func ToProblemDetails(err error) *ProblemDetails {
+	if err == nil {
+		return nil
+	}
+	t := translateError(err)
+	return &ProblemDetails{
+		Type:   "https://datatracker.ietf.org/doc/html/rfc6750#section-3.1",
+		Title:  t.title,
+		Status: t.status,
+		Detail: t.description,
+		Code:   t.code,
+		Scope:  requiredScope(err),
+	}
+}
+
+func quoteAuthParam(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
@@ -0,0 +1,53 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestToOAuthError(t *testing.T) {
+	var tests = []struct {
+		name   string
+		err    error
+		code   string
+		status int
+		scope  string
+	}{
+		{"expired", &jwt.ExpiredError{ExpiredAt: time.Unix(0, 0), AttemptedAt: time.Unix(10, 0)}, jwt.OAuthErrorInvalidToken, 401, ""},
+		{"malformed", jwt.MalformedTokenError("bad"), jwt.OAuthErrorInvalidToken, 401, ""},
+		{"bearer", jwt.ErrTokenContainsBearer, jwt.OAuthErrorInvalidRequest, 400, ""},
+		{"insufficient scope", &jwt.InsufficientScopeError{Required: []string{"read:users", "write:users"}, Missing: []string{"write:users"}}, jwt.OAuthErrorInsufficientScope, 403, "read:users write:users"},
+		{"unknown", jwt.ErrKeyFuncError, jwt.OAuthErrorInvalidToken, 401, ""},
+	}
+
+	for _, data := range tests {
+		oerr := jwt.ToOAuthError(data.err)
+		if oerr.Code != data.code {
+			t.Errorf("[%v] Expected code %v. Got: %v", data.name, data.code, oerr.Code)
+		}
+		if oerr.StatusCode != data.status {
+			t.Errorf("[%v] Expected status %v. Got: %v", data.name, data.status, oerr.StatusCode)
+		}
+		if oerr.Scope != data.scope {
+			t.Errorf("[%v] Expected scope %q. Got: %q", data.name, data.scope, oerr.Scope)
+		}
+		pd := jwt.ToProblemDetails(data.err)
+		if pd.Status != data.status || pd.Code != data.code || pd.Scope != data.scope {
+			t.Errorf("[%v] Problem details mismatch: %+v", data.name, pd)
+		}
+	}
+
+	if jwt.ToOAuthError(nil) != nil || jwt.ToProblemDetails(nil) != nil {
+		t.Errorf("Expected nil translation for nil error")
+	}
+}
+
+func TestOAuthError_WWWAuthenticate(t *testing.T) {
+	oerr := jwt.ToOAuthError(&jwt.InsufficientScopeError{Required: []string{"read:users"}, Missing: []string{"read:users"}})
+	expected := `Bearer realm="api", error="insufficient_scope", error_description="The access token does not carry the required scope", scope="read:users"`
+	if got := oerr.WWWAuthenticate("api"); got != expected {
+		t.Errorf("WWW-Authenticate mismatch.\nExpecting: %v\nGot: %v", expected, got)
+	}
+}
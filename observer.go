@@ -0,0 +1,129 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+)
+
+// Failure categories reported in ParseEvent and SignEvent, and returned by
+// FailureCategory. They are few and stable, to be used as metric labels.
+const (
+	FailureMalformed   = "malformed"     // The token could not be decoded, or is too large
+	FailureAlgorithm   = "algorithm"     // The algorithm is disallowed, unknown or does not suit the key
+	FailureKey         = "key"           // No usable key was found
+	FailureSignature   = "signature"     // The signature is invalid
+	FailureExpired     = "expired"       // The token has expired
+	FailureNotYetValid = "not_yet_valid" // The token is not valid yet
+	FailureRevoked     = "revoked"       // The token or its session has been revoked
+	FailureReplayed    = "replayed"      // The token has already been used
+	FailureClaims      = "claims"        // Another claim, or the type, is invalid
+	FailureOther       = "other"         // Any other error, such as of a blocklist
+)
+
+// failureCategories is consulted in order; the first entry matching the error
+// (by errors.Is) is used.
+var failureCategories = []struct {
+	err      error
+	category string
+}{
+	{ErrKeyFuncError, FailureKey},
+	{ErrUnknownKeyID, FailureKey},
+	{ErrInvalidKey, FailureKey},
+	{ErrInvalidKeyType, FailureKey},
+	{ErrMissingKeyFunc, FailureKey},
+	{ErrMalformedToken, FailureMalformed},
+	{ErrInvalidSegments, FailureMalformed},
+	{ErrTokenTooLarge, FailureMalformed},
+	{ErrTokenContainsBearer, FailureMalformed},
+	{ErrTokenNestingTooDeep, FailureMalformed},
+	{ErrInvalidSigningMethod, FailureAlgorithm},
+	{ErrUnregisteredSigningMethod, FailureAlgorithm},
+	{ErrNoneSignatureTypeDisallowed, FailureAlgorithm},
+	{ErrKeyAlgorithmMismatch, FailureAlgorithm},
+	{ErrMissingValidMethods, FailureAlgorithm},
+	{ErrSignatureInvalid, FailureSignature},
+	{ErrTokenUnsigned, FailureSignature},
+	{ErrTokenRevoked, FailureRevoked},
+	{ErrSessionInvalid, FailureRevoked},
+	{ErrTokenReplayed, FailureReplayed},
+	{ErrTokenExpired, FailureExpired},
+	{ErrTokenNotYetValid, FailureNotYetValid},
+	{ErrTokenUsedBeforeIssued, FailureNotYetValid},
+}
+
+// FailureCategory returns the category of err, an error returned by parsing
+// or signing a token, or "" if err is nil. Errors of the signature take
+// precedence over those of the claims.
+func FailureCategory(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, c := range failureCategories {
+		if errors.Is(err, c.err) {
+			return c.category
+		}
+	}
+	var verr *ValidationError
+	if errors.As(err, &verr) || errors.Is(err, ErrTokenInvalidType) {
+		return FailureClaims
+	}
+	return FailureOther
+}
+
+// UnknownAlgorithm is the Algorithm of a ParseEvent for a token whose "alg"
+// header could not be decoded or does not name a registered signing method.
+const UnknownAlgorithm = "unknown"
+
+// ParseEvent describes a token parsed by a Parser, as reported to
+// Hooks.OnParse.
+//
+// The values taken from the token have not been verified, and may have been
+// chosen by whoever presented it. Algorithm is therefore limited to the
+// registered signing methods, and KeyID is cut to 64 bytes; KeyID should still
+// not be used where each distinct value has a cost, such as a metric label.
+type ParseEvent struct {
+	Start     time.Time     // When parsing began
+	Duration  time.Duration // How long parsing took, including the Keyfunc
+	Algorithm string        // The signing method named by the "alg" header, or UnknownAlgorithm
+	KeyID     string        // The "kid" header, if any, cut to 64 bytes
+	Err       error         // The error returned, or nil if the token is valid
+	Failure   string        // FailureCategory(Err)
+}
+
+// SignEvent describes a token signed by a Signer, as reported to
+// Hooks.OnSign.
+type SignEvent struct {
+	Start     time.Time
+	Duration  time.Duration
+	Algorithm string
+	Err       error
+	Failure   string // FailureCategory(Err)
+}
+
+// WithParserHooks sets the Hooks of the Parser, whose OnParse is called for
+// every token parsed with ParseWithContext and the methods built on it.
+func WithParserHooks(h Hooks) ParserOption {
+	return func(p *Parser) {
+		p.Hooks = h
+	}
+}
+
+// WithHooks returns a copy of s reporting to h.OnSign.
+func (s *Signer) WithHooks(h Hooks) *Signer {
+	c := *s
+	c.hooks = h
+	return &c
+}
+
+func newParseEvent(start time.Time, token *Token, err error) ParseEvent {
+	e := ParseEvent{Start: start, Duration: time.Since(start), Err: err, Failure: FailureCategory(err)}
+	e.Algorithm = UnknownAlgorithm
+	if token != nil {
+		if token.Method != nil {
+			e.Algorithm = token.Method.Alg()
+		}
+		kid, _ := token.Header["kid"].(string)
+		e.KeyID = truncateHeader(kid)
+	}
+	return e
+}
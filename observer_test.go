@@ -0,0 +1,100 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestFailureCategory(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{jwt.ErrSignatureInvalid, jwt.FailureSignature},
+		{&jwt.ExpiredError{}, jwt.FailureExpired},
+		{&jwt.KeyFuncError{Err: jwt.ErrUnknownKeyID}, jwt.FailureKey},
+		{&jwt.UnregisteredSigningMethodError{Alg: "XX"}, jwt.FailureAlgorithm},
+		{jwt.MalformedTokenError("bad"), jwt.FailureMalformed},
+		{&jwt.ValidationError{Err: jwt.ErrTokenInvalidIssuer, Claim: "iss"}, jwt.FailureClaims},
+		{&jwt.ValidationError{Err: jwt.ErrTokenReplayed, Claim: "jti"}, jwt.FailureReplayed},
+		{jwt.JoinErrors(&jwt.ValidationError{Err: jwt.ErrTokenInvalidAudience}, &jwt.ExpiredError{}), jwt.FailureExpired},
+		{fmt.Errorf("wrapped: %w", jwt.ErrTokenRevoked), jwt.FailureRevoked},
+		{errors.New("unavailable"), jwt.FailureOther},
+	}
+	for _, tc := range tests {
+		if got := jwt.FailureCategory(tc.err); got != tc.want {
+			t.Errorf("FailureCategory(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestParser_Hooks(t *testing.T) {
+	key := []byte("secret")
+	var signed []jwt.SignEvent
+	signer, err := jwt.NewSigner(jwt.SigningMethodHS256, key, map[string]interface{}{"kid": "k1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer = signer.WithHooks(jwt.Hooks{OnSign: func(e jwt.SignEvent) { signed = append(signed, e) }})
+	valid, err := signer.Sign(jwt.MapClaims{"sub": "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := signer.Sign(jwt.MapClaims{"exp": float64(time.Now().Add(-time.Hour).Unix())})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signed) != 2 || signed[0].Algorithm != "HS256" || signed[0].Err != nil || signed[0].Start.IsZero() {
+		t.Errorf("Unexpected sign events: %+v", signed)
+	}
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	var parsed []jwt.ParseEvent
+	p := jwt.NewParser(jwt.WithParserHooks(jwt.Hooks{OnParse: func(c context.Context, e jwt.ParseEvent) {
+		if c.Value(ctxKey{}) != "request" {
+			t.Error("Expected the context of the call")
+		}
+		parsed = append(parsed, e)
+	}}))
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+	for _, s := range []string{valid, expired, valid + "x", "bad"} {
+		p.ParseWithContext(ctx, s, jwt.MapClaims{}, keyFunc)
+	}
+
+	want := []string{"", jwt.FailureExpired, jwt.FailureSignature, jwt.FailureMalformed}
+	if len(parsed) != len(want) {
+		t.Fatalf("Expected %d parse events, got %d", len(want), len(parsed))
+	}
+	for i, e := range parsed {
+		if e.Failure != want[i] || (e.Err == nil) != (want[i] == "") {
+			t.Errorf("Event %d: unexpected failure %q (%v), want %q", i, e.Failure, e.Err, want[i])
+		}
+		if i < 3 && (e.Algorithm != "HS256" || e.KeyID != "k1") {
+			t.Errorf("Event %d: unexpected header values %+v", i, e)
+		}
+	}
+}
+
+func TestParser_Hooks_untrustedHeaders(t *testing.T) {
+	var parsed []jwt.ParseEvent
+	p := jwt.NewParser(jwt.WithParserHooks(jwt.Hooks{OnParse: func(_ context.Context, e jwt.ParseEvent) {
+		parsed = append(parsed, e)
+	}}))
+	header := jwt.EncodeSegment([]byte(`{"alg":"` + strings.Repeat("x", 100) + `","kid":"` + strings.Repeat("k", 100) + `"}`))
+	p.Parse(header+"."+jwt.EncodeSegment([]byte(`{}`))+".sig", func(*jwt.Token) (interface{}, error) { return nil, nil })
+
+	if len(parsed) != 1 {
+		t.Fatalf("Expected 1 parse event, got %d", len(parsed))
+	}
+	if e := parsed[0]; e.Algorithm != jwt.UnknownAlgorithm || e.KeyID != strings.Repeat("k", 64) {
+		t.Errorf("Expected the unverified headers to be bounded. Got: %q, %q", e.Algorithm, e.KeyID)
+	}
+}
@@ -0,0 +1,9 @@
+// Package oidc validates OpenID Connect ID tokens as described in
+// https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation.
+//
+// ValidateIDToken performs every check a relying party must make on an ID
+// token received from the token endpoint, which are easy to get subtly wrong
+// when assembled by hand: the signature, "iss", "aud" and "azp", "exp" and
+// "iat", the "nonce" of the authentication request and, when an access token
+// was issued alongside, "at_hash".
+package oidc
@@ -0,0 +1,97 @@
+package oidc
+
+import (
+	"crypto"
+	_ "crypto/sha256" // linked for the hashes of the algorithms of RFC 7518
+	_ "crypto/sha512"
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+var (
+	ErrAccessTokenHashMismatch  = errors.New(`oidc: the "at_hash" claim does not match the access token`)
+	ErrCodeHashMismatch         = errors.New(`oidc: the "c_hash" claim does not match the authorization code`)
+	ErrUnsupportedHashAlgorithm = errors.New("oidc: the hash of the token's algorithm is unknown")
+)
+
+// AccessTokenHash returns the "at_hash" value of accessToken for an ID token
+// signed with method: the base64url encoding of the left-most half of the
+// hash of the access token, the hash being that of the algorithm of method,
+// as described in
+// https://openid.net/specs/openid-connect-core-1_0.html#CodeIDToken. EdDSA
+// uses SHA-512.
+func AccessTokenHash(method jwt.SigningMethod, accessToken string) (string, error) {
+	return halfHash(method, accessToken)
+}
+
+// CodeHash returns the "c_hash" value of the authorization code for an ID
+// token signed with method, computed as for AccessTokenHash.
+func CodeHash(method jwt.SigningMethod, code string) (string, error) {
+	return halfHash(method, code)
+}
+
+// VerifyAccessTokenHash checks that atHash, the "at_hash" claim of an ID
+// token signed with method, is that of accessToken. It fails with
+// ErrAccessTokenHashMismatch otherwise.
+func VerifyAccessTokenHash(method jwt.SigningMethod, atHash, accessToken string) error {
+	return verifyHalfHash(method, atHash, accessToken, ErrAccessTokenHashMismatch)
+}
+
+// VerifyCodeHash checks that cHash, the "c_hash" claim of an ID token signed
+// with method, is that of the authorization code. It fails with
+// ErrCodeHashMismatch otherwise, including when cHash is empty.
+func VerifyCodeHash(method jwt.SigningMethod, cHash, code string) error {
+	return verifyHalfHash(method, cHash, code, ErrCodeHashMismatch)
+}
+
+func verifyHalfHash(method jwt.SigningMethod, value, s string, mismatch error) error {
+	want, err := halfHash(method, s)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(value), []byte(want)) != 1 {
+		return mismatch
+	}
+	return nil
+}
+
+func halfHash(method jwt.SigningMethod, s string) (string, error) {
+	h, err := hashOf(method)
+	if err != nil {
+		return "", err
+	}
+	hasher := h.New()
+	hasher.Write([]byte(s))
+	sum := hasher.Sum(nil)
+	return jwt.EncodeSegment(sum[:len(sum)/2]), nil
+}
+
+// hashOf returns the hash used by the algorithm of method: that of its
+// signatures, or SHA-512 for EdDSA with Ed25519.
+func hashOf(method jwt.SigningMethod) (crypto.Hash, error) {
+	switch m := method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return m.Hash, nil
+	case *jwt.SigningMethodRSA:
+		return m.Hash, nil
+	case *jwt.SigningMethodRSAPSS:
+		return m.Hash, nil
+	case *jwt.SigningMethodECDSA:
+		return m.Hash, nil
+	case *jwt.SigningMethodEd25519:
+		return crypto.SHA512, nil
+	}
+	// Other methods are assumed to follow the naming of RFC 7518.
+	switch alg := method.Alg(); {
+	case strings.HasSuffix(alg, "256"):
+		return crypto.SHA256, nil
+	case strings.HasSuffix(alg, "384"):
+		return crypto.SHA384, nil
+	case strings.HasSuffix(alg, "512"):
+		return crypto.SHA512, nil
+	}
+	return 0, ErrUnsupportedHashAlgorithm
+}
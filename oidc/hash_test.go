@@ -0,0 +1,52 @@
+package oidc_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/oidc"
+)
+
+func TestAccessTokenHash(t *testing.T) {
+	// OpenID Connect Core 1.0, appendix A.4
+	const accessToken = "jHkWEdUXMU1BwAsC4vtUsZwnNvTIxEl0z9K3vx5KF0Y"
+	got, err := oidc.AccessTokenHash(jwt.SigningMethodRS256, accessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "77QmUPtjPfzWtF2AnpK9RQ" {
+		t.Errorf("AccessTokenHash() = %v", got)
+	}
+	if err = oidc.VerifyAccessTokenHash(jwt.SigningMethodRS256, got, accessToken); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err = oidc.VerifyAccessTokenHash(jwt.SigningMethodRS512, got, accessToken); !errors.Is(err, oidc.ErrAccessTokenHashMismatch) {
+		t.Errorf("Expected ErrAccessTokenHashMismatch with another hash, got %v", err)
+	}
+
+	// SHA-512 for EdDSA and the 512 bit algorithms
+	for _, method := range []jwt.SigningMethod{jwt.SigningMethodEdDSA, jwt.SigningMethodES512} {
+		if h, _ := oidc.AccessTokenHash(method, accessToken); len(h) != 43 {
+			t.Errorf("[%v] AccessTokenHash() = %v, want 32 bytes", method.Alg(), h)
+		}
+	}
+}
+
+func TestCodeHash(t *testing.T) {
+	// OpenID Connect Core 1.0, appendix A.4
+	const code = "Qcb0Orv1zh30vL1MPRsbm-diHiMwcLyZvn1arpZv-Jxf_11jnpEX3Tgfvk"
+	got, err := oidc.CodeHash(jwt.SigningMethodRS256, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "LDktKdoQak3Pk0cnXxCltA" {
+		t.Errorf("CodeHash() = %v", got)
+	}
+	if err = oidc.VerifyCodeHash(jwt.SigningMethodRS256, "", code); !errors.Is(err, oidc.ErrCodeHashMismatch) {
+		t.Errorf("Expected ErrCodeHashMismatch for a missing c_hash, got %v", err)
+	}
+	if _, err = oidc.CodeHash(jwt.SigningMethodNone, code); !errors.Is(err, oidc.ErrUnsupportedHashAlgorithm) {
+		t.Errorf("Expected ErrUnsupportedHashAlgorithm, got %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+package oidc
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwk"
+)
+
+var (
+	ErrNonceMismatch          = errors.New(`oidc: the "nonce" claim does not match the nonce of the request`)
+	ErrMissingAuthorizedParty = errors.New(`oidc: the token has several audiences but no "azp" claim`)
+	ErrInvalidAuthorizedParty = errors.New(`oidc: the "azp" claim is not the client ID`)
+	ErrUntrustedAudience      = errors.New(`oidc: the "aud" claim contains an untrusted audience`)
+	ErrMissingKeyProvider     = errors.New("oidc: KeyProvider not provided")
+)
+
+// IDTokenClaims are the claims of an ID token, as referenced at
+// https://openid.net/specs/openid-connect-core-1_0.html#IDToken.
+type IDTokenClaims struct {
+	jwt.RegisteredClaims
+	jwt.AuthenticationClaims
+
+	// the `nonce` claim, the nonce of the authentication request
+	Nonce string `json:"nonce,omitempty"`
+
+	// the `azp` (Authorized Party) claim, the client the token was issued to
+	AuthorizedParty string `json:"azp,omitempty"`
+
+	// the `at_hash` (Access Token hash) claim
+	AccessTokenHash string `json:"at_hash,omitempty"`
+
+	// the `c_hash` (Code hash) claim
+	CodeHash string `json:"c_hash,omitempty"`
+}
+
+// KeyProvider supplies the keys ID tokens are verified with. *jwk.Set and
+// *jwk.Remote, holding the provider's JWKS, implement it; a jwt.Keyfunc can
+// be adapted with KeyfuncProvider. Providers which, like *jwk.Remote, also
+// have a Set(context.Context) (*jwk.Set, error) method are asked for their
+// keys with the context of ValidateIDToken first, so that fetching them
+// honors its cancellation.
+type KeyProvider interface {
+	Keyfunc(token *jwt.Token) (interface{}, error)
+}
+
+// KeyfuncProvider adapts a jwt.Keyfunc to a KeyProvider.
+type KeyfuncProvider jwt.Keyfunc
+
+// Keyfunc calls f(token).
+func (f KeyfuncProvider) Keyfunc(token *jwt.Token) (interface{}, error) {
+	return f(token)
+}
+
+// Option configures ValidateIDToken.
+type Option func(*config)
+
+type config struct {
+	accessToken      string
+	code             string
+	trustedAudiences []string
+	maxAuthAge       time.Duration
+	leeway           time.Duration
+	parserOptions    []jwt.ParserOption
+}
+
+// WithAccessToken verifies the "at_hash" claim, if present, against the
+// access token issued with the ID token. The claim is required of ID tokens
+// issued with an access token from the authorization endpoint, but optional
+// for those from the token endpoint.
+func WithAccessToken(accessToken string) Option {
+	return func(c *config) {
+		c.accessToken = accessToken
+	}
+}
+
+// WithAuthorizationCode verifies the "c_hash" claim against the authorization
+// code returned with the ID token from the authorization endpoint, as in the
+// hybrid flow, where the claim is required.
+func WithAuthorizationCode(code string) Option {
+	return func(c *config) {
+		c.code = code
+	}
+}
+
+// WithTrustedAudiences accepts ID tokens whose "aud" claim lists the given
+// audiences besides the client ID. By default, any other audience is
+// rejected with ErrUntrustedAudience.
+func WithTrustedAudiences(audiences ...string) Option {
+	return func(c *config) {
+		c.trustedAudiences = append(c.trustedAudiences, audiences...)
+	}
+}
+
+// WithMaxAuthAge requires the "auth_time" claim to be no further in the past
+// than d, when the "max_age" parameter was sent in the authentication request.
+func WithMaxAuthAge(d time.Duration) Option {
+	return func(c *config) {
+		c.maxAuthAge = d
+	}
+}
+
+// WithLeeway allows for clock skew of up to d when checking "exp", "nbf" and
+// "iat".
+func WithLeeway(d time.Duration) Option {
+	return func(c *config) {
+		c.leeway = d
+	}
+}
+
+// WithParserOptions configures the parser of the ID token, such as with
+// jwt.WithValidMethods to accept only the algorithm registered for the client
+// as id_token_signed_response_alg.
+func WithParserOptions(opts ...jwt.ParserOption) Option {
+	return func(c *config) {
+		c.parserOptions = append(c.parserOptions, opts...)
+	}
+}
+
+// ValidateIDToken verifies the ID token raw, issued by issuer to the client
+// identified by clientID in response to an authentication request carrying
+// nonce, with the keys of keys, following
+// https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation:
+//
+//   - the signature must verify, with an algorithm other than "none";
+//   - "iss" must be issuer, exactly;
+//   - "aud" must contain clientID, and no audience which is not trusted;
+//   - "azp" must be present if there are several audiences, and must be
+//     clientID if present;
+//   - "exp" and "iat" must be present, and "exp" in the future;
+//   - "nonce" must equal nonce, unless nonce is empty;
+//   - "at_hash" must match the access token given with WithAccessToken;
+//   - "c_hash" must match the code given with WithAuthorizationCode.
+//
+// All failures of the claims are reported, combined with jwt.JoinErrors.
+func ValidateIDToken(ctx context.Context, raw, issuer, clientID, nonce string, keys KeyProvider, opts ...Option) (*IDTokenClaims, error) {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if keys == nil {
+		return nil, ErrMissingKeyProvider
+	}
+	if r, ok := keys.(interface {
+		Set(context.Context) (*jwk.Set, error)
+	}); ok {
+		if _, err := r.Set(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	p := jwt.NewParser(c.parserOptions...)
+	p.SkipClaimsValidation = true
+	claims := new(IDTokenClaims)
+	token, err := p.ParseWithClaims(raw, claims, keys.Keyfunc)
+	if err != nil {
+		return nil, err
+	}
+
+	v := jwt.Validator{Policy: jwt.Policy{
+		Issuers:           []string{issuer},
+		Audiences:         []string{clientID},
+		RequiredClaims:    []string{"sub"},
+		RequireExpiration: true,
+		RequireIssuedAt:   true,
+		MaxAuthAge:        c.maxAuthAge,
+		Leeway:            c.leeway,
+	}}
+	errs := []error{v.Validate(token)}
+
+	for _, aud := range claims.Audience {
+		if aud != clientID && !contains(c.trustedAudiences, aud) {
+			errs = append(errs, &jwt.ValidationError{Err: ErrUntrustedAudience, Claim: "aud", Actual: aud})
+			break
+		}
+	}
+	if claims.AuthorizedParty == "" && len(claims.Audience) > 1 {
+		errs = append(errs, &jwt.ValidationError{Err: ErrMissingAuthorizedParty, Claim: "azp"})
+	}
+	if claims.AuthorizedParty != "" && claims.AuthorizedParty != clientID {
+		errs = append(errs, &jwt.ValidationError{Err: ErrInvalidAuthorizedParty, Claim: "azp", Expected: clientID, Actual: claims.AuthorizedParty})
+	}
+	if nonce != "" && subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		errs = append(errs, &jwt.ValidationError{Err: ErrNonceMismatch, Claim: "nonce"})
+	}
+	if c.accessToken != "" && claims.AccessTokenHash != "" {
+		if err := VerifyAccessTokenHash(token.Method, claims.AccessTokenHash, c.accessToken); err != nil {
+			errs = append(errs, &jwt.ValidationError{Err: err, Claim: "at_hash"})
+		}
+	}
+	if c.code != "" {
+		if err := VerifyCodeHash(token.Method, claims.CodeHash, c.code); err != nil {
+			errs = append(errs, &jwt.ValidationError{Err: err, Claim: "c_hash"})
+		}
+	}
+
+	if err = jwt.JoinErrors(errs...); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwk"
+	"github.com/chanced/go-jwt/v4/oidc"
+)
+
+const (
+	issuer   = "https://issuer.example"
+	clientID = "client"
+	nonce    = "n-0S6_WzA2Mj"
+)
+
+func newProvider(t *testing.T) (*ecdsa.PrivateKey, *jwk.Set) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := jwk.NewKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.KeyID, k.Use = "k1", "sig"
+	return key, &jwk.Set{Keys: []*jwk.Key{k}}
+}
+
+func idTokenClaims() *oidc.IDTokenClaims {
+	now := time.Now()
+	return &oidc.IDTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   "user",
+			Audience:  jwt.ClaimStrings{clientID},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Nonce: nonce,
+	}
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, claims *oidc.IDTokenClaims) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims, jwt.WithKeyID("k1")).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func atHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return jwt.EncodeSegment(sum[:16])
+}
+
+func TestValidateIDToken(t *testing.T) {
+	key, set := newProvider(t)
+	ctx := context.Background()
+
+	claims, err := oidc.ValidateIDToken(ctx, sign(t, key, idTokenClaims()), issuer, clientID, nonce, set)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if claims.Subject != "user" {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+
+	withAtHash := idTokenClaims()
+	withAtHash.AccessTokenHash = atHash("access-token")
+	if _, err = oidc.ValidateIDToken(ctx, sign(t, key, withAtHash), issuer, clientID, nonce, set, oidc.WithAccessToken("access-token")); err != nil {
+		t.Errorf("Unexpected error with at_hash: %v", err)
+	}
+
+	multiple := idTokenClaims()
+	multiple.Audience = jwt.ClaimStrings{clientID, "api"}
+	multiple.AuthorizedParty = clientID
+	if _, err = oidc.ValidateIDToken(ctx, sign(t, key, multiple), issuer, clientID, nonce, set, oidc.WithTrustedAudiences("api")); err != nil {
+		t.Errorf("Unexpected error with a trusted audience: %v", err)
+	}
+}
+
+func TestValidateIDToken_failures(t *testing.T) {
+	key, set := newProvider(t)
+	otherKey, _ := newProvider(t)
+
+	tests := []struct {
+		name   string
+		modify func(*oidc.IDTokenClaims)
+		key    *ecdsa.PrivateKey
+		opts   []oidc.Option
+		err    error
+	}{
+		{"wrong issuer", func(c *oidc.IDTokenClaims) { c.Issuer = "https://issuer.example/" }, key, nil, jwt.ErrTokenInvalidIssuer},
+		{"wrong audience", func(c *oidc.IDTokenClaims) { c.Audience = jwt.ClaimStrings{"other"} }, key, nil, jwt.ErrTokenInvalidAudience},
+		{"untrusted audience", func(c *oidc.IDTokenClaims) {
+			c.Audience = jwt.ClaimStrings{clientID, "other"}
+			c.AuthorizedParty = clientID
+		}, key, nil, oidc.ErrUntrustedAudience},
+		{"missing azp", func(c *oidc.IDTokenClaims) { c.Audience = jwt.ClaimStrings{clientID, "api"} }, key, []oidc.Option{oidc.WithTrustedAudiences("api")}, oidc.ErrMissingAuthorizedParty},
+		{"wrong azp", func(c *oidc.IDTokenClaims) { c.AuthorizedParty = "other" }, key, nil, oidc.ErrInvalidAuthorizedParty},
+		{"expired", func(c *oidc.IDTokenClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute)) }, key, nil, jwt.ErrTokenExpired},
+		{"missing exp", func(c *oidc.IDTokenClaims) { c.ExpiresAt = nil }, key, nil, jwt.ErrTokenRequiredClaimMissing},
+		{"missing iat", func(c *oidc.IDTokenClaims) { c.IssuedAt = nil }, key, nil, jwt.ErrTokenRequiredClaimMissing},
+		{"wrong nonce", func(c *oidc.IDTokenClaims) { c.Nonce = "replayed" }, key, nil, oidc.ErrNonceMismatch},
+		{"missing nonce", func(c *oidc.IDTokenClaims) { c.Nonce = "" }, key, nil, oidc.ErrNonceMismatch},
+		{"wrong at_hash", func(c *oidc.IDTokenClaims) { c.AccessTokenHash = atHash("other") }, key, []oidc.Option{oidc.WithAccessToken("access-token")}, oidc.ErrAccessTokenHashMismatch},
+		{"wrong key", func(*oidc.IDTokenClaims) {}, otherKey, nil, jwt.ErrSignatureInvalid},
+		{"disallowed algorithm", func(*oidc.IDTokenClaims) {}, key, []oidc.Option{oidc.WithParserOptions(jwt.WithValidMethods("RS256"))}, jwt.ErrInvalidSigningMethod},
+		{"too old authentication", func(c *oidc.IDTokenClaims) {
+			c.AuthTime = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+		}, key, []oidc.Option{oidc.WithMaxAuthAge(time.Minute)}, jwt.ErrTokenAuthTooOld},
+	}
+	for _, tc := range tests {
+		claims := idTokenClaims()
+		tc.modify(claims)
+		_, err := oidc.ValidateIDToken(context.Background(), sign(t, tc.key, claims), issuer, clientID, nonce, set, tc.opts...)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.err)
+		}
+	}
+}
+
+func TestValidateIDToken_keyfunc(t *testing.T) {
+	key, _ := newProvider(t)
+	keys := oidc.KeyfuncProvider(func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil })
+	if _, err := oidc.ValidateIDToken(context.Background(), sign(t, key, idTokenClaims()), issuer, clientID, nonce, keys); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := oidc.ValidateIDToken(context.Background(), sign(t, key, idTokenClaims()), issuer, clientID, nonce, nil); !errors.Is(err, oidc.ErrMissingKeyProvider) {
+		t.Errorf("Expected ErrMissingKeyProvider, got %v", err)
+	}
+}
+
+func TestValidateIDToken_codeHash(t *testing.T) {
+	key, set := newProvider(t)
+	claims := idTokenClaims()
+	claims.CodeHash, _ = oidc.CodeHash(jwt.SigningMethodES256, "code")
+	signed := sign(t, key, claims)
+
+	if _, err := oidc.ValidateIDToken(context.Background(), signed, issuer, clientID, nonce, set, oidc.WithAuthorizationCode("code")); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := oidc.ValidateIDToken(context.Background(), signed, issuer, clientID, nonce, set, oidc.WithAuthorizationCode("other")); !errors.Is(err, oidc.ErrCodeHashMismatch) {
+		t.Errorf("Expected ErrCodeHashMismatch, got %v", err)
+	}
+}
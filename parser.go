@@ -2,14 +2,183 @@ package jwt
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"strings"
+	"sync"
+	"time"
 )
 
 type Parser struct {
-	ValidMethods         []string // If populated, only these methods will be considered valid
-	UseJSONNumber        bool     // Use JSON Number format in JSON decoder
-	SkipClaimsValidation bool     // Skip claims validation during token parsing
+	ValidMethods         []string   // If populated, only these methods will be considered valid
+	UseJSONNumber        bool       // Use JSON Number format in JSON decoder
+	SkipClaimsValidation bool       // Skip claims validation during token parsing
+	MaxNestingDepth      int        // Maximum number of enclosing tokens unwrapped by ParseNested. Defaults to DefaultMaxNestingDepth
+	Decrypter            Decrypter  // Decrypts encrypted tokens encountered by ParseNested
+	Quirks               []Quirk    // Lenient decoding options for non-conforming issuers, such as QuirkADFS
+	Validator            *Validator // Optional. Applies a Policy to the claims after Claims.Valid
+
+	Abbreviations ClaimAbbreviations // Optional. Abbreviated claim names expanded before decoding
+	JSONUnmarshal JSONUnmarshalFunc  // Optional. Decodes the header and claims. Defaults to the provider set with SetJSONProvider
+	MaxTokenSize  int                // Optional. Maximum length of a token in bytes. Defaults to no limit, or DefaultMaxTokenSize for ParseReader
+	MaxClaimsSize int                // Optional. Maximum length of the decoded claims in bytes. Defaults to no limit
+
+	// StrictDecoding rejects segments which are not in the canonical
+	// base64url encoding: padded segments, segments containing line breaks
+	// and segments whose unused trailing bits are not zero. Otherwise several
+	// token strings may carry the same token, which matters to systems
+	// keying caches or blocklists by the raw token. It overrides
+	// Quirk.AllowPaddedSegments.
+	StrictDecoding bool
+
+	// StrictAlgorithms rejects every token unless ValidMethods is set, so
+	// that the accepted algorithms are always stated explicitly.
+	StrictAlgorithms bool
+
+	// InsecureAllowAnyAlgorithm disables the check that the key returned by
+	// the Keyfunc suits the algorithm of the token, such as an RSA public key
+	// in PEM form not being used as an HMAC secret. Unsigned tokens are still
+	// only accepted if the Keyfunc returns UnsafeAllowNoneSignatureType.
+	InsecureAllowAnyAlgorithm bool
+
+	// ValidTypes, if populated, lists the only "typ" header values accepted,
+	// such as TypeAccessToken, so that a resource server cannot be handed an
+	// ID token in place of an access token. Values are compared without
+	// regard to case or an "application/" prefix, as described in RFC 7515,
+	// section 4.1.9. Tokens without a "typ" header are only accepted if
+	// ValidTypes holds an empty string.
+	ValidTypes []string
+
+	// SigningMethods, if set, resolves the "alg" header of tokens in place of
+	// the global registry. Algorithms it does not hold are rejected with an
+	// UnregisteredSigningMethodError.
+	SigningMethods *SigningMethodRegistry
+
+	// RevocationChecker, if set, is called once the signature and claims of
+	// a token are valid, and rejects it by returning an error, such as one
+	// wrapping ErrTokenRevoked. See BlocklistChecker.
+	RevocationChecker RevocationChecker
+
+	// VerificationCache, if set, skips the verification of signatures it
+	// recently saw verified with the same key. See VerificationCache.
+	VerificationCache *VerificationCache
+
+	// Hooks, if OnParse is set, are reported every token parsed, for
+	// metrics and tracing.
+	Hooks Hooks
+
+	// Logger, if set, is told at debug level why tokens are rejected. See
+	// WithLogger.
+	Logger Logger
+
+	// AllowUnsignedEncryptedTokens makes ParseNested accept encrypted tokens
+	// whose plaintext is the claims set rather than a signed token. Such
+	// tokens are rejected with ErrTokenUnsigned otherwise: decryption only
+	// proves the token was encrypted for the recipient, and with algorithms
+	// such as RSA-OAEP and ECDH-ES anyone holding the recipient's public key
+	// can encrypt any claims. Tokens accepted this way have Unsigned set and
+	// Valid unset, and must only be trusted if the sender is authenticated by
+	// other means.
+	AllowUnsignedEncryptedTokens bool
+}
+
+// ParserOption configures a Parser created with NewParser.
+type ParserOption func(*Parser)
+
+// NewParser returns a Parser configured with opts.
+func NewParser(opts ...ParserOption) *Parser {
+	p := new(Parser)
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithMaxTokenSize sets the maximum length of a token in bytes. Longer tokens
+// are rejected with ErrTokenTooLarge before any of them is decoded.
+func WithMaxTokenSize(bytes int) ParserOption {
+	return func(p *Parser) {
+		p.MaxTokenSize = bytes
+	}
+}
+
+// WithStrictDecoding rejects segments which are not in the canonical base64url
+// encoding, as described for Parser.StrictDecoding.
+func WithStrictDecoding() ParserOption {
+	return func(p *Parser) {
+		p.StrictDecoding = true
+	}
+}
+
+// WithValidMethods sets the only signing methods accepted, by "alg" name.
+func WithValidMethods(methods ...string) ParserOption {
+	return func(p *Parser) {
+		p.ValidMethods = methods
+	}
+}
+
+// WithValidTypes sets the only "typ" header values accepted, as described for
+// Parser.ValidTypes.
+func WithValidTypes(types ...string) ParserOption {
+	return func(p *Parser) {
+		p.ValidTypes = types
+	}
+}
+
+// WithAccessTokenType accepts only access tokens in the JWT profile of RFC
+// 9068, whose "typ" header is "at+jwt".
+func WithAccessTokenType() ParserOption {
+	return WithValidTypes(TypeAccessToken)
+}
+
+// WithLogoutTokenType accepts only OpenID Connect back-channel logout tokens,
+// whose "typ" header is "logout+jwt".
+func WithLogoutTokenType() ParserOption {
+	return WithValidTypes(TypeLogoutToken)
+}
+
+// WithSigningMethods resolves the "alg" header of tokens from r only, as
+// described for Parser.SigningMethods.
+func WithSigningMethods(r *SigningMethodRegistry) ParserOption {
+	return func(p *Parser) {
+		p.SigningMethods = r
+	}
+}
+
+// WithStrictAlgorithms rejects every token unless valid methods are set with
+// WithValidMethods.
+func WithStrictAlgorithms() ParserOption {
+	return func(p *Parser) {
+		p.StrictAlgorithms = true
+	}
+}
+
+// WithInsecureAllowAnyAlgorithm disables the default rejection of keys which
+// do not suit the token's algorithm, as described for
+// Parser.InsecureAllowAnyAlgorithm. It exists for compatibility only.
+func WithInsecureAllowAnyAlgorithm() ParserOption {
+	return func(p *Parser) {
+		p.InsecureAllowAnyAlgorithm = true
+	}
+}
+
+// WithInsecureUnsignedEncryptedTokens makes ParseNested accept encrypted
+// tokens which do not enclose a signed token, as described for
+// Parser.AllowUnsignedEncryptedTokens.
+func WithInsecureUnsignedEncryptedTokens() ParserOption {
+	return func(p *Parser) {
+		p.AllowUnsignedEncryptedTokens = true
+	}
+}
+
+// WithMaxClaimsSize sets the maximum length of the decoded claims in bytes.
+// Tokens with larger claims are rejected with ErrTokenTooLarge before the
+// claims are decoded.
+func WithMaxClaimsSize(bytes int) ParserOption {
+	return func(p *Parser) {
+		p.MaxClaimsSize = bytes
+	}
 }
 
 // Parse parses, validates, and returns a token.
@@ -20,28 +189,53 @@ func (p *Parser) Parse(tokenString string, keyFunc Keyfunc) (*Token, error) {
 }
 
 func (p *Parser) ParseWithClaims(tokenString string, claims Claims, keyFunc Keyfunc) (*Token, error) {
-	token, parts, err := p.ParseUnverified(tokenString, claims)
+	return p.ParseWithContext(context.Background(), tokenString, claims, keyFunc)
+}
+
+// ParseWithContext is ParseWithClaims, passing ctx to the RevocationChecker
+// and Hooks.OnParse.
+func (p *Parser) ParseWithContext(ctx context.Context, tokenString string, claims Claims, keyFunc Keyfunc) (*Token, error) {
+	return p.observe(ctx, tokenString, func(stage *string) (*Token, error) {
+		return p.parseWithContext(ctx, tokenString, claims, keyFunc, stage)
+	})
+}
+
+// observe runs parse, which sets stage to the step being taken, reporting the
+// outcome to Hooks.OnParse and the reason for a failure to the Logger. Every
+// entry point of the Parser which verifies tokens goes through it.
+func (p *Parser) observe(ctx context.Context, tokenString string, parse func(stage *string) (*Token, error)) (*Token, error) {
+	var start time.Time
+	if p.Hooks.OnParse != nil {
+		start = time.Now()
+	}
+	var stage string
+	token, err := parse(&stage)
+	if err != nil {
+		p.logFailure(stage, tokenString, token, err)
+	}
+	if p.Hooks.OnParse != nil {
+		p.Hooks.OnParse(ctx, newParseEvent(start, token, err))
+	}
+	return token, err
+}
+
+func (p *Parser) parseWithContext(ctx context.Context, tokenString string, claims Claims, keyFunc Keyfunc, stage *string) (*Token, error) {
+	token, parts, err := p.parseUnverified(tokenString, claims, stage)
 	if err != nil {
 		return token, err
 	}
 
 	// Verify signing method is in the required set
-	if p.ValidMethods != nil {
-		var signingMethodValid = false
-		var alg = token.Method.Alg()
-		for _, m := range p.ValidMethods {
-			if m == alg {
-				signingMethodValid = true
-				break
-			}
-		}
-		if !signingMethodValid {
-			// signing method is not in the listed set
-			return token, &InvalidSigningMethodError{Alg: alg}
-		}
+	*stage = stageMethod
+	if err = p.verifyMethod(token); err != nil {
+		return token, err
+	}
+	if err = p.verifyType(token); err != nil {
+		return token, err
 	}
 
 	// Lookup key
+	*stage = stageKeyfunc
 	var key interface{}
 	if keyFunc == nil {
 		// keyFunc was not provided.  short circuiting validation
@@ -54,22 +248,44 @@ func (p *Parser) ParseWithClaims(tokenString string, claims Claims, keyFunc Keyf
 	}
 
 	// Validate Claims
-	if !p.SkipClaimsValidation {
-		if err := token.Claims.Valid(); err != nil {
-			return token, err
-		}
+	*stage = stageClaimsValidation
+	if err = p.validateClaims(ctx, token); err != nil {
+		return token, err
 	}
 
 	// Perform validation
+	*stage = stageSignature
 	token.Signature = parts[2]
-	if err = token.Method.Verify(strings.Join(parts[0:2], "."), token.Signature, key); err != nil {
+	if p.allowPadding() {
+		token.Signature = strings.TrimRight(token.Signature, "=")
+	}
+	if err = p.verifySignature(token, strings.Join(parts[0:2], "."), key); err != nil {
 		token.Valid = false
 		return token, err
 	}
+	*stage = stageRevocation
+	if err = p.checkVerified(ctx, token); err != nil {
+		return token, err
+	}
 	token.Valid = true
 	return token, nil
 }
 
+// checkVerified applies the checks which must wait until the token has been
+// verified: recording it with the ReplayDetector of the Validator, unless
+// SkipClaimsValidation is set, and consulting the RevocationChecker.
+func (p *Parser) checkVerified(ctx context.Context, token *Token) error {
+	if !p.SkipClaimsValidation && p.Validator != nil && p.Validator.ReplayDetector != nil {
+		if err := p.Validator.checkReplay(ctx, token); err != nil {
+			return err
+		}
+	}
+	if p.RevocationChecker != nil {
+		return p.RevocationChecker(ctx, token)
+	}
+	return nil
+}
+
 // ParseUnverified parses the token but doesn't validate the signature.
 //
 // WARNING: Don't use this method unless you know what you're doing.
@@ -77,59 +293,327 @@ func (p *Parser) ParseWithClaims(tokenString string, claims Claims, keyFunc Keyf
 // It's only ever useful in cases where you know the signature is valid (because it has
 // been checked previously in the stack) and you want to extract values from it.
 func (p *Parser) ParseUnverified(tokenString string, claims Claims) (token *Token, parts []string, err error) {
-	parts = strings.Split(tokenString, ".")
-	if len(parts) != 3 {
-		return nil, parts, MalformedTokenError("token contains an invalid number of segments")
+	var stage string
+	token, parts, err = p.parseUnverified(tokenString, claims, &stage)
+	if err != nil {
+		p.logFailure(stage, tokenString, token, err)
+	}
+	return token, parts, err
+}
+
+// parseUnverified implements ParseUnverified, setting stage to the step being
+// taken, so that the step which failed can be logged.
+func (p *Parser) parseUnverified(tokenString string, claims Claims, stage *string) (token *Token, parts []string, err error) {
+	*stage = stageSplit
+	if parts, err = p.splitToken(tokenString); err != nil {
+		return nil, parts, err
 	}
 
 	token = &Token{Raw: tokenString}
 
+	// Both segments are decoded into one buffer, which is pooled unless the
+	// JSON decoder in use might retain it.
+	header, payload := parts[0], parts[1]
+	if p.allowPadding() {
+		header, payload = strings.TrimRight(header, "="), strings.TrimRight(payload, "=")
+	}
+	*stage = stageClaimsDecode
+	if err = p.checkClaimsSize(base64.RawURLEncoding.DecodedLen(len(payload))); err != nil {
+		return token, parts, err
+	}
+	n := base64.RawURLEncoding.DecodedLen(len(header)) + base64.RawURLEncoding.DecodedLen(len(payload))
+	var buf []byte
+	if p.JSONUnmarshal == nil && !customJSON {
+		bp := decodeBuffers.Get().(*[]byte)
+		defer decodeBuffers.Put(bp)
+		if cap(*bp) < n {
+			*bp = make([]byte, n)
+		}
+		buf = (*bp)[:n]
+	} else {
+		buf = make([]byte, n)
+	}
+
 	// parse Header
-	var headerBytes []byte
-	headerBytes, err = DecodeSegment(parts[0])
+	*stage = stageHeader
+	hn, err := base64.RawURLEncoding.Decode(buf, []byte(header))
 	if err != nil {
 		if strings.HasPrefix(strings.ToLower(tokenString), "bearer ") {
 			return token, parts, MalformedTokenError(`token may not contain "bearer "`)
 		}
 		return token, parts, MalformedTokenError(err.Error())
 	}
+	headerBytes, buf := buf[:hn:hn], buf[hn:]
 
-	if err = json.Unmarshal(headerBytes, &token.Header); err != nil {
+	if err = p.unmarshalJSON(headerBytes, &token.Header); err != nil {
 		return token, parts, MalformedTokenError(err.Error())
 	}
+	p.applyHeaderQuirks(token.Header)
 
 	// parse Claims
-	var claimBytes []byte
+	*stage = stageClaimsDecode
 	token.Claims = claims
 
-	claimBytes, err = DecodeSegment(parts[1])
+	cn, err := base64.RawURLEncoding.Decode(buf, []byte(payload))
 	if err != nil {
 		return token, parts, MalformedTokenError(err.Error())
 	}
-	dec := json.NewDecoder(bytes.NewBuffer(claimBytes))
-	if p.UseJSONNumber {
-		dec.UseNumber()
+	if err = p.decodeClaims(buf[:cn:cn], claims); err != nil {
+		return token, parts, err
+	}
+
+	// Lookup signature method
+	*stage = stageMethod
+	if err = p.lookupMethod(token); err != nil {
+		return token, parts, err
+	}
+	return token, parts, nil
+}
+
+// decodeBuffers holds the buffers ParseUnverified decodes segments into.
+// encoding/json copies the values it decodes, so the buffers can be reused
+// once decoding is done.
+var decodeBuffers = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 512)
+		return &b
+	},
+}
+
+// splitToken splits a token in the compact serialization into its three
+// segments. If it does not have three segments, all of them are returned
+// along with false.
+func splitToken(s string) ([]string, bool) {
+	i := strings.IndexByte(s, '.')
+	j := i + 1 + strings.IndexByte(s[i+1:], '.')
+	if i < 0 || j <= i || strings.IndexByte(s[j+1:], '.') >= 0 {
+		return strings.Split(s, "."), false
+	}
+	return []string{s[:i], s[i+1 : j], s[j+1:]}, true
+}
+
+// checkClaimsSize checks the length of the decoded claims against
+// MaxClaimsSize.
+func (p *Parser) checkClaimsSize(n int) error {
+	if p.MaxClaimsSize > 0 && n > p.MaxClaimsSize {
+		return ErrTokenTooLarge
+	}
+	return nil
+}
+
+// checkSegments splits a token in the compact serialization, rejecting it
+// unless it has exactly three segments of which the header and claims are not
+// empty.
+func checkSegments(s string) ([]string, error) {
+	parts, ok := splitToken(s)
+	if !ok {
+		return parts, segmentsError("token contains an invalid number of segments")
+	}
+	if parts[0] == "" || parts[1] == "" {
+		return parts, segmentsError("token contains an empty segment")
+	}
+	return parts, nil
+}
+
+// splitToken checks the size of tokenString and splits it into its segments,
+// which must be canonical if StrictDecoding is set.
+func (p *Parser) splitToken(tokenString string) ([]string, error) {
+	if p.MaxTokenSize > 0 && len(tokenString) > p.MaxTokenSize {
+		return nil, ErrTokenTooLarge
+	}
+	parts, err := checkSegments(tokenString)
+	if err != nil {
+		return parts, err
+	}
+	if p.StrictDecoding {
+		for _, part := range parts {
+			if !canonicalSegment(part) {
+				return parts, MalformedTokenError("token contains a segment which is not canonical base64url")
+			}
+		}
+	}
+	return parts, nil
+}
+
+// canonicalSegment reports whether s is the one encoding of its content in
+// unpadded base64url: it only contains characters of the alphabet, has a valid
+// length and leaves the unused bits of its last character zero.
+func canonicalSegment(s string) bool {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	if len(s) == 0 {
+		return true
+	}
+	last := strings.IndexByte(base64URLAlphabet, s[len(s)-1])
+	switch len(s) % 4 {
+	case 1:
+		return false
+	case 2:
+		return last&0x0f == 0
+	case 3:
+		return last&0x03 == 0
+	}
+	return true
+}
+
+const base64URLAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+func (p *Parser) decodeClaims(claimBytes []byte, claims Claims) error {
+	if err := p.checkClaimsSize(len(claimBytes)); err != nil {
+		return err
 	}
-	// JSON Decode.  Special case for map type to avoid weird pointer behavior
-	if c, ok := token.Claims.(MapClaims); ok {
-		err = dec.Decode(&c)
+	claimBytes = p.applyClaimsQuirks(claimBytes)
+	if len(p.Abbreviations) > 0 {
+		var err error
+		if claimBytes, err = p.Abbreviations.Expand(claimBytes); err != nil {
+			return MalformedTokenError(err.Error())
+		}
+	}
+	var err error
+	if (p.JSONUnmarshal != nil || customJSON) && !p.UseJSONNumber {
+		// Other decoders may replace, rather than fill, the map.
+		if c, ok := claims.(MapClaims); ok {
+			var m map[string]interface{}
+			if err = p.unmarshalJSON(claimBytes, &m); err == nil {
+				for k, v := range m {
+					c[k] = v
+				}
+			}
+		} else {
+			err = p.unmarshalJSON(claimBytes, claims)
+		}
+	} else if p.UseJSONNumber {
+		dec := json.NewDecoder(bytes.NewBuffer(claimBytes))
+		dec.UseNumber()
+		// JSON Decode.  Special case for map type to avoid weird pointer behavior
+		if c, ok := claims.(MapClaims); ok {
+			err = dec.Decode(&c)
+		} else {
+			err = dec.Decode(&claims)
+		}
+	} else if c, ok := claims.(MapClaims); ok {
+		err = json.Unmarshal(claimBytes, &c)
 	} else {
-		err = dec.Decode(&claims)
+		err = json.Unmarshal(claimBytes, &claims)
 	}
 	// Handle decode error
 	if err != nil {
-		return token, parts, MalformedTokenError(err.Error())
+		return MalformedTokenError(err.Error())
+	}
+	if err = captureUnknownClaims(claimBytes, claims); err != nil {
+		return MalformedTokenError(err.Error())
 	}
+	return nil
+}
 
-	// Lookup signature method
+// validateClaims validates the token's claims with Claims.Valid and the
+// parser's Validator, unless SkipClaimsValidation is set. The token is not
+// recorded with the ReplayDetector of the Validator, which must wait until the
+// token has been verified; see checkVerified.
+func (p *Parser) validateClaims(ctx context.Context, token *Token) error {
+	if p.SkipClaimsValidation {
+		return nil
+	}
+	if err := token.Claims.Valid(); err != nil {
+		return err
+	}
+	if p.Validator != nil {
+		return p.Validator.validate(ctx, token, false)
+	}
+	return nil
+}
 
+// lookupMethod sets token.Method from the "alg" header, resolved through
+// SigningMethods or the global registry.
+func (p *Parser) lookupMethod(token *Token) error {
 	alg, ok := token.Header["alg"].(string)
 	if !ok || len(alg) == 0 {
-		return token, parts, MalformedTokenError("signing method (alg) not specified")
+		return MalformedTokenError("signing method (alg) not specified")
+	}
+	registry := p.SigningMethods
+	if registry == nil {
+		registry = signingMethods
 	}
-	token.Method = GetSigningMethod(alg)
+	token.Method = registry.Get(alg)
 	if token.Method == nil {
-		return token, parts, &UnregisteredSigningMethodError{Alg: alg}
+		return &UnregisteredSigningMethodError{Alg: alg}
 	}
-	return token, parts, nil
+	return nil
+}
+
+// verifyMethod checks that the token's signing method is in ValidMethods, if
+// set. "none" is otherwise left to verifySignature, as the Keyfunc opts in to
+// unsigned tokens by returning UnsafeAllowNoneSignatureType.
+func (p *Parser) verifyMethod(token *Token) error {
+	alg := token.Method.Alg()
+	if p.ValidMethods == nil {
+		if p.StrictAlgorithms {
+			return ErrMissingValidMethods
+		}
+		return nil
+	}
+	for _, m := range p.ValidMethods {
+		if m == alg {
+			return nil
+		}
+	}
+	// signing method is not in the listed set
+	return &InvalidSigningMethodError{Alg: alg}
+}
+
+// verifyType checks that the token's "typ" header is in ValidTypes, if set.
+func (p *Parser) verifyType(token *Token) error {
+	if p.ValidTypes == nil {
+		return nil
+	}
+	typ, _ := token.Header["typ"].(string)
+	for _, t := range p.ValidTypes {
+		if sameType(typ, t) {
+			return nil
+		}
+	}
+	return ErrTokenInvalidType
+}
+
+// sameType compares two media types of the "typ" or "cty" header, ignoring
+// case and the "application/" prefix.
+func sameType(a, b string) bool {
+	const prefix = "application/"
+	if len(a) > len(prefix) && strings.EqualFold(a[:len(prefix)], prefix) {
+		a = a[len(prefix):]
+	}
+	if len(b) > len(prefix) && strings.EqualFold(b[:len(prefix)], prefix) {
+		b = b[len(prefix):]
+	}
+	return strings.EqualFold(a, b)
+}
+
+// verifySignature checks that key suits the token's signing method, unless
+// InsecureAllowAnyAlgorithm is set, and verifies the signature with it,
+// consulting the VerificationCache if one is set.
+func (p *Parser) verifySignature(token *Token, signingString string, key interface{}) error {
+	if !p.InsecureAllowAnyAlgorithm {
+		if err := checkKeyConfusion(token.Method, key); err != nil {
+			return err
+		}
+	}
+	var cacheKey string
+	if p.VerificationCache != nil {
+		cacheKey = verificationKey(token, signingString, key)
+		if cacheKey != "" && p.VerificationCache.verified(cacheKey) {
+			return nil
+		}
+	}
+	if err := verifySignature(token, signingString, key); err != nil {
+		return err
+	}
+	if cacheKey != "" {
+		p.VerificationCache.add(cacheKey, token)
+	}
+	return nil
 }
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -381,6 +383,168 @@ func TestParser_ParseUnverified(t *testing.T) {
 	}
 }
 
+func TestParser_ParseUnverified_segments(t *testing.T) {
+	var tests = []struct {
+		token string
+		parts int
+	}{
+		{"a", 1},
+		{"a.b", 2},
+		{"a.b.c.d", 4},
+		{"..", 3},
+		{".e30.", 3},
+		{"e30..", 3},
+	}
+
+	for _, data := range tests {
+		_, parts, err := new(jwt.Parser).ParseUnverified(data.token, jwt.MapClaims{})
+		if !errors.Is(err, jwt.ErrMalformedToken) || !errors.Is(err, jwt.ErrInvalidSegments) {
+			t.Errorf("[%v] Expected ErrMalformedToken and ErrInvalidSegments. Got: %v", data.token, err)
+		}
+		if len(parts) != data.parts {
+			t.Errorf("[%v] Expected %v parts. Got: %v", data.token, data.parts, parts)
+		}
+	}
+}
+
+func TestNewParser_WithMaxTokenSize(t *testing.T) {
+	// The token is rejected on its length alone, so it need not be valid.
+	token := strings.Repeat("a", 1<<20)
+	_, _, err := jwt.NewParser(jwt.WithMaxTokenSize(1024)).ParseUnverified(token, jwt.MapClaims{})
+	if !errors.Is(err, jwt.ErrTokenTooLarge) {
+		t.Errorf("Expected ErrTokenTooLarge. Got: %v", err)
+	}
+	_, _, err = jwt.NewParser(jwt.WithMaxClaimsSize(2)).ParseUnverified("e30.eyJhIjoxfQ.", jwt.MapClaims{})
+	if !errors.Is(err, jwt.ErrTokenTooLarge) {
+		t.Errorf("Expected ErrTokenTooLarge. Got: %v", err)
+	}
+}
+
+func TestParser_StrictDecoding(t *testing.T) {
+	key := []byte("secret")
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"a": "b"}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+	parts := strings.Split(signed, ".")
+
+	// The signature of HS256 is 43 characters long, leaving two unused bits.
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	sig := []byte(parts[2])
+	sig[len(sig)-1] = alphabet[strings.IndexByte(alphabet, sig[len(sig)-1])|1]
+
+	var tests = []struct {
+		name  string
+		token string
+	}{
+		{"trailing bits", parts[0] + "." + parts[1] + "." + string(sig)},
+		{"line break", parts[0] + "." + parts[1] + "." + parts[2][:4] + "\r\n" + parts[2][4:]},
+		{"padding", parts[0] + "." + parts[1] + "." + parts[2] + "="},
+	}
+	for _, data := range tests {
+		lenient := &jwt.Parser{Quirks: []jwt.Quirk{jwt.QuirkADFS}}
+		if _, err := lenient.Parse(data.token, keyFunc); err != nil {
+			t.Errorf("[%v] Expected the lenient parser to accept the token. Got: %v", data.name, err)
+		}
+		strict := &jwt.Parser{Quirks: []jwt.Quirk{jwt.QuirkADFS}, StrictDecoding: true}
+		if _, err := strict.Parse(data.token, keyFunc); !errors.Is(err, jwt.ErrMalformedToken) {
+			t.Errorf("[%v] Expected ErrMalformedToken. Got: %v", data.name, err)
+		}
+	}
+	if _, err := jwt.NewParser(jwt.WithStrictDecoding()).Parse(signed, keyFunc); err != nil {
+		t.Errorf("Expected the canonical token to be accepted. Got: %v", err)
+	}
+}
+
+func TestParser_algorithms(t *testing.T) {
+	pemKey, err := ioutil.ReadFile("test/sample_key.pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A token forged by signing with the public key as an HMAC secret,
+	// verified by a Keyfunc returning the raw key file for every algorithm.
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "admin"}).SignedString(pemKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"sub": "admin"}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKeyFunc := func(*jwt.Token) (interface{}, error) { return pemKey, nil }
+	noneKeyFunc := func(*jwt.Token) (interface{}, error) { return jwt.UnsafeAllowNoneSignatureType, nil }
+
+	var tests = []struct {
+		name    string
+		parser  *jwt.Parser
+		token   string
+		keyFunc jwt.Keyfunc
+		err     error
+	}{
+		{"key confusion", jwt.NewParser(), forged, pemKeyFunc, jwt.ErrKeyAlgorithmMismatch},
+		{"key confusion in a key set", jwt.NewParser(), forged, func(*jwt.Token) (interface{}, error) {
+			return jwt.NewVerificationKeySet([]byte("secret"), pemKey), nil
+		}, jwt.ErrKeyAlgorithmMismatch},
+		{"key confusion, insecure", jwt.NewParser(jwt.WithInsecureAllowAnyAlgorithm()), forged, pemKeyFunc, nil},
+		{"none", jwt.NewParser(), unsigned, noneKeyFunc, nil},
+		{"none, no sentinel", jwt.NewParser(), unsigned, pemKeyFunc, jwt.ErrNoneSignatureTypeDisallowed},
+		{"none, no sentinel, insecure", jwt.NewParser(jwt.WithInsecureAllowAnyAlgorithm()), unsigned, pemKeyFunc, jwt.ErrNoneSignatureTypeDisallowed},
+		{"none, listed", jwt.NewParser(jwt.WithValidMethods("none")), unsigned, noneKeyFunc, nil},
+		{"none, not listed", jwt.NewParser(jwt.WithValidMethods("RS256")), unsigned, noneKeyFunc, jwt.ErrInvalidSigningMethod},
+		{"strict", jwt.NewParser(jwt.WithStrictAlgorithms()), forged, pemKeyFunc, jwt.ErrMissingValidMethods},
+		{"strict, listed", jwt.NewParser(jwt.WithStrictAlgorithms(), jwt.WithValidMethods("RS256")), forged, pemKeyFunc, jwt.ErrInvalidSigningMethod},
+	}
+	for _, data := range tests {
+		_, err := data.parser.Parse(data.token, data.keyFunc)
+		if data.err == nil && err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		} else if !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+}
+
+func TestParser_ValidTypes(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	sign := func(opts ...jwt.TokenOption) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "a"}, opts...).SignedString(hmacTestKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	accessToken := sign(jwt.WithType(jwt.TypeAccessToken))
+	idToken := sign()
+	untyped := sign(jwt.WithType(""))
+	mediaType := sign(jwt.WithType("application/AT+JWT"))
+
+	var tests = []struct {
+		name   string
+		parser *jwt.Parser
+		token  string
+		valid  bool
+	}{
+		{"no restriction", jwt.NewParser(), idToken, true},
+		{"access token", jwt.NewParser(jwt.WithAccessTokenType()), accessToken, true},
+		{"media type", jwt.NewParser(jwt.WithAccessTokenType()), mediaType, true},
+		{"ID token as access token", jwt.NewParser(jwt.WithAccessTokenType()), idToken, false},
+		{"missing typ", jwt.NewParser(jwt.WithAccessTokenType()), untyped, false},
+		{"missing typ allowed", jwt.NewParser(jwt.WithValidTypes(jwt.TypeAccessToken, "")), untyped, true},
+		{"several types", jwt.NewParser(jwt.WithValidTypes(jwt.TypeJWT, jwt.TypeAccessToken)), idToken, true},
+		{"logout token", jwt.NewParser(jwt.WithLogoutTokenType()), accessToken, false},
+	}
+	for _, data := range tests {
+		_, err := data.parser.Parse(data.token, keyFunc)
+		if data.valid && err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		}
+		if !data.valid && !errors.Is(err, jwt.ErrTokenInvalidType) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, jwt.ErrTokenInvalidType, err)
+		}
+	}
+}
+
 func BenchmarkParseUnverified(b *testing.B) {
 	privateKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
 
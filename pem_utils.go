@@ -0,0 +1,165 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+)
+
+var (
+	ErrNotPrivateKey = errors.New("key is not a valid RSA, ECDSA or Ed25519 private key")
+	ErrNotPublicKey  = errors.New("key is not a valid RSA, ECDSA or Ed25519 public key")
+	ErrKeyEncrypted  = errors.New("key is encrypted and requires a password")
+
+	ErrUnsupportedKeyEncryption = errors.New("key is encrypted with an unsupported scheme")
+)
+
+// ParsePrivateKeyFromPEM parses a PEM encoded PKCS1, SEC 1 (EC) or PKCS8
+// private key, detecting its type. The result is an *rsa.PrivateKey,
+// *ecdsa.PrivateKey or ed25519.PrivateKey. Blocks which do not hold a private
+// key, such as the "EC PARAMETERS" written by openssl ecparam, are skipped.
+// Encrypted keys fail with ErrKeyEncrypted.
+func ParsePrivateKeyFromPEM(key []byte) (crypto.Signer, error) {
+	block, err := privateKeyBlock(key)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type == "ENCRYPTED PRIVATE KEY" || x509.IsEncryptedPEMBlock(block) {
+		return nil, ErrKeyEncrypted
+	}
+	return ParsePrivateKeyFromDER(block.Bytes)
+}
+
+// ParsePrivateKeyFromPEMWithPassword is like ParsePrivateKeyFromPEM, but
+// decrypts a key protected with password. Encrypted PKCS #8 keys ("ENCRYPTED
+// PRIVATE KEY") are decrypted as described for DecryptPKCS8PrivateKey. Keys
+// encrypted with the legacy PEM encryption of RFC 1423 ("Proc-Type:
+// 4,ENCRYPTED") are supported too; that scheme is insecure by design and
+// should only be used to read existing keys.
+// Unencrypted keys are returned as they are.
+func ParsePrivateKeyFromPEMWithPassword(key []byte, password string) (crypto.Signer, error) {
+	block, err := privateKeyBlock(key)
+	if err != nil {
+		return nil, err
+	}
+	der := block.Bytes
+	switch {
+	case block.Type == "ENCRYPTED PRIVATE KEY":
+		if der, err = DecryptPKCS8PrivateKey(der, password); err != nil {
+			return nil, err
+		}
+		// Decryption with a wrong password yields valid padding about once
+		// in 256 tries; the garbage that results does not parse.
+		k, err := ParsePrivateKeyFromDER(der)
+		if err != nil {
+			return nil, x509.IncorrectPasswordError
+		}
+		return k, nil
+	case x509.IsEncryptedPEMBlock(block):
+		if der, err = x509.DecryptPEMBlock(block, []byte(password)); err != nil {
+			return nil, err
+		}
+	}
+	return ParsePrivateKeyFromDER(der)
+}
+
+// ParsePrivateKeyFromDER parses a DER encoded PKCS1, SEC 1 (EC) or PKCS8
+// private key, detecting its type.
+func ParsePrivateKeyFromDER(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+		return k.(crypto.Signer), nil
+	}
+	return nil, ErrNotPrivateKey
+}
+
+// ParsePublicKeyFromPEM parses a PEM encoded PKIX or PKCS1 public key, or the
+// public key of a certificate, detecting its type. The result is an
+// *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey.
+func ParsePublicKeyFromPEM(key []byte) (crypto.PublicKey, error) {
+	for rest := key; ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			return nil, ErrKeyMustBePEMEncoded
+		}
+		switch block.Type {
+		case "PUBLIC KEY", "RSA PUBLIC KEY", "CERTIFICATE":
+			return ParsePublicKeyFromDER(block.Bytes)
+		}
+	}
+}
+
+// ParsePublicKeyFromDER parses a DER encoded PKIX or PKCS1 public key, or
+// the public key of a certificate, detecting its type.
+func ParsePublicKeyFromDER(der []byte) (crypto.PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		if key, err = x509.ParsePKCS1PublicKey(der); err != nil {
+			cert, cerr := x509.ParseCertificate(der)
+			if cerr != nil {
+				return nil, err
+			}
+			key = cert.PublicKey
+		}
+	}
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	}
+	return nil, ErrNotPublicKey
+}
+
+// ParseECPrivateKeyFromPEMWithPassword parses a PEM encoded ECDSA private key
+// protected with password, as described for ParsePrivateKeyFromPEMWithPassword.
+func ParseECPrivateKeyFromPEMWithPassword(key []byte, password string) (*ecdsa.PrivateKey, error) {
+	k, err := ParsePrivateKeyFromPEMWithPassword(key, password)
+	if err != nil {
+		return nil, err
+	}
+	if ec, ok := k.(*ecdsa.PrivateKey); ok {
+		return ec, nil
+	}
+	return nil, ErrNotECPrivateKey
+}
+
+// ParseEdPrivateKeyFromPEMWithPassword parses a PEM encoded Ed25519 private
+// key protected with password, as described for
+// ParsePrivateKeyFromPEMWithPassword.
+func ParseEdPrivateKeyFromPEMWithPassword(key []byte, password string) (crypto.PrivateKey, error) {
+	k, err := ParsePrivateKeyFromPEMWithPassword(key, password)
+	if err != nil {
+		return nil, err
+	}
+	if ed, ok := k.(ed25519.PrivateKey); ok {
+		return ed, nil
+	}
+	return nil, ErrNotEdPrivateKey
+}
+
+// privateKeyBlock returns the first PEM block of key holding a private key.
+func privateKeyBlock(key []byte) (*pem.Block, error) {
+	for rest := key; ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			return nil, ErrKeyMustBePEMEncoded
+		}
+		switch block.Type {
+		case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY", "ENCRYPTED PRIVATE KEY":
+			return block, nil
+		}
+	}
+}
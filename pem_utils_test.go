@@ -0,0 +1,114 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestParsePrivateKeyFromPEM(t *testing.T) {
+	var tests = []struct {
+		file string
+		typ  interface{}
+	}{
+		{"test/sample_key", &rsa.PrivateKey{}},
+		{"test/ec256-private.pem", &ecdsa.PrivateKey{}},
+		{"test/ec512-private.pem", &ecdsa.PrivateKey{}},
+		{"test/ed25519-private.pem", ed25519.PrivateKey{}},
+	}
+	for _, data := range tests {
+		pemData, err := ioutil.ReadFile(data.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := jwt.ParsePrivateKeyFromPEM(pemData)
+		if err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.file, err)
+			continue
+		}
+		if reflect.TypeOf(key) != reflect.TypeOf(data.typ) {
+			t.Errorf("[%v] Parsed a %T", data.file, key)
+		}
+		pub, err := jwt.ParsePublicKeyFromPEM(publicPEM(t, key.Public()))
+		if err != nil || !reflect.DeepEqual(pub, key.Public()) {
+			t.Errorf("[%v] ParsePublicKeyFromPEM() = %v, %v", data.file, pub, err)
+		}
+	}
+}
+
+func TestParsePrivateKeyFromPEM_formats(t *testing.T) {
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	sec1, _ := x509.MarshalECPrivateKey(ec)
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(ec)
+
+	// openssl ecparam -genkey writes the curve parameters first.
+	withParams := append(pem.EncodeToMemory(&pem.Block{Type: "EC PARAMETERS", Bytes: []byte{0x06, 0x08, 0x2a, 0x86, 0x48, 0xce, 0x3d, 0x03, 0x01, 0x07}}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1})...)
+	for name, data := range map[string][]byte{
+		"SEC 1 with parameters": withParams,
+		"PKCS8":                 pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+	} {
+		key, err := jwt.ParsePrivateKeyFromPEM(data)
+		if err != nil || !ec.Equal(key) {
+			t.Errorf("[%v] ParsePrivateKeyFromPEM() = %v, %v", name, key, err)
+		}
+	}
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)})
+	if pub, err := jwt.ParsePublicKeyFromPEM(pkcs1); err != nil || !rsaKey.PublicKey.Equal(pub) {
+		t.Errorf("[PKCS1 public key] ParsePublicKeyFromPEM() = %v, %v", pub, err)
+	}
+
+	if _, err := jwt.ParsePrivateKeyFromPEM([]byte("not a key")); !errors.Is(err, jwt.ErrKeyMustBePEMEncoded) {
+		t.Errorf("Expected ErrKeyMustBePEMEncoded, got %v", err)
+	}
+}
+
+func TestParsePrivateKeyFromPEMWithPassword(t *testing.T) {
+	secure, err := ioutil.ReadFile("test/privateSecure.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = jwt.ParsePrivateKeyFromPEM(secure); !errors.Is(err, jwt.ErrKeyEncrypted) {
+		t.Errorf("Expected ErrKeyEncrypted, got %v", err)
+	}
+	key, err := jwt.ParsePrivateKeyFromPEMWithPassword(secure, "password")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := key.(*rsa.PrivateKey); !ok {
+		t.Errorf("Parsed a %T", key)
+	}
+	if _, err = jwt.ParsePrivateKeyFromPEMWithPassword(secure, "wrong"); err == nil {
+		t.Error("Expected an error with a wrong password")
+	}
+	if _, err = jwt.ParseECPrivateKeyFromPEMWithPassword(secure, "password"); !errors.Is(err, jwt.ErrNotECPrivateKey) {
+		t.Errorf("Expected ErrNotECPrivateKey, got %v", err)
+	}
+
+	// Unencrypted keys are accepted as they are.
+	plain, _ := ioutil.ReadFile("test/ed25519-private.pem")
+	if _, err = jwt.ParseEdPrivateKeyFromPEMWithPassword(plain, "unused"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func publicPEM(t *testing.T, pub interface{}) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
@@ -0,0 +1,188 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"hash"
+
+	"github.com/chanced/go-jwt/v4/internal/kdf"
+)
+
+// Object identifiers of RFC 8018 and RFC 7914 for the PBES2 encryption of
+// PKCS #8 private keys.
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidScrypt = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11591, 4, 11}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA224 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 8}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+type scryptParams struct {
+	Salt                     []byte
+	CostParameter            int
+	BlockSize                int
+	ParallelizationParameter int
+	KeyLength                int `asn1:"optional"`
+}
+
+// DecryptPKCS8PrivateKey decrypts the DER encoded PKCS #8
+// EncryptedPrivateKeyInfo der, the content of an "ENCRYPTED PRIVATE KEY" PEM
+// block, with password, returning the DER encoded PKCS #8 private key. Keys
+// encrypted with PBES2, deriving the key with PBKDF2 or scrypt and encrypting
+// with AES-CBC or 3DES-CBC, are supported; this covers the output of
+// "openssl pkcs8 -topk8" and "openssl genpkey" with and without -scrypt.
+// Other schemes fail with ErrUnsupportedKeyEncryption, and a wrong password
+// with x509.IncorrectPasswordError.
+func DecryptPKCS8PrivateKey(der []byte, password string) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, err
+	} else if len(rest) > 0 {
+		return nil, asn1.SyntaxError{Msg: "trailing data after encrypted private key"}
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, ErrUnsupportedKeyEncryption
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, err
+	}
+
+	newCipher, keyLen, err := pbes2Cipher(params.EncryptionScheme.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, err
+	}
+	key, err := pbes2Key(params.KeyDerivationFunc, []byte(password), keyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data := info.EncryptedData
+	if len(iv) != block.BlockSize() || len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, asn1.SyntaxError{Msg: "invalid encrypted private key"}
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+
+	// A wrong password almost always shows as bad padding; the rare case
+	// where it does not is caught when the plaintext fails to parse.
+	n := int(out[len(out)-1])
+	if n == 0 || n > block.BlockSize() {
+		return nil, x509.IncorrectPasswordError
+	}
+	for _, b := range out[len(out)-n:] {
+		if int(b) != n {
+			return nil, x509.IncorrectPasswordError
+		}
+	}
+	return out[:len(out)-n], nil
+}
+
+// pbes2Cipher returns the constructor and key length of the PBES2 encryption
+// scheme oid.
+func pbes2Cipher(oid asn1.ObjectIdentifier) (func([]byte) (cipher.Block, error), int, error) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return aes.NewCipher, 16, nil
+	case oid.Equal(oidAES192CBC):
+		return aes.NewCipher, 24, nil
+	case oid.Equal(oidAES256CBC):
+		return aes.NewCipher, 32, nil
+	case oid.Equal(oidDESEDE3CBC):
+		return des.NewTripleDESCipher, 24, nil
+	}
+	return nil, 0, ErrUnsupportedKeyEncryption
+}
+
+// pbes2Key derives the keyLen byte encryption key from password with the key
+// derivation function fn.
+func pbes2Key(fn pkix.AlgorithmIdentifier, password []byte, keyLen int) ([]byte, error) {
+	switch {
+	case fn.Algorithm.Equal(oidPBKDF2):
+		var params pbkdf2Params
+		if _, err := asn1.Unmarshal(fn.Parameters.FullBytes, &params); err != nil {
+			return nil, err
+		}
+		if params.KeyLength != 0 && params.KeyLength != keyLen {
+			return nil, ErrUnsupportedKeyEncryption
+		}
+		if params.IterationCount <= 0 {
+			return nil, asn1.StructuralError{Msg: "invalid PBKDF2 iteration count"}
+		}
+		h, err := pbkdf2PRF(params.PRF.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		return kdf.PBKDF2(password, params.Salt, params.IterationCount, keyLen, h), nil
+	case fn.Algorithm.Equal(oidScrypt):
+		var params scryptParams
+		if _, err := asn1.Unmarshal(fn.Parameters.FullBytes, &params); err != nil {
+			return nil, err
+		}
+		if params.KeyLength != 0 && params.KeyLength != keyLen {
+			return nil, ErrUnsupportedKeyEncryption
+		}
+		return kdf.Scrypt(password, params.Salt, params.CostParameter, params.BlockSize, params.ParallelizationParameter, keyLen)
+	}
+	return nil, ErrUnsupportedKeyEncryption
+}
+
+// pbkdf2PRF returns the hash of the HMAC pseudorandom function oid, which
+// defaults to HMAC-SHA1 when absent.
+func pbkdf2PRF(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+	switch {
+	case len(oid) == 0, oid.Equal(oidHMACWithSHA1):
+		return sha1.New, nil
+	case oid.Equal(oidHMACWithSHA224):
+		return sha256.New224, nil
+	case oid.Equal(oidHMACWithSHA256):
+		return sha256.New, nil
+	case oid.Equal(oidHMACWithSHA384):
+		return sha512.New384, nil
+	case oid.Equal(oidHMACWithSHA512):
+		return sha512.New, nil
+	}
+	return nil, ErrUnsupportedKeyEncryption
+}
@@ -0,0 +1,75 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestParsePrivateKeyFromPEMWithPassword_pkcs8(t *testing.T) {
+	var tests = []struct {
+		name      string
+		encrypted string
+		plain     string
+	}{
+		{"PBKDF2 HMAC-SHA256 AES-256", "test/pkcs8-pbkdf2-ec256.pem", "test/ec256-private.pem"},
+		{"PBKDF2 HMAC-SHA1 AES-128", "test/pkcs8-pbkdf2-sha1-rsa.pem", "test/sample_key"},
+		{"PBKDF2 HMAC-SHA256 3DES", "test/pkcs8-pbkdf2-des3-ec384.pem", "test/ec384-private.pem"},
+		{"scrypt AES-256", "test/pkcs8-scrypt-ed25519.pem", "test/ed25519-private.pem"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := ioutil.ReadFile(tt.encrypted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plain, err := ioutil.ReadFile(tt.plain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := jwt.ParsePrivateKeyFromPEM(plain)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = jwt.ParsePrivateKeyFromPEM(encrypted); !errors.Is(err, jwt.ErrKeyEncrypted) {
+				t.Errorf("Expected ErrKeyEncrypted, got %v", err)
+			}
+			got, err := jwt.ParsePrivateKeyFromPEMWithPassword(encrypted, "password")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.(interface{ Equal(crypto.PrivateKey) bool }).Equal(want) {
+				t.Error("Decrypted key does not match")
+			}
+			if _, err = jwt.ParsePrivateKeyFromPEMWithPassword(encrypted, "wrong"); err != x509.IncorrectPasswordError {
+				t.Errorf("Expected x509.IncorrectPasswordError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecryptPKCS8PrivateKey_unsupported(t *testing.T) {
+	encrypted, err := ioutil.ReadFile("test/pkcs8-pbkdf2-ec256.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(encrypted)
+	// Replace the PBES2 object identifier with that of PBES1
+	// pbeWithMD5AndDES-CBC (1.2.840.113549.1.5.3).
+	der := append([]byte(nil), block.Bytes...)
+	for i := 0; i+11 <= len(der); i++ {
+		if der[i] == 0x06 && der[i+1] == 0x09 && der[i+10] == 0x0d && der[i+9] == 0x05 {
+			der[i+10] = 0x03
+			break
+		}
+	}
+	if _, err = jwt.DecryptPKCS8PrivateKey(der, "password"); !errors.Is(err, jwt.ErrUnsupportedKeyEncryption) {
+		t.Errorf("Expected ErrUnsupportedKeyEncryption, got %v", err)
+	}
+}
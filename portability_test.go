@@ -0,0 +1,44 @@
+package jwt_test
+
+import (
+	"go/build"
+	"strings"
+	"testing"
+)
+
+// TestPortableImports keeps the sign and verify path, and the parsing of JWK
+// Sets with the jwt_nonet build tag, free of packages which are unavailable or
+// heavy in WebAssembly and TinyGo builds.
+func TestPortableImports(t *testing.T) {
+	const module = "github.com/chanced/go-jwt/v4"
+	forbidden := []string{"net/http", "os", "os/exec", "os/signal", "plugin", "unsafe"}
+
+	ctx := build.Default
+	ctx.BuildTags = append(ctx.BuildTags, "jwt_nonet")
+	ctx.GOOS, ctx.GOARCH = "js", "wasm"
+
+	seen := map[string]bool{}
+	var check func(dir, path string)
+	check = func(dir, path string) {
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+		pkg, err := ctx.ImportDir(dir, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		for _, imp := range pkg.Imports {
+			for _, f := range forbidden {
+				if imp == f {
+					t.Errorf("%s imports %s", path, imp)
+				}
+			}
+			if strings.HasPrefix(imp, module+"/") {
+				check("."+strings.TrimPrefix(imp, module), imp)
+			}
+		}
+	}
+	check(".", module)
+	check("./jwk", module+"/jwk")
+}
@@ -0,0 +1,139 @@
+package pqc
+
+import (
+	"crypto/ed25519"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// CompositeKey is the key of a Composite method: a pair of keys for its
+// first and second methods, private for signing and public for verifying.
+type CompositeKey struct {
+	First  interface{}
+	Second interface{}
+}
+
+// Composite is a signing method combining two methods, typically a
+// post-quantum method and a classical one, such as ML-DSA-65 with ES256. Its
+// signature is the signature of First followed by that of Second, and it is
+// valid only if both are. The key is a CompositeKey.
+//
+// The signatures of First must be of a fixed size, as those of ML-DSA are, so
+// that the two can be told apart.
+type Composite struct {
+	Name   string
+	First  jwt.SigningMethod
+	Second jwt.SigningMethod
+}
+
+// RegisterComposite creates a Composite of first and second and registers it
+// as alg with jwt.RegisterSigningMethod.
+func RegisterComposite(alg string, first, second jwt.SigningMethod) *Composite {
+	m := &Composite{Name: alg, First: first, Second: second}
+	jwt.RegisterSigningMethod(alg, func() jwt.SigningMethod { return m })
+	return m
+}
+
+func (m *Composite) Alg() string {
+	return m.Name
+}
+
+// SignatureSize returns the largest size of a composite signature in bytes.
+// It implements jwt.SignatureSizer.
+func (m *Composite) SignatureSize() int {
+	return signatureSize(m.First) + signatureSize(m.Second)
+}
+
+// SigningHeader returns the header parameters required by First and Second.
+// It implements jwt.HeaderSigningMethod.
+func (m *Composite) SigningHeader() map[string]interface{} {
+	var header map[string]interface{}
+	for _, method := range []jwt.SigningMethod{m.First, m.Second} {
+		if hm, ok := method.(jwt.HeaderSigningMethod); ok {
+			for k, v := range hm.SigningHeader() {
+				if header == nil {
+					header = map[string]interface{}{}
+				}
+				header[k] = v
+			}
+		}
+	}
+	return header
+}
+
+// Sign signs signingString with both keys of key, a CompositeKey.
+func (m *Composite) Sign(signingString string, key interface{}) (string, error) {
+	k, ok := compositeKey(key)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	first, err := signRaw(m.First, signingString, k.First)
+	if err != nil {
+		return "", err
+	}
+	if len(first) != signatureSize(m.First) {
+		return "", jwt.ErrInvalidKey
+	}
+	second, err := signRaw(m.Second, signingString, k.Second)
+	if err != nil {
+		return "", err
+	}
+	return jwt.EncodeSegment(append(first, second...)), nil
+}
+
+// Verify verifies both signatures of signature over signingString with the
+// keys of key, a CompositeKey.
+func (m *Composite) Verify(signingString, signature string, key interface{}) error {
+	k, ok := compositeKey(key)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+	n := signatureSize(m.First)
+	if len(sig) <= n {
+		return &jwt.SignatureVerificationError{Algorithm: m.Name}
+	}
+	if err = m.First.Verify(signingString, jwt.EncodeSegment(sig[:n]), k.First); err != nil {
+		return err
+	}
+	return m.Second.Verify(signingString, jwt.EncodeSegment(sig[n:]), k.Second)
+}
+
+func compositeKey(key interface{}) (CompositeKey, bool) {
+	switch k := key.(type) {
+	case CompositeKey:
+		return k, true
+	case *CompositeKey:
+		return *k, k != nil
+	}
+	return CompositeKey{}, false
+}
+
+func signRaw(method jwt.SigningMethod, signingString string, key interface{}) ([]byte, error) {
+	sig, err := method.Sign(signingString, key)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.DecodeSegment(sig)
+}
+
+// signatureSize returns the size of the signatures of method in bytes, or
+// the largest size for methods whose signatures vary in size with the key.
+func signatureSize(method jwt.SigningMethod) int {
+	switch m := method.(type) {
+	case jwt.SignatureSizer:
+		return m.SignatureSize()
+	case *jwt.SigningMethodECDSA:
+		return 2 * m.KeySize
+	case *jwt.SigningMethodEd25519:
+		return ed25519.SignatureSize
+	case *jwt.SigningMethodHMAC:
+		return m.Hash.Size()
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		return 8192 / 8
+	}
+	return 0
+}
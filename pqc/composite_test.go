@@ -0,0 +1,57 @@
+package pqc_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/pqc"
+)
+
+func TestComposite(t *testing.T) {
+	pqPub, pqPriv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pq := &pqc.SigningMethod{Name: "TEST-ML-DSA-44", Scheme: paddedScheme{size: 2420}}
+	method := pqc.RegisterComposite("TEST-ML-DSA-44-ES256", pq, jwt.SigningMethodES256)
+	defer jwt.UnregisterSigningMethod("TEST-ML-DSA-44-ES256")
+
+	if size := method.SignatureSize(); size != 2420+64 {
+		t.Errorf("SignatureSize() = %d", size)
+	}
+	signed, err := jwt.NewWithClaims(method, jwt.MapClaims{"sub": "a"}).SignedString(pqc.CompositeKey{First: pqPriv, Second: ecKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyFunc := func(key pqc.CompositeKey) jwt.Keyfunc {
+		return func(*jwt.Token) (interface{}, error) { return key, nil }
+	}
+	if _, err = jwt.Parse(signed, keyFunc(pqc.CompositeKey{First: pqPub, Second: &ecKey.PublicKey})); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Both signatures must verify.
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	otherEC, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	for _, key := range []pqc.CompositeKey{
+		{First: otherPub, Second: &ecKey.PublicKey},
+		{First: pqPub, Second: &otherEC.PublicKey},
+	} {
+		if _, err = jwt.Parse(signed, keyFunc(key)); !errors.Is(err, jwt.ErrSignatureInvalid) {
+			t.Errorf("Expected ErrSignatureInvalid, got %v", err)
+		}
+	}
+	if _, err = method.Sign("payload", pqPriv); !errors.Is(err, jwt.ErrInvalidKeyType) {
+		t.Errorf("Expected ErrInvalidKeyType, got %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+// Package pqc adapts experimental post-quantum and composite signature
+// algorithms to jwt.SigningMethod, so that early adopters can issue and
+// verify tokens signed with algorithms such as ML-DSA (FIPS 204) before
+// their JOSE registrations are final.
+//
+// The package implements no post-quantum cryptography itself. A Scheme wraps
+// an implementation, such as that of a third-party library, and Register
+// makes it available under an "alg" name:
+//
+//	method := pqc.Register(pqc.MLDSA65, mldsa65Scheme{})
+//	token := jwt.NewWithClaims(method, claims)
+//
+// Composite pairs a post-quantum method with a classical one, so that a
+// token stays secure as long as either algorithm is unbroken.
+//
+// Post-quantum signatures and public keys are several kilobytes long.
+// Methods report their signature size, which Parser.ParseReader accounts for,
+// but tokens should reference keys by "kid" rather than embedding them in a
+// "jwk" or "x5c" header, and may outgrow the header size limits of proxies
+// when sent in an Authorization header.
+package pqc
@@ -0,0 +1,90 @@
+package pqc
+
+import (
+	"github.com/chanced/go-jwt/v4"
+)
+
+// Algorithm names of the ML-DSA registrations proposed for JOSE by
+// draft-ietf-cose-dilithium.
+const (
+	MLDSA44 = "ML-DSA-44"
+	MLDSA65 = "ML-DSA-65"
+	MLDSA87 = "ML-DSA-87"
+)
+
+// Scheme is a signature scheme, such as ML-DSA-65, implemented outside this
+// module. Keys are of whatever types the implementation defines.
+type Scheme interface {
+	// Sign signs message with the private key.
+	Sign(key interface{}, message []byte) ([]byte, error)
+
+	// Verify reports whether signature is a valid signature of message by
+	// the public key. It returns jwt.ErrInvalidKeyType for keys of the
+	// wrong type.
+	Verify(key interface{}, message, signature []byte) (bool, error)
+
+	// SignatureSize returns the size of a signature in bytes.
+	SignatureSize() int
+}
+
+// SigningMethod implements jwt.SigningMethod over a Scheme.
+type SigningMethod struct {
+	Name   string
+	Scheme Scheme
+
+	// Header holds parameters added to the header of tokens created with
+	// jwt.New or jwt.NewWithClaims, besides "alg". Optional.
+	Header map[string]interface{}
+}
+
+// Register creates a SigningMethod for scheme and registers it as alg with
+// jwt.RegisterSigningMethod.
+func Register(alg string, scheme Scheme) *SigningMethod {
+	m := &SigningMethod{Name: alg, Scheme: scheme}
+	jwt.RegisterSigningMethod(alg, func() jwt.SigningMethod { return m })
+	return m
+}
+
+func (m *SigningMethod) Alg() string {
+	return m.Name
+}
+
+// SignatureSize returns the size of a signature of the scheme in bytes. It
+// implements jwt.SignatureSizer.
+func (m *SigningMethod) SignatureSize() int {
+	return m.Scheme.SignatureSize()
+}
+
+// SigningHeader returns m.Header. It implements jwt.HeaderSigningMethod.
+func (m *SigningMethod) SigningHeader() map[string]interface{} {
+	return m.Header
+}
+
+// Sign signs signingString with key, a private key of the scheme.
+func (m *SigningMethod) Sign(signingString string, key interface{}) (string, error) {
+	sig, err := m.Scheme.Sign(key, []byte(signingString))
+	if err != nil {
+		return "", err
+	}
+	return jwt.EncodeSegment(sig), nil
+}
+
+// Verify verifies signature over signingString with key, a public key of the
+// scheme.
+func (m *SigningMethod) Verify(signingString, signature string, key interface{}) error {
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+	if len(sig) != m.Scheme.SignatureSize() {
+		return &jwt.SignatureVerificationError{Algorithm: m.Name}
+	}
+	ok, err := m.Scheme.Verify(key, []byte(signingString), sig)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &jwt.SignatureVerificationError{Algorithm: m.Name}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package pqc_test
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/pqc"
+)
+
+// paddedScheme stands in for a post-quantum scheme: Ed25519 signatures padded
+// with zeros to size bytes.
+type paddedScheme struct {
+	size int
+}
+
+func (s paddedScheme) Sign(key interface{}, message []byte) ([]byte, error) {
+	k, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, jwt.ErrInvalidKeyType
+	}
+	sig := make([]byte, s.size)
+	copy(sig, ed25519.Sign(k, message))
+	return sig, nil
+}
+
+func (s paddedScheme) Verify(key interface{}, message, signature []byte) (bool, error) {
+	k, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return false, jwt.ErrInvalidKeyType
+	}
+	for _, b := range signature[ed25519.SignatureSize:] {
+		if b != 0 {
+			return false, nil
+		}
+	}
+	return ed25519.Verify(k, message, signature[:ed25519.SignatureSize]), nil
+}
+
+func (s paddedScheme) SignatureSize() int {
+	return s.size
+}
+
+func TestRegister(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := pqc.Register("TEST-ML-DSA-65", paddedScheme{size: 3309})
+	defer jwt.UnregisterSigningMethod("TEST-ML-DSA-65")
+	method.Header = map[string]interface{}{"kid": "pq-1"}
+
+	token := jwt.NewWithClaims(method, jwt.MapClaims{"sub": "a"})
+	if token.Header["kid"] != "pq-1" {
+		t.Errorf("Header = %v, want the method's parameters", token.Header)
+	}
+	signed, err := token.SignedString(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return pub, nil })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed.Method != method {
+		t.Errorf("Parsed with %v, want the registered method", parsed.Method)
+	}
+
+	tampered := signed[:len(signed)-2] + "AB"
+	if _, err = jwt.Parse(tampered, func(*jwt.Token) (interface{}, error) { return pub, nil }); !errors.Is(err, jwt.ErrSignatureInvalid) {
+		t.Errorf("Expected ErrSignatureInvalid, got %v", err)
+	}
+	if _, err = jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return []byte("secret"), nil }); !errors.Is(err, jwt.ErrInvalidKeyType) {
+		t.Errorf("Expected ErrInvalidKeyType, got %v", err)
+	}
+}
+
+func TestRegister_largeSignatures(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Signatures larger than DefaultMaxTokenSize on their own.
+	method := pqc.Register("TEST-LARGE", paddedScheme{size: jwt.DefaultMaxTokenSize})
+	defer jwt.UnregisterSigningMethod("TEST-LARGE")
+
+	signed, err := jwt.NewWithClaims(method, jwt.MapClaims{"sub": "a"}).SignedString(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFunc := func(*jwt.Token) (interface{}, error) { return pub, nil }
+	if _, err = new(jwt.Parser).ParseReader(strings.NewReader(signed), jwt.MapClaims{}, keyFunc); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	scoped := jwt.NewParser(jwt.WithSigningMethods(jwt.NewSigningMethodRegistry(jwt.SigningMethodHS256)))
+	if _, err = scoped.ParseReader(strings.NewReader(signed), jwt.MapClaims{}, keyFunc); !errors.Is(err, jwt.ErrTokenTooLarge) {
+		t.Errorf("Expected ErrTokenTooLarge from a parser without the method, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package qr
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidBase45 is returned when decoding malformed Base45.
+var ErrInvalidBase45 = errors.New("qr: invalid base45")
+
+const base45Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// EncodeBase45 encodes src as described in
+// https://datatracker.ietf.org/doc/html/rfc9285#section-4
+func EncodeBase45(src []byte) string {
+	var b strings.Builder
+	b.Grow(len(src)/2*3 + len(src)%2*2)
+	for i := 0; i+1 < len(src); i += 2 {
+		n := int(src[i])<<8 | int(src[i+1])
+		b.WriteByte(base45Alphabet[n%45])
+		b.WriteByte(base45Alphabet[n/45%45])
+		b.WriteByte(base45Alphabet[n/(45*45)])
+	}
+	if len(src)%2 == 1 {
+		n := int(src[len(src)-1])
+		b.WriteByte(base45Alphabet[n%45])
+		b.WriteByte(base45Alphabet[n/45])
+	}
+	return b.String()
+}
+
+// DecodeBase45 decodes s as described in
+// https://datatracker.ietf.org/doc/html/rfc9285#section-4
+func DecodeBase45(s string) ([]byte, error) {
+	if len(s)%3 == 1 {
+		return nil, ErrInvalidBase45
+	}
+	dst := make([]byte, 0, len(s)/3*2+len(s)%3/2)
+	for i := 0; i < len(s); i += 3 {
+		n, m := 0, 1
+		end := i + 3
+		if end > len(s) {
+			end = len(s)
+		}
+		for j := i; j < end; j++ {
+			v := strings.IndexByte(base45Alphabet, s[j])
+			if v < 0 {
+				return nil, ErrInvalidBase45
+			}
+			n += v * m
+			m *= 45
+		}
+		if end-i == 3 {
+			if n > 0xffff {
+				return nil, ErrInvalidBase45
+			}
+			dst = append(dst, byte(n>>8), byte(n))
+		} else {
+			if n > 0xff {
+				return nil, ErrInvalidBase45
+			}
+			dst = append(dst, byte(n))
+		}
+	}
+	return dst, nil
+}
@@ -0,0 +1,38 @@
+package qr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4/qr"
+)
+
+// Test vectors from https://datatracker.ietf.org/doc/html/rfc9285#section-4.3
+func TestBase45(t *testing.T) {
+	var tests = []struct {
+		decoded string
+		encoded string
+	}{
+		{"AB", "BB8"},
+		{"Hello!!", "%69 VD92EX0"},
+		{"base-45", "UJCLQE7W581"},
+		{"ietf!", "QED8WEX0"},
+		{"", ""},
+	}
+
+	for _, data := range tests {
+		if got := qr.EncodeBase45([]byte(data.decoded)); got != data.encoded {
+			t.Errorf("[%q] Expected %q. Got: %q", data.decoded, data.encoded, got)
+		}
+		got, err := qr.DecodeBase45(data.encoded)
+		if err != nil || string(got) != data.decoded {
+			t.Errorf("[%q] Expected %q. Got: %q, %v", data.encoded, data.decoded, got, err)
+		}
+	}
+
+	for _, invalid := range []string{"GGW", "A", "ab8", "BB8A"} {
+		if _, err := qr.DecodeBase45(invalid); !errors.Is(err, qr.ErrInvalidBase45) {
+			t.Errorf("[%q] Expected ErrInvalidBase45. Got: %v", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+// Package qr encodes tokens for QR codes, for offline verification
+// scenarios such as tickets and passes.
+//
+// Encode packs the segments of a compact JWS in binary form, compresses them
+// and encodes the result in Base45 (https://datatracker.ietf.org/doc/html/rfc9285),
+// whose alphabet is the alphanumeric mode of QR codes. This yields a
+// considerably smaller code than the compact serialization, which QR codes
+// must carry in the less efficient byte mode. Decode restores the compact
+// serialization, to be verified with a jwt.Parser.
+package qr
@@ -0,0 +1,83 @@
+package qr
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// Prefix starts every token encoded by Encode, identifying the format.
+const Prefix = "JWT1:"
+
+// ErrInvalidEncoding is returned by Decode for input not produced by Encode.
+var ErrInvalidEncoding = errors.New("qr: invalid token encoding")
+
+// maxDecodedSize bounds the decompressed size of an encoded token.
+const maxDecodedSize = 64 << 10
+
+// Encode encodes a token in the compact JWS serialization for a QR code
+// using alphanumeric mode.
+func Encode(compact string) (string, error) {
+	parts := strings.Split(compact, ".")
+	if len(parts) != 3 {
+		return "", jwt.MalformedTokenError("token contains an invalid number of segments")
+	}
+
+	var packed bytes.Buffer
+	var n [binary.MaxVarintLen64]byte
+	for _, part := range parts {
+		seg, err := jwt.DecodeSegment(part)
+		if err != nil {
+			return "", jwt.MalformedTokenError(err.Error())
+		}
+		packed.Write(n[:binary.PutUvarint(n[:], uint64(len(seg)))])
+		packed.Write(seg)
+	}
+
+	var compressed bytes.Buffer
+	w, _ := flate.NewWriter(&compressed, flate.BestCompression)
+	if _, err := w.Write(packed.Bytes()); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return Prefix + EncodeBase45(compressed.Bytes()), nil
+}
+
+// Decode decodes a token encoded by Encode, returning its compact JWS
+// serialization. The token is not verified.
+func Decode(s string) (string, error) {
+	if !strings.HasPrefix(s, Prefix) {
+		return "", ErrInvalidEncoding
+	}
+	compressed, err := DecodeBase45(s[len(Prefix):])
+	if err != nil {
+		return "", err
+	}
+	r := flate.NewReader(bytes.NewReader(compressed))
+	packed, err := ioutil.ReadAll(io.LimitReader(r, maxDecodedSize+1))
+	if err != nil || len(packed) > maxDecodedSize {
+		return "", ErrInvalidEncoding
+	}
+
+	parts := make([]string, 3)
+	for i := range parts {
+		l, n := binary.Uvarint(packed)
+		if n <= 0 || l > uint64(len(packed)-n) {
+			return "", ErrInvalidEncoding
+		}
+		parts[i] = jwt.EncodeSegment(packed[n : n+int(l)])
+		packed = packed[n+int(l):]
+	}
+	if len(packed) != 0 {
+		return "", ErrInvalidEncoding
+	}
+	return strings.Join(parts, "."), nil
+}
@@ -0,0 +1,49 @@
+package qr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/qr"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	key := []byte("secret")
+	compact, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "ticket-1234",
+		"event": "Concert",
+		"seat":  "A12",
+		"iss":   "https://tickets.example.com",
+	}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := qr.Encode(compact)
+	if err != nil {
+		t.Fatalf("Error encoding: %v", err)
+	}
+	if strings.Trim(encoded, "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:") != "" {
+		t.Errorf("Expected only QR alphanumeric characters. Got: %s", encoded)
+	}
+
+	decoded, err := qr.Decode(encoded)
+	if err != nil {
+		t.Fatalf("Error decoding: %v", err)
+	}
+	if decoded != compact {
+		t.Errorf("Expected %s. Got: %s", compact, decoded)
+	}
+	if _, err = jwt.Parse(decoded, func(*jwt.Token) (interface{}, error) { return key, nil }); err != nil {
+		t.Errorf("Error verifying decoded token: %v", err)
+	}
+
+	if _, err = qr.Decode(compact); !errors.Is(err, qr.ErrInvalidEncoding) {
+		t.Errorf("Expected ErrInvalidEncoding. Got: %v", err)
+	}
+	if _, err = qr.Decode(qr.Prefix + qr.EncodeBase45([]byte("garbage"))); !errors.Is(err, qr.ErrInvalidEncoding) {
+		t.Errorf("Expected ErrInvalidEncoding. Got: %v", err)
+	}
+}
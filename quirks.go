@@ -0,0 +1,112 @@
+package jwt
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Quirk bundles the lenient decoding options needed to accept tokens from an
+// issuer which does not follow the specifications. Quirks are enabled per
+// Parser through Parser.Quirks, so that every workaround in use is explicit
+// and can be removed once the issuer is fixed.
+type Quirk struct {
+	Name string // Identifies the quirk in documentation and diagnostics
+
+	// AllowPaddedSegments accepts segments encoded with base64url padding
+	// ("=") which RFC 7515 forbids.
+	AllowPaddedSegments bool
+
+	// AllowStringNumericDates accepts the "exp", "iat" and "nbf" claims
+	// encoded as JSON strings holding a number, rather than as numbers.
+	AllowStringNumericDates bool
+
+	// KeyIDFromX5t sets the "kid" header to the value of the "x5t" header
+	// when "kid" is absent, for issuers which only identify their signing
+	// certificate.
+	KeyIDFromX5t bool
+}
+
+// Interop quirks for known issuers.
+var (
+	// QuirkAzureADV1 covers Azure AD v1.0 endpoints, some of which identify
+	// their signing certificate with "x5t" only.
+	QuirkAzureADV1 = Quirk{Name: "AzureADV1", KeyIDFromX5t: true}
+
+	// QuirkADFS covers AD FS deployments which identify the signing
+	// certificate with "x5t" only and may pad encoded segments.
+	QuirkADFS = Quirk{Name: "ADFS", KeyIDFromX5t: true, AllowPaddedSegments: true}
+
+	// QuirkOldKeycloak covers older Keycloak releases, and extensions of
+	// them, which may encode timestamps as strings.
+	QuirkOldKeycloak = Quirk{Name: "OldKeycloak", AllowStringNumericDates: true}
+)
+
+// quirks returns the union of the options of the parser's quirks.
+func (p *Parser) quirks() Quirk {
+	var q Quirk
+	for _, o := range p.Quirks {
+		q.AllowPaddedSegments = q.AllowPaddedSegments || o.AllowPaddedSegments
+		q.AllowStringNumericDates = q.AllowStringNumericDates || o.AllowStringNumericDates
+		q.KeyIDFromX5t = q.KeyIDFromX5t || o.KeyIDFromX5t
+	}
+	return q
+}
+
+// allowPadding reports whether padded segments are accepted.
+func (p *Parser) allowPadding() bool {
+	return p.quirks().AllowPaddedSegments && !p.StrictDecoding
+}
+
+// decodeSegment decodes a segment, stripping padding if allowed.
+func (p *Parser) decodeSegment(seg string) ([]byte, error) {
+	if p.allowPadding() {
+		seg = strings.TrimRight(seg, "=")
+	}
+	return DecodeSegment(seg)
+}
+
+// applyHeaderQuirks adjusts a decoded header according to the parser's quirks.
+func (p *Parser) applyHeaderQuirks(header map[string]interface{}) {
+	if !p.quirks().KeyIDFromX5t {
+		return
+	}
+	if _, ok := header["kid"]; !ok {
+		if x5t, ok := header["x5t"].(string); ok {
+			header["kid"] = x5t
+		}
+	}
+}
+
+// applyClaimsQuirks rewrites encoded claims according to the parser's quirks
+// before they are decoded.
+func (p *Parser) applyClaimsQuirks(claimBytes []byte) []byte {
+	if !p.quirks().AllowStringNumericDates {
+		return claimBytes
+	}
+	var raw map[string]json.RawMessage
+	if json.Unmarshal(claimBytes, &raw) != nil {
+		// Leave reporting the error to the decoder
+		return claimBytes
+	}
+	changed := false
+	for _, name := range []string{"exp", "iat", "nbf"} {
+		var s string
+		if v, ok := raw[name]; !ok || json.Unmarshal(v, &s) != nil {
+			continue
+		}
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			continue
+		}
+		raw[name] = json.RawMessage(s)
+		changed = true
+	}
+	if !changed {
+		return claimBytes
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return claimBytes
+	}
+	return b
+}
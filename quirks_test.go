@@ -0,0 +1,62 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// signQuirky signs a token assembled from raw header and claims JSON, using
+// enc to encode the segments.
+func signQuirky(t *testing.T, header, claims string, enc *base64.Encoding, key []byte) string {
+	signingString := enc.EncodeToString([]byte(header)) + "." + enc.EncodeToString([]byte(claims))
+	sig, err := jwt.SigningMethodHS256.Sign(signingString, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signingString + "." + sig
+}
+
+func TestParser_Quirks(t *testing.T) {
+	key := []byte("secret")
+	padded := signQuirky(t, `{"alg":"HS256","x5t":"thumb"}`, `{"sub":"a"}`, base64.URLEncoding, key)
+	if !strings.Contains(padded, "=") {
+		t.Fatalf("Expected padded segments: %v", padded)
+	}
+	stringDates := signQuirky(t, `{"alg":"HS256"}`, `{"exp":"4000000000"}`, base64.RawURLEncoding, key)
+
+	var tests = []struct {
+		name   string
+		token  string
+		quirks []jwt.Quirk
+		kid    interface{}
+		err    error
+	}{
+		{"padded without quirk", padded, nil, nil, jwt.ErrMalformedToken},
+		{"padded with ADFS", padded, []jwt.Quirk{jwt.QuirkADFS}, "thumb", nil},
+		{"x5t only with Azure AD v1", signQuirky(t, `{"alg":"HS256","x5t":"thumb"}`, `{}`, base64.RawURLEncoding, key), []jwt.Quirk{jwt.QuirkAzureADV1}, "thumb", nil},
+		{"string dates without quirk", stringDates, nil, nil, jwt.ErrTokenExpired},
+		{"string dates with old Keycloak", stringDates, []jwt.Quirk{jwt.QuirkOldKeycloak}, nil, nil},
+	}
+
+	for _, data := range tests {
+		p := &jwt.Parser{Quirks: data.quirks}
+		token, err := p.ParseWithClaims(data.token, jwt.MapClaims{}, func(*jwt.Token) (interface{}, error) { return key, nil })
+		if data.err != nil {
+			if !errors.Is(err, data.err) {
+				t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%v] Error parsing token: %v", data.name, err)
+			continue
+		}
+		if token.Header["kid"] != data.kid {
+			t.Errorf("[%v] Expected kid %v. Got: %v", data.name, data.kid, token.Header["kid"])
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"io"
+)
+
+// RandReader is the source of entropy used by this library and its
+// subpackages to generate token identifiers, nonces, keys and randomized
+// signatures. It defaults to crypto/rand.Reader. It may be replaced, before any
+// token is signed, with a reader backed by a DRBG mandated by the environment,
+// or with a deterministic reader for reproducible tests, such as the one
+// provided by the jwttest package.
+//
+// Since Go 1.26, crypto/rsa, crypto/ecdsa and crypto/ecdh ignore a reader
+// other than crypto/rand.Reader unless GODEBUG=cryptocustomrand=1 is set, so
+// RSA and ECDSA keys and signatures, and ECDH keys, are drawn from the system
+// source whatever RandReader is. Tests needing them to be reproducible should
+// use testing/cryptotest.SetGlobalRandom, or ECDSA signers with Deterministic
+// set. Identifiers, nonces and Ed25519 keys still come from RandReader.
+var RandReader io.Reader = rand.Reader
+
+// NewID returns a random identifier suitable for the "jti" claim or a nonce:
+// 128 bits read from RandReader, base64url encoded.
+func NewID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(RandReader, b); err != nil {
+		return "", err
+	}
+	return EncodeSegment(b), nil
+}
@@ -0,0 +1,39 @@
+package jwt_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("no entropy") }
+
+func TestNewID(t *testing.T) {
+	a, err := jwt.NewID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := jwt.NewID()
+	if len(a) != 22 || a == b {
+		t.Errorf("NewID() = %q, %q", a, b)
+	}
+
+	defer func(r io.Reader) { jwt.RandReader = r }(jwt.RandReader)
+	jwt.RandReader = failingReader{}
+	if _, err := jwt.NewID(); err == nil {
+		t.Error("expected an error from a failing RandReader")
+	}
+	keyData, _ := ioutil.ReadFile("test/ec256-private.pem")
+	key, err := jwt.ParseECPrivateKeyFromPEM(keyData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jwt.SigningMethodES256.Sign("a.b", key); err == nil {
+		t.Error("expected ES256 signing to use RandReader")
+	}
+}
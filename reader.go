@@ -0,0 +1,48 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+)
+
+// DefaultMaxTokenSize is the length in bytes of the largest token ParseReader
+// reads when Parser.MaxTokenSize is unset, in addition to the encoded size of
+// the signatures of methods implementing SignatureSizer.
+const DefaultMaxTokenSize = 64 << 10
+
+// ParseReader parses, validates, and returns a token in the compact
+// serialization read from r, such as a request body. Surrounding whitespace
+// is ignored. At most MaxTokenSize bytes are read; a longer token is rejected
+// with ErrTokenTooLarge without being read in full.
+func (p *Parser) ParseReader(r io.Reader, claims Claims, keyFunc Keyfunc) (*Token, error) {
+	max := p.MaxTokenSize
+	if max <= 0 {
+		max = DefaultMaxTokenSize + p.maxSignatureSize()
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(r, int64(max)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > max {
+		return nil, ErrTokenTooLarge
+	}
+	return p.ParseWithClaims(string(bytes.TrimSpace(b)), claims, keyFunc)
+}
+
+// maxSignatureSize returns the base64url encoded size of the largest
+// signature of the methods resolved by p which implement SignatureSizer.
+func (p *Parser) maxSignatureSize() int {
+	r := p.SigningMethods
+	if r == nil {
+		r = signingMethods
+	}
+	max := 0
+	for _, alg := range r.Algorithms() {
+		if s, ok := r.Get(alg).(SignatureSizer); ok && s.SignatureSize() > max {
+			max = s.SignatureSize()
+		}
+	}
+	return base64.RawURLEncoding.EncodedLen(max)
+}
@@ -0,0 +1,38 @@
+package jwt_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestParser_ParseReader(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": strings.Repeat("a", 100)}).SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := new(jwt.Parser).ParseReader(strings.NewReader(signed+"\n"), jwt.MapClaims{}, keyFunc)
+	if err != nil || !token.Valid {
+		t.Fatalf("ParseReader: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		parser *jwt.Parser
+		input  string
+	}{
+		{"token too large", &jwt.Parser{MaxTokenSize: len(signed) - 1}, signed},
+		{"default limit", &jwt.Parser{}, strings.Repeat("a", jwt.DefaultMaxTokenSize+1)},
+		{"claims too large", &jwt.Parser{MaxClaimsSize: 100}, signed},
+	}
+	for _, tc := range tests {
+		_, err := tc.parser.ParseReader(strings.NewReader(tc.input), jwt.MapClaims{}, keyFunc)
+		if !errors.Is(err, jwt.ErrTokenTooLarge) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, jwt.ErrTokenTooLarge)
+		}
+	}
+}
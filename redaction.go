@@ -0,0 +1,127 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// RedactedValue replaces claim values which a RedactionPolicy hides.
+const RedactedValue = "[REDACTED]"
+
+// RedactionPolicy decides which claim values may appear in error messages,
+// String output and anything else this package and its subpackages render for
+// logs. If Allow is non-empty only the claims it names are shown. Claims named
+// in Deny are never shown, even if allowed.
+type RedactionPolicy struct {
+	Allow []string
+	Deny  []string
+}
+
+// Redaction is the policy applied wherever claim values are rendered. It
+// defaults to hiding the personal information claims defined by OpenID Connect
+// and may be replaced, typically during initialization, to suit an
+// application's claims.
+var Redaction = &RedactionPolicy{
+	Deny: []string{
+		"name", "given_name", "family_name", "middle_name", "nickname",
+		"preferred_username", "email", "phone_number", "address", "birthdate",
+	},
+}
+
+// Shows reports whether the value of the named claim may be rendered. A nil
+// policy shows everything.
+func (p *RedactionPolicy) Shows(claim string) bool {
+	if p == nil {
+		return true
+	}
+	for _, c := range p.Deny {
+		if c == claim {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, c := range p.Allow {
+		if c == claim {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns value if the policy shows the named claim, otherwise
+// RedactedValue.
+func (p *RedactionPolicy) Redact(claim string, value interface{}) interface{} {
+	if p.Shows(claim) {
+		return value
+	}
+	return RedactedValue
+}
+
+// RedactClaims returns a copy of claims with the values the policy hides
+// replaced by RedactedValue.
+func (p *RedactionPolicy) RedactClaims(claims MapClaims) MapClaims {
+	redacted := make(MapClaims, len(claims))
+	for k, v := range claims {
+		redacted[k] = p.Redact(k, v)
+	}
+	return redacted
+}
+
+// redact renders the value of claim for an error message according to the
+// package-level Redaction policy.
+func redact(claim string, value interface{}) string {
+	return fmt.Sprintf("%v", Redaction.Redact(claim, value))
+}
+
+// String renders the claims as JSON with hidden values redacted according to
+// the package-level Redaction policy, so that claims can be logged safely.
+func (m MapClaims) String() string {
+	b, err := json.Marshal(Redaction.RedactClaims(m))
+	if err != nil {
+		// Fall back to listing the claim names only.
+		names := make([]string, 0, len(m))
+		for k := range m {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		return fmt.Sprintf("%v", names)
+	}
+	return string(b)
+}
+
+// String renders the header and claims of the token as JSON for logs, with
+// claim values redacted according to the package-level Redaction policy. The
+// signature is masked and Raw is left out, so that a token formatted with %v
+// cannot be replayed by whoever reads the log.
+func (t Token) String() string {
+	claims, ok := t.Claims.(MapClaims)
+	if !ok && t.Claims != nil {
+		// Other claims are rendered by way of their JSON encoding.
+		if b, err := json.Marshal(t.Claims); err == nil {
+			json.Unmarshal(b, &claims)
+		}
+	}
+	var signature string
+	if t.Signature != "" {
+		signature = RedactedValue
+	}
+	b, err := json.Marshal(struct {
+		Header    map[string]interface{} `json:"header"`
+		Claims    MapClaims              `json:"claims"`
+		Signature string                 `json:"signature,omitempty"`
+		Valid     bool                   `json:"valid"`
+	}{t.Header, Redaction.RedactClaims(claims), signature, t.Valid})
+	if err != nil {
+		return RedactedValue
+	}
+	return string(b)
+}
+
+// GoString renders the token as String does, so that %#v does not reveal it
+// either.
+func (t Token) GoString() string {
+	return t.String()
+}
@@ -0,0 +1,76 @@
+package jwt_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestRedactionPolicy_Shows(t *testing.T) {
+	var tests = []struct {
+		name   string
+		policy *jwt.RedactionPolicy
+		claim  string
+		shows  bool
+	}{
+		{"nil policy", nil, "email", true},
+		{"denied", &jwt.RedactionPolicy{Deny: []string{"email"}}, "email", false},
+		{"not denied", &jwt.RedactionPolicy{Deny: []string{"email"}}, "sub", true},
+		{"allowed", &jwt.RedactionPolicy{Allow: []string{"iss"}}, "iss", true},
+		{"not allowed", &jwt.RedactionPolicy{Allow: []string{"iss"}}, "sub", false},
+		{"allowed and denied", &jwt.RedactionPolicy{Allow: []string{"sub"}, Deny: []string{"sub"}}, "sub", false},
+	}
+
+	for _, data := range tests {
+		if shows := data.policy.Shows(data.claim); shows != data.shows {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.shows, shows)
+		}
+	}
+}
+
+func TestMapClaims_String(t *testing.T) {
+	s := jwt.MapClaims{"sub": "1234", "email": "jane@example.com"}.String()
+	if strings.Contains(s, "jane@example.com") || !strings.Contains(s, jwt.RedactedValue) || !strings.Contains(s, "1234") {
+		t.Errorf("Unexpected redacted claims: %v", s)
+	}
+}
+
+func TestToken_String(t *testing.T) {
+	claims := &jwt.RegisteredClaims{Subject: "1234"}
+	raw, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.ParseWithClaims(raw, &jwt.RegisteredClaims{}, func(*jwt.Token) (interface{}, error) {
+		return []byte("secret"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{token.String(), fmt.Sprintf("%v", token), fmt.Sprintf("%+v", *token), fmt.Sprintf("%#v", token)} {
+		if strings.Contains(s, token.Signature) || strings.Contains(s, raw) {
+			t.Errorf("Token rendered with its signature: %v", s)
+		}
+		if !strings.Contains(s, `"sub":"1234"`) || !strings.Contains(s, `"alg":"HS256"`) || !strings.Contains(s, `"signature":"`+jwt.RedactedValue+`"`) {
+			t.Errorf("Unexpected rendering: %v", s)
+		}
+	}
+
+	withEmail := jwt.Token{Claims: jwt.MapClaims{"email": "jane@example.com"}}
+	if s := withEmail.String(); strings.Contains(s, "jane@example.com") || strings.Contains(s, "signature") {
+		t.Errorf("Unexpected rendering: %v", s)
+	}
+}
+
+func TestRedaction_errors(t *testing.T) {
+	defer func(p *jwt.RedactionPolicy) { jwt.Redaction = p }(jwt.Redaction)
+	jwt.Redaction = &jwt.RedactionPolicy{Deny: []string{"aud"}}
+
+	_, err := jwt.MapClaims{"aud": []interface{}{"ok", map[string]interface{}{"secret": "value"}}}.Audience()
+	if err == nil || strings.Contains(err.Error(), "value") || !strings.Contains(err.Error(), jwt.RedactedValue) {
+		t.Errorf("Expected aud value to be redacted from error. Got: %v", err)
+	}
+}
@@ -0,0 +1,12 @@
+// Package refresh implements refresh token rotation with reuse detection, as
+// recommended by
+// https://datatracker.ietf.org/doc/html/draft-ietf-oauth-security-topics#section-4.14.2.
+//
+// Every refresh token belongs to a family, named by the "family" claim and
+// started by the first token issued to a grant. When a refresh token is
+// rotated, its replacement joins the family and records the rotated token in
+// the "parent_jti" claim. A Rotator remembers rotated tokens in a
+// kvstore.Store: a rotated token presented again indicates it was stolen, and
+// the whole family is revoked, logging out both the attacker and the
+// legitimate client.
+package refresh
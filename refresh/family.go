@@ -0,0 +1,168 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/kvstore"
+)
+
+// DefaultTTL is how long a Rotator remembers revoked families, and rotated
+// tokens without an expiration time, when Rotator.TTL is unset.
+const DefaultTTL = 30 * 24 * time.Hour
+
+var (
+	ErrTokenReused   = errors.New("refresh: the refresh token has already been rotated")
+	ErrFamilyRevoked = errors.New("refresh: the refresh token family has been revoked")
+	ErrMissingFamily = errors.New("refresh: the refresh token has no family")
+)
+
+// Claims are the claims of a refresh token.
+type Claims struct {
+	jwt.RegisteredClaims
+
+	// the `family` claim, naming the family of the token
+	Family string `json:"family,omitempty"`
+
+	// the `parent_jti` claim, the "jti" of the token this one replaced
+	ParentID string `json:"parent_jti,omitempty"`
+}
+
+// NewFamily starts a new family with claims as its first token. A random
+// "jti" is generated if it is unset, and the family is named after it.
+func NewFamily(claims *Claims) error {
+	if claims.ID == "" {
+		id, err := jwt.NewID()
+		if err != nil {
+			return err
+		}
+		claims.ID = id
+	}
+	claims.Family, claims.ParentID = claims.ID, ""
+	return nil
+}
+
+// Rotator tracks the rotation of refresh tokens.
+//
+// If the Store implements kvstore.Adder, a token is marked as rotated
+// atomically, so only one of two concurrent rotations of the same token
+// succeeds. Other Stores are read before they are written, so both may
+// succeed; the reuse is then detected at the next rotation of either
+// replacement.
+type Rotator struct {
+	Store kvstore.Store
+	TTL   time.Duration // Optional. Should exceed the lifetime of a family. Defaults to DefaultTTL
+
+	// OnReuse, if set, is called when a rotated token is presented again,
+	// after its family has been revoked, for example to alert the user.
+	OnReuse func(ctx context.Context, claims *Claims)
+}
+
+// Check reports whether the token may still be used: its family must not be
+// revoked, and the token must not have been rotated. Unlike Rotate it does not
+// revoke the family of a reused token.
+func (r *Rotator) Check(ctx context.Context, claims *Claims) error {
+	if claims.Family == "" {
+		return ErrMissingFamily
+	}
+	if revoked, err := r.exists(ctx, familyKey(claims.Family)); err != nil || revoked {
+		if err == nil {
+			err = ErrFamilyRevoked
+		}
+		return err
+	}
+	if used, err := r.exists(ctx, usedKey(claims)); err != nil || used {
+		if err == nil {
+			err = ErrTokenReused
+		}
+		return err
+	}
+	return nil
+}
+
+// Rotate records that current, a validated refresh token, has been exchanged
+// for next, and links next into the family of current, generating its "jti"
+// if unset. If current was rotated before, its family is revoked and
+// ErrTokenReused is returned.
+func (r *Rotator) Rotate(ctx context.Context, current, next *Claims) error {
+	err := r.Check(ctx, current)
+	if err == nil {
+		err = r.markRotated(ctx, current)
+	}
+	if err != nil {
+		if errors.Is(err, ErrTokenReused) {
+			if rerr := r.RevokeFamily(ctx, current.Family); rerr != nil {
+				return rerr
+			}
+			if r.OnReuse != nil {
+				r.OnReuse(ctx, current)
+			}
+		}
+		return err
+	}
+
+	if next.ID == "" {
+		id, err := jwt.NewID()
+		if err != nil {
+			return err
+		}
+		next.ID = id
+	}
+	next.Family, next.ParentID = current.Family, current.ID
+	return nil
+}
+
+// markRotated records that the token has been rotated, until it expires. If
+// the Store implements kvstore.Adder and the token was already recorded,
+// ErrTokenReused is returned.
+func (r *Rotator) markRotated(ctx context.Context, claims *Claims) error {
+	ttl := r.ttl()
+	if claims.ExpiresAt != nil {
+		ttl = claims.ExpiresAt.Sub(jwt.TimeFunc())
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	key, value := usedKey(claims), []byte(claims.Family)
+	if a, ok := r.Store.(kvstore.Adder); ok {
+		added, err := a.Add(ctx, key, value, ttl)
+		if err == nil && !added {
+			err = ErrTokenReused
+		}
+		return err
+	}
+	return r.Store.Set(ctx, key, value, ttl)
+}
+
+// RevokeFamily revokes every token of family.
+func (r *Rotator) RevokeFamily(ctx context.Context, family string) error {
+	return r.Store.Set(ctx, familyKey(family), []byte{1}, r.ttl())
+}
+
+func (r *Rotator) ttl() time.Duration {
+	if r.TTL > 0 {
+		return r.TTL
+	}
+	return DefaultTTL
+}
+
+func (r *Rotator) exists(ctx context.Context, key string) (bool, error) {
+	_, err := r.Store.Get(ctx, key)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, kvstore.ErrNotFound):
+		return false, nil
+	}
+	return false, err
+}
+
+func familyKey(family string) string {
+	return "refresh:revoked:" + family
+}
+
+func usedKey(claims *Claims) string {
+	return "refresh:used:" + claims.Family + ":" + claims.ID
+}
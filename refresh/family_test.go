@@ -0,0 +1,89 @@
+package refresh_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4/kvstore"
+	"github.com/chanced/go-jwt/v4/refresh"
+)
+
+func TestRotator(t *testing.T) {
+	ctx := context.Background()
+	var reused []*refresh.Claims
+	r := &refresh.Rotator{
+		Store:   new(kvstore.Memory),
+		OnReuse: func(_ context.Context, c *refresh.Claims) { reused = append(reused, c) },
+	}
+
+	first := new(refresh.Claims)
+	if err := refresh.NewFamily(first); err != nil {
+		t.Fatal(err)
+	}
+	if first.ID == "" || first.Family != first.ID {
+		t.Fatalf("Unexpected first token of the family: %+v", first)
+	}
+
+	second := new(refresh.Claims)
+	if err := r.Rotate(ctx, first, second); err != nil {
+		t.Fatalf("Unexpected error rotating: %v", err)
+	}
+	if second.Family != first.Family || second.ParentID != first.ID || second.ID == "" || second.ID == first.ID {
+		t.Errorf("Unexpected replacement token: %+v", second)
+	}
+	if err := r.Check(ctx, second); err != nil {
+		t.Errorf("Unexpected error checking the replacement: %v", err)
+	}
+
+	// The first token is presented again: the family is revoked.
+	if err := r.Rotate(ctx, first, new(refresh.Claims)); !errors.Is(err, refresh.ErrTokenReused) {
+		t.Errorf("Expected ErrTokenReused. Got: %v", err)
+	}
+	if len(reused) != 1 || reused[0] != first {
+		t.Errorf("Expected OnReuse to be called with the reused token. Got: %v", reused)
+	}
+	if err := r.Rotate(ctx, second, new(refresh.Claims)); !errors.Is(err, refresh.ErrFamilyRevoked) {
+		t.Errorf("Expected ErrFamilyRevoked. Got: %v", err)
+	}
+
+	if err := r.Check(ctx, new(refresh.Claims)); !errors.Is(err, refresh.ErrMissingFamily) {
+		t.Errorf("Expected ErrMissingFamily. Got: %v", err)
+	}
+}
+
+func TestRotator_concurrentRotation(t *testing.T) {
+	ctx := context.Background()
+	r := &refresh.Rotator{Store: new(kvstore.Memory)}
+	first := new(refresh.Claims)
+	if err := refresh.NewFamily(first); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 8
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- r.Rotate(ctx, first, new(refresh.Claims))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	rotated := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			rotated++
+		case !errors.Is(err, refresh.ErrTokenReused) && !errors.Is(err, refresh.ErrFamilyRevoked):
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if rotated != 1 {
+		t.Errorf("Expected exactly one rotation to succeed. Got: %v", rotated)
+	}
+}
@@ -0,0 +1,151 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chanced/go-jwt/v4/internal/lru"
+)
+
+// DefaultReplayCacheSize is the number of identifiers a MemoryReplayDetector
+// holds when MaxEntries is not set.
+const DefaultReplayCacheSize = 100000
+
+// ReplayDetector records the "jti" values of one-time-use tokens, such as
+// DPoP proofs and client assertions, so that a token presented twice can be
+// rejected. Entries only need to be kept until the tokens they record would
+// be rejected anyway, having expired or fallen out of the accepted window.
+type ReplayDetector interface {
+	// Seen records id until the given time and reports whether it had
+	// already been recorded. Implementations shared between processes must
+	// record and report atomically.
+	Seen(ctx context.Context, id string, until time.Time) (bool, error)
+}
+
+// MemoryReplayDetector is a ReplayDetector held in memory, suitable for a
+// single process and for tests. Expired entries are removed as they are looked
+// up, and periodically as new ones are recorded. The zero value is ready to
+// use.
+//
+// Once MaxEntries is reached, the least recently used entries are evicted. An
+// evicted identifier may be replayed, so the bound should leave room for
+// every token accepted within the replay window.
+type MemoryReplayDetector struct {
+	MaxEntries int   // Optional. Defaults to DefaultReplayCacheSize
+	Hooks      Hooks // Optional. OnCacheEvent is reported to as "replay"
+
+	// SweepInterval is how often Start sweeps out expired entries. Defaults
+	// to DefaultSweepInterval.
+	SweepInterval time.Duration
+
+	mu      sync.Mutex
+	entries lru.Expiring
+	stats   CacheStats
+	sweeps  sweeper
+}
+
+// Seen implements ReplayDetector.
+func (d *MemoryReplayDetector) Seen(_ context.Context, id string, until time.Time) (bool, error) {
+	d.mu.Lock()
+	now := TimeFunc()
+	if _, ok := d.entries.Until(id, now); ok {
+		d.stats.Count(CacheHit)
+		d.mu.Unlock()
+		d.Hooks.ReportCacheEvent("replay", CacheHit)
+		return true, nil
+	}
+	d.stats.Count(CacheMiss)
+	d.entries.MaxEntries = d.MaxEntries
+	if d.entries.MaxEntries <= 0 {
+		d.entries.MaxEntries = DefaultReplayCacheSize
+	}
+	d.entries.OnEvict = d.evicted
+	evictions := d.stats.Evictions
+	d.entries.AddUntil(id, until, now)
+	evictions = d.stats.Evictions - evictions
+	d.mu.Unlock()
+	d.Hooks.ReportCacheEvent("replay", CacheMiss)
+	d.Hooks.reportEvictions("replay", evictions)
+	return false, nil
+}
+
+// Start removes expired identifiers every SweepInterval in the background,
+// until Close is called or ctx is done. MemoryReplayDetector implements
+// Component.
+func (d *MemoryReplayDetector) Start(ctx context.Context) error {
+	return d.sweeps.start(ctx, d.SweepInterval, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.entries.Sweep(TimeFunc())
+	})
+}
+
+// Close stops the sweeps begun by Start.
+func (d *MemoryReplayDetector) Close() error {
+	return d.sweeps.close()
+}
+
+func (d *MemoryReplayDetector) evicted(string, interface{}) {
+	d.stats.Count(CacheEviction)
+}
+
+// Stats returns a snapshot of the counters of d.
+func (d *MemoryReplayDetector) Stats() CacheStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := d.stats
+	s.Entries = d.entries.Len()
+	return s
+}
+
+// WithReplayDetection makes the Validator reject tokens whose "jti" claim has
+// been seen by d before, or which lack one. Identifiers are remembered for
+// window, or until the token expires if that is later; tokens without an
+// "exp" claim are rejected if window is zero. See Validator.ReplayDetector.
+func WithReplayDetection(d ReplayDetector, window time.Duration) ValidatorOption {
+	return func(v *Validator) {
+		v.ReplayDetector, v.ReplayWindow = d, window
+	}
+}
+
+// checkReplay records the "jti" claim of token with v.ReplayDetector.
+func (v *Validator) checkReplay(ctx context.Context, token *Token) error {
+	claims, err := claimsMap(token.Claims)
+	if err != nil {
+		return err
+	}
+	jti, ok := claims["jti"].(string)
+	if !ok || jti == "" {
+		if _, present := claims["jti"]; present {
+			return &ValidationError{Err: ErrInvalidClaimType, Claim: "jti"}
+		}
+		return &ValidationError{Err: ErrTokenRequiredClaimMissing, Claim: "jti"}
+	}
+
+	now := TimeFunc()
+	var until time.Time
+	if v.ReplayWindow > 0 {
+		until = now.Add(v.ReplayWindow)
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil {
+		return err
+	}
+	if exp != nil && exp.Add(v.Policy.Leeway).After(until) {
+		until = exp.Add(v.Policy.Leeway)
+	}
+	if until.IsZero() {
+		return &ValidationError{Err: ErrTokenRequiredClaimMissing, Claim: "exp"}
+	}
+
+	seen, err := v.ReplayDetector.Seen(ctx, jti, until)
+	if err != nil {
+		return fmt.Errorf("jwt: checking for replay: %w", err)
+	}
+	if seen {
+		return &ValidationError{Err: ErrTokenReplayed, Claim: "jti"}
+	}
+	return nil
+}
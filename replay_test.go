@@ -0,0 +1,101 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestMemoryReplayDetector(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	ctx := context.Background()
+	events := map[jwt.CacheEvent]int{}
+	d := &jwt.MemoryReplayDetector{MaxEntries: 2}
+	// The hook may read the stats of the store reporting to it.
+	d.Hooks.OnCacheEvent = func(_ string, e jwt.CacheEvent) {
+		events[e]++
+		d.Stats()
+	}
+	if seen, _ := d.Seen(ctx, "a", now.Add(time.Minute)); seen {
+		t.Error("Expected a not to have been seen")
+	}
+	if seen, _ := d.Seen(ctx, "a", now.Add(time.Minute)); !seen {
+		t.Error("Expected a to have been seen")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if seen, _ := d.Seen(ctx, "a", now.Add(time.Minute)); seen {
+		t.Error("Expected the entry for a to have expired")
+	}
+
+	d.Seen(ctx, "b", now.Add(time.Minute))
+	d.Seen(ctx, "c", now.Add(time.Minute))
+	if s := d.Stats(); s.Entries != 2 || s.Evictions != 1 {
+		t.Errorf("Unexpected stats: %+v", s)
+	}
+	if events[jwt.CacheHit] != 1 || events[jwt.CacheMiss] != 4 || events[jwt.CacheEviction] != 1 {
+		t.Errorf("Unexpected events: %v", events)
+	}
+}
+
+func TestValidator_ReplayDetector(t *testing.T) {
+	key := []byte("secret")
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+	exp := jwt.NewNumericDate(time.Now().Add(time.Minute))
+	sign := func(claims jwt.Claims) string {
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	p := jwt.NewParser()
+	p.Validator = jwt.NewValidator(jwt.WithReplayDetection(new(jwt.MemoryReplayDetector), 0))
+	once := sign(&jwt.RegisteredClaims{ID: "a", ExpiresAt: exp})
+	if _, err := p.Parse(once, keyFunc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := p.Parse(once, keyFunc); !errors.Is(err, jwt.ErrTokenReplayed) {
+		t.Errorf("Expected ErrTokenReplayed, got %v", err)
+	}
+
+	// A token failing verification must not use up its jti.
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.RegisteredClaims{ID: "b", ExpiresAt: exp}).SignedString([]byte("other"))
+	if _, err := p.Parse(forged, keyFunc); !errors.Is(err, jwt.ErrSignatureInvalid) {
+		t.Fatalf("Expected ErrSignatureInvalid, got %v", err)
+	}
+	if _, err := p.Parse(sign(&jwt.RegisteredClaims{ID: "b", ExpiresAt: exp}), keyFunc); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		claims jwt.Claims
+		want   error
+	}{
+		{"missing jti", &jwt.RegisteredClaims{ExpiresAt: exp}, jwt.ErrTokenRequiredClaimMissing},
+		{"missing exp", &jwt.RegisteredClaims{ID: "c"}, jwt.ErrTokenRequiredClaimMissing},
+		{"invalid jti", jwt.MapClaims{"jti": 1, "exp": float64(exp.Unix())}, jwt.ErrInvalidClaimType},
+	}
+	for _, tc := range tests {
+		if _, err := p.ParseWithClaims(sign(tc.claims), tc.claims, keyFunc); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	v := jwt.NewValidator(jwt.WithReplayDetection(new(jwt.MemoryReplayDetector), time.Minute))
+	token := &jwt.Token{Claims: jwt.MapClaims{"jti": "d"}}
+	if err := v.Validate(token); err != nil {
+		t.Errorf("Unexpected error with a window: %v", err)
+	}
+	if err := v.ValidateContext(context.Background(), token); !errors.Is(err, jwt.ErrTokenReplayed) {
+		t.Errorf("Expected ErrTokenReplayed, got %v", err)
+	}
+}
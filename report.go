@@ -0,0 +1,94 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ValidationReport is the structured form of an error from parsing or
+// validating a token, listing every problem found. It is rendered as a
+// multi-line report by String and encodes to JSON for structured logs.
+type ValidationReport struct {
+	Problems []ValidationProblem `json:"problems"`
+}
+
+// ValidationProblem is one problem of a ValidationReport. The details of a
+// ValidationError are carried over, with claim values hidden according to
+// Redaction.
+type ValidationProblem struct {
+	Message  string        `json:"message"`
+	Claim    string        `json:"claim,omitempty"`
+	Expected interface{}   `json:"expected,omitempty"`
+	Actual   interface{}   `json:"actual,omitempty"`
+	Delta    time.Duration `json:"delta,omitempty"` // How far outside the accepted time range the claim is
+
+	deltaInMessage bool
+}
+
+// NewValidationReport breaks err down into the errors it joins, such as those
+// returned by Claims.Valid and Validator.Validate. A nil error yields an empty
+// report.
+func NewValidationReport(err error) *ValidationReport {
+	r := new(ValidationReport)
+	r.add(err)
+	return r
+}
+
+func (r *ValidationReport) add(err error) {
+	if err == nil {
+		return
+	}
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range j.Unwrap() {
+			r.add(e)
+		}
+		return
+	}
+	p := ValidationProblem{Message: strings.TrimPrefix(err.Error(), "jwt: ")}
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		p.Message = strings.TrimPrefix(verr.Err.Error(), "jwt: ")
+		p.Claim, p.Expected, p.Delta = verr.Claim, verr.Expected, verr.Delta
+		// Errors such as *ExpiredError state the delta themselves.
+		_, p.deltaInMessage = verr.Err.(interface{ Delta() time.Duration })
+		if verr.Actual != nil {
+			p.Actual = redact(verr.Claim, verr.Actual)
+		}
+	}
+	r.Problems = append(r.Problems, p)
+}
+
+// String renders the report with one problem per line.
+func (r *ValidationReport) String() string {
+	var b strings.Builder
+	switch len(r.Problems) {
+	case 0:
+		return "token is valid"
+	case 1:
+		b.WriteString("token is invalid: 1 problem")
+	default:
+		fmt.Fprintf(&b, "token is invalid: %d problems", len(r.Problems))
+	}
+	for _, p := range r.Problems {
+		b.WriteString("\n  - ")
+		if p.Claim != "" {
+			b.WriteString(p.Claim + ": ")
+		}
+		b.WriteString(p.Message)
+		if p.Expected != nil {
+			fmt.Fprintf(&b, "; expected %v, got %v", p.Expected, p.Actual)
+		}
+		if p.Delta != 0 && !p.deltaInMessage {
+			fmt.Fprintf(&b, "; off by %v", p.Delta)
+		}
+	}
+	return b.String()
+}
+
+// FormatValidationError renders err as a multi-line report of every problem it
+// joins, as by NewValidationReport(err).String().
+func FormatValidationError(err error) string {
+	return NewValidationReport(err).String()
+}
@@ -0,0 +1,52 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestFormatValidationError(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	claims := &jwt.RegisteredClaims{
+		Issuer:    "https://other.example.com",
+		ExpiresAt: jwt.NewNumericDate(now.Add(-time.Hour)),
+	}
+	v := jwt.NewValidator(jwt.WithPolicy(jwt.Policy{Issuers: []string{"https://issuer.example.com"}, RequiredClaims: []string{"sub"}}))
+	err := v.Validate(&jwt.Token{Claims: claims})
+
+	want := `token is invalid: 3 problems
+  - exp: token is expired by 1h0m0s
+  - iss: the token has an invalid issuer; expected [https://issuer.example.com], got https://other.example.com
+  - sub: the token is missing a required claim`
+	if got := jwt.FormatValidationError(err); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	b, err := json.Marshal(jwt.NewValidationReport(err))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Problems []map[string]interface{}
+	}
+	if err = json.Unmarshal(b, &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Problems) != 3 || report.Problems[1]["claim"] != "iss" || report.Problems[0]["delta"] != float64(time.Hour) {
+		t.Errorf("unexpected JSON %s", b)
+	}
+
+	if got := jwt.FormatValidationError(errors.New("jwt: something broke")); got != "token is invalid: 1 problem\n  - something broke" {
+		t.Errorf("unexpected report %q", got)
+	}
+	if got := jwt.FormatValidationError(nil); got != "token is valid" {
+		t.Errorf("unexpected report %q", got)
+	}
+}
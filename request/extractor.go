@@ -50,6 +50,34 @@ func (e ArgumentExtractor) ExtractToken(req *http.Request) (string, error) {
 	return "", ErrNoTokenInRequest
 }
 
+// QueryExtractor extracts a token from the URL query parameters. Parameter names
+// are tried in order until there's a match. Unlike ArgumentExtractor, the
+// request body is never read.
+type QueryExtractor []string
+
+func (e QueryExtractor) ExtractToken(req *http.Request) (string, error) {
+	query := req.URL.Query()
+	for _, param := range e {
+		if ah := query.Get(param); ah != "" {
+			return ah, nil
+		}
+	}
+	return "", ErrNoTokenInRequest
+}
+
+// CookieExtractor extracts a token from cookies. Cookie names are tried in
+// order until there's a match.
+type CookieExtractor []string
+
+func (e CookieExtractor) ExtractToken(req *http.Request) (string, error) {
+	for _, name := range e {
+		if c, err := req.Cookie(name); err == nil && c.Value != "" {
+			return c.Value, nil
+		}
+	}
+	return "", ErrNoTokenInRequest
+}
+
 // MultiExtractor tries Extractors in order until one returns a token string or an error occurs
 type MultiExtractor []Extractor
 
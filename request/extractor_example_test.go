@@ -30,3 +30,14 @@ func ExampleArgumentExtractor() {
 	}
 	//Output: A
 }
+
+func ExampleCookieExtractor() {
+	req := makeExampleRequest("GET", "/", map[string]string{"Cookie": "token=" + exampleTokenA}, nil)
+	tokenString, err := CookieExtractor{"token"}.ExtractToken(req)
+	if err == nil {
+		fmt.Println(tokenString)
+	} else {
+		fmt.Println(err)
+	}
+	//Output: A
+}
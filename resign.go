@@ -0,0 +1,163 @@
+package jwt
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// keyHeaders are the header parameters identifying the signing key, which
+// Resign drops as they describe the key the token was signed with before.
+var keyHeaders = []string{"kid", "jku", "jwk", "x5u", "x5c", "x5t", "x5t#S256"}
+
+// ResignOption configures Token.Resign.
+type ResignOption func(*resignOptions)
+
+type resignOptions struct {
+	update    func(Claims) error
+	lifetime  time.Duration
+	tokenOpts []TokenOption
+}
+
+// WithClaimsUpdate sets a function which modifies the token's claims before
+// it is signed again. An error it returns is returned by Resign.
+func WithClaimsUpdate(update func(Claims) error) ResignOption {
+	return func(o *resignOptions) {
+		o.update = update
+	}
+}
+
+// WithLifetime sets the "exp" claim of the token signed by Resign to the
+// given duration after its new "iat" claim, rather than keeping the lifetime
+// of the original token.
+func WithLifetime(d time.Duration) ResignOption {
+	return func(o *resignOptions) {
+		o.lifetime = d
+	}
+}
+
+// WithTokenOptions applies opts to the token signed by Resign, such as
+// WithKeyID to name the new signing key.
+func WithTokenOptions(opts ...TokenOption) ResignOption {
+	return func(o *resignOptions) {
+		o.tokenOpts = append(o.tokenOpts, opts...)
+	}
+}
+
+// Resign signs the token again with method and key, returning the new token,
+// as gateways exchanging tokens do. The token is typically parsed; it need not
+// be valid.
+//
+// The claims are first modified by the function set with WithClaimsUpdate,
+// if any, then encoded, keeping those captured by an embedded UnknownClaims.
+// If the token has an "iat" claim, it and "nbf", if present, are set to the
+// current time and "exp" moves with them, keeping the lifetime of the token.
+// WithLifetime sets another lifetime, adding "iat" and "exp" if the token
+// lacks them.
+//
+// The header is kept, including parameters this package does not know, except
+// for those identifying the previous signing key, such as "kid" and "x5t",
+// which may be set with WithTokenOptions or a KeyIDStrategy.
+func (t *Token) Resign(method SigningMethod, key interface{}, opts ...ResignOption) (string, error) {
+	var o resignOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.update != nil {
+		if err := o.update(t.Claims); err != nil {
+			return "", err
+		}
+	}
+
+	b, err := marshalClaims(t.Claims)
+	if err != nil {
+		return "", err
+	}
+	var claims map[string]json.RawMessage
+	if err = json.Unmarshal(b, &claims); err != nil {
+		return "", err
+	}
+	if err = refreshTimes(claims, o.lifetime); err != nil {
+		return "", err
+	}
+	resigned := make(MapClaims, len(claims))
+	for k, v := range claims {
+		resigned[k] = v
+	}
+
+	header := make(map[string]interface{}, len(t.Header))
+	for k, v := range t.Header {
+		header[k] = v
+	}
+	for _, k := range keyHeaders {
+		delete(header, k)
+	}
+	token := &Token{
+		Header:        header,
+		Claims:        resigned,
+		Method:        method,
+		Abbreviations: t.Abbreviations,
+		KeyIDStrategy: t.KeyIDStrategy,
+	}
+	for _, opt := range o.tokenOpts {
+		opt(token)
+	}
+	header["alg"] = method.Alg()
+	return token.SignedString(key)
+}
+
+// refreshTimes moves the "iat", "nbf" and "exp" claims to the current time,
+// as described for Resign.
+func refreshTimes(claims map[string]json.RawMessage, lifetime time.Duration) error {
+	now := TimeFunc()
+	if lifetime == 0 {
+		iat, err := numericDateClaim(claims, "iat")
+		if err != nil {
+			return err
+		}
+		exp, err := numericDateClaim(claims, "exp")
+		if err != nil {
+			return err
+		}
+		if iat == nil {
+			// Without "iat" the lifetime is unknown and "exp" is left alone.
+			return nil
+		}
+		if exp != nil {
+			lifetime = exp.Sub(iat.Time)
+		}
+	}
+
+	set := func(name string, t time.Time) error {
+		b, err := json.Marshal(NewNumericDate(t))
+		if err != nil {
+			return err
+		}
+		claims[name] = b
+		return nil
+	}
+	if err := set("iat", now); err != nil {
+		return err
+	}
+	if _, ok := claims["nbf"]; ok {
+		if err := set("nbf", now); err != nil {
+			return err
+		}
+	}
+	if lifetime != 0 {
+		return set("exp", now.Add(lifetime))
+	}
+	return nil
+}
+
+// numericDateClaim decodes the named claim, returning nil if it is absent.
+func numericDateClaim(claims map[string]json.RawMessage, name string) (*NumericDate, error) {
+	raw, ok := claims[name]
+	if !ok || string(raw) == "null" {
+		return nil, nil
+	}
+	var date NumericDate
+	if err := json.Unmarshal(raw, &date); err != nil {
+		return nil, &ValidationError{Err: ErrInvalidClaimType, Claim: name}
+	}
+	return &date, nil
+}
@@ -0,0 +1,95 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+type resignClaims struct {
+	jwt.RegisteredClaims
+	jwt.UnknownClaims
+	Scope string `json:"scope"`
+}
+
+func TestToken_Resign(t *testing.T) {
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	issued := time.Unix(1700000000, 0)
+	jwt.TimeFunc = func() time.Time { return issued }
+
+	original := jwt.MapClaims{
+		"sub":    "subject",
+		"scope":  "read",
+		"tenant": "acme",
+		"iat":    issued.Unix(),
+		"exp":    issued.Add(time.Hour).Unix(),
+	}
+	raw, err := jwt.NewWithClaims(jwt.SigningMethodHS256, original, jwt.WithKeyID("old"), jwt.WithHeader("x-trace", "abc")).SignedString([]byte("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var claims resignClaims
+	token, err := jwt.ParseWithClaims(raw, &claims, func(*jwt.Token) (interface{}, error) { return []byte("old"), nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := issued.Add(30 * time.Minute)
+	jwt.TimeFunc = func() time.Time { return now }
+	resigned, err := token.Resign(jwt.SigningMethodHS384, []byte("new"),
+		jwt.WithClaimsUpdate(func(c jwt.Claims) error {
+			c.(*resignClaims).Scope = "read write"
+			return nil
+		}),
+		jwt.WithTokenOptions(jwt.WithKeyID("new")),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	parsed, err := jwt.Parse(resigned, func(*jwt.Token) (interface{}, error) { return []byte("new"), nil })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed.Header["alg"] != "HS384" || parsed.Header["kid"] != "new" || parsed.Header["x-trace"] != "abc" {
+		t.Errorf("Unexpected header: %v", parsed.Header)
+	}
+	got := parsed.Claims.(jwt.MapClaims)
+	if got["sub"] != "subject" || got["scope"] != "read write" || got["tenant"] != "acme" {
+		t.Errorf("Unexpected claims: %v", got)
+	}
+	if got["iat"] != float64(now.Unix()) || got["exp"] != float64(now.Add(time.Hour).Unix()) {
+		t.Errorf("Expected the times to move to %v, got iat %v and exp %v", now.Unix(), got["iat"], got["exp"])
+	}
+
+	resigned, err = token.Resign(jwt.SigningMethodHS256, []byte("new"), jwt.WithLifetime(5*time.Minute))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parsed, err = jwt.Parse(resigned, func(*jwt.Token) (interface{}, error) { return []byte("new"), nil })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := parsed.Header["kid"]; ok {
+		t.Errorf("Expected the previous kid to be dropped: %v", parsed.Header)
+	}
+	if exp := parsed.Claims.(jwt.MapClaims)["exp"]; exp != float64(now.Add(5*time.Minute).Unix()) {
+		t.Errorf("Unexpected exp: %v", exp)
+	}
+}
+
+func TestToken_Resign_errors(t *testing.T) {
+	errUpdate := errors.New("update failed")
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "subject"})
+	_, err := token.Resign(jwt.SigningMethodHS256, []byte("key"), jwt.WithClaimsUpdate(func(jwt.Claims) error { return errUpdate }))
+	if !errors.Is(err, errUpdate) {
+		t.Errorf("Expected the update error, got %v", err)
+	}
+
+	token = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iat": "yesterday"})
+	if _, err = token.Resign(jwt.SigningMethodHS256, []byte("key")); !errors.Is(err, jwt.ErrInvalidClaimType) {
+		t.Errorf("Expected ErrInvalidClaimType, got %v", err)
+	}
+}
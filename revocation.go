@@ -0,0 +1,87 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RevocationChecker is called by a Parser once the signature and claims of a
+// token are valid. It returns an error if the token has been revoked, such as
+// on logout or the compromise of a credential, or if revocation could not be
+// checked.
+type RevocationChecker func(ctx context.Context, token *Token) error
+
+// WithRevocationChecker sets the RevocationChecker of the Parser. Use
+// ParseWithContext to pass the context of a request to it.
+func WithRevocationChecker(check RevocationChecker) ParserOption {
+	return func(p *Parser) {
+		p.RevocationChecker = check
+	}
+}
+
+// BlocklistChecker returns a RevocationChecker which rejects tokens whose
+// "jti" claim is blocked in b with ErrTokenRevoked. Tokens without a "jti"
+// claim are accepted, unless required is set. Tokens are revoked with
+// RevokeToken.
+func BlocklistChecker(b Blocklist, required bool) RevocationChecker {
+	return func(ctx context.Context, token *Token) error {
+		jti, err := tokenID(token)
+		if err != nil {
+			return err
+		}
+		if jti == "" {
+			if required {
+				return &ValidationError{Err: ErrTokenRequiredClaimMissing, Claim: "jti"}
+			}
+			return nil
+		}
+		blocked, err := b.Blocked(ctx, jti)
+		if err != nil {
+			return fmt.Errorf("jwt: checking revocation: %w", err)
+		}
+		if blocked {
+			return &ValidationError{Err: ErrTokenRevoked, Claim: "jti"}
+		}
+		return nil
+	}
+}
+
+// RevokeToken blocks the "jti" claim of token in b until the token expires,
+// after which it no longer needs to be remembered. Tokens without an "exp"
+// claim are blocked for ttl. MemoryBlocklist evicts the entry once it lapses.
+func RevokeToken(ctx context.Context, b Blocklist, token *Token, ttl time.Duration) error {
+	jti, err := tokenID(token)
+	if err != nil {
+		return err
+	}
+	if jti == "" {
+		return &ValidationError{Err: ErrTokenRequiredClaimMissing, Claim: "jti"}
+	}
+	until := TimeFunc().Add(ttl)
+	if c, ok := token.Claims.(interface {
+		GetExpirationTime() (*NumericDate, error)
+	}); ok {
+		if exp, err := c.GetExpirationTime(); err == nil && exp != nil {
+			until = exp.Time
+		}
+	}
+	return b.Block(ctx, jti, until)
+}
+
+// tokenID returns the "jti" claim of token, or "" if it has none.
+func tokenID(token *Token) (string, error) {
+	m, err := claimsMap(token.Claims)
+	if err != nil {
+		return "", err
+	}
+	jti, ok := m["jti"]
+	if !ok {
+		return "", nil
+	}
+	s, ok := jti.(string)
+	if !ok {
+		return "", &ValidationError{Err: ErrInvalidClaimType, Claim: "jti"}
+	}
+	return s, nil
+}
@@ -0,0 +1,133 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestParser_RevocationChecker(t *testing.T) {
+	ctx := context.Background()
+	key := []byte("secret")
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+	sign := func(claims jwt.Claims) string {
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	var b jwt.MemoryBlocklist
+	p := jwt.NewParser(jwt.WithRevocationChecker(jwt.BlocklistChecker(&b, false)))
+	revoked := sign(&jwt.RegisteredClaims{ID: "a", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))})
+	token, err := p.ParseWithContext(ctx, revoked, &jwt.RegisteredClaims{}, keyFunc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = jwt.RevokeToken(ctx, &b, token, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if token, err = p.ParseWithClaims(revoked, &jwt.RegisteredClaims{}, keyFunc); !errors.Is(err, jwt.ErrTokenRevoked) || token.Valid {
+		t.Errorf("Expected ErrTokenRevoked, got %v", err)
+	}
+	if _, err = p.Parse(sign(jwt.MapClaims{"jti": "b"}), keyFunc); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err = p.Parse(sign(jwt.MapClaims{}), keyFunc); err != nil {
+		t.Errorf("Unexpected error without jti: %v", err)
+	}
+
+	p = jwt.NewParser(jwt.WithRevocationChecker(jwt.BlocklistChecker(&b, true)))
+	if _, err = p.Parse(sign(jwt.MapClaims{}), keyFunc); !errors.Is(err, jwt.ErrTokenRequiredClaimMissing) {
+		t.Errorf("Expected ErrTokenRequiredClaimMissing, got %v", err)
+	}
+
+	failing := errors.New("unavailable")
+	p = jwt.NewParser(jwt.WithRevocationChecker(func(context.Context, *jwt.Token) error { return failing }))
+	if _, err = p.Parse(sign(jwt.MapClaims{"jti": "b"}), keyFunc); !errors.Is(err, failing) {
+		t.Errorf("Expected the error of the checker, got %v", err)
+	}
+}
+
+func TestParser_RevocationChecker_serializations(t *testing.T) {
+	ctx := context.Background()
+	key := []byte("secret")
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"jti": "a"})
+	compact, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serialized, err := token.SignedJSONString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b jwt.MemoryBlocklist
+	if err = b.Block(ctx, "a", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	var parsed int
+	p := jwt.NewParser(jwt.WithRevocationChecker(jwt.BlocklistChecker(&b, false)))
+	p.Hooks.OnParse = func(_ context.Context, e jwt.ParseEvent) {
+		if e.Failure == jwt.FailureRevoked {
+			parsed++
+		}
+	}
+
+	var tests = []struct {
+		name  string
+		parse func() (*jwt.Token, error)
+	}{
+		{"ParseJSON", func() (*jwt.Token, error) { return p.ParseJSON(serialized, jwt.MapClaims{}, keyFunc) }},
+		{"ParseJSONAny", func() (*jwt.Token, error) { return p.ParseJSONAny(serialized, jwt.MapClaims{}, keyFunc) }},
+		{"ParseNested", func() (*jwt.Token, error) {
+			token, _, err := p.ParseNested(wrap(t, compact, key), jwt.MapClaims{}, keyFunc)
+			return token, err
+		}},
+	}
+
+	for _, data := range tests {
+		token, err := data.parse()
+		if !errors.Is(err, jwt.ErrTokenRevoked) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, jwt.ErrTokenRevoked, err)
+		}
+		if token != nil && token.Valid {
+			t.Errorf("[%v] Expected the token to be invalid", data.name)
+		}
+	}
+	if parsed != len(tests) {
+		t.Errorf("Expected %v revocations reported to OnParse. Got: %v", len(tests), parsed)
+	}
+}
+
+func TestRevokeToken_expiry(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	ctx := context.Background()
+	var b jwt.MemoryBlocklist
+	withExp := &jwt.Token{Claims: jwt.MapClaims{"jti": "a", "exp": float64(now.Add(time.Minute).Unix())}}
+	withoutExp := &jwt.Token{Claims: jwt.MapClaims{"jti": "b"}}
+	for _, token := range []*jwt.Token{withExp, withoutExp} {
+		if err := jwt.RevokeToken(ctx, &b, token, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := jwt.RevokeToken(ctx, &b, &jwt.Token{Claims: jwt.MapClaims{}}, time.Hour); !errors.Is(err, jwt.ErrTokenRequiredClaimMissing) {
+		t.Errorf("Expected ErrTokenRequiredClaimMissing, got %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if blocked, _ := b.Blocked(ctx, "a"); blocked {
+		t.Error("Expected a to lapse when the token expires")
+	}
+	if blocked, _ := b.Blocked(ctx, "b"); !blocked {
+		t.Error("Expected b to be blocked for the ttl")
+	}
+}
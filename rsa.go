@@ -2,12 +2,12 @@ package jwt
 
 import (
 	"crypto"
-	"crypto/rand"
 	"crypto/rsa"
 )
 
 // SigningMethodRSA implements the RSA family of signing methods.
-// Expects *rsa.PrivateKey for signing and *rsa.PublicKey for validation
+// Expects *rsa.PrivateKey, or a crypto.Signer with an RSA public key, for
+// signing and *rsa.PublicKey for validation
 type SigningMethodRSA struct {
 	Name string
 	Hash crypto.Hash
@@ -81,10 +81,18 @@ func (m *SigningMethodRSA) Verify(signingString, signature string, key interface
 // For this signing method, must be an *rsa.PrivateKey structure.
 func (m *SigningMethodRSA) Sign(signingString string, key interface{}) (string, error) {
 	var rsaKey *rsa.PrivateKey
-	var ok bool
+	var signer crypto.Signer
 
 	// Validate type of key
-	if rsaKey, ok = key.(*rsa.PrivateKey); !ok {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		rsaKey = k
+	case crypto.Signer:
+		if _, ok := k.Public().(*rsa.PublicKey); !ok {
+			return "", ErrInvalidKey
+		}
+		signer = k
+	default:
 		return "", ErrInvalidKey
 	}
 
@@ -96,8 +104,13 @@ func (m *SigningMethodRSA) Sign(signingString string, key interface{}) (string,
 	hasher := m.Hash.New()
 	hasher.Write([]byte(signingString))
 
+	// Sign with an opaque signer, such as a key held by a KMS or HSM
+	if signer != nil {
+		return signWithSigner(signer, hasher.Sum(nil), m.Hash)
+	}
+
 	// Sign the string and return the encoded bytes
-	if sigBytes, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, m.Hash, hasher.Sum(nil)); err == nil {
+	if sigBytes, err := rsa.SignPKCS1v15(RandReader, rsaKey, m.Hash, hasher.Sum(nil)); err == nil {
 		return EncodeSegment(sigBytes), nil
 	} else {
 		return "", err
@@ -4,7 +4,6 @@ package jwt
 
 import (
 	"crypto"
-	"crypto/rand"
 	"crypto/rsa"
 )
 
@@ -114,13 +113,20 @@ func (m *SigningMethodRSAPSS) Verify(signingString, signature string, key interf
 }
 
 // Sign implements token signing for the SigningMethod.
-// For this signing method, key must be an rsa.PrivateKey struct
+// For this signing method, key must be an rsa.PrivateKey struct, or a
+// crypto.Signer with an RSA public key which supports *rsa.PSSOptions
 func (m *SigningMethodRSAPSS) Sign(signingString string, key interface{}) (string, error) {
 	var rsaKey *rsa.PrivateKey
+	var signer crypto.Signer
 
 	switch k := key.(type) {
 	case *rsa.PrivateKey:
 		rsaKey = k
+	case crypto.Signer:
+		if _, ok := k.Public().(*rsa.PublicKey); !ok {
+			return "", ErrInvalidKeyType
+		}
+		signer = k
 	default:
 		return "", ErrInvalidKeyType
 	}
@@ -133,8 +139,13 @@ func (m *SigningMethodRSAPSS) Sign(signingString string, key interface{}) (strin
 	hasher := m.Hash.New()
 	hasher.Write([]byte(signingString))
 
+	// Sign with an opaque signer, such as a key held by a KMS or HSM
+	if signer != nil {
+		return signWithSigner(signer, hasher.Sum(nil), &rsa.PSSOptions{SaltLength: m.Options.SaltLength, Hash: m.Hash})
+	}
+
 	// Sign the string and return the encoded bytes
-	if sigBytes, err := rsa.SignPSS(rand.Reader, rsaKey, m.Hash, hasher.Sum(nil), m.Options); err == nil {
+	if sigBytes, err := rsa.SignPSS(RandReader, rsaKey, m.Hash, hasher.Sum(nil), m.Options); err == nil {
 		return EncodeSegment(sigBytes), nil
 	} else {
 		return "", err
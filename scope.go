@@ -0,0 +1,137 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Scopes represents the "scope" claim, as referenced at
+// https://datatracker.ietf.org/doc/html/rfc8693#section-4.2. It is serialized
+// as a space-delimited string, but may be deserialized from either a
+// space-delimited string or an array of strings.
+type Scopes []string
+
+// ParseScopes splits a space-delimited scope string.
+func ParseScopes(s string) Scopes {
+	return Scopes(strings.Fields(s))
+}
+
+func (s *Scopes) UnmarshalJSON(data []byte) (err error) {
+	var value interface{}
+
+	if err = json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case string:
+		*s = ParseScopes(v)
+	case []interface{}:
+		scopes := make(Scopes, 0, len(v))
+		for _, vv := range v {
+			vs, ok := vv.(string)
+			if !ok {
+				return &json.UnsupportedTypeError{Type: reflect.TypeOf(vv)}
+			}
+			scopes = append(scopes, vs)
+		}
+		*s = scopes
+	case nil:
+		return nil
+	default:
+		return &json.UnsupportedTypeError{Type: reflect.TypeOf(v)}
+	}
+
+	return nil
+}
+
+func (s Scopes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// String returns the space-delimited form of s.
+func (s Scopes) String() string {
+	return strings.Join(s, " ")
+}
+
+// Has reports whether s contains scope.
+func (s Scopes) Has(scope string) bool {
+	for _, v := range s {
+		if v == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAll reports whether s contains every one of scopes.
+func (s Scopes) HasAll(scopes ...string) bool {
+	return len(s.Missing(scopes...)) == 0
+}
+
+// HasAny reports whether s contains at least one of scopes.
+func (s Scopes) HasAny(scopes ...string) bool {
+	return len(s.Missing(scopes...)) < len(scopes)
+}
+
+// Missing returns the members of scopes which are not in s.
+func (s Scopes) Missing(scopes ...string) []string {
+	var missing []string
+	for _, scope := range scopes {
+		if !s.Has(scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// Verify returns an *InsufficientScopeError if s does not contain every one
+// of required.
+func (s Scopes) Verify(required ...string) error {
+	if missing := s.Missing(required...); len(missing) > 0 {
+		return &InsufficientScopeError{Required: required, Missing: missing}
+	}
+	return nil
+}
+
+// VerifyAny returns an *InsufficientScopeError if s contains none of
+// accepted.
+func (s Scopes) VerifyAny(accepted ...string) error {
+	if missing := s.Missing(accepted...); len(missing) > 0 && len(missing) == len(accepted) {
+		return &InsufficientScopeError{Required: accepted, Missing: missing}
+	}
+	return nil
+}
+
+// ScopesGetter is implemented by claims types which carry a scope claim.
+// MapClaims implements it by reading the "scope" claim.
+type ScopesGetter interface {
+	GetScopes() (Scopes, error)
+}
+
+// GetScopes returns the "scope" claim, which may be a space-delimited string or
+// an array of strings.
+func (m MapClaims) GetScopes() (Scopes, error) {
+	switch v := m["scope"].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return ParseScopes(v), nil
+	case []string:
+		return Scopes(v), nil
+	case []interface{}:
+		scopes := make(Scopes, 0, len(v))
+		for _, a := range v {
+			vs, ok := a.(string)
+			if !ok {
+				return nil, fmt.Errorf("jwt: scope entry [%v] is not a string", redact("scope", a))
+			}
+			scopes = append(scopes, vs)
+		}
+		return scopes, nil
+	default:
+		return nil, fmt.Errorf("jwt: scope claim of type %T is not supported", v)
+	}
+}
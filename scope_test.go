@@ -0,0 +1,88 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestScopes_UnmarshalJSON(t *testing.T) {
+	var tests = []struct {
+		name     string
+		raw      string
+		expected jwt.Scopes
+		err      bool
+	}{
+		{"space delimited", `"read:users write:users"`, jwt.Scopes{"read:users", "write:users"}, false},
+		{"array", `["read:users","write:users"]`, jwt.Scopes{"read:users", "write:users"}, false},
+		{"extra whitespace", `"  read:users   write:users "`, jwt.Scopes{"read:users", "write:users"}, false},
+		{"null", `null`, nil, false},
+		{"wrong type", `1`, nil, true},
+		{"wrong entry type", `["read:users",1]`, nil, true},
+	}
+
+	for _, data := range tests {
+		var s jwt.Scopes
+		err := json.Unmarshal([]byte(data.raw), &s)
+		if data.err != (err != nil) {
+			t.Errorf("[%v] Unexpected error state: %v", data.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(s, data.expected) {
+			t.Errorf("[%v] Expecting: %v  Got: %v", data.name, data.expected, s)
+		}
+	}
+}
+
+func TestScopes_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(jwt.Scopes{"read:users", "write:users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"read:users write:users"` {
+		t.Errorf("Serialized format of scopes mismatch. Got: %s", b)
+	}
+}
+
+func TestScopes_Verify(t *testing.T) {
+	s := jwt.ParseScopes("read:users write:users")
+	if !s.HasAll("read:users", "write:users") {
+		t.Errorf("Expected scopes to contain read:users and write:users")
+	}
+	err := s.Verify("read:users", "delete:users")
+	if !errors.Is(err, jwt.ErrInsufficientScope) {
+		t.Fatalf("Expected ErrInsufficientScope. Got: %v", err)
+	}
+	var scopeErr *jwt.InsufficientScopeError
+	if !errors.As(err, &scopeErr) || !reflect.DeepEqual(scopeErr.Missing, []string{"delete:users"}) {
+		t.Errorf("Unexpected missing scopes: %v", scopeErr)
+	}
+}
+
+func TestScopes_VerifyAny(t *testing.T) {
+	s := jwt.ParseScopes("read:users write:users")
+	if !s.HasAny("delete:users", "write:users") || s.HasAny("delete:users") {
+		t.Errorf("Unexpected HasAny results for %v", s)
+	}
+	if err := s.VerifyAny("admin", "read:users"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	var scopeErr *jwt.InsufficientScopeError
+	if err := s.VerifyAny("admin", "delete:users"); !errors.As(err, &scopeErr) || !reflect.DeepEqual(scopeErr.Required, []string{"admin", "delete:users"}) {
+		t.Errorf("Expected an InsufficientScopeError. Got: %v", err)
+	}
+}
+
+func TestMapClaims_GetScopes(t *testing.T) {
+	scopes, err := jwt.MapClaims{"scope": []interface{}{"a", "b"}}.GetScopes()
+	if err != nil || !reflect.DeepEqual(scopes, jwt.Scopes{"a", "b"}) {
+		t.Errorf("Unexpected scopes %v: %v", scopes, err)
+	}
+	scopes, err = jwt.MapClaims{"scope": "a b"}.GetScopes()
+	if err != nil || !reflect.DeepEqual(scopes, jwt.Scopes{"a", "b"}) {
+		t.Errorf("Unexpected scopes %v: %v", scopes, err)
+	}
+}
@@ -0,0 +1,102 @@
+package sdjwt
+
+import (
+	"crypto"
+	"encoding/json"
+	"io"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// Disclosure discloses the value of a claim, or of an array element, whose
+// digest an SD-JWT contains in its place.
+type Disclosure struct {
+	Salt  string
+	Name  string // The claim name, empty for array elements
+	Value interface{}
+
+	// Encoded is the base64url encoded JSON array [salt, name, value], or
+	// [salt, value] for array elements, which the digest is computed over.
+	Encoded string
+
+	element bool
+}
+
+// NewDisclosure returns a disclosure of the claim name with value, with a
+// random salt of 128 bits read from jwt.RandReader.
+func NewDisclosure(name string, value interface{}) (*Disclosure, error) {
+	return newDisclosure(name, value, false)
+}
+
+// NewElementDisclosure returns a disclosure of the array element value, with
+// a random salt of 128 bits read from jwt.RandReader.
+func NewElementDisclosure(value interface{}) (*Disclosure, error) {
+	return newDisclosure("", value, true)
+}
+
+func newDisclosure(name string, value interface{}, element bool) (*Disclosure, error) {
+	if !element && (name == sdKey || name == elementKey) {
+		return nil, ErrInvalidDisclosure
+	}
+	salt, err := jwt.NewID()
+	if err != nil {
+		return nil, err
+	}
+	array := []interface{}{salt, name, value}
+	if element {
+		array = []interface{}{salt, value}
+	}
+	b, err := json.Marshal(array)
+	if err != nil {
+		return nil, err
+	}
+	return &Disclosure{Salt: salt, Name: name, Value: value, Encoded: jwt.EncodeSegment(b), element: element}, nil
+}
+
+// ParseDisclosure decodes the encoded disclosure s.
+func ParseDisclosure(s string) (*Disclosure, error) {
+	b, err := jwt.DecodeSegment(s)
+	if err != nil {
+		return nil, ErrInvalidDisclosure
+	}
+	var array []interface{}
+	if err = json.Unmarshal(b, &array); err != nil {
+		return nil, ErrInvalidDisclosure
+	}
+	d := &Disclosure{Encoded: s}
+	var ok bool
+	switch len(array) {
+	case 2:
+		d.element = true
+		d.Value = array[1]
+	case 3:
+		if d.Name, ok = array[1].(string); !ok || d.Name == sdKey || d.Name == elementKey {
+			return nil, ErrInvalidDisclosure
+		}
+		d.Value = array[2]
+	default:
+		return nil, ErrInvalidDisclosure
+	}
+	if d.Salt, ok = array[0].(string); !ok {
+		return nil, ErrInvalidDisclosure
+	}
+	return d, nil
+}
+
+// IsElement reports whether d discloses an array element rather than a
+// claim.
+func (d *Disclosure) IsElement() bool {
+	return d.element
+}
+
+// Digest returns the base64url encoded digest of d computed with hash, which
+// the SD-JWT contains in place of the disclosed value.
+func (d *Disclosure) Digest(hash crypto.Hash) string {
+	return digest(hash, d.Encoded)
+}
+
+func digest(hash crypto.Hash, s string) string {
+	h := hash.New()
+	io.WriteString(h, s)
+	return jwt.EncodeSegment(h.Sum(nil))
+}
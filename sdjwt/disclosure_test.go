@@ -0,0 +1,60 @@
+package sdjwt_test
+
+import (
+	"crypto"
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4/sdjwt"
+)
+
+func TestParseDisclosure(t *testing.T) {
+	// RFC 9901, section 4.2.1
+	d, err := sdjwt.ParseDisclosure("WyJfMjZiYzRMVC1hYzZxMktJNmNCVzVlcyIsICJmYW1pbHlfbmFtZSIsICJNw7ZiaXVzIl0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Salt != "_26bc4LT-ac6q2KI6cBW5es" || d.Name != "family_name" || d.Value != "Möbius" || d.IsElement() {
+		t.Errorf("ParseDisclosure() = %+v", d)
+	}
+	if got := d.Digest(crypto.SHA256); got != "X9yH0Ajrdm1Oij4tWso9UzzKJvPoDxwmuEcO3XAdRC0" {
+		t.Errorf("Digest() = %v", got)
+	}
+
+	// RFC 9901, section 4.2.2
+	d, err = sdjwt.ParseDisclosure("WyJsa2x4RjVqTVlsR1RQVW92TU5JdkNBIiwgIkZSIl0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Value != "FR" || !d.IsElement() {
+		t.Errorf("ParseDisclosure() = %+v", d)
+	}
+
+	for _, s := range []string{"", "!", "WyJzYWx0Il0", "WyJzYWx0IiwgIl9zZCIsIDFd"} {
+		if _, err = sdjwt.ParseDisclosure(s); !errors.Is(err, sdjwt.ErrInvalidDisclosure) {
+			t.Errorf("ParseDisclosure(%q): expected ErrInvalidDisclosure, got %v", s, err)
+		}
+	}
+}
+
+func TestNewDisclosure(t *testing.T) {
+	d, err := sdjwt.NewDisclosure("given_name", "John")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := sdjwt.ParseDisclosure(d.Encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Salt != d.Salt || parsed.Name != "given_name" || parsed.Value != "John" {
+		t.Errorf("ParseDisclosure() = %+v, want %+v", parsed, d)
+	}
+
+	other, _ := sdjwt.NewDisclosure("given_name", "John")
+	if other.Salt == d.Salt {
+		t.Error("Disclosures share a salt")
+	}
+	if _, err = sdjwt.NewDisclosure("_sd", "x"); !errors.Is(err, sdjwt.ErrInvalidDisclosure) {
+		t.Errorf("Expected ErrInvalidDisclosure, got %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+// Package sdjwt issues, presents and verifies Selective Disclosure JWTs, as
+// described in https://datatracker.ietf.org/doc/html/rfc9901.
+//
+// An SD-JWT is a signed JWT in which some claims are replaced by digests of
+// disclosures, followed by the disclosures themselves:
+//
+//	<Issuer-signed JWT>~<Disclosure 1>~...~<Disclosure N>~<KB-JWT>
+//
+// An Issuer signs claims in which the selectively disclosable values are
+// wrapped with Disclosable, and optionally binds the SD-JWT to the key of the
+// holder. The holder keeps the disclosures it chooses to reveal with
+// SDJWT.Select and, if the SD-JWT is key bound, proves possession of its key
+// with a key binding JWT made by SDJWT.Present. The verifier checks the
+// signature, the disclosures and the key binding with Verify, which returns
+// the claims with the disclosed values in place.
+package sdjwt
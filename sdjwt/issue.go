@@ -0,0 +1,167 @@
+package sdjwt
+
+import (
+	"crypto"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwk"
+)
+
+// Disclosable wraps the value of a claim, or an array element, to make it
+// selectively disclosable when the claims are issued by an Issuer.
+type Disclosable struct {
+	Value interface{}
+}
+
+// Issuer issues SD-JWTs.
+type Issuer struct {
+	Method jwt.SigningMethod // The algorithm the JWT is signed with
+	Key    interface{}       // The private key the JWT is signed with
+
+	// Hash computes the digests of disclosures. It defaults to SHA-256; SHA-384
+	// and SHA-512 are also supported.
+	Hash crypto.Hash
+
+	// Decoys is the number of decoy digests added to every "_sd" claim, so
+	// that verifiers cannot tell how many claims were withheld.
+	Decoys int
+}
+
+// Issue returns an SD-JWT of claims, in which the values wrapped with
+// Disclosable, at any depth of the maps and slices of claims, are replaced by
+// digests: those of claims are listed in the "_sd" claim of the enclosing
+// object, and those of array elements take their place as {"...": digest}.
+// Values are walked if they are a map[string]interface{} or a []interface{},
+// including within disclosed values, which may thus hold disclosures too.
+//
+// If holderKey is not nil, the SD-JWT is bound to it, the public key of the
+// holder, through the "jwk" member of the "cnf" claim, and presentations
+// need a key binding JWT. opts configure the header of the JWT, such as with
+// jwt.WithType.
+func (i *Issuer) Issue(claims map[string]interface{}, holderKey interface{}, opts ...jwt.TokenOption) (*SDJWT, error) {
+	hash := i.Hash
+	if hash == 0 {
+		hash = crypto.SHA256
+	}
+	name, err := hashName(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &builder{hash: hash, decoys: i.Decoys}
+	payload, err := b.object(claims)
+	if err != nil {
+		return nil, err
+	}
+	payload[sdAlgKey] = name
+	if holderKey != nil {
+		key, err := jwk.NewKey(holderKey)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		payload["cnf"] = &jwt.Confirmation{Key: raw}
+	}
+
+	signed, err := jwt.NewWithClaims(i.Method, jwt.MapClaims(payload), opts...).SignedString(i.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &SDJWT{JWT: signed, Disclosures: b.disclosures}, nil
+}
+
+// builder replaces disclosable values by digests, collecting the disclosures.
+type builder struct {
+	hash        crypto.Hash
+	decoys      int
+	disclosures []*Disclosure
+}
+
+func (b *builder) object(in map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(in))
+	var digests []string
+	for name, v := range in {
+		d, ok := v.(Disclosable)
+		if !ok {
+			value, err := b.value(v)
+			if err != nil {
+				return nil, err
+			}
+			out[name] = value
+			continue
+		}
+		value, err := b.value(d.Value)
+		if err != nil {
+			return nil, err
+		}
+		disclosure, err := NewDisclosure(name, value)
+		if err != nil {
+			return nil, err
+		}
+		b.disclosures = append(b.disclosures, disclosure)
+		digests = append(digests, disclosure.Digest(b.hash))
+	}
+	if len(digests) > 0 {
+		for n := 0; n < b.decoys; n++ {
+			decoy, err := b.decoy()
+			if err != nil {
+				return nil, err
+			}
+			digests = append(digests, decoy)
+		}
+		// Sorting hides the order of the claims, and the decoys among them.
+		sort.Strings(digests)
+		out[sdKey] = digests
+	}
+	return out, nil
+}
+
+func (b *builder) value(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return b.object(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for n, e := range v {
+			d, ok := e.(Disclosable)
+			if !ok {
+				value, err := b.value(e)
+				if err != nil {
+					return nil, err
+				}
+				out[n] = value
+				continue
+			}
+			value, err := b.value(d.Value)
+			if err != nil {
+				return nil, err
+			}
+			disclosure, err := NewElementDisclosure(value)
+			if err != nil {
+				return nil, err
+			}
+			b.disclosures = append(b.disclosures, disclosure)
+			out[n] = map[string]interface{}{elementKey: disclosure.Digest(b.hash)}
+		}
+		return out, nil
+	case Disclosable:
+		return nil, ErrInvalidDisclosure
+	}
+	return v, nil
+}
+
+// decoy returns the digest of random data, indistinguishable from the
+// digest of a disclosure.
+func (b *builder) decoy() (string, error) {
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(jwt.RandReader, salt); err != nil {
+		return "", err
+	}
+	return digest(b.hash, jwt.EncodeSegment(salt)), nil
+}
@@ -0,0 +1,169 @@
+package sdjwt
+
+import (
+	"crypto"
+	"errors"
+	"strings"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// KeyBindingType is the "typ" header of key binding JWTs.
+const KeyBindingType = "kb+jwt"
+
+// Names of the claims holding digests.
+const (
+	sdKey      = "_sd"     // The digests of the disclosable claims of an object
+	sdAlgKey   = "_sd_alg" // The hash algorithm of the digests
+	elementKey = "..."     // The digest of a disclosable array element
+)
+
+var (
+	ErrInvalidDisclosure      = errors.New("sdjwt: disclosure is malformed")
+	ErrMalformed              = errors.New("sdjwt: SD-JWT is malformed")
+	ErrUnsupportedHash        = errors.New(`sdjwt: the "_sd_alg" claim names an unsupported hash algorithm`)
+	ErrUnreferencedDisclosure = errors.New("sdjwt: a disclosure is not referenced by the SD-JWT")
+	ErrDuplicateDigest        = errors.New("sdjwt: a digest appears more than once in the SD-JWT")
+	ErrClaimConflict          = errors.New("sdjwt: a disclosed claim already exists")
+	ErrMissingKeyBinding      = errors.New("sdjwt: the SD-JWT has no key binding JWT")
+	ErrMissingHolderKey       = errors.New(`sdjwt: the SD-JWT has no "cnf" claim with a "jwk" member`)
+	ErrSDHashMismatch         = errors.New(`sdjwt: the "sd_hash" claim does not match the SD-JWT`)
+	ErrNonceMismatch          = errors.New(`sdjwt: the "nonce" claim does not match the nonce of the verifier`)
+)
+
+// hashes are the hash algorithms of the "_sd_alg" claim, by their names in
+// the IANA "Named Information Hash Algorithm" registry.
+var hashes = map[string]crypto.Hash{
+	"sha-256": crypto.SHA256,
+	"sha-384": crypto.SHA384,
+	"sha-512": crypto.SHA512,
+}
+
+func hashName(hash crypto.Hash) (string, error) {
+	for name, h := range hashes {
+		if h == hash {
+			return name, nil
+		}
+	}
+	return "", ErrUnsupportedHash
+}
+
+// SDJWT is an SD-JWT in its parts.
+type SDJWT struct {
+	JWT         string        // The issuer-signed JWT
+	Disclosures []*Disclosure // The disclosures revealed
+	KeyBinding  string        // The key binding JWT, if any
+}
+
+// Parse splits the SD-JWT s into its parts and decodes its disclosures. It
+// verifies nothing; use Verify for that.
+func Parse(s string) (*SDJWT, error) {
+	parts := strings.Split(s, "~")
+	if len(parts) < 2 || parts[0] == "" {
+		return nil, ErrMalformed
+	}
+	sd := &SDJWT{JWT: parts[0], KeyBinding: parts[len(parts)-1]}
+	for _, p := range parts[1 : len(parts)-1] {
+		d, err := ParseDisclosure(p)
+		if err != nil {
+			return nil, err
+		}
+		sd.Disclosures = append(sd.Disclosures, d)
+	}
+	return sd, nil
+}
+
+// String returns the serialization of s.
+func (s *SDJWT) String() string {
+	return s.withoutKeyBinding() + s.KeyBinding
+}
+
+// withoutKeyBinding returns the serialization of s up to and including the
+// "~" preceding the key binding JWT, the input of its "sd_hash" claim.
+func (s *SDJWT) withoutKeyBinding() string {
+	var b strings.Builder
+	b.WriteString(s.JWT)
+	b.WriteByte('~')
+	for _, d := range s.Disclosures {
+		b.WriteString(d.Encoded)
+		b.WriteByte('~')
+	}
+	return b.String()
+}
+
+// Select returns a copy of s, without key binding JWT, revealing only the
+// disclosures for which keep returns true. A holder uses it to withhold the
+// claims it does not want to present. Disclosures nested in a disclosure
+// which is not kept are dropped as well by verifiers, so keep must keep the
+// disclosures enclosing those it keeps.
+func (s *SDJWT) Select(keep func(d *Disclosure) bool) *SDJWT {
+	out := &SDJWT{JWT: s.JWT}
+	for _, d := range s.Disclosures {
+		if keep(d) {
+			out.Disclosures = append(out.Disclosures, d)
+		}
+	}
+	return out
+}
+
+// KeyBindingClaims are the claims of a key binding JWT.
+type KeyBindingClaims struct {
+	jwt.RegisteredClaims
+
+	// the `nonce` claim, the nonce provided by the verifier
+	Nonce string `json:"nonce"`
+
+	// the `sd_hash` claim, the digest of the SD-JWT it is presented with
+	SDHash string `json:"sd_hash"`
+}
+
+// Present returns the serialization of s with a key binding JWT, proving
+// possession of key, the private key of the holder the SD-JWT is bound to,
+// to the verifier identified by audience, which provided nonce. The key
+// binding JWT is signed using method.
+func (s *SDJWT) Present(method jwt.SigningMethod, key interface{}, audience, nonce string) (string, error) {
+	hash, err := s.hash()
+	if err != nil {
+		return "", err
+	}
+	presented := &SDJWT{JWT: s.JWT, Disclosures: s.Disclosures}
+	prefix := presented.withoutKeyBinding()
+	claims := &KeyBindingClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience: jwt.ClaimStrings{audience},
+			IssuedAt: jwt.NewNumericDate(jwt.TimeFunc()),
+		},
+		Nonce:  nonce,
+		SDHash: digest(hash, prefix),
+	}
+	kb, err := jwt.NewWithClaims(method, claims, jwt.WithType(KeyBindingType)).SignedString(key)
+	if err != nil {
+		return "", err
+	}
+	return prefix + kb, nil
+}
+
+// hash returns the hash algorithm named by the "_sd_alg" claim of the
+// issuer-signed JWT, which is read without being verified.
+func (s *SDJWT) hash() (crypto.Hash, error) {
+	claims := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(s.JWT, claims); err != nil {
+		return 0, err
+	}
+	return sdHash(claims)
+}
+
+// sdHash returns the hash algorithm named by the "_sd_alg" claim of claims,
+// SHA-256 if it is absent.
+func sdHash(claims jwt.MapClaims) (crypto.Hash, error) {
+	v, ok := claims[sdAlgKey]
+	if !ok {
+		return crypto.SHA256, nil
+	}
+	name, _ := v.(string)
+	hash, ok := hashes[name]
+	if !ok || !hash.Available() {
+		return 0, ErrUnsupportedHash
+	}
+	return hash, nil
+}
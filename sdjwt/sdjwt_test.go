@@ -0,0 +1,165 @@
+package sdjwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/sdjwt"
+)
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func issue(t *testing.T, issuerKey, holderKey *ecdsa.PrivateKey) *sdjwt.SDJWT {
+	t.Helper()
+	issuer := &sdjwt.Issuer{Method: jwt.SigningMethodES256, Key: issuerKey, Decoys: 2}
+	var holder interface{}
+	if holderKey != nil {
+		holder = &holderKey.PublicKey
+	}
+	sd, err := issuer.Issue(map[string]interface{}{
+		"iss":         "https://issuer.example",
+		"given_name":  sdjwt.Disclosable{Value: "John"},
+		"family_name": sdjwt.Disclosable{Value: "Doe"},
+		"address": sdjwt.Disclosable{Value: map[string]interface{}{
+			"country":  "DE",
+			"locality": sdjwt.Disclosable{Value: "Berlin"},
+		}},
+		"nationalities": []interface{}{sdjwt.Disclosable{Value: "DE"}, "FR"},
+	}, holder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sd
+}
+
+func TestVerify(t *testing.T) {
+	issuerKey := newKey(t)
+	keyFunc := func(*jwt.Token) (interface{}, error) { return &issuerKey.PublicKey, nil }
+	sd := issue(t, issuerKey, nil)
+	if len(sd.Disclosures) != 5 {
+		t.Fatalf("Issue() made %d disclosures, want 5", len(sd.Disclosures))
+	}
+
+	result, err := sdjwt.Verify(sd.String(), keyFunc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := jwt.MapClaims{
+		"iss":         "https://issuer.example",
+		"given_name":  "John",
+		"family_name": "Doe",
+		"address": map[string]interface{}{
+			"country":  "DE",
+			"locality": "Berlin",
+		},
+		"nationalities": []interface{}{"DE", "FR"},
+	}
+	if !reflect.DeepEqual(result.Claims, want) {
+		t.Errorf("Claims = %v, want %v", result.Claims, want)
+	}
+
+	// The holder withholds the family name and the locality.
+	selected := sd.Select(func(d *sdjwt.Disclosure) bool {
+		return d.Name != "family_name" && d.Name != "locality"
+	})
+	result, err = sdjwt.Verify(selected.String(), keyFunc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := result.Claims["family_name"]; ok {
+		t.Error("The withheld family_name claim is present")
+	}
+	if address := result.Claims["address"].(map[string]interface{}); !reflect.DeepEqual(address, map[string]interface{}{"country": "DE"}) {
+		t.Errorf("address = %v", address)
+	}
+}
+
+func TestVerify_invalid(t *testing.T) {
+	issuerKey := newKey(t)
+	keyFunc := func(*jwt.Token) (interface{}, error) { return &issuerKey.PublicKey, nil }
+	sd := issue(t, issuerKey, nil)
+
+	forged, _ := sdjwt.NewDisclosure("given_name", "Mallory")
+	duplicate := &sdjwt.SDJWT{JWT: sd.JWT, Disclosures: append(sd.Disclosures, sd.Disclosures[0])}
+
+	tests := []struct {
+		name string
+		sd   string
+		want error
+	}{
+		{"unreferenced", (&sdjwt.SDJWT{JWT: sd.JWT, Disclosures: []*sdjwt.Disclosure{forged}}).String(), sdjwt.ErrUnreferencedDisclosure},
+		{"duplicate", duplicate.String(), sdjwt.ErrUnreferencedDisclosure},
+		{"malformed", sd.JWT, sdjwt.ErrMalformed},
+		{"disclosure", sd.JWT + "~!~", sdjwt.ErrInvalidDisclosure},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := sdjwt.Verify(tc.sd, keyFunc); !errors.Is(err, tc.want) {
+				t.Errorf("Expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestVerify_keyBinding(t *testing.T) {
+	issuerKey, holderKey := newKey(t), newKey(t)
+	keyFunc := func(*jwt.Token) (interface{}, error) { return &issuerKey.PublicKey, nil }
+	sd := issue(t, issuerKey, holderKey)
+
+	presented, err := sd.Present(jwt.SigningMethodES256, holderKey, "https://verifier.example", "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := sdjwt.Verify(presented, keyFunc, sdjwt.WithKeyBinding("https://verifier.example", "nonce"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.KeyBinding == nil || result.KeyBinding.Header["typ"] != sdjwt.KeyBindingType {
+		t.Errorf("KeyBinding = %v", result.KeyBinding)
+	}
+
+	// Dropping a disclosure after the key binding JWT is made breaks sd_hash.
+	parsed, _ := sdjwt.Parse(presented)
+	parsed.Disclosures = parsed.Disclosures[1:]
+	stolen, err := sd.Present(jwt.SigningMethodES256, newKey(t), "https://verifier.example", "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		sd   string
+		opt  sdjwt.Option
+		want error
+	}{
+		{"missing", sd.String(), sdjwt.WithKeyBinding("https://verifier.example", "nonce"), sdjwt.ErrMissingKeyBinding},
+		{"nonce", presented, sdjwt.WithKeyBinding("https://verifier.example", "other"), sdjwt.ErrNonceMismatch},
+		{"audience", presented, sdjwt.WithKeyBinding("https://other.example", "nonce"), jwt.ErrTokenInvalidAudience},
+		{"sd_hash", parsed.String(), sdjwt.WithKeyBinding("https://verifier.example", "nonce"), sdjwt.ErrSDHashMismatch},
+		{"holder key", stolen, sdjwt.WithKeyBinding("https://verifier.example", "nonce"), jwt.ErrSignatureInvalid},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := sdjwt.Verify(tc.sd, keyFunc, tc.opt); !errors.Is(err, tc.want) {
+				t.Errorf("Expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+
+	if !strings.HasSuffix(sd.String(), "~") {
+		t.Error("An SD-JWT without key binding must end with ~")
+	}
+}
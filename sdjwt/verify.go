@@ -0,0 +1,291 @@
+package sdjwt
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"encoding/json"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/jwk"
+)
+
+// DefaultMaxKeyBindingAge is how long after it was issued Verify accepts a
+// key binding JWT, unless configured with WithMaxKeyBindingAge.
+const DefaultMaxKeyBindingAge = 5 * time.Minute
+
+// Result is a verified SD-JWT.
+type Result struct {
+	// Token is the issuer-signed JWT, whose Claims hold the digests.
+	Token *jwt.Token
+
+	// Claims are the claims of the JWT with the disclosed values in place of
+	// their digests, and without "_sd", "_sd_alg" and undisclosed digests.
+	Claims jwt.MapClaims
+
+	// Disclosures are the disclosures presented.
+	Disclosures []*Disclosure
+
+	// KeyBinding is the verified key binding JWT, if one was required.
+	KeyBinding *jwt.Token
+}
+
+// Option configures Verify.
+type Option func(*config)
+
+type config struct {
+	keyBinding    bool
+	audience      string
+	nonce         string
+	maxAge        time.Duration
+	leeway        time.Duration
+	parserOptions []jwt.ParserOption
+}
+
+// WithKeyBinding requires a key binding JWT for the verifier identified by
+// audience, carrying nonce, signed with the key of the "cnf" claim. Without
+// it, a key binding JWT is not verified, and SD-JWTs bound to a key may be
+// presented by anyone holding them.
+func WithKeyBinding(audience, nonce string) Option {
+	return func(c *config) {
+		c.keyBinding = true
+		c.audience = audience
+		c.nonce = nonce
+	}
+}
+
+// WithMaxKeyBindingAge sets how long after it was issued a key binding JWT is
+// accepted. It defaults to DefaultMaxKeyBindingAge.
+func WithMaxKeyBindingAge(d time.Duration) Option {
+	return func(c *config) {
+		c.maxAge = d
+	}
+}
+
+// WithLeeway allows for clock skew of up to d when checking the "iat" of key
+// binding JWTs.
+func WithLeeway(d time.Duration) Option {
+	return func(c *config) {
+		c.leeway = d
+	}
+}
+
+// WithParserOptions configures the parser of the issuer-signed JWT and of the
+// key binding JWT, such as with jwt.WithValidMethods.
+func WithParserOptions(opts ...jwt.ParserOption) Option {
+	return func(c *config) {
+		c.parserOptions = append(c.parserOptions, opts...)
+	}
+}
+
+// Verify verifies the SD-JWT s, whose issuer-signed JWT is verified with the
+// key keyFunc returns, and returns its claims with the disclosed values in
+// place. Every disclosure must be referenced by exactly one digest, and no
+// digest may appear twice. With WithKeyBinding, the key binding JWT must be
+// present and verify with the key of the "cnf" claim, and its "sd_hash" must
+// match the presented SD-JWT.
+func Verify(s string, keyFunc jwt.Keyfunc, opts ...Option) (*Result, error) {
+	c := config{maxAge: DefaultMaxKeyBindingAge}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	sd, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := jwt.NewParser(c.parserOptions...)
+	claims := jwt.MapClaims{}
+	token, err := p.ParseWithClaims(sd.JWT, claims, keyFunc)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := sdHash(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// The claims are processed on a copy, leaving Token.Claims as signed.
+	var processed map[string]interface{}
+	if err = copyJSON(claims, &processed); err != nil {
+		return nil, err
+	}
+	pr := &processor{disclosures: make(map[string]*Disclosure, len(sd.Disclosures)), seen: map[string]bool{}}
+	for _, d := range sd.Disclosures {
+		pr.disclosures[d.Digest(hash)] = d
+	}
+	if err = pr.object(processed); err != nil {
+		return nil, err
+	}
+	if len(pr.used) != len(pr.disclosures) || len(pr.disclosures) != len(sd.Disclosures) {
+		return nil, ErrUnreferencedDisclosure
+	}
+	delete(processed, sdAlgKey)
+
+	result := &Result{Token: token, Claims: processed, Disclosures: sd.Disclosures}
+	if c.keyBinding {
+		if result.KeyBinding, err = c.verifyKeyBinding(sd, hash, processed); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (c *config) verifyKeyBinding(sd *SDJWT, hash crypto.Hash, claims jwt.MapClaims) (*jwt.Token, error) {
+	if sd.KeyBinding == "" {
+		return nil, ErrMissingKeyBinding
+	}
+	var cnf struct {
+		Confirmation *jwt.Confirmation `json:"cnf"`
+	}
+	if err := copyJSON(claims, &cnf); err != nil || cnf.Confirmation == nil || len(cnf.Confirmation.Key) == 0 {
+		return nil, ErrMissingHolderKey
+	}
+	key := new(jwk.Key)
+	if err := json.Unmarshal(cnf.Confirmation.Key, key); err != nil {
+		return nil, ErrMissingHolderKey
+	}
+	pub, err := key.Materialize()
+	if err != nil {
+		return nil, err
+	}
+
+	p := jwt.NewParser(append([]jwt.ParserOption{jwt.WithValidTypes(KeyBindingType)}, c.parserOptions...)...)
+	p.SkipClaimsValidation = true
+	kb := new(KeyBindingClaims)
+	token, err := p.ParseWithClaims(sd.KeyBinding, kb, func(*jwt.Token) (interface{}, error) {
+		return pub, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	v := jwt.Validator{Policy: jwt.Policy{
+		Audiences:          []string{c.audience},
+		RequiredClaims:     []string{"nonce", "sd_hash"},
+		RequireIssuedAt:    true,
+		IssuedWithinPast:   c.maxAge + c.leeway,
+		IssuedWithinFuture: c.leeway,
+	}}
+	errs := []error{v.Validate(token)}
+	if subtle.ConstantTimeCompare([]byte(kb.Nonce), []byte(c.nonce)) != 1 {
+		errs = append(errs, &jwt.ValidationError{Err: ErrNonceMismatch, Claim: "nonce"})
+	}
+	presented := &SDJWT{JWT: sd.JWT, Disclosures: sd.Disclosures}
+	if subtle.ConstantTimeCompare([]byte(kb.SDHash), []byte(digest(hash, presented.withoutKeyBinding()))) != 1 {
+		errs = append(errs, &jwt.ValidationError{Err: ErrSDHashMismatch, Claim: "sd_hash"})
+	}
+	if err = jwt.JoinErrors(errs...); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// processor replaces the digests of a payload with the disclosed values.
+type processor struct {
+	disclosures map[string]*Disclosure
+	used        []*Disclosure
+	seen        map[string]bool
+}
+
+// lookup returns the disclosure of digest, if presented, and checks that no
+// digest appears twice.
+func (p *processor) lookup(digest string) (*Disclosure, error) {
+	if p.seen[digest] {
+		return nil, ErrDuplicateDigest
+	}
+	p.seen[digest] = true
+	d, ok := p.disclosures[digest]
+	if ok {
+		p.used = append(p.used, d)
+	}
+	return d, nil
+}
+
+func (p *processor) object(obj map[string]interface{}) error {
+	if v, ok := obj[sdKey]; ok {
+		digests, ok := v.([]interface{})
+		if !ok {
+			return ErrMalformed
+		}
+		delete(obj, sdKey)
+		for _, v := range digests {
+			digest, ok := v.(string)
+			if !ok {
+				return ErrMalformed
+			}
+			d, err := p.lookup(digest)
+			if err != nil {
+				return err
+			}
+			if d == nil {
+				continue
+			}
+			if d.IsElement() {
+				return ErrInvalidDisclosure
+			}
+			if _, exists := obj[d.Name]; exists {
+				return ErrClaimConflict
+			}
+			// Disclosed values are copied, as the same disclosure may be
+			// processed again by another verification.
+			var value interface{}
+			if err = copyJSON(d.Value, &value); err != nil {
+				return err
+			}
+			obj[d.Name] = value
+		}
+	}
+	for name, v := range obj {
+		value, err := p.value(v)
+		if err != nil {
+			return err
+		}
+		obj[name] = value
+	}
+	return nil
+}
+
+func (p *processor) value(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return v, p.object(v)
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			if m, ok := e.(map[string]interface{}); ok && len(m) == 1 {
+				if digest, ok := m[elementKey].(string); ok {
+					d, err := p.lookup(digest)
+					if err != nil {
+						return nil, err
+					}
+					if d == nil {
+						// Withheld, or a decoy.
+						continue
+					}
+					if !d.IsElement() {
+						return nil, ErrInvalidDisclosure
+					}
+					if err = copyJSON(d.Value, &e); err != nil {
+						return nil, err
+					}
+				}
+			}
+			value, err := p.value(e)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, value)
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+func copyJSON(v, out interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
@@ -0,0 +1,34 @@
+package jwt
+
+// SecretProvider supplies the HMAC secrets a token may be signed with while
+// a secret is rotated: the current secret first, followed by previous ones.
+// It may be used as the key of the HMAC signing methods, in place of a
+// []byte. Tokens are signed with the first secret and verified with each in
+// turn, so an implementation backed by a secret store can rotate secrets
+// without a Keyfunc retrying verification itself.
+type SecretProvider interface {
+	Secrets() [][]byte
+}
+
+// SecretSet is a SecretProvider holding a fixed list of secrets, the current
+// one first:
+//
+//	key := jwt.SecretSet{current, previous}
+type SecretSet [][]byte
+
+// Secrets returns the secrets of the set.
+func (s SecretSet) Secrets() [][]byte {
+	return s
+}
+
+// hmacSecrets returns the secrets held by key, which is a []byte or a
+// SecretProvider.
+func hmacSecrets(key interface{}) ([][]byte, bool) {
+	switch k := key.(type) {
+	case []byte:
+		return [][]byte{k}, true
+	case SecretProvider:
+		return k.Secrets(), true
+	}
+	return nil, false
+}
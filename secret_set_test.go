@@ -0,0 +1,53 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestSecretSet(t *testing.T) {
+	current := []byte("current-secret-current-secret-32")
+	previous := []byte("previous-secret-previous-secret!")
+	retired := []byte("retired-secret-retired-secret-32")
+
+	oldToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "a"}).SignedString(previous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := jwt.SecretSet{current, previous}
+	newToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "a"}).SignedString(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Signing uses the current secret.
+	if _, err = jwt.Parse(newToken, func(*jwt.Token) (interface{}, error) { return current, nil }); err != nil {
+		t.Errorf("Token was not signed with the current secret: %v", err)
+	}
+	for _, token := range []string{oldToken, newToken} {
+		if _, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return set, nil }); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	_, err = jwt.Parse(oldToken, func(*jwt.Token) (interface{}, error) { return jwt.SecretSet{current, retired}, nil })
+	if !errors.Is(err, jwt.ErrSignatureInvalid) {
+		t.Errorf("Expected ErrSignatureInvalid, got %v", err)
+	}
+
+	if _, err = jwt.SigningMethodHS256.Sign("payload", jwt.SecretSet{}); !errors.Is(err, jwt.ErrInvalidKey) {
+		t.Errorf("Expected ErrInvalidKey signing with an empty set, got %v", err)
+	}
+}
+
+func TestSecretSet_keyConfusion(t *testing.T) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "a"}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := jwt.SecretSet{[]byte("secret"), []byte("-----BEGIN PUBLIC KEY-----\n")}
+	if _, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return set, nil }); !errors.Is(err, jwt.ErrKeyAlgorithmMismatch) {
+		t.Errorf("Expected ErrKeyAlgorithmMismatch, got %v", err)
+	}
+}
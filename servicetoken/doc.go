@@ -0,0 +1,10 @@
+// Package servicetoken implements a profile of short-lived tokens for calls
+// between the services of a trusted subsystem, minted per request.
+//
+// An Issuer, held by the calling service, signs tokens with HS256 or EdDSA
+// naming the called service as the audience. Tokens live for seconds and
+// carry a random "jti". A Verifier, held by the called service, accepts only
+// tokens addressed to it from issuers it holds a key for, and may reject
+// replays of a "jti". Verifier implements jwt.Verifier, so it can be used with
+// the jwtmiddleware and jwtgrpc packages.
+package servicetoken
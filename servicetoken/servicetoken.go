@@ -0,0 +1,175 @@
+package servicetoken
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// Defaults for Issuer and Verifier.
+const (
+	DefaultTTL    = 30 * time.Second
+	DefaultMaxAge = time.Minute
+	DefaultLeeway = 5 * time.Second
+)
+
+var (
+	ErrUnsupportedKey = errors.New("servicetoken: key must be an HMAC secret or an Ed25519 key")
+	ErrUnknownIssuer  = errors.New("servicetoken: no key is known for the issuer")
+	ErrReplayed       = errors.New("servicetoken: the token has already been used")
+)
+
+// methodFor returns the signing method used with key: HS256 for HMAC secrets
+// and EdDSA for Ed25519 keys.
+func methodFor(key interface{}) (jwt.SigningMethod, error) {
+	switch key.(type) {
+	case []byte:
+		return jwt.SigningMethodHS256, nil
+	case ed25519.PrivateKey, ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// Issuer mints tokens for calls made by one service.
+type Issuer struct {
+	name   string
+	ttl    time.Duration
+	signer *jwt.Signer
+}
+
+// NewIssuer returns an Issuer for the service called name, signing with key,
+// either an HMAC secret ([]byte) or an ed25519.PrivateKey. Tokens live for
+// ttl, or DefaultTTL if it is zero.
+func NewIssuer(name string, key interface{}, ttl time.Duration) (*Issuer, error) {
+	method, err := methodFor(key)
+	if err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	signer, err := jwt.NewSigner(method, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Issuer{name: name, ttl: ttl, signer: signer}, nil
+}
+
+// Issue returns a token for a call to the service called audience. subject,
+// if not empty, names the principal the call is made for.
+func (i *Issuer) Issue(audience, subject string) (string, error) {
+	id, err := jwt.NewID()
+	if err != nil {
+		return "", err
+	}
+	now := jwt.TimeFunc()
+	return i.signer.Sign(&jwt.RegisteredClaims{
+		Issuer:    i.name,
+		Subject:   subject,
+		Audience:  jwt.ClaimStrings{audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(i.ttl)),
+		ID:        id,
+	})
+}
+
+// Verifier verifies the tokens presented to one service. Its fields must not
+// be changed once it is in use.
+type Verifier struct {
+	Audience string                 // The name of this service
+	Keys     map[string]interface{} // Verification keys by issuer: HMAC secrets or ed25519.PublicKeys
+	MaxAge   time.Duration          // Optional. How long after "iat" tokens are accepted. Defaults to DefaultMaxAge
+	Leeway   time.Duration          // Optional. Allowance for clock skew. Defaults to DefaultLeeway
+
+	// Replay, if set, records the "jti" of every accepted token until it
+	// expires, and rejects tokens presenting one again with ErrReplayed.
+	Replay jwt.Blocklist
+
+	once      sync.Once
+	parser    *jwt.Parser
+	validator *jwt.Validator
+}
+
+// Verify implements jwt.Verifier. The claims of the returned token are
+// *jwt.RegisteredClaims.
+func (v *Verifier) Verify(ctx context.Context, credential string) (*jwt.Token, error) {
+	v.once.Do(v.init)
+	claims := new(jwt.RegisteredClaims)
+	token, err := v.parser.ParseWithClaims(credential, claims, v.keyfunc)
+	if err != nil {
+		return token, err
+	}
+	if err = v.validator.Validate(token); err != nil {
+		token.Valid = false
+		return token, err
+	}
+	if v.Replay != nil {
+		if err = v.checkReplay(ctx, claims); err != nil {
+			token.Valid = false
+			return token, err
+		}
+	}
+	return token, nil
+}
+
+func (v *Verifier) init() {
+	maxAge := v.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+	// The claims are validated by the policy alone, so that Leeway applies.
+	v.parser = &jwt.Parser{
+		ValidMethods:         []string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodEdDSA.Alg()},
+		SkipClaimsValidation: true,
+	}
+	v.validator = jwt.NewValidator(jwt.WithPolicy(jwt.Policy{
+		Audiences:         []string{v.Audience},
+		RequiredClaims:    []string{"iss", "jti"},
+		RequireExpiration: true,
+		RequireIssuedAt:   true,
+		MaxAge:            maxAge,
+		Leeway:            v.leeway(),
+	}))
+}
+
+// keyfunc returns the key of the issuer of the token, provided the token's
+// algorithm is the one used with that key.
+func (v *Verifier) keyfunc(token *jwt.Token) (interface{}, error) {
+	iss := token.Claims.(*jwt.RegisteredClaims).Issuer
+	key, ok := v.Keys[iss]
+	if !ok {
+		return nil, ErrUnknownIssuer
+	}
+	method, err := methodFor(key)
+	if err != nil {
+		return nil, err
+	}
+	if method.Alg() != token.Method.Alg() {
+		return nil, jwt.ErrKeyAlgorithmMismatch
+	}
+	return key, nil
+}
+
+func (v *Verifier) checkReplay(ctx context.Context, claims *jwt.RegisteredClaims) error {
+	key := claims.Issuer + ":" + claims.ID
+	used, err := v.Replay.Blocked(ctx, key)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrReplayed
+	}
+	return v.Replay.Block(ctx, key, claims.ExpiresAt.Add(v.leeway()))
+}
+
+func (v *Verifier) leeway() time.Duration {
+	if v.Leeway <= 0 {
+		return DefaultLeeway
+	}
+	return v.Leeway
+}
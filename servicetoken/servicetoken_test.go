@@ -0,0 +1,105 @@
+package servicetoken_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/servicetoken"
+)
+
+func TestVerifier(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := []byte("orders secret")
+	billing, err := servicetoken.NewIssuer("billing", priv, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders, err := servicetoken.NewIssuer("orders", secret, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A token of billing signed with the secret of orders.
+	impostor, err := servicetoken.NewIssuer("billing", secret, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stranger, err := servicetoken.NewIssuer("stranger", []byte("unknown"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := &servicetoken.Verifier{
+		Audience: "inventory",
+		Keys:     map[string]interface{}{"billing": pub, "orders": secret},
+		Replay:   new(jwt.MemoryBlocklist),
+	}
+	issue := func(i *servicetoken.Issuer, aud string) string {
+		s, err := i.Issue(aud, "user")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	replayed := issue(orders, "inventory")
+
+	var tests = []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"Ed25519", issue(billing, "inventory"), nil},
+		{"HS256", replayed, nil},
+		{"replayed", replayed, servicetoken.ErrReplayed},
+		{"other audience", issue(orders, "shipping"), jwt.ErrTokenInvalidAudience},
+		{"key confusion", issue(impostor, "inventory"), jwt.ErrKeyAlgorithmMismatch},
+		{"unknown issuer", issue(stranger, "inventory"), servicetoken.ErrUnknownIssuer},
+	}
+	for _, data := range tests {
+		token, err := v.Verify(context.Background(), data.token)
+		if data.err == nil {
+			if err != nil || !token.Valid {
+				t.Errorf("[%v] Unexpected error: %v", data.name, err)
+				continue
+			}
+			claims := token.Claims.(*jwt.RegisteredClaims)
+			if claims.ID == "" || claims.Subject != "user" || claims.ExpiresAt.Sub(claims.IssuedAt.Time) != servicetoken.DefaultTTL {
+				t.Errorf("[%v] Unexpected claims: %+v", data.name, claims)
+			}
+		} else if !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+}
+
+func TestVerifier_expired(t *testing.T) {
+	issuer, err := servicetoken.NewIssuer("orders", []byte("secret"), 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := issuer.Issue("inventory", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().Add(time.Minute)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	v := &servicetoken.Verifier{Audience: "inventory", Keys: map[string]interface{}{"orders": []byte("secret")}}
+	if _, err = v.Verify(context.Background(), s); !errors.Is(err, jwt.ErrTokenExpired) {
+		t.Errorf("Expected ErrTokenExpired. Got: %v", err)
+	}
+}
+
+func TestNewIssuer_unsupportedKey(t *testing.T) {
+	if _, err := servicetoken.NewIssuer("orders", "secret", 0); !errors.Is(err, servicetoken.ErrUnsupportedKey) {
+		t.Errorf("Expected ErrUnsupportedKey. Got: %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+// Package session issues linked pairs of access and refresh tokens, and
+// rotates them when the refresh token is redeemed.
+//
+// Both tokens of a pair carry the same "sid" claim, naming the session they
+// belong to, but differ in lifetime and type: access tokens are short-lived
+// and typed TypeAccessToken, refresh tokens long-lived, typed
+// TypeRefreshToken and addressed to the issuer itself, so neither can be
+// used in place of the other. Refresh redeems a refresh token for a new pair
+// of the same session, rotating the refresh token with a refresh.Rotator:
+// presenting a rotated refresh token again revokes every refresh token of
+// the session and calls Rotator.OnReuse.
+//
+// Access tokens already issued remain valid until they expire. To end them
+// with the session, have resource servers check the "sid" claim, such as
+// with jwtmiddleware.SessionStore.
+package session
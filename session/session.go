@@ -0,0 +1,227 @@
+package session
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/refresh"
+)
+
+// TypeRefreshToken is the "typ" header of refresh tokens.
+const TypeRefreshToken = "refresh+jwt"
+
+// Defaults for Config.
+const (
+	DefaultAccessTTL  = 15 * time.Minute
+	DefaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+var (
+	ErrMissingIssuer  = errors.New("session: the issuer is not configured")
+	ErrMissingRotator = errors.New("session: the rotator is not configured")
+	ErrRefreshTTL     = errors.New("session: the refresh token lifetime exceeds the TTL of the rotator")
+)
+
+// Config configures a Manager.
+type Config struct {
+	Issuer   string            // The "iss" claim of both tokens, and the "aud" claim of refresh tokens
+	Audience []string          // The "aud" claim of access tokens
+	Method   jwt.SigningMethod // The signing method of both tokens
+	Key      interface{}       // The signing key
+	KeyID    string            // Optional. The "kid" header of both tokens
+
+	AccessTTL  time.Duration // Optional. Defaults to DefaultAccessTTL
+	RefreshTTL time.Duration // Optional. Defaults to DefaultRefreshTTL. Must not exceed the TTL of the Rotator
+
+	// Rotator records rotated refresh tokens, detecting their reuse. Its
+	// OnReuse hook is called when a stolen refresh token is presented.
+	Rotator *refresh.Rotator
+
+	// Keyfunc, if set, supplies the key refresh tokens are verified with.
+	// It defaults to the public half of Key, or Key itself for HMAC.
+	Keyfunc jwt.Keyfunc
+}
+
+// Pair is an access token and the refresh token issued with it.
+type Pair struct {
+	AccessToken   string
+	RefreshToken  string
+	AccessClaims  *jwt.RegisteredClaims
+	RefreshClaims *refresh.Claims
+}
+
+// ExpiresIn returns the lifetime of the access token, for the "expires_in"
+// member of a token response.
+func (p *Pair) ExpiresIn() time.Duration {
+	return p.AccessClaims.ExpiresAt.Sub(p.AccessClaims.IssuedAt.Time)
+}
+
+// Manager issues and refreshes token pairs. It is safe for concurrent use.
+type Manager struct {
+	config  Config
+	access  *jwt.Signer
+	refresh *jwt.Signer
+	parser  *jwt.Parser
+	keyfunc jwt.Keyfunc
+}
+
+// New returns a Manager configured by config.
+func New(config Config) (*Manager, error) {
+	if config.Issuer == "" {
+		return nil, ErrMissingIssuer
+	}
+	if config.Rotator == nil {
+		return nil, ErrMissingRotator
+	}
+	if config.Method == nil {
+		return nil, jwt.ErrInvalidSigningMethod
+	}
+	if config.AccessTTL <= 0 {
+		config.AccessTTL = DefaultAccessTTL
+	}
+	if config.RefreshTTL <= 0 {
+		config.RefreshTTL = DefaultRefreshTTL
+	}
+	// A revoked family is only remembered for the TTL of the Rotator, and the
+	// last token issued to it may stay unexpired for RefreshTTL after that.
+	rotatorTTL := config.Rotator.TTL
+	if rotatorTTL <= 0 {
+		rotatorTTL = refresh.DefaultTTL
+	}
+	if config.RefreshTTL > rotatorTTL {
+		return nil, ErrRefreshTTL
+	}
+	m := &Manager{config: config, keyfunc: config.Keyfunc}
+
+	header := map[string]interface{}{"typ": jwt.TypeAccessToken}
+	if config.KeyID != "" {
+		header["kid"] = config.KeyID
+	}
+	var err error
+	if m.access, err = jwt.NewSigner(config.Method, config.Key, header); err != nil {
+		return nil, err
+	}
+	header["typ"] = TypeRefreshToken
+	if m.refresh, err = jwt.NewSigner(config.Method, config.Key, header); err != nil {
+		return nil, err
+	}
+
+	if m.keyfunc == nil {
+		key := config.Key
+		if signer, ok := key.(crypto.Signer); ok {
+			key = signer.Public()
+		}
+		m.keyfunc = func(*jwt.Token) (interface{}, error) { return key, nil }
+	}
+	m.parser = jwt.NewParser(jwt.WithValidTypes(TypeRefreshToken), jwt.WithValidMethods(config.Method.Alg()))
+	m.parser.Validator = jwt.NewValidator(jwt.WithPolicy(jwt.Policy{
+		Issuers:           []string{config.Issuer},
+		Audiences:         []string{config.Issuer},
+		RequireExpiration: true,
+		RequiredClaims:    []string{"sid", "family"},
+	}))
+	return m, nil
+}
+
+// Issue starts a new session for subject and returns its first pair.
+func (m *Manager) Issue(ctx context.Context, subject string) (*Pair, error) {
+	sid, err := jwt.NewID()
+	if err != nil {
+		return nil, err
+	}
+	claims := m.refreshClaims(subject, sid)
+	if err = refresh.NewFamily(claims); err != nil {
+		return nil, err
+	}
+	return m.pair(claims)
+}
+
+// Refresh redeems refreshToken for a new pair of the same session. The
+// refresh token must be valid and not rotated before; a rotated token
+// presented again fails with refresh.ErrTokenReused and revokes the session.
+func (m *Manager) Refresh(ctx context.Context, refreshToken string) (*Pair, error) {
+	current, err := m.parse(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	next := m.refreshClaims(current.Subject, current.SessionID)
+	if err = m.config.Rotator.Rotate(ctx, current, next); err != nil {
+		return nil, err
+	}
+	return m.pair(next)
+}
+
+// Verify verifies refreshToken and checks that it may still be redeemed,
+// without rotating it.
+func (m *Manager) Verify(ctx context.Context, refreshToken string) (*refresh.Claims, error) {
+	claims, err := m.parse(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if err = m.config.Rotator.Check(ctx, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Revoke ends the session of refreshToken, such as on logout: no refresh
+// token of the session can be redeemed afterwards.
+func (m *Manager) Revoke(ctx context.Context, refreshToken string) error {
+	claims, err := m.Verify(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+	return m.config.Rotator.RevokeFamily(ctx, claims.Family)
+}
+
+// parse verifies refreshToken and its claims, which must name a session and
+// family, leaving the checks of the Rotator to the caller.
+func (m *Manager) parse(ctx context.Context, refreshToken string) (*refresh.Claims, error) {
+	claims := new(refresh.Claims)
+	if _, err := m.parser.ParseWithContext(ctx, refreshToken, claims, m.keyfunc); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (m *Manager) refreshClaims(subject, sid string) *refresh.Claims {
+	now := jwt.TimeFunc()
+	return &refresh.Claims{RegisteredClaims: jwt.RegisteredClaims{
+		Issuer:    m.config.Issuer,
+		Subject:   subject,
+		Audience:  jwt.ClaimStrings{m.config.Issuer},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(m.config.RefreshTTL)),
+		SessionID: sid,
+	}}
+}
+
+// pair signs the refresh token with claims, and an access token of the same
+// subject and session.
+func (m *Manager) pair(claims *refresh.Claims) (*Pair, error) {
+	id, err := jwt.NewID()
+	if err != nil {
+		return nil, err
+	}
+	now := claims.IssuedAt.Time
+	access := &jwt.RegisteredClaims{
+		Issuer:    m.config.Issuer,
+		Subject:   claims.Subject,
+		Audience:  m.config.Audience,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(m.config.AccessTTL)),
+		ID:        id,
+		SessionID: claims.SessionID,
+	}
+	p := &Pair{AccessClaims: access, RefreshClaims: claims}
+	if p.AccessToken, err = m.access.Sign(access); err != nil {
+		return nil, err
+	}
+	if p.RefreshToken, err = m.refresh.Sign(claims); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
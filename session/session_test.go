@@ -0,0 +1,133 @@
+package session_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/kvstore"
+	"github.com/chanced/go-jwt/v4/refresh"
+	"github.com/chanced/go-jwt/v4/session"
+)
+
+const issuer = "https://auth.example.com"
+
+func newManager(t *testing.T, onReuse func(context.Context, *refresh.Claims)) (*session.Manager, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := session.New(session.Config{
+		Issuer:   issuer,
+		Audience: []string{"https://api.example.com"},
+		Method:   jwt.SigningMethodES256,
+		Key:      key,
+		KeyID:    "k1",
+		Rotator:  &refresh.Rotator{Store: new(kvstore.Memory), OnReuse: onReuse},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, key
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	var reused int
+	m, key := newManager(t, func(context.Context, *refresh.Claims) { reused++ })
+
+	first, err := m.Issue(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ExpiresIn() != session.DefaultAccessTTL {
+		t.Errorf("ExpiresIn = %v, want %v", first.ExpiresIn(), session.DefaultAccessTTL)
+	}
+	if first.AccessClaims.SessionID == "" || first.AccessClaims.SessionID != first.RefreshClaims.SessionID {
+		t.Errorf("Expected the tokens to share a session: %+v %+v", first.AccessClaims, first.RefreshClaims)
+	}
+
+	// The access token is typed as such and cannot be refreshed.
+	access, err := jwt.NewParser(jwt.WithAccessTokenType()).ParseWithClaims(first.AccessToken, &jwt.RegisteredClaims{}, func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil })
+	if err != nil || access.Header["kid"] != "k1" {
+		t.Errorf("Unexpected access token: %v %v", access, err)
+	}
+	if _, err = m.Refresh(ctx, first.AccessToken); !errors.Is(err, jwt.ErrTokenInvalidType) {
+		t.Errorf("Expected ErrTokenInvalidType, got %v", err)
+	}
+
+	second, err := m.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Unexpected error refreshing: %v", err)
+	}
+	if second.RefreshClaims.SessionID != first.RefreshClaims.SessionID || second.RefreshClaims.ParentID != first.RefreshClaims.ID {
+		t.Errorf("Unexpected refreshed claims: %+v", second.RefreshClaims)
+	}
+	if second.AccessClaims.Subject != "user" || second.AccessClaims.ID == first.AccessClaims.ID {
+		t.Errorf("Unexpected refreshed access claims: %+v", second.AccessClaims)
+	}
+
+	// Presenting the first refresh token again revokes the session.
+	if _, err = m.Refresh(ctx, first.RefreshToken); !errors.Is(err, refresh.ErrTokenReused) {
+		t.Errorf("Expected ErrTokenReused, got %v", err)
+	}
+	if reused != 1 {
+		t.Errorf("Expected OnReuse to be called once, got %d", reused)
+	}
+	if _, err = m.Refresh(ctx, second.RefreshToken); !errors.Is(err, refresh.ErrFamilyRevoked) {
+		t.Errorf("Expected ErrFamilyRevoked, got %v", err)
+	}
+}
+
+func TestManager_Revoke(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newManager(t, nil)
+	p, err := m.Issue(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = m.Revoke(ctx, p.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.Refresh(ctx, p.RefreshToken); !errors.Is(err, refresh.ErrFamilyRevoked) {
+		t.Errorf("Expected ErrFamilyRevoked, got %v", err)
+	}
+}
+
+func TestManager_expired(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newManager(t, nil)
+	p, err := m.Issue(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return time.Now().Add(session.DefaultRefreshTTL + time.Hour) }
+	if _, err = m.Refresh(ctx, p.RefreshToken); !errors.Is(err, jwt.ErrTokenExpired) {
+		t.Errorf("Expected ErrTokenExpired, got %v", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := session.New(session.Config{Rotator: new(refresh.Rotator)}); !errors.Is(err, session.ErrMissingIssuer) {
+		t.Errorf("Expected ErrMissingIssuer, got %v", err)
+	}
+	if _, err := session.New(session.Config{Issuer: issuer}); !errors.Is(err, session.ErrMissingRotator) {
+		t.Errorf("Expected ErrMissingRotator, got %v", err)
+	}
+	// Revocations would lapse while tokens of the family are still valid.
+	config := session.Config{Issuer: issuer, Method: jwt.SigningMethodHS256, Key: []byte("secret"), Rotator: &refresh.Rotator{TTL: time.Hour}}
+	if _, err := session.New(config); !errors.Is(err, session.ErrRefreshTTL) {
+		t.Errorf("Expected ErrRefreshTTL, got %v", err)
+	}
+	config.RefreshTTL = time.Hour
+	if _, err := session.New(config); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"time"
+)
+
+// Signer signs tokens with a fixed method, key and header. The header is
+// encoded once, when the Signer is created, so issuing a token only costs
+// encoding its claims and computing the signature. A Signer is safe for
+// concurrent use.
+type Signer struct {
+	method SigningMethod
+	key    interface{}
+	header []byte // The encoded header segment
+	hooks  Hooks
+}
+
+// NewSigner returns a Signer for method and key. header holds additional
+// header parameters, such as "kid"; "typ" defaults to "JWT", the parameters
+// of a HeaderSigningMethod are included as by NewWithClaims, and "alg" is
+// always set to match method. The type of key is checked for the built-in
+// methods, without signing, so that an unusable key is reported here rather
+// than on every Sign.
+func NewSigner(method SigningMethod, key interface{}, header map[string]interface{}) (*Signer, error) {
+	h := make(map[string]interface{}, len(header)+2)
+	h["typ"] = "JWT"
+	if hm, ok := method.(HeaderSigningMethod); ok {
+		for k, v := range hm.SigningHeader() {
+			h[k] = v
+		}
+	}
+	for k, v := range header {
+		h[k] = v
+	}
+	h["alg"] = method.Alg()
+
+	b, ok := appendStringHeader(nil, h)
+	if !ok || customJSON {
+		var err error
+		if b, err = jsonMarshal(h); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkSigningKeyType(method, key); err != nil {
+		return nil, err
+	}
+	return &Signer{method: method, key: key, header: appendSegment(nil, b)}, nil
+}
+
+// checkSigningKeyType checks that key is a signing key for method. Keys
+// held elsewhere, such as by a KMS, are only asked for their public key.
+func checkSigningKeyType(method SigningMethod, key interface{}) error {
+	ok := false
+	signer, isSigner := key.(crypto.Signer)
+	switch m := method.(type) {
+	case *SigningMethodHMAC:
+		_, ok = hmacSecrets(key)
+	case *SigningMethodRSA, *SigningMethodRSAPSS:
+		if isSigner {
+			_, ok = signer.Public().(*rsa.PublicKey)
+		}
+	case *SigningMethodECDSA:
+		if isSigner {
+			var pub *ecdsa.PublicKey
+			if pub, ok = signer.Public().(*ecdsa.PublicKey); ok {
+				ok = pub.Curve.Params().BitSize == m.CurveBits
+			}
+		}
+	case *SigningMethodEd25519:
+		if isSigner {
+			_, ok = signer.Public().(ed25519.PublicKey)
+		}
+	default:
+		// Custom signing methods check the key when signing
+		ok = true
+	}
+	if !ok {
+		return ErrInvalidKeyType
+	}
+	return nil
+}
+
+// Method returns the signing method of s.
+func (s *Signer) Method() SigningMethod {
+	return s.method
+}
+
+// Sign returns the complete, signed token carrying claims.
+func (s *Signer) Sign(claims Claims) (string, error) {
+	bp := signBuffers.Get().(*[]byte)
+	defer signBuffers.Put(bp)
+	b, err := s.AppendSign((*bp)[:0], claims)
+	if err != nil {
+		return "", err
+	}
+	*bp = b
+	return string(b), nil
+}
+
+// AppendSign appends the complete, signed token carrying claims to dst and
+// returns the extended buffer.
+func (s *Signer) AppendSign(dst []byte, claims Claims) ([]byte, error) {
+	if s.hooks.OnSign == nil {
+		return s.appendSign(dst, claims)
+	}
+	start := time.Now()
+	dst, err := s.appendSign(dst, claims)
+	s.hooks.OnSign(SignEvent{Start: start, Duration: time.Since(start), Algorithm: s.method.Alg(), Err: err, Failure: FailureCategory(err)})
+	return dst, err
+}
+
+func (s *Signer) appendSign(dst []byte, claims Claims) ([]byte, error) {
+	start := len(dst)
+	claimsJSON, err := marshalClaims(claims)
+	if err != nil {
+		return dst, err
+	}
+	dst = append(dst, s.header...)
+	dst = append(dst, '.')
+	dst = appendSegment(dst, claimsJSON)
+	sig, err := s.method.Sign(string(dst[start:]), s.key)
+	if err != nil {
+		return dst[:start], err
+	}
+	dst = append(dst, '.')
+	return append(dst, sig...), nil
+}
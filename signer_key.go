@@ -0,0 +1,13 @@
+package jwt
+
+import "crypto"
+
+// signWithSigner signs digest with signer, which may hold its private key
+// outside the process, as a KMS or an HSM does.
+func signWithSigner(signer crypto.Signer, digest []byte, opts crypto.SignerOpts) (string, error) {
+	sig, err := signer.Sign(RandReader, digest, opts)
+	if err != nil {
+		return "", err
+	}
+	return EncodeSegment(sig), nil
+}
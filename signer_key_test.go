@@ -0,0 +1,65 @@
+package jwt_test
+
+import (
+	"crypto"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/test"
+)
+
+// opaqueSigner hides the concrete type of a private key, as a KMS or HSM
+// backed crypto.Signer does.
+type opaqueSigner struct{ crypto.Signer }
+
+func TestSign_cryptoSigner(t *testing.T) {
+	rsaKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	ec256 := loadECPrivateKey(t, "test/ec256-private.pem")
+	ec384 := loadECPrivateKey(t, "test/ec384-private.pem")
+
+	var tests = []struct {
+		method jwt.SigningMethod
+		key    crypto.Signer
+		err    error
+	}{
+		{jwt.SigningMethodRS256, rsaKey, nil},
+		{jwt.SigningMethodPS256, rsaKey, nil},
+		{jwt.SigningMethodES256, ec256, nil},
+		{jwt.SigningMethodES384, ec384, nil},
+		{jwt.SigningMethodES256, ec384, jwt.ErrInvalidKey},
+		{jwt.SigningMethodES256, rsaKey, jwt.ErrInvalidKeyType},
+		{jwt.SigningMethodRS256, ec256, jwt.ErrInvalidKey},
+	}
+	for _, data := range tests {
+		name := data.method.Alg()
+		token, err := jwt.NewWithClaims(data.method, jwt.MapClaims{"sub": "a"}).SignedString(opaqueSigner{data.key})
+		if data.err != nil {
+			if !errors.Is(err, data.err) {
+				t.Errorf("[%v] Expected %v, got %v", name, data.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%v] Error signing: %v", name, err)
+			continue
+		}
+		if _, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return data.key.Public(), nil }); err != nil {
+			t.Errorf("[%v] Error verifying: %v", name, err)
+		}
+	}
+}
+
+func loadECPrivateKey(t *testing.T, path string) crypto.Signer {
+	t.Helper()
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
@@ -0,0 +1,126 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestSigner(t *testing.T) {
+	signer, err := jwt.NewSigner(jwt.SigningMethodHS256, hmacTestKey, map[string]interface{}{"kid": "k1", "alg": "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := jwt.MapClaims{"sub": "alice"}
+	signed, err := signer.Sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The result matches signing a Token with the same header.
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = "k1"
+	want, err := token.SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed != want {
+		t.Errorf("got %q, want %q", signed, want)
+	}
+
+	parsed, err := jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Header["alg"] != "HS256" || parsed.Header["kid"] != "k1" {
+		t.Errorf("unexpected header %v", parsed.Header)
+	}
+}
+
+// headerMethod is HS256 requiring a header parameter.
+type headerMethod struct{ *jwt.SigningMethodHMAC }
+
+func (headerMethod) SigningHeader() map[string]interface{} {
+	return map[string]interface{}{"ext": "required"}
+}
+
+func TestSigner_headerSigningMethod(t *testing.T) {
+	method := headerMethod{jwt.SigningMethodHS256}
+	signer, err := jwt.NewSigner(method, hmacTestKey, map[string]interface{}{"kid": "k1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := jwt.MapClaims{"sub": "alice"}
+	signed, err := signer.Sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := jwt.NewWithClaims(method, claims, jwt.WithKeyID("k1")).SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed != want {
+		t.Errorf("got %q, want %q", signed, want)
+	}
+}
+
+func TestNewSigner_invalidKey(t *testing.T) {
+	_, err := jwt.NewSigner(jwt.SigningMethodHS256, "not a []byte", nil)
+	if !errors.Is(err, jwt.ErrInvalidKeyType) {
+		t.Errorf("got %v, want %v", err, jwt.ErrInvalidKeyType)
+	}
+}
+
+// countingSigner counts the signatures made with its key, as a KMS would bill.
+type countingSigner struct {
+	crypto.Signer
+	signed int
+}
+
+func (s *countingSigner) Sign(r io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	s.signed++
+	return s.Signer.Sign(r, digest, opts)
+}
+
+func TestNewSigner_keyType(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := &countingSigner{Signer: key}
+	if _, err := jwt.NewSigner(jwt.SigningMethodES256, remote, nil); err != nil {
+		t.Fatal(err)
+	}
+	if remote.signed != 0 {
+		t.Errorf("Expected no signature from NewSigner. Got: %d", remote.signed)
+	}
+	if _, err := jwt.NewSigner(jwt.SigningMethodES384, remote, nil); !errors.Is(err, jwt.ErrInvalidKeyType) {
+		t.Errorf("got %v, want %v", err, jwt.ErrInvalidKeyType)
+	}
+	if _, err := jwt.NewSigner(jwt.SigningMethodRS256, key, nil); !errors.Is(err, jwt.ErrInvalidKeyType) {
+		t.Errorf("got %v, want %v", err, jwt.ErrInvalidKeyType)
+	}
+}
+
+func BenchmarkSigner_Sign(b *testing.B) {
+	signer, err := jwt.NewSigner(jwt.SigningMethodHS256, hmacTestKey, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	claims := jwt.MapClaims{}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := signer.Sign(claims); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
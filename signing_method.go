@@ -1,13 +1,15 @@
 package jwt
 
 import (
+	"sort"
 	"sync"
 )
 
 type signingMethodFunc = func() SigningMethod
 
-var signingMethods = map[string]signingMethodFunc{}
-var signingMethodsMutex = new(sync.Mutex)
+// signingMethods is the global registry, consulted by GetSigningMethod and by
+// Parsers without a registry of their own.
+var signingMethods = new(SigningMethodRegistry)
 
 // SigningMethod can be used add new methods for signing or verifying tokens.
 type SigningMethod interface {
@@ -16,23 +18,89 @@ type SigningMethod interface {
 	Alg() string                                                   // returns the alg identifier for this method (example: 'HS256')
 }
 
+// SigningMethodRegistry maps "alg" names to signing methods. It is safe for
+// concurrent use. The zero value is an empty registry.
+//
+// A registry may be set as Parser.SigningMethods to scope the algorithms a
+// Parser resolves, such as those a tenant of a multi-tenant server may use,
+// or to override the implementation of an algorithm for that Parser only.
+type SigningMethodRegistry struct {
+	mu      sync.RWMutex
+	methods map[string]signingMethodFunc
+}
+
+// NewSigningMethodRegistry returns a registry holding methods, each under the
+// name returned by its Alg method.
+func NewSigningMethodRegistry(methods ...SigningMethod) *SigningMethodRegistry {
+	r := new(SigningMethodRegistry)
+	for _, m := range methods {
+		m := m
+		r.Register(m.Alg(), func() SigningMethod { return m })
+	}
+	return r
+}
+
+// Register registers the "alg" name and a factory function for the signing
+// method, replacing any method registered under that name.
+func (r *SigningMethodRegistry) Register(alg string, f func() SigningMethod) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.methods == nil {
+		r.methods = map[string]signingMethodFunc{}
+	}
+	r.methods[alg] = f
+}
+
+// Unregister removes the method registered under alg, if any.
+func (r *SigningMethodRegistry) Unregister(alg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.methods, alg)
+}
+
+// Get retrieves the signing method registered under alg, or nil.
+func (r *SigningMethodRegistry) Get(alg string) SigningMethod {
+	r.mu.RLock()
+	f, ok := r.methods[alg]
+	r.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return f()
+}
+
+// Algorithms returns the registered "alg" names, sorted.
+func (r *SigningMethodRegistry) Algorithms() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	algs := make([]string, 0, len(r.methods))
+	for alg := range r.methods {
+		algs = append(algs, alg)
+	}
+	sort.Strings(algs)
+	return algs
+}
+
 // RegisterSigningMethod registers the "alg" name and a factory function for signing method.
 // This is typically done during init() in the method's implementation
 func RegisterSigningMethod(alg string, f func() SigningMethod) {
-	signingMethodsMutex.Lock()
-	defer signingMethodsMutex.Unlock()
-	copy := map[string]signingMethodFunc{}
-	for k, sm := range signingMethods {
-		copy[k] = sm
-	}
-	copy[alg] = f
-	signingMethods = copy
+	signingMethods.Register(alg, f)
+}
+
+// UnregisterSigningMethod removes the signing method registered under alg
+// from the global registry, so that no Parser without a registry of its own
+// accepts it.
+func UnregisterSigningMethod(alg string) {
+	signingMethods.Unregister(alg)
 }
 
 // GetSigningMethod retrieves a signing method from an "alg" string
 func GetSigningMethod(alg string) SigningMethod {
-	if methodF, ok := signingMethods[alg]; ok {
-		return methodF()
-	}
-	return nil
+	return signingMethods.Get(alg)
+}
+
+// GetAlgorithms returns the "alg" names of the globally registered signing
+// methods, sorted.
+func GetAlgorithms() []string {
+	return signingMethods.Algorithms()
 }
@@ -0,0 +1,94 @@
+package jwt_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestGetAlgorithms(t *testing.T) {
+	algs := jwt.GetAlgorithms()
+	for _, want := range []string{"ES256", "EdDSA", "HS256", "PS512", "RS256", "none"} {
+		found := false
+		for _, alg := range algs {
+			found = found || alg == want
+		}
+		if !found {
+			t.Errorf("GetAlgorithms() = %v, missing %q", algs, want)
+		}
+	}
+	for i := 1; i < len(algs); i++ {
+		if algs[i-1] > algs[i] {
+			t.Fatalf("GetAlgorithms() = %v, not sorted", algs)
+		}
+	}
+}
+
+func TestUnregisterSigningMethod(t *testing.T) {
+	method := &jwt.SigningMethodHMAC{Name: "HS256-test", Hash: jwt.SigningMethodHS256.Hash}
+	jwt.RegisterSigningMethod(method.Alg(), func() jwt.SigningMethod { return method })
+	if jwt.GetSigningMethod("HS256-test") != method {
+		t.Fatal("method was not registered")
+	}
+	jwt.UnregisterSigningMethod("HS256-test")
+	if m := jwt.GetSigningMethod("HS256-test"); m != nil {
+		t.Errorf("GetSigningMethod() = %v after unregistering", m)
+	}
+}
+
+func TestSigningMethodRegistry_concurrent(t *testing.T) {
+	r := jwt.NewSigningMethodRegistry(jwt.SigningMethodHS256)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r.Register("HS384", func() jwt.SigningMethod { return jwt.SigningMethodHS384 })
+				r.Get("HS256")
+				r.Algorithms()
+				r.Unregister("HS384")
+			}
+		}()
+	}
+	wg.Wait()
+	if algs := r.Algorithms(); len(algs) != 1 || algs[0] != "HS256" {
+		t.Errorf("Algorithms() = %v", algs)
+	}
+}
+
+func TestParser_SigningMethods(t *testing.T) {
+	key := []byte("secret")
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+	hs256, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "a"}).SignedString(key)
+	hs512, _ := jwt.NewWithClaims(jwt.SigningMethodHS512, jwt.MapClaims{"sub": "a"}).SignedString(key)
+
+	// A tenant scoped to HS256.
+	p := jwt.NewParser(jwt.WithSigningMethods(jwt.NewSigningMethodRegistry(jwt.SigningMethodHS256)))
+	if _, err := p.Parse(hs256, keyFunc); err != nil {
+		t.Errorf("HS256: unexpected error: %v", err)
+	}
+	if _, err := p.Parse(hs512, keyFunc); !errors.Is(err, jwt.ErrUnregisteredSigningMethod) {
+		t.Errorf("HS512: expected ErrUnregisteredSigningMethod, got %v", err)
+	}
+	if _, err := new(jwt.Parser).Parse(hs512, keyFunc); err != nil {
+		t.Errorf("HS512 with the global registry: unexpected error: %v", err)
+	}
+
+	// Overriding the implementation of an algorithm for one Parser.
+	override := jwt.NewSigningMethodRegistry()
+	override.Register("HS256", func() jwt.SigningMethod { return rejectingMethod{} })
+	if _, err := jwt.NewParser(jwt.WithSigningMethods(override)).Parse(hs256, keyFunc); !errors.Is(err, errRejected) {
+		t.Errorf("override: expected errRejected, got %v", err)
+	}
+}
+
+var errRejected = errors.New("rejected")
+
+type rejectingMethod struct{}
+
+func (rejectingMethod) Verify(string, string, interface{}) error { return errRejected }
+func (rejectingMethod) Sign(string, interface{}) (string, error) { return "", errRejected }
+func (rejectingMethod) Alg() string                              { return "HS256" }
@@ -0,0 +1,14 @@
+// Package template implements claims templates: JSON claims sets containing
+// placeholders which are compiled once and rendered for each token issued.
+//
+//	tmpl, err := template.New(`{"sub":"{{.UserID}}","tenant":"{{.Tenant}}","roles":"{{.Roles}}"}`)
+//	...
+//	claims, err := tmpl.Claims(User{UserID: "42", Tenant: "acme", Roles: []string{"admin"}})
+//	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+//
+// Placeholders name a field of the data, as in text/template, and may only
+// appear inside JSON strings. A placeholder making up an entire string is
+// replaced by the JSON encoding of the value, so "{{.Roles}}" above renders as
+// an array. A placeholder embedded in other text is replaced by the value's
+// text, which must be a string, a number, a bool or a fmt.Stringer.
+package template
@@ -0,0 +1,301 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Error constants
+var (
+	ErrInvalidTemplate = errors.New("template: invalid claims template")
+	ErrMissingField    = errors.New("template: data has no such field")
+	ErrFieldType       = errors.New("template: field cannot be embedded in a string")
+)
+
+// Template is a compiled claims template. It is safe for concurrent use.
+type Template struct {
+	segments []segment
+	size     int // size of the literal text, used to size the output
+}
+
+type segment struct {
+	literal []byte
+	path    []string // placeholder path, if not a literal
+	whole   bool     // the placeholder makes up an entire JSON string
+}
+
+// New compiles a claims template.
+func New(src string) (*Template, error) {
+	t := &Template{}
+	var (
+		inString bool
+		escaped  bool
+		start    int
+	)
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case !inString:
+			if c == '"' {
+				inString = true
+			} else if c == '{' && strings.HasPrefix(src[i:], "{{") {
+				return nil, fmt.Errorf("%w: placeholder outside of a string at offset %d", ErrInvalidTemplate, i)
+			}
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			inString = false
+		case c == '{' && strings.HasPrefix(src[i:], "{{"):
+			end := strings.Index(src[i:], "}}")
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated placeholder at offset %d", ErrInvalidTemplate, i)
+			}
+			path, err := parsePath(src[i+2 : i+end])
+			if err != nil {
+				return nil, err
+			}
+			end += i + 2
+			whole := src[i-1] == '"' && end < len(src) && src[end] == '"'
+			if whole {
+				// The quotes are replaced along with the placeholder.
+				t.addLiteral(src[start : i-1])
+				start = end + 1
+				inString = false
+			} else {
+				t.addLiteral(src[start:i])
+				start = end
+			}
+			t.segments = append(t.segments, segment{path: path, whole: whole})
+			i = start - 1
+		}
+	}
+	t.addLiteral(src[start:])
+
+	// Check the template is valid JSON once the placeholders are filled in.
+	var probe bytes.Buffer
+	for _, s := range t.segments {
+		switch {
+		case s.path == nil:
+			probe.Write(s.literal)
+		case s.whole:
+			probe.WriteString("null")
+		}
+	}
+	var v map[string]interface{}
+	if err := json.Unmarshal(probe.Bytes(), &v); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return t, nil
+}
+
+// Must is a helper that wraps a call to New and panics if the error is
+// non-nil. It is intended for templates compiled during initialization.
+func Must(t *Template, err error) *Template {
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func parsePath(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, ".") || len(s) < 2 {
+		return nil, fmt.Errorf("%w: placeholder %q must name a field, such as {{.UserID}}", ErrInvalidTemplate, s)
+	}
+	path := strings.Split(s[1:], ".")
+	for _, p := range path {
+		if p == "" {
+			return nil, fmt.Errorf("%w: placeholder %q is malformed", ErrInvalidTemplate, s)
+		}
+	}
+	return path, nil
+}
+
+func (t *Template) addLiteral(s string) {
+	if s == "" {
+		return
+	}
+	t.segments = append(t.segments, segment{literal: []byte(s)})
+	t.size += len(s)
+}
+
+// Check verifies that data, typically the zero value of the type later passed
+// to Render, has a field of a suitable type for every placeholder. Calling it
+// at startup surfaces template mistakes before the first token is issued.
+// Map values cannot be checked until rendered.
+func (t *Template) Check(data interface{}) error {
+	typ := reflect.TypeOf(data)
+	for _, s := range t.segments {
+		if s.path == nil {
+			continue
+		}
+		ft, err := fieldType(typ, s.path)
+		if err != nil {
+			return err
+		}
+		if !s.whole && ft != nil && !embeddable(ft) {
+			return fmt.Errorf("%w: .%s of type %v", ErrFieldType, strings.Join(s.path, "."), ft)
+		}
+	}
+	return nil
+}
+
+// fieldType resolves path on typ. It returns nil if the type cannot be known
+// before rendering, such as for map values.
+func fieldType(typ reflect.Type, path []string) (reflect.Type, error) {
+	for i, name := range path {
+		for typ != nil && typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ == nil {
+			return nil, fmt.Errorf("%w: .%s", ErrMissingField, strings.Join(path[:i+1], "."))
+		}
+		switch typ.Kind() {
+		case reflect.Map, reflect.Interface:
+			return nil, nil
+		case reflect.Struct:
+			f, ok := typ.FieldByName(name)
+			if !ok || f.PkgPath != "" {
+				return nil, fmt.Errorf("%w: .%s", ErrMissingField, strings.Join(path[:i+1], "."))
+			}
+			typ = f.Type
+		default:
+			return nil, fmt.Errorf("%w: .%s", ErrMissingField, strings.Join(path[:i+1], "."))
+		}
+	}
+	return typ, nil
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+func embeddable(typ reflect.Type) bool {
+	if typ.Implements(stringerType) {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// Render renders the template with data, a struct, a map with string keys, or
+// a pointer to either, returning the JSON claims set.
+func (t *Template) Render(data interface{}) ([]byte, error) {
+	root := reflect.ValueOf(data)
+	buf := bytes.NewBuffer(make([]byte, 0, t.size+32*len(t.segments)))
+	for _, s := range t.segments {
+		if s.path == nil {
+			buf.Write(s.literal)
+			continue
+		}
+		v, err := t.lookup(root, s.path)
+		if err != nil {
+			return nil, err
+		}
+		if s.whole {
+			b, err := json.Marshal(v.Interface())
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(b)
+			continue
+		}
+		text, err := valueText(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: .%s", err, strings.Join(s.path, "."))
+		}
+		b, _ := json.Marshal(text)
+		buf.Write(b[1 : len(b)-1])
+	}
+	return buf.Bytes(), nil
+}
+
+// Claims renders the template with data, returning claims which may be passed
+// to jwt.NewWithClaims.
+func (t *Template) Claims(data interface{}) (*Claims, error) {
+	b, err := t.Render(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Claims{raw: b}, nil
+}
+
+func (t *Template) lookup(v reflect.Value, path []string) (reflect.Value, error) {
+	for i, name := range path {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}, fmt.Errorf("%w: .%s is nil", ErrMissingField, strings.Join(path[:i], "."))
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			f, ok := v.Type().FieldByName(name)
+			if !ok || f.PkgPath != "" {
+				return reflect.Value{}, fmt.Errorf("%w: .%s", ErrMissingField, strings.Join(path[:i+1], "."))
+			}
+			v = v.FieldByIndex(f.Index)
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, fmt.Errorf("%w: .%s", ErrMissingField, strings.Join(path[:i+1], "."))
+			}
+			mv := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !mv.IsValid() {
+				return reflect.Value{}, fmt.Errorf("%w: .%s", ErrMissingField, strings.Join(path[:i+1], "."))
+			}
+			v = mv
+		default:
+			return reflect.Value{}, fmt.Errorf("%w: .%s", ErrMissingField, strings.Join(path[:i+1], "."))
+		}
+	}
+	return v, nil
+}
+
+func valueText(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String(), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	}
+	return "", ErrFieldType
+}
+
+// Claims are rendered claims. They are marshaled as rendered; Valid performs
+// no validation, since the claims are being issued rather than verified.
+type Claims struct {
+	raw []byte
+}
+
+// Valid implements jwt.Claims.
+func (c *Claims) Valid() error {
+	return nil
+}
+
+// MarshalJSON returns the rendered claims set.
+func (c *Claims) MarshalJSON() ([]byte, error) {
+	return c.raw, nil
+}
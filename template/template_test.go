@@ -0,0 +1,104 @@
+package template_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/template"
+)
+
+type user struct {
+	UserID string
+	Tenant string
+	Roles  []string
+	Level  int
+	Meta   map[string]interface{}
+}
+
+func TestTemplate_Render(t *testing.T) {
+	tmpl, err := template.New(`{"sub":"{{.UserID}}","iss":"https://{{.Tenant}}.example.com","roles":"{{.Roles}}","lvl":"level-{{ .Level }}","src":"{{.Meta.source}}","lit":"a \"b\""}`)
+	if err != nil {
+		t.Fatalf("Error compiling template: %v", err)
+	}
+	if err = tmpl.Check(user{}); err != nil {
+		t.Fatalf("Error checking template: %v", err)
+	}
+
+	data := user{UserID: "42", Tenant: "ac\"me", Roles: []string{"admin"}, Level: 3, Meta: map[string]interface{}{"source": "sso"}}
+	b, err := tmpl.Render(&data)
+	if err != nil {
+		t.Fatalf("Error rendering template: %v", err)
+	}
+	var got map[string]interface{}
+	if err = json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Rendered invalid JSON %s: %v", b, err)
+	}
+	expected := map[string]interface{}{
+		"sub":   "42",
+		"iss":   `https://ac"me.example.com`,
+		"roles": []interface{}{"admin"},
+		"lvl":   "level-3",
+		"src":   "sso",
+		"lit":   `a "b"`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Rendered claims mismatch.\nExpecting: %v\nGot: %v", expected, got)
+	}
+
+	claims, err := tmpl.Claims(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Error signing templated claims: %v", err)
+	}
+	parsed, err := jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return []byte("secret"), nil })
+	if err != nil || parsed.Claims.(jwt.MapClaims)["sub"] != "42" {
+		t.Errorf("Unexpected parsed claims %v: %v", parsed.Claims, err)
+	}
+}
+
+func TestTemplate_errors(t *testing.T) {
+	var tests = []struct {
+		name string
+		src  string
+		err  error
+	}{
+		{"placeholder outside string", `{"sub":{{.UserID}}}`, template.ErrInvalidTemplate},
+		{"unterminated placeholder", `{"sub":"{{.UserID"}`, template.ErrInvalidTemplate},
+		{"not a field", `{"sub":"{{UserID}}"}`, template.ErrInvalidTemplate},
+		{"invalid JSON", `{"sub":"{{.UserID}}",}`, template.ErrInvalidTemplate},
+		{"unknown field", `{"sub":"{{.Name}}"}`, template.ErrMissingField},
+		{"slice embedded in string", `{"roles":"r:{{.Roles}}"}`, template.ErrFieldType},
+	}
+
+	for _, data := range tests {
+		tmpl, err := template.New(data.src)
+		if err == nil {
+			err = tmpl.Check(user{})
+		}
+		if !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+
+	tmpl := template.Must(template.New(`{"src":"{{.Meta.missing}}"}`))
+	if _, err := tmpl.Render(user{Meta: map[string]interface{}{}}); !errors.Is(err, template.ErrMissingField) {
+		t.Errorf("Expected ErrMissingField rendering. Got: %v", err)
+	}
+}
+
+func BenchmarkTemplate_Render(b *testing.B) {
+	tmpl := template.Must(template.New(`{"sub":"{{.UserID}}","tenant":"{{.Tenant}}","roles":"{{.Roles}}"}`))
+	data := &user{UserID: "42", Tenant: "acme", Roles: []string{"admin", "user"}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := tmpl.Render(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"math/big"
+)
+
+// Thumbprint computes the JWK thumbprint of key, as described in
+// https://datatracker.ietf.org/doc/html/rfc7638, using hash. RSA, ECDSA and
+// Ed25519 keys are supported, public or private; the thumbprint of a private key
+// is that of its public key. A []byte key is treated as a symmetric ("oct") key.
+func Thumbprint(key interface{}, hash crypto.Hash) ([]byte, error) {
+	if !hash.Available() {
+		return nil, ErrHashUnavailable
+	}
+
+	members, err := jwkMembers(publicKey(key))
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+	h := hash.New()
+	h.Write(b)
+	return h.Sum(nil), nil
+}
+
+// jwkMembers returns the required members of the JWK of a public or symmetric
+// key. Encoded as JSON, which sorts them, they are the input of its thumbprint.
+func jwkMembers(key interface{}) (map[string]string, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return map[string]string{"kty": "RSA", "e": EncodeSegment(big.NewInt(int64(k.E)).Bytes()), "n": EncodeSegment(k.N.Bytes())}, nil
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return map[string]string{"kty": "EC", "crv": k.Curve.Params().Name, "x": EncodeSegment(padBytes(k.X.Bytes(), size)), "y": EncodeSegment(padBytes(k.Y.Bytes(), size))}, nil
+	case ed25519.PublicKey:
+		return map[string]string{"kty": "OKP", "crv": "Ed25519", "x": EncodeSegment(k)}, nil
+	case []byte:
+		return map[string]string{"kty": "oct", "k": EncodeSegment(k)}, nil
+	}
+	return nil, ErrInvalidKeyType
+}
+
+// publicKey returns the public half of key if it is a private key, otherwise
+// key itself.
+func publicKey(key interface{}) interface{} {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey
+	case *ecdsa.PrivateKey:
+		return &k.PublicKey
+	case ed25519.PrivateKey:
+		return k.Public()
+	case crypto.Signer:
+		return k.Public()
+	}
+	return key
+}
+
+func padBytes(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+	return padded
+}
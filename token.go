@@ -2,8 +2,7 @@ package jwt
 
 import (
 	"encoding/base64"
-	"encoding/json"
-	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,46 +15,84 @@ var TimeFunc = time.Now
 // the key for verification.  The function receives the parsed,
 // but unverified Token.  This allows you to use properties in the
 // Header of the token (such as `kid`) to identify which key to use.
+// It may return a *VerificationKeySet to accept any of several keys.
 type Keyfunc func(*Token) (interface{}, error)
 
 // Token represents a JWT Token.  Different fields will be used depending on whether you're
 // creating or parsing/verifying a token.
 type Token struct {
-	Raw       string                 // The raw token.  Populated when you Parse a token
+	Raw       string                 // The raw token.  Populated when you Parse a token.  Never rendered by String
 	Method    SigningMethod          // The signing method used or to be used
 	Header    map[string]interface{} // The first segment of the token
 	Claims    Claims                 // The second segment of the token
 	Signature string                 // The third segment of the token.  Populated when you Parse a token
 	Valid     bool                   // Is the token valid?  Populated when you Parse/Verify a token
+	Unsigned  bool                   // The claims were encrypted but not signed.  See Parser.AllowUnsignedEncryptedTokens
+
+	Abbreviations ClaimAbbreviations // Optional. Claim names abbreviated when signing
+	KeyIDStrategy KeyIDStrategy      // Optional. Sets the "kid" header from the signing key when signing, such as ThumbprintURIKeyID
 }
 
 // New creates a new Token.  Takes a signing method
-func New(method SigningMethod) *Token {
-	return NewWithClaims(method, MapClaims{})
+func New(method SigningMethod, opts ...TokenOption) *Token {
+	return NewWithClaims(method, MapClaims{}, opts...)
 }
 
-func NewWithClaims(method SigningMethod, claims Claims) *Token {
-	return &Token{
-		Header: map[string]interface{}{
-			"typ": "JWT",
-			"alg": method.Alg(),
-		},
+// NewWithClaims creates a new Token with claims. opts set header parameters,
+// such as WithKeyID; "alg" always matches method.
+func NewWithClaims(method SigningMethod, claims Claims, opts ...TokenOption) *Token {
+	header := map[string]interface{}{
+		"typ": "JWT",
+	}
+	if hm, ok := method.(HeaderSigningMethod); ok {
+		for k, v := range hm.SigningHeader() {
+			header[k] = v
+		}
+	}
+	t := &Token{
+		Header: header,
 		Claims: claims,
 		Method: method,
 	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	header["alg"] = method.Alg()
+	return t
 }
 
 // SignedString retrieves the complete, signed token
 func (t *Token) SignedString(key interface{}) (string, error) {
-	var sig, sstr string
-	var err error
-	if sstr, err = t.SigningString(); err != nil {
+	bp := signBuffers.Get().(*[]byte)
+	defer signBuffers.Put(bp)
+	b, err := t.AppendSignedString((*bp)[:0], key)
+	if err != nil {
 		return "", err
 	}
-	if sig, err = t.Method.Sign(sstr, key); err != nil {
-		return "", err
+	*bp = b
+	return string(b), nil
+}
+
+// AppendSignedString appends the complete, signed token to dst and returns the
+// extended buffer. Services minting many tokens can reuse dst across calls to
+// avoid allocating a string per token.
+func (t *Token) AppendSignedString(dst []byte, key interface{}) ([]byte, error) {
+	if t.KeyIDStrategy != nil {
+		if err := t.SetKeyID(t.KeyIDStrategy, key); err != nil {
+			return dst, err
+		}
+	}
+	start := len(dst)
+	dst, err := t.appendSigningString(dst)
+	if err != nil {
+		return dst[:start], err
 	}
-	return strings.Join([]string{sstr, sig}, "."), nil
+	sig, err := t.Method.Sign(string(dst[start:]), key)
+	if err != nil {
+		return dst[:start], err
+	}
+	dst = append(dst, '.')
+	return append(dst, sig...), nil
 }
 
 // SigningString generates the signing string.  This is the
@@ -63,23 +100,105 @@ func (t *Token) SignedString(key interface{}) (string, error) {
 // need this for something special, just go straight for
 // the SignedString.
 func (t *Token) SigningString() (string, error) {
-	var err error
-	parts := make([]string, 2)
-	for i := range parts {
-		var jsonValue []byte
-		if i == 0 {
-			if jsonValue, err = json.Marshal(t.Header); err != nil {
-				return "", err
-			}
-		} else {
-			if jsonValue, err = json.Marshal(t.Claims); err != nil {
-				return "", err
-			}
+	bp := signBuffers.Get().(*[]byte)
+	defer signBuffers.Put(bp)
+	b, err := t.appendSigningString((*bp)[:0])
+	if err != nil {
+		return "", err
+	}
+	*bp = b
+	return string(b), nil
+}
+
+func (t *Token) appendSigningString(dst []byte) ([]byte, error) {
+	var headerJSON []byte
+	var scratch [256]byte
+	if b, ok := appendStringHeader(scratch[:0], t.Header); ok && !customJSON {
+		headerJSON = b
+	} else {
+		var err error
+		if headerJSON, err = jsonMarshal(t.Header); err != nil {
+			return dst, err
+		}
+	}
+	dst = appendSegment(dst, headerJSON)
+	claimsJSON, err := t.marshalClaims()
+	if err != nil {
+		return dst, err
+	}
+	dst = append(dst, '.')
+	return appendSegment(dst, claimsJSON), nil
+}
+
+// signBuffers holds the buffers SignedString and SigningString assemble tokens
+// in before copying them to a string.
+var signBuffers = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 512)
+		return &b
+	},
+}
+
+// appendStringHeader appends the JSON encoding of a small header whose values
+// are all plain strings, as encoding/json would produce it, without the cost of
+// reflection. It reports false for any other header.
+func appendStringHeader(dst []byte, header map[string]interface{}) ([]byte, bool) {
+	var keys [8]string
+	if len(header) > len(keys) {
+		return dst, false
+	}
+	n := 0
+	for k, v := range header {
+		if s, ok := v.(string); !ok || !plainJSONString(k) || !plainJSONString(s) {
+			return dst, false
+		}
+		// Insertion sort, as encoding/json orders map keys.
+		i := n
+		for ; i > 0 && keys[i-1] > k; i-- {
+			keys[i] = keys[i-1]
 		}
+		keys[i] = k
+		n++
+	}
+	dst = append(dst, '{')
+	for i, k := range keys[:n] {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, '"')
+		dst = append(dst, k...)
+		dst = append(dst, '"', ':', '"')
+		dst = append(dst, header[k].(string)...)
+		dst = append(dst, '"')
+	}
+	return append(dst, '}'), true
+}
+
+// plainJSONString reports whether s is encoded by encoding/json without any
+// escaping.
+func plainJSONString(s string) bool {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c < 0x20 || c >= 0x80, c == '"', c == '\\', c == '<', c == '>', c == '&':
+			return false
+		}
+	}
+	return true
+}
 
-		parts[i] = EncodeSegment(jsonValue)
+// appendSegment appends the base64url encoding of seg, with padding stripped,
+// to dst.
+func appendSegment(dst, seg []byte) []byte {
+	n := len(dst)
+	size := base64.RawURLEncoding.EncodedLen(len(seg))
+	if cap(dst)-n < size {
+		grown := make([]byte, n, 2*cap(dst)+size)
+		copy(grown, dst)
+		dst = grown
 	}
-	return strings.Join(parts, "."), nil
+	dst = dst[:n+size]
+	base64.RawURLEncoding.Encode(dst[n:], seg)
+	return dst
 }
 
 // Parse parses, validates, and returns a token.
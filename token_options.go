@@ -0,0 +1,66 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+)
+
+// Values of the "typ" header of explicitly typed tokens, for WithType and
+// Parser.ValidTypes.
+const (
+	TypeJWT          = "JWT"                 // The default, for tokens of no particular kind
+	TypeAccessToken  = "at+jwt"              // OAuth 2.0 access tokens in the profile of RFC 9068
+	TypeLogoutToken  = "logout+jwt"          // OpenID Connect back-channel logout tokens
+	TypeDPoPProof    = "dpop+jwt"            // DPoP proofs of RFC 9449
+	TypeAuthzRequest = "oauth-authz-req+jwt" // Authorization request objects of RFC 9101
+)
+
+// TokenOption configures a Token created with New or NewWithClaims.
+type TokenOption func(*Token)
+
+// WithHeader sets the header parameter name to value. The "alg" parameter
+// cannot be set; it always matches the signing method.
+func WithHeader(name string, value interface{}) TokenOption {
+	return func(t *Token) {
+		t.Header[name] = value
+	}
+}
+
+// WithKeyID sets the "kid" (key ID) header parameter.
+func WithKeyID(kid string) TokenOption {
+	return WithHeader("kid", kid)
+}
+
+// WithType sets the "typ" (type) header parameter, such as "at+jwt". It
+// defaults to "JWT". An empty typ removes the parameter.
+func WithType(typ string) TokenOption {
+	return func(t *Token) {
+		if typ == "" {
+			delete(t.Header, "typ")
+			return
+		}
+		t.Header["typ"] = typ
+	}
+}
+
+// WithContentType sets the "cty" (content type) header parameter, such as
+// "JWT" for nested tokens.
+func WithContentType(cty string) TokenOption {
+	return WithHeader("cty", cty)
+}
+
+// WithCertificateThumbprint sets the "x5t#S256" header parameter to the
+// base64url encoded SHA-256 thumbprint of cert, the certificate of the
+// signing key.
+func WithCertificateThumbprint(cert *x509.Certificate) TokenOption {
+	sum := sha256.Sum256(cert.Raw)
+	return WithHeader("x5t#S256", EncodeSegment(sum[:]))
+}
+
+// WithKeyIDStrategy sets Token.KeyIDStrategy, which sets the "kid"
+// header parameter from the signing key when the token is signed.
+func WithKeyIDStrategy(strategy KeyIDStrategy) TokenOption {
+	return func(t *Token) {
+		t.KeyIDStrategy = strategy
+	}
+}
@@ -0,0 +1,64 @@
+package jwt_test
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"reflect"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/test"
+)
+
+func TestNewWithClaims_options(t *testing.T) {
+	cert := &x509.Certificate{Raw: []byte("certificate")}
+	sum := sha256.Sum256(cert.Raw)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "a"},
+		jwt.WithKeyID("key-1"),
+		jwt.WithType("at+jwt"),
+		jwt.WithContentType("JWT"),
+		jwt.WithCertificateThumbprint(cert),
+		jwt.WithHeader("custom", []string{"a", "b"}),
+		jwt.WithHeader("alg", "none"),
+	)
+	want := map[string]interface{}{
+		"alg":      "HS256",
+		"typ":      "at+jwt",
+		"kid":      "key-1",
+		"cty":      "JWT",
+		"x5t#S256": jwt.EncodeSegment(sum[:]),
+		"custom":   []string{"a", "b"},
+	}
+	if !reflect.DeepEqual(token.Header, want) {
+		t.Errorf("Header = %v, want %v", token.Header, want)
+	}
+
+	signed, err := token.SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Header["kid"] != "key-1" || parsed.Header["typ"] != "at+jwt" {
+		t.Errorf("Unexpected parsed header: %v", parsed.Header)
+	}
+
+	if token = jwt.New(jwt.SigningMethodHS256, jwt.WithType("")); token.Header["typ"] != nil {
+		t.Errorf("WithType(\"\") left typ %v", token.Header["typ"])
+	}
+}
+
+func TestWithKeyIDStrategy(t *testing.T) {
+	priv := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	token := jwt.New(jwt.SigningMethodRS256, jwt.WithKeyIDStrategy(jwt.ThumbprintURIKeyID))
+	if _, err := token.SignedString(priv); err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := jwt.ThumbprintURIKeyID.KeyID(priv)
+	if token.Header["kid"] != expected {
+		t.Errorf("Expected kid %v. Got: %v", expected, token.Header["kid"])
+	}
+}
@@ -0,0 +1,58 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestToken_AppendSignedString(t *testing.T) {
+	headers := []map[string]interface{}{
+		{"alg": "HS256", "typ": "JWT", "kid": "k1"},
+		{"alg": "HS256", "kid": "<escaped>"},
+		{"alg": "HS256", "kid": "café"},
+		{"alg": "HS256", "crit": []string{"exp"}, "exp": true},
+	}
+	for _, header := range headers {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "alice"})
+		token.Header = header
+
+		signed, err := token.SignedString(hmacTestKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prefix := []byte("Bearer ")
+		b, err := token.AppendSignedString(prefix, hmacTestKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != "Bearer "+signed {
+			t.Errorf("got %q, want %q", b, "Bearer "+signed)
+		}
+
+		// The header must be encoded exactly as encoding/json would.
+		want, _ := json.Marshal(header)
+		if got := strings.Split(signed, ".")[0]; got != jwt.EncodeSegment(want) {
+			t.Errorf("header %v: got %q, want %q", header, got, jwt.EncodeSegment(want))
+		}
+		if _, err = jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }); err != nil {
+			t.Errorf("header %v: %v", header, err)
+		}
+	}
+}
+
+func BenchmarkToken_AppendSignedString(b *testing.B) {
+	token := jwt.New(jwt.SigningMethodHS256)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		var buf []byte
+		for pb.Next() {
+			var err error
+			if buf, err = token.AppendSignedString(buf[:0], hmacTestKey); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
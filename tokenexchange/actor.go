@@ -0,0 +1,91 @@
+package tokenexchange
+
+import (
+	"errors"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// Error constants
+var (
+	ErrActorChainTooLong  = errors.New("tokenexchange: actor chain exceeds the maximum length")
+	ErrUntrustedActor     = errors.New("tokenexchange: actor is not trusted")
+	ErrInvalidActor       = errors.New("tokenexchange: actor claim must identify the actor with sub")
+	ErrActorNotAuthorized = errors.New("tokenexchange: actor is not authorized to act for the subject")
+)
+
+// Actor is the "act" (actor) claim, as described in
+// https://datatracker.ietf.org/doc/html/rfc8693#section-4.1. Prior actors in a
+// delegation chain are nested in Actor.
+type Actor struct {
+	Issuer  string `json:"iss,omitempty"`
+	Subject string `json:"sub,omitempty"`
+	Actor   *Actor `json:"act,omitempty"`
+}
+
+// Chain returns the actors, current actor first, followed by each prior actor.
+func (a *Actor) Chain() []*Actor {
+	var chain []*Actor
+	for ; a != nil; a = a.Actor {
+		chain = append(chain, a)
+	}
+	return chain
+}
+
+// Matches reports whether a and b identify the same party: their subjects are
+// equal, and so are their issuers if both are set. Prior actors are ignored.
+func (a *Actor) Matches(b *Actor) bool {
+	if a == nil || b == nil || a.Subject == "" || a.Subject != b.Subject {
+		return false
+	}
+	return a.Issuer == "" || b.Issuer == "" || a.Issuer == b.Issuer
+}
+
+// Claims are the claims of an exchanged token: the registered claims plus
+// "act", "may_act", "scope" and "client_id".
+type Claims struct {
+	jwt.RegisteredClaims
+	Actor    *Actor     `json:"act,omitempty"`
+	MayAct   *Actor     `json:"may_act,omitempty"` // The party authorized to act for the subject, see https://datatracker.ietf.org/doc/html/rfc8693#section-4.4
+	Scope    jwt.Scopes `json:"scope,omitempty"`
+	ClientID string     `json:"client_id,omitempty"`
+}
+
+// GetScopes implements jwt.ScopesGetter.
+func (c *Claims) GetScopes() (jwt.Scopes, error) {
+	return c.Scope, nil
+}
+
+// AuthorizeActor checks that actor may act for the subject of a token with
+// the given claims. If the token carries a "may_act" claim, it must match the
+// actor; otherwise any actor is authorized, and the decision is left to the
+// authorization server's policy.
+func AuthorizeActor(subject *Claims, actor *Actor) error {
+	if actor == nil || actor.Subject == "" {
+		return ErrInvalidActor
+	}
+	if subject.MayAct != nil && !subject.MayAct.Matches(actor) {
+		return ErrActorNotAuthorized
+	}
+	return nil
+}
+
+// ValidateActorChain checks the "act" claim of an exchanged token. Every actor
+// must carry a subject, the chain may hold at most maxDepth actors (unbounded
+// if maxDepth is zero), and trusted, if non-nil, must accept every actor.
+// A nil act is valid; the token was not issued through delegation.
+func ValidateActorChain(act *Actor, maxDepth int, trusted func(*Actor) bool) error {
+	chain := act.Chain()
+	if maxDepth > 0 && len(chain) > maxDepth {
+		return ErrActorChainTooLong
+	}
+	for _, a := range chain {
+		if a.Subject == "" {
+			return ErrInvalidActor
+		}
+		if trusted != nil && !trusted(a) {
+			return ErrUntrustedActor
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package tokenexchange_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4/tokenexchange"
+)
+
+func TestValidateActorChain(t *testing.T) {
+	var claims tokenexchange.Claims
+	raw := `{"sub":"user@example.net","act":{"sub":"admin.example.com","act":{"sub":"svc.example.com"}}}`
+	if err := json.Unmarshal([]byte(raw), &claims); err != nil {
+		t.Fatal(err)
+	}
+
+	trustAll := func(*tokenexchange.Actor) bool { return true }
+	trustAdmin := func(a *tokenexchange.Actor) bool { return a.Subject == "admin.example.com" }
+
+	var tests = []struct {
+		name     string
+		act      *tokenexchange.Actor
+		maxDepth int
+		trusted  func(*tokenexchange.Actor) bool
+		err      error
+	}{
+		{"no actor", nil, 1, nil, nil},
+		{"trusted chain", claims.Actor, 2, trustAll, nil},
+		{"unbounded", claims.Actor, 0, nil, nil},
+		{"too long", claims.Actor, 1, trustAll, tokenexchange.ErrActorChainTooLong},
+		{"untrusted prior actor", claims.Actor, 0, trustAdmin, tokenexchange.ErrUntrustedActor},
+		{"missing subject", &tokenexchange.Actor{Issuer: "x"}, 0, nil, tokenexchange.ErrInvalidActor},
+	}
+
+	for _, data := range tests {
+		if err := tokenexchange.ValidateActorChain(data.act, data.maxDepth, data.trusted); !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+
+	if chain := claims.Actor.Chain(); len(chain) != 2 || chain[1].Subject != "svc.example.com" {
+		t.Errorf("Unexpected chain: %v", chain)
+	}
+}
+
+func TestAuthorizeActor(t *testing.T) {
+	subject := &tokenexchange.Claims{MayAct: &tokenexchange.Actor{Issuer: "https://as.example.com", Subject: "admin@example.net"}}
+
+	var tests = []struct {
+		name    string
+		subject *tokenexchange.Claims
+		actor   *tokenexchange.Actor
+		err     error
+	}{
+		{"authorized", subject, &tokenexchange.Actor{Issuer: "https://as.example.com", Subject: "admin@example.net"}, nil},
+		{"authorized without issuer", subject, &tokenexchange.Actor{Subject: "admin@example.net"}, nil},
+		{"other subject", subject, &tokenexchange.Actor{Subject: "other@example.net"}, tokenexchange.ErrActorNotAuthorized},
+		{"other issuer", subject, &tokenexchange.Actor{Issuer: "https://other.example.com", Subject: "admin@example.net"}, tokenexchange.ErrActorNotAuthorized},
+		{"no may_act", &tokenexchange.Claims{}, &tokenexchange.Actor{Subject: "other@example.net"}, nil},
+		{"missing subject", &tokenexchange.Claims{}, &tokenexchange.Actor{}, tokenexchange.ErrInvalidActor},
+	}
+
+	for _, data := range tests {
+		if err := tokenexchange.AuthorizeActor(data.subject, data.actor); !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package tokenexchange
+
+import (
+	"errors"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// DefaultDelegationLifetime is the lifetime of delegation tokens when
+// Delegation.Lifetime is unset.
+const DefaultDelegationLifetime = 5 * time.Minute
+
+// ErrScopeNotGranted is returned by Delegation if it requests scopes the
+// subject token does not carry.
+var ErrScopeNotGranted = errors.New("tokenexchange: scope exceeds the scope of the subject token")
+
+// Delegation describes the tokens an authorization server issues when an actor
+// exchanges a subject token for a token to act on the subject's behalf, as
+// described in https://datatracker.ietf.org/doc/html/rfc8693#section-1.1
+type Delegation struct {
+	Issuer   string           // The "iss" claim of issued tokens
+	Audience jwt.ClaimStrings // The "aud" claim of issued tokens
+	Lifetime time.Duration    // Optional. Defaults to DefaultDelegationLifetime. Tokens never outlive the subject token
+	Scope    jwt.Scopes       // Optional. Narrows the scope of the subject token, which is kept by default
+	ClientID string           // Optional. The "client_id" claim of issued tokens
+}
+
+// Claims returns the claims of a token issued to actor on behalf of the
+// subject of a token with the given claims. The subject is kept, actor becomes
+// the "act" claim and the actors of the subject token, if any, are nested in
+// it as prior actors. The subject token's "may_act" claim must authorize the
+// actor, as checked by AuthorizeActor.
+func (d *Delegation) Claims(subject *Claims, actor *Actor) (*Claims, error) {
+	if err := AuthorizeActor(subject, actor); err != nil {
+		return nil, err
+	}
+	scope := subject.Scope
+	if len(d.Scope) > 0 {
+		if missing := subject.Scope.Missing(d.Scope...); len(missing) > 0 {
+			return nil, &jwt.ValidationError{Err: ErrScopeNotGranted, Claim: "scope", Expected: subject.Scope.String(), Actual: jwt.Scopes(missing).String()}
+		}
+		scope = d.Scope
+	}
+	id, err := jwt.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	lifetime := d.Lifetime
+	if lifetime <= 0 {
+		lifetime = DefaultDelegationLifetime
+	}
+	now := jwt.TimeFunc()
+	exp := now.Add(lifetime)
+	if subject.ExpiresAt != nil && subject.ExpiresAt.Before(exp) {
+		exp = subject.ExpiresAt.Time
+	}
+
+	return &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    d.Issuer,
+			Subject:   subject.Subject,
+			Audience:  d.Audience,
+			ExpiresAt: jwt.NewNumericDate(exp),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        id,
+		},
+		Actor:    &Actor{Issuer: actor.Issuer, Subject: actor.Subject, Actor: subject.Actor},
+		Scope:    scope,
+		ClientID: d.ClientID,
+	}, nil
+}
+
+// Mint returns a signed token issued to actor on behalf of the subject of a
+// token with the given claims, as described for Claims. opts set header
+// parameters, such as jwt.WithKeyID.
+func (d *Delegation) Mint(method jwt.SigningMethod, key interface{}, subject *Claims, actor *Actor, opts ...jwt.TokenOption) (string, error) {
+	claims, err := d.Claims(subject, actor)
+	if err != nil {
+		return "", err
+	}
+	return jwt.NewWithClaims(method, claims, opts...).SignedString(key)
+}
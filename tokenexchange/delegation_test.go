@@ -0,0 +1,68 @@
+package tokenexchange_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/tokenexchange"
+)
+
+func TestDelegation_Mint(t *testing.T) {
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	now := time.Unix(1700000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	subject := &tokenexchange.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "user@example.net", ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour))},
+		Actor:            &tokenexchange.Actor{Subject: "gateway.example.com"},
+		Scope:            jwt.Scopes{"read", "write"},
+	}
+	actor := &tokenexchange.Actor{Issuer: "https://as.example.com", Subject: "billing.example.com"}
+	d := &tokenexchange.Delegation{Issuer: "https://as.example.com", Audience: jwt.ClaimStrings{"https://api.example.com"}, Scope: jwt.Scopes{"read"}}
+
+	key := []byte("secret")
+	raw, err := d.Mint(jwt.SigningMethodHS256, key, subject, actor)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var claims tokenexchange.Claims
+	if _, err = jwt.ParseWithClaims(raw, &claims, func(*jwt.Token) (interface{}, error) { return key, nil }); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if claims.Subject != "user@example.net" || claims.Issuer != d.Issuer || claims.Scope.String() != "read" || claims.ID == "" {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+	if chain := claims.Actor.Chain(); len(chain) != 2 || !chain[0].Matches(actor) || chain[1].Subject != "gateway.example.com" {
+		t.Errorf("Unexpected actor chain: %+v", chain)
+	}
+	if !claims.ExpiresAt.Equal(now.Add(tokenexchange.DefaultDelegationLifetime)) {
+		t.Errorf("Unexpected exp: %v", claims.ExpiresAt)
+	}
+
+	d.Lifetime = 2 * time.Hour
+	c, err := d.Claims(subject, actor)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !c.ExpiresAt.Equal(subject.ExpiresAt.Time) {
+		t.Errorf("Expected the token not to outlive the subject token, got exp %v", c.ExpiresAt)
+	}
+}
+
+func TestDelegation_Claims_errors(t *testing.T) {
+	subject := &tokenexchange.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "user@example.net"},
+		MayAct:           &tokenexchange.Actor{Subject: "billing.example.com"},
+		Scope:            jwt.Scopes{"read"},
+	}
+	d := &tokenexchange.Delegation{Scope: jwt.Scopes{"read", "admin"}}
+	if _, err := d.Claims(subject, &tokenexchange.Actor{Subject: "billing.example.com"}); !errors.Is(err, tokenexchange.ErrScopeNotGranted) {
+		t.Errorf("Expected ErrScopeNotGranted, got %v", err)
+	}
+	d.Scope = nil
+	if _, err := d.Claims(subject, &tokenexchange.Actor{Subject: "other.example.com"}); !errors.Is(err, tokenexchange.ErrActorNotAuthorized) {
+		t.Errorf("Expected ErrActorNotAuthorized, got %v", err)
+	}
+}
@@ -0,0 +1,9 @@
+// Package tokenexchange implements OAuth 2.0 Token Exchange as described in
+// https://datatracker.ietf.org/doc/html/rfc8693.
+//
+// Client is used to exchange a token at an authorization server, ParseRequest
+// reads an exchange request on the server side, and Claims, Actor and
+// ValidateActorChain handle the "act" (actor) claim of exchanged tokens.
+// AuthorizeActor checks the "may_act" claim of a subject token, and
+// Delegation mints the tokens an actor receives to act for the subject.
+package tokenexchange
@@ -0,0 +1,198 @@
+package tokenexchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// GrantType is the grant_type of a token exchange request.
+const GrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// Token type identifiers, as registered in
+// https://datatracker.ietf.org/doc/html/rfc8693#section-3
+const (
+	TokenTypeAccessToken  = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeRefreshToken = "urn:ietf:params:oauth:token-type:refresh_token"
+	TokenTypeIDToken      = "urn:ietf:params:oauth:token-type:id_token"
+	TokenTypeSAML1        = "urn:ietf:params:oauth:token-type:saml1"
+	TokenTypeSAML2        = "urn:ietf:params:oauth:token-type:saml2"
+	TokenTypeJWT          = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// Error constants
+var (
+	ErrInvalidGrantType    = errors.New("tokenexchange: grant_type is not " + GrantType)
+	ErrMissingSubjectToken = errors.New("tokenexchange: subject_token and subject_token_type are required")
+	ErrMissingActorType    = errors.New("tokenexchange: actor_token_type is required with actor_token")
+)
+
+// Request is a token exchange request, as described in
+// https://datatracker.ietf.org/doc/html/rfc8693#section-2.1
+type Request struct {
+	SubjectToken       string
+	SubjectTokenType   string
+	ActorToken         string
+	ActorTokenType     string
+	RequestedTokenType string
+	Resource           []string
+	Audience           []string
+	Scope              jwt.Scopes
+}
+
+// Validate checks that the parameters required by RFC 8693 are present.
+func (r *Request) Validate() error {
+	if r.SubjectToken == "" || r.SubjectTokenType == "" {
+		return ErrMissingSubjectToken
+	}
+	if r.ActorToken != "" && r.ActorTokenType == "" {
+		return ErrMissingActorType
+	}
+	return nil
+}
+
+// Values encodes the request as form parameters, including grant_type.
+func (r *Request) Values() url.Values {
+	v := url.Values{}
+	v.Set("grant_type", GrantType)
+	v.Set("subject_token", r.SubjectToken)
+	v.Set("subject_token_type", r.SubjectTokenType)
+	if r.ActorToken != "" {
+		v.Set("actor_token", r.ActorToken)
+		v.Set("actor_token_type", r.ActorTokenType)
+	}
+	if r.RequestedTokenType != "" {
+		v.Set("requested_token_type", r.RequestedTokenType)
+	}
+	for _, res := range r.Resource {
+		v.Add("resource", res)
+	}
+	for _, aud := range r.Audience {
+		v.Add("audience", aud)
+	}
+	if len(r.Scope) > 0 {
+		v.Set("scope", r.Scope.String())
+	}
+	return v
+}
+
+// Response is a successful token exchange response, as described in
+// https://datatracker.ietf.org/doc/html/rfc8693#section-2.2.1
+type Response struct {
+	AccessToken     string     `json:"access_token"`
+	IssuedTokenType string     `json:"issued_token_type"`
+	TokenType       string     `json:"token_type"`
+	ExpiresIn       int64      `json:"expires_in,omitempty"`
+	Scope           jwt.Scopes `json:"scope,omitempty"`
+	RefreshToken    string     `json:"refresh_token,omitempty"`
+}
+
+// ErrorResponse is returned by Client.Exchange when the authorization server
+// answers with an error, as described in
+// https://datatracker.ietf.org/doc/html/rfc8693#section-2.2.2
+type ErrorResponse struct {
+	StatusCode int
+	jwt.OAuthError
+}
+
+func (e *ErrorResponse) Error() string {
+	s := fmt.Sprintf("tokenexchange: %s (status %d)", e.Code, e.StatusCode)
+	if e.Description != "" {
+		s += ": " + e.Description
+	}
+	return s
+}
+
+// Client performs token exchange requests against an authorization server.
+type Client struct {
+	Endpoint     string       // The token endpoint
+	ClientID     string       // Optional. Sent with HTTP basic authentication along with ClientSecret
+	ClientSecret string       // Optional
+	HTTPClient   *http.Client // Optional. Defaults to http.DefaultClient
+}
+
+// maxResponseSize bounds the size of token endpoint responses read by Client.
+const maxResponseSize = 1 << 20
+
+// Exchange sends req to the token endpoint and returns the issued token.
+func (c *Client) Exchange(ctx context.Context, req *Request) (*Response, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	hreq, err := http.NewRequest(http.MethodPost, c.Endpoint, strings.NewReader(req.Values().Encode()))
+	if err != nil {
+		return nil, err
+	}
+	hreq = hreq.WithContext(ctx)
+	hreq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	hreq.Header.Set("Accept", "application/json")
+	if c.ClientID != "" {
+		hreq.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	hres, err := client.Do(hreq)
+	if err != nil {
+		return nil, err
+	}
+	defer hres.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(hres.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if hres.StatusCode != http.StatusOK {
+		eres := &ErrorResponse{StatusCode: hres.StatusCode}
+		if json.Unmarshal(body, &eres.OAuthError) != nil || eres.Code == "" {
+			eres.Code = "server_error"
+		}
+		eres.OAuthError.StatusCode = hres.StatusCode
+		return nil, eres
+	}
+
+	res := &Response{}
+	if err = json.Unmarshal(body, res); err != nil {
+		return nil, fmt.Errorf("tokenexchange: invalid response: %w", err)
+	}
+	if res.AccessToken == "" || res.IssuedTokenType == "" || res.TokenType == "" {
+		return nil, errors.New("tokenexchange: response is missing access_token, issued_token_type or token_type")
+	}
+	return res, nil
+}
+
+// ParseRequest reads a token exchange request from the form parameters of r,
+// as received by an authorization server.
+func ParseRequest(r *http.Request) (*Request, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	f := r.PostForm
+	if f.Get("grant_type") != GrantType {
+		return nil, ErrInvalidGrantType
+	}
+	req := &Request{
+		SubjectToken:       f.Get("subject_token"),
+		SubjectTokenType:   f.Get("subject_token_type"),
+		ActorToken:         f.Get("actor_token"),
+		ActorTokenType:     f.Get("actor_token_type"),
+		RequestedTokenType: f.Get("requested_token_type"),
+		Resource:           f["resource"],
+		Audience:           f["audience"],
+		Scope:              jwt.ParseScopes(f.Get("scope")),
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
@@ -0,0 +1,63 @@
+package tokenexchange_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4/tokenexchange"
+)
+
+func TestClient_Exchange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := tokenexchange.ParseRequest(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_request", "error_description": err.Error()})
+			return
+		}
+		if id, secret, _ := r.BasicAuth(); id != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":      "exchanged:" + req.SubjectToken,
+			"issued_token_type": tokenexchange.TokenTypeAccessToken,
+			"token_type":        "Bearer",
+			"scope":             req.Scope.String(),
+		})
+	}))
+	defer srv.Close()
+
+	client := &tokenexchange.Client{Endpoint: srv.URL, ClientID: "client", ClientSecret: "secret"}
+	res, err := client.Exchange(context.Background(), &tokenexchange.Request{
+		SubjectToken:     "subject",
+		SubjectTokenType: tokenexchange.TokenTypeAccessToken,
+		Audience:         []string{"https://backend.example.com"},
+		Scope:            []string{"read"},
+	})
+	if err != nil {
+		t.Fatalf("Error exchanging token: %v", err)
+	}
+	if res.AccessToken != "exchanged:subject" || !res.Scope.Has("read") {
+		t.Errorf("Unexpected response: %+v", res)
+	}
+
+	client.ClientSecret = "wrong"
+	_, err = client.Exchange(context.Background(), &tokenexchange.Request{
+		SubjectToken:     "subject",
+		SubjectTokenType: tokenexchange.TokenTypeAccessToken,
+	})
+	var eres *tokenexchange.ErrorResponse
+	if !errors.As(err, &eres) || eres.Code != "invalid_client" || eres.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected invalid_client error response. Got: %v", err)
+	}
+
+	if _, err = client.Exchange(context.Background(), &tokenexchange.Request{}); !errors.Is(err, tokenexchange.ErrMissingSubjectToken) {
+		t.Errorf("Expected ErrMissingSubjectToken. Got: %v", err)
+	}
+}
@@ -3,8 +3,10 @@ package jwt
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,7 +16,10 @@ import (
 // when serializing.
 //
 // For backwards compatibility the default precision is set to seconds, so that
-// no fractional timestamps are generated.
+// no fractional timestamps are generated: at second precision NumericDate
+// serializes to a JSON integer, as some relying parties reject fractional
+// values. A finer precision, such as time.Millisecond, serializes the
+// fraction exactly, with trailing zeros removed.
 var TimePrecision = time.Second
 
 // MarshalSingleStringAsArray modifies the behaviour of the ClaimStrings type, especially
@@ -41,15 +46,30 @@ func NewNumericDate(t time.Time) *NumericDate {
 // newNumericDateFromSeconds creates a new *NumericDate out of a float64 representing a
 // UNIX epoch with the float fraction representing non-integer seconds.
 func newNumericDateFromSeconds(f float64) *NumericDate {
-	return NewNumericDate(time.Unix(0, int64(f*float64(time.Second))))
+	sec, frac := math.Modf(f)
+	return NewNumericDate(time.Unix(int64(sec), int64(frac*float64(time.Second))))
 }
 
 // MarshalJSON is an implementation of the json.RawMessage interface and serializes the UNIX epoch
 // represented in NumericDate to a byte array, using the precision specified in TimePrecision.
 func (date NumericDate) MarshalJSON() (b []byte, err error) {
-	f := float64(date.Truncate(TimePrecision).UnixNano()) / float64(time.Second)
+	t := date.Truncate(TimePrecision)
 
-	return []byte(strconv.FormatFloat(f, 'f', -1, 64)), nil
+	// The seconds and the fraction are formatted separately, so that no
+	// precision is lost to floating point and whole seconds are integers.
+	sec, nsec := t.Unix(), t.Nanosecond()
+	if nsec == 0 {
+		return strconv.AppendInt(nil, sec, 10), nil
+	}
+	if sec < 0 {
+		sec, nsec = sec+1, int(time.Second)-nsec
+		if sec == 0 {
+			b = append(b, '-')
+		}
+	}
+	b = strconv.AppendInt(b, sec, 10)
+	frac := strconv.Itoa(nsec + int(time.Second))[1:]
+	return append(append(b, '.'), strings.TrimRight(frac, "0")...), nil
 }
 
 // UnmarshalJSON is an implementation of the json.RawMessage interface and deserializses a
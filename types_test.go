@@ -65,3 +65,31 @@ func TestSingleArrayMarshal(t *testing.T) {
 		t.Errorf("Serialized format of string array mismatch. Expecting: %s  Got: %s", string(expected), string(b))
 	}
 }
+
+func TestNumericDate_MarshalJSON(t *testing.T) {
+	defer func(p time.Duration) { jwt.TimePrecision = p }(jwt.TimePrecision)
+
+	var tests = []struct {
+		precision time.Duration
+		date      time.Time
+		expected  string
+	}{
+		{time.Second, time.Unix(1516239022, 999999999), "1516239022"},
+		{time.Second, time.Unix(4000000000, 0), "4000000000"},
+		{time.Millisecond, time.Unix(1516239022, 120000000), "1516239022.12"},
+		{time.Millisecond, time.Unix(1516239022, 0), "1516239022"},
+		{time.Nanosecond, time.Unix(1516239022, 1), "1516239022.000000001"},
+		{time.Millisecond, time.Unix(-1, 500000000), "-0.5"},
+		{time.Millisecond, time.Unix(-2, 250000000), "-1.75"},
+	}
+
+	for _, data := range tests {
+		jwt.TimePrecision = data.precision
+		b, err := json.Marshal(jwt.NumericDate{Time: data.date})
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		} else if string(b) != data.expected {
+			t.Errorf("[%v] Expected %s. Got: %s", data.precision, data.expected, b)
+		}
+	}
+}
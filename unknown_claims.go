@@ -0,0 +1,108 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// UnknownClaims captures the claims of a token which the claims type
+// embedding it does not declare, so that tokens carrying vendor-specific
+// claims can be parsed into a struct and signed again without losing them.
+//
+// The claims are captured by the Parser and re-emitted by Token when signing;
+// encoding/json alone does neither. Claims declared by the embedding type take
+// precedence over captured claims of the same name.
+//
+//	type MyClaims struct {
+//		jwt.RegisteredClaims
+//		jwt.UnknownClaims
+//		Email string `json:"email"`
+//	}
+type UnknownClaims struct {
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+func (u UnknownClaims) unknownClaims() map[string]json.RawMessage {
+	return u.Unknown
+}
+
+func (u *UnknownClaims) setUnknownClaims(claims map[string]json.RawMessage) {
+	u.Unknown = claims
+}
+
+type unknownClaimsGetter interface {
+	unknownClaims() map[string]json.RawMessage
+}
+
+type unknownClaimsSetter interface {
+	setUnknownClaims(map[string]json.RawMessage)
+}
+
+// marshalClaims encodes the claims of the token, adding the claims captured
+// by an embedded UnknownClaims and applying the token's Abbreviations.
+func (t *Token) marshalClaims() ([]byte, error) {
+	b, err := marshalClaims(t.Claims)
+	if err != nil || len(t.Abbreviations) == 0 {
+		return b, err
+	}
+	return t.Abbreviations.Abbreviate(b)
+}
+
+// marshalClaims encodes claims, adding the claims captured by an embedded
+// UnknownClaims. Claims types declaring a claim more than once are rejected.
+func marshalClaims(claims Claims) ([]byte, error) {
+	if err := checkClaimCollisions(claims); err != nil {
+		return nil, err
+	}
+	b, err := jsonMarshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	u, ok := claims.(unknownClaimsGetter)
+	if !ok || len(u.unknownClaims()) == 0 || !bytes.HasPrefix(b, []byte("{")) {
+		return b, nil
+	}
+	var m map[string]json.RawMessage
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for k, v := range u.unknownClaims() {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
+	return json.Marshal(m)
+}
+
+// captureUnknownClaims stores the members of data which the encoding of the
+// decoded claims lacks in an embedded UnknownClaims. Declared claims which are
+// omitted when empty are captured as well, so that they are reproduced as
+// they were.
+func captureUnknownClaims(data []byte, claims Claims) error {
+	u, ok := claims.(unknownClaimsSetter)
+	if !ok {
+		return nil
+	}
+	u.setUnknownClaims(nil)
+	var all, known map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(b, &known); err != nil {
+		return err
+	}
+	unknown := make(map[string]json.RawMessage)
+	for k, v := range all {
+		if _, ok := known[k]; !ok {
+			unknown[k] = v
+		}
+	}
+	if len(unknown) > 0 {
+		u.setUnknownClaims(unknown)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+type vendorClaims struct {
+	jwt.RegisteredClaims
+	jwt.UnknownClaims
+	Email string `json:"email,omitempty"`
+}
+
+func TestUnknownClaims(t *testing.T) {
+	key := []byte("secret")
+	original := jwt.MapClaims{
+		"sub":                   "user",
+		"email":                 "user@example.com",
+		"https://vendor/roles":  []interface{}{"admin"},
+		"https://vendor/tenant": map[string]interface{}{"id": 12345678901234567.0},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, original).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := new(vendorClaims)
+	if _, err = jwt.ParseWithClaims(signed, claims, func(*jwt.Token) (interface{}, error) { return key, nil }); err != nil {
+		t.Fatalf("Error parsing token: %v", err)
+	}
+	if claims.Subject != "user" || claims.Email != "user@example.com" {
+		t.Errorf("Unexpected declared claims: %+v", claims)
+	}
+	if len(claims.Unknown) != 2 || string(claims.Unknown["https://vendor/roles"]) != `["admin"]` {
+		t.Errorf("Unexpected unknown claims: %v", claims.Unknown)
+	}
+
+	// Re-sign with a changed declared claim; the unknown claims are kept.
+	claims.Email = "new@example.com"
+	resigned, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Parse(resigned, func(*jwt.Token) (interface{}, error) { return key, nil })
+	if err != nil {
+		t.Fatalf("Error parsing re-signed token: %v", err)
+	}
+	want := jwt.MapClaims{}
+	b, _ := json.Marshal(original)
+	_ = json.Unmarshal(b, &want)
+	want["email"] = "new@example.com"
+	if !reflect.DeepEqual(token.Claims, want) {
+		t.Errorf("Expected %v. Got: %v", want, token.Claims)
+	}
+}
@@ -0,0 +1,384 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Policy describes the checks a Validator applies to the claims of a token,
+// in addition to those of Claims.Valid. Durations are encoded in JSON as
+// nanoseconds.
+type Policy struct {
+	Issuers           []string      `json:"issuers,omitempty"`            // If set, "iss" must be one of these
+	Audiences         []string      `json:"audiences,omitempty"`          // If set, "aud" must contain at least one of these
+	RequiredClaims    []string      `json:"required_claims,omitempty"`    // Claims which must be present
+	RequireExpiration bool          `json:"require_expiration,omitempty"` // Require the "exp" claim
+	RequireIssuedAt   bool          `json:"require_issued_at,omitempty"`  // Require the "iat" claim
+	MaxAge            time.Duration `json:"max_age,omitempty"`            // If set, "iat" must be no further in the past than this
+	Leeway            time.Duration `json:"leeway,omitempty"`             // Allowance for clock skew applied to "exp", "nbf" and "iat"
+	ACRValues         []string      `json:"acr_values,omitempty"`         // If set, "acr" must be one of these
+	RequiredAMR       []string      `json:"required_amr,omitempty"`       // Authentication methods which "amr" must all contain
+	MaxAuthAge        time.Duration `json:"max_auth_age,omitempty"`       // If set, "auth_time" must be no further in the past than this
+
+	// RequireAuthorizedParty requires the "azp" (authorized party) claim of
+	// tokens whose "aud" holds more than one value, and AuthorizedParty, if
+	// set, is the value "azp" must have when present, typically the client ID
+	// of the relying party. See
+	// https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation
+	RequireAuthorizedParty bool   `json:"require_authorized_party,omitempty"`
+	AuthorizedParty        string `json:"authorized_party,omitempty"`
+
+	// IssuedWithinPast and IssuedWithinFuture, if either is set, bound "iat"
+	// to the window from IssuedWithinPast before now to IssuedWithinFuture
+	// after now, without Leeway. The future bound replaces the rejection of
+	// tokens used before they were issued.
+	IssuedWithinPast   time.Duration `json:"issued_within_past,omitempty"`
+	IssuedWithinFuture time.Duration `json:"issued_within_future,omitempty"`
+}
+
+// Validator validates the claims of tokens against a Policy. It may be used on
+// its own, or set as Parser.Validator to be applied when parsing. Note that the
+// Parser calls Claims.Valid first, which does not allow for Policy.Leeway; set
+// Parser.SkipClaimsValidation and call Validate after parsing to rely on the
+// policy alone.
+type Validator struct {
+	Policy Policy
+	Hooks  Hooks
+
+	// PresentedKey, if set, is the key or *x509.Certificate the presenter of
+	// the token proved possession of, such as the client certificate of a
+	// mutual TLS connection. The "cnf" claim of the token must confirm it, as
+	// checked by VerifyConfirmation. Since it differs between requests, set
+	// it on a copy of a shared Validator, or with WithPresentedKey.
+	PresentedKey interface{}
+
+	// ReplayDetector, if set, records the "jti" claim of every token which
+	// passes the other checks, and rejects those it has seen before with
+	// ErrTokenReplayed. Identifiers are remembered for ReplayWindow, or until
+	// the token expires, allowing for Policy.Leeway, if that is later. Use it
+	// for one-time-use tokens such as DPoP proofs and client assertions.
+	ReplayDetector ReplayDetector
+	ReplayWindow   time.Duration
+}
+
+// ValidatorOption configures a Validator created with NewValidator.
+type ValidatorOption func(*Validator)
+
+// NewValidator returns a Validator configured by opts.
+func NewValidator(opts ...ValidatorOption) *Validator {
+	v := new(Validator)
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// WithPolicy sets the policy of the Validator. Options applied after it amend
+// the policy.
+func WithPolicy(p Policy) ValidatorOption {
+	return func(v *Validator) {
+		v.Policy = p
+	}
+}
+
+// WithHooks sets the hooks of the Validator.
+func WithHooks(h Hooks) ValidatorOption {
+	return func(v *Validator) {
+		v.Hooks = h
+	}
+}
+
+// WithRequiredACR requires the "acr" claim to be one of values, such as
+// "urn:mace:incommon:iap:silver". Failures wrap ErrTokenInsufficientACR, so
+// that a step-up authentication can be requested.
+func WithRequiredACR(values ...string) ValidatorOption {
+	return func(v *Validator) {
+		v.Policy.ACRValues = append(v.Policy.ACRValues, values...)
+	}
+}
+
+// WithRequiredAMR requires the "amr" claim to contain every one of methods,
+// such as "otp" or "hwk". Failures wrap ErrTokenInsufficientAMR, so that a
+// step-up authentication can be requested.
+func WithRequiredAMR(methods ...string) ValidatorOption {
+	return func(v *Validator) {
+		v.Policy.RequiredAMR = append(v.Policy.RequiredAMR, methods...)
+	}
+}
+
+// WithMaxAuthAge requires the "auth_time" claim to be no further in the past
+// than d, as for the OpenID Connect "max_age" request parameter. Failures wrap
+// ErrTokenAuthTooOld, so that the end-user can be asked to authenticate again.
+func WithMaxAuthAge(d time.Duration) ValidatorOption {
+	return func(v *Validator) {
+		v.Policy.MaxAuthAge = d
+	}
+}
+
+// WithAuthorizedParty requires the "azp" claim of tokens with several
+// audiences and, if clientID is not empty, requires "azp" to be clientID when
+// present. Failures wrap ErrTokenRequiredClaimMissing or
+// ErrTokenInvalidAuthorizedParty.
+func WithAuthorizedParty(clientID string) ValidatorOption {
+	return func(v *Validator) {
+		v.Policy.RequireAuthorizedParty = true
+		v.Policy.AuthorizedParty = clientID
+	}
+}
+
+// WithPresentedKey requires the "cnf" claim to confirm key, a key or
+// *x509.Certificate the presenter of the token proved possession of. See
+// Validator.PresentedKey.
+func WithPresentedKey(key interface{}) ValidatorOption {
+	return func(v *Validator) {
+		v.PresentedKey = key
+	}
+}
+
+// WithIssuanceWindow requires the "iat" claim to lie between past before and
+// future after the time of validation, rejecting tokens minted suspiciously
+// long ago or ahead of time regardless of "exp" and "nbf". Failures wrap
+// ErrTokenOutsideIssuanceWindow.
+func WithIssuanceWindow(past, future time.Duration) ValidatorOption {
+	return func(v *Validator) {
+		v.Policy.IssuedWithinPast, v.Policy.IssuedWithinFuture = past, future
+	}
+}
+
+// Validate checks the claims of token against the policy. All failures are
+// reported, combined as by Claims.Valid.
+func (v *Validator) Validate(token *Token) error {
+	return v.ValidateContext(context.Background(), token)
+}
+
+// ValidateContext is Validate, passing ctx to the ReplayDetector.
+func (v *Validator) ValidateContext(ctx context.Context, token *Token) error {
+	return v.validate(ctx, token, true)
+}
+
+// validate applies the checks of the Validator, recording the token with the
+// ReplayDetector only if replay is set.
+func (v *Validator) validate(ctx context.Context, token *Token, replay bool) error {
+	err := v.Policy.check(token)
+	if v.PresentedKey != nil {
+		err = JoinErrors(err, VerifyConfirmation(token, v.PresentedKey))
+	}
+	if err == nil && replay && v.ReplayDetector != nil {
+		err = v.checkReplay(ctx, token)
+	}
+	if err != nil && v.Hooks.OnValidationFailure != nil {
+		v.Hooks.OnValidationFailure(token, err)
+	}
+	return err
+}
+
+// DryRun validates token against the active policy, and also evaluates
+// candidate, a policy being considered to replace it. Failures of the
+// candidate are reported to Hooks.OnDryRunFailure only; the result of the
+// active policy is returned. This allows the effect of stricter rules to be
+// observed on live traffic before they are enforced. The "jti" claim is not
+// recorded with the ReplayDetector, so the token may still be validated.
+func (v *Validator) DryRun(token *Token, candidate Policy) error {
+	activeErr := v.validate(context.Background(), token, false)
+	if candidateErr := candidate.check(token); candidateErr != nil && v.Hooks.OnDryRunFailure != nil {
+		v.Hooks.OnDryRunFailure(token, candidateErr, activeErr)
+	}
+	return activeErr
+}
+
+func (p *Policy) check(token *Token) error {
+	// The registered claims are read through ClaimsGetter, falling back to
+	// the JSON encoding of claims types which do not implement it. Other
+	// claims are only read from the JSON encoding if the policy needs them.
+	getter, ok := token.Claims.(ClaimsGetter)
+	var claims MapClaims
+	if !ok || p.needsClaimsMap() {
+		var err error
+		if claims, err = claimsMap(token.Claims); err != nil {
+			return err
+		}
+		if !ok {
+			getter = claims
+		}
+	}
+
+	now := TimeFunc()
+	errs := p.checkRegistered(getter, now)
+	if claims != nil {
+		errs = append(errs, p.checkOther(claims, now)...)
+	}
+	return JoinErrors(errs...)
+}
+
+func (p *Policy) needsClaimsMap() bool {
+	return len(p.RequiredClaims) > 0 || p.MaxAuthAge > 0 || len(p.ACRValues) > 0 || len(p.RequiredAMR) > 0 ||
+		p.RequireAuthorizedParty || p.AuthorizedParty != ""
+}
+
+func (p *Policy) checkRegistered(claims ClaimsGetter, now time.Time) []error {
+	var errs []error
+
+	if exp, err := claims.GetExpirationTime(); err != nil {
+		errs = append(errs, err)
+	} else if exp != nil {
+		if now.After(exp.Add(p.Leeway)) {
+			errs = append(errs, newExpiredError(exp.Time, now))
+		}
+	} else if p.RequireExpiration {
+		errs = append(errs, &ValidationError{Err: ErrTokenRequiredClaimMissing, Claim: "exp"})
+	}
+
+	if nbf, err := claims.GetNotBefore(); err != nil {
+		errs = append(errs, err)
+	} else if nbf != nil && now.Add(p.Leeway).Before(nbf.Time) {
+		errs = append(errs, newNotYetValidError(nbf.Time, now))
+	}
+
+	if iat, err := claims.GetIssuedAt(); err != nil {
+		errs = append(errs, err)
+	} else if iat != nil {
+		if p.hasIssuanceWindow() {
+			errs = append(errs, p.checkIssuanceWindow(iat.Time, now)...)
+		} else if now.Add(p.Leeway).Before(iat.Time) {
+			errs = append(errs, newUsedBeforeIssuedError(iat.Time, now))
+		}
+		if age := now.Sub(iat.Time); p.MaxAge > 0 && age > p.MaxAge+p.Leeway {
+			errs = append(errs, &ValidationError{Err: ErrTokenTooOld, Claim: "iat", Actual: iat.Time, Delta: age - p.MaxAge})
+		}
+	} else if p.RequireIssuedAt || p.MaxAge > 0 || p.hasIssuanceWindow() {
+		errs = append(errs, &ValidationError{Err: ErrTokenRequiredClaimMissing, Claim: "iat"})
+	}
+
+	if len(p.Issuers) > 0 {
+		iss, err := claims.GetIssuer()
+		if err != nil {
+			errs = append(errs, err)
+		} else if !containsString(p.Issuers, iss) {
+			errs = append(errs, &ValidationError{Err: ErrTokenInvalidIssuer, Claim: "iss", Expected: p.Issuers, Actual: iss})
+		}
+	}
+
+	if len(p.Audiences) > 0 {
+		aud, err := claims.GetAudience()
+		if err != nil {
+			errs = append(errs, err)
+		} else if !containsAny(aud, p.Audiences) {
+			errs = append(errs, &ValidationError{Err: ErrTokenInvalidAudience, Claim: "aud", Expected: p.Audiences, Actual: []string(aud)})
+		}
+	}
+
+	return errs
+}
+
+func (p *Policy) hasIssuanceWindow() bool {
+	return p.IssuedWithinPast > 0 || p.IssuedWithinFuture > 0
+}
+
+func (p *Policy) checkIssuanceWindow(iat, now time.Time) []error {
+	if p.IssuedWithinPast > 0 {
+		if d := now.Sub(iat) - p.IssuedWithinPast; d > 0 {
+			return []error{&ValidationError{Err: ErrTokenOutsideIssuanceWindow, Claim: "iat", Actual: iat, Delta: d}}
+		}
+	}
+	if d := iat.Sub(now) - p.IssuedWithinFuture; d > 0 {
+		return []error{&ValidationError{Err: ErrTokenOutsideIssuanceWindow, Claim: "iat", Actual: iat, Delta: d}}
+	}
+	return nil
+}
+
+func (p *Policy) checkOther(claims MapClaims, now time.Time) []error {
+	var errs []error
+
+	for _, name := range p.RequiredClaims {
+		if _, ok := claims[name]; !ok {
+			errs = append(errs, &ValidationError{Err: ErrTokenRequiredClaimMissing, Claim: name})
+		}
+	}
+
+	if p.MaxAuthAge > 0 {
+		if at, ok := claims.AuthTime().(time.Time); !ok {
+			errs = append(errs, &ValidationError{Err: ErrTokenRequiredClaimMissing, Claim: "auth_time"})
+		} else if age := now.Sub(at); age > p.MaxAuthAge+p.Leeway {
+			errs = append(errs, &ValidationError{Err: ErrTokenAuthTooOld, Claim: "auth_time", Actual: at, Delta: age - p.MaxAuthAge})
+		}
+	}
+
+	if len(p.ACRValues) > 0 && !containsString(p.ACRValues, claims.ACR()) {
+		errs = append(errs, &ValidationError{Err: ErrTokenInsufficientACR, Claim: "acr", Expected: p.ACRValues, Actual: claims["acr"]})
+	}
+
+	if len(p.RequiredAMR) > 0 {
+		amr, err := claims.AMR()
+		if err != nil || !(AuthenticationClaims{AMR: amr}).HasAMR(p.RequiredAMR...) {
+			errs = append(errs, &ValidationError{Err: ErrTokenInsufficientAMR, Claim: "amr", Expected: p.RequiredAMR, Actual: claims["amr"]})
+		}
+	}
+
+	if p.RequireAuthorizedParty || p.AuthorizedParty != "" {
+		errs = append(errs, p.checkAuthorizedParty(claims)...)
+	}
+
+	return errs
+}
+
+// checkAuthorizedParty checks the "azp" claim against RequireAuthorizedParty
+// and AuthorizedParty.
+func (p *Policy) checkAuthorizedParty(claims MapClaims) []error {
+	azp, ok := claims["azp"].(string)
+	if !ok && claims["azp"] != nil {
+		return []error{&ValidationError{Err: ErrInvalidClaimType, Claim: "azp"}}
+	}
+	if azp == "" {
+		if !p.RequireAuthorizedParty {
+			return nil
+		}
+		aud, err := claims.GetAudience()
+		if err != nil {
+			return []error{err}
+		}
+		if len(aud) > 1 {
+			return []error{&ValidationError{Err: ErrTokenRequiredClaimMissing, Claim: "azp"}}
+		}
+		return nil
+	}
+	if p.AuthorizedParty != "" && azp != p.AuthorizedParty {
+		return []error{&ValidationError{Err: ErrTokenInvalidAuthorizedParty, Claim: "azp", Expected: p.AuthorizedParty, Actual: azp}}
+	}
+	return nil
+}
+
+// claimsMap returns claims as MapClaims, converting other types through their
+// JSON encoding.
+func claimsMap(claims Claims) (MapClaims, error) {
+	if m, ok := claims.(MapClaims); ok {
+		return m, nil
+	}
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	m := MapClaims{}
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(list []string, candidates []string) bool {
+	for _, c := range candidates {
+		if containsString(list, c) {
+			return true
+		}
+	}
+	return false
+}
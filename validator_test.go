@@ -0,0 +1,206 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestValidator_Validate(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	claims := &jwt.RegisteredClaims{
+		Issuer:    "https://issuer.example.com",
+		Audience:  jwt.ClaimStrings{"api", "other"},
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Hour)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(-time.Second)),
+	}
+
+	var tests = []struct {
+		name   string
+		policy jwt.Policy
+		err    error
+	}{
+		{"issuer", jwt.Policy{Issuers: []string{"https://issuer.example.com"}, Leeway: time.Minute}, nil},
+		{"wrong issuer", jwt.Policy{Issuers: []string{"https://other.example.com"}, Leeway: time.Minute}, jwt.ErrTokenInvalidIssuer},
+		{"audience", jwt.Policy{Audiences: []string{"api"}, Leeway: time.Minute}, nil},
+		{"wrong audience", jwt.Policy{Audiences: []string{"billing"}, Leeway: time.Minute}, jwt.ErrTokenInvalidAudience},
+		{"expired", jwt.Policy{}, jwt.ErrTokenExpired},
+		{"required claim", jwt.Policy{RequiredClaims: []string{"sub"}, Leeway: time.Minute}, jwt.ErrTokenRequiredClaimMissing},
+		{"max age", jwt.Policy{MaxAge: 30 * time.Minute, Leeway: time.Minute}, jwt.ErrTokenTooOld},
+	}
+
+	for _, data := range tests {
+		v := &jwt.Validator{Policy: data.policy}
+		err := v.Validate(&jwt.Token{Claims: claims})
+		if data.err == nil && err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		} else if !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+}
+
+func TestWithIssuanceWindow(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	v := jwt.NewValidator(jwt.WithIssuanceWindow(time.Hour, time.Minute))
+	var tests = []struct {
+		name string
+		iat  *jwt.NumericDate
+		err  error
+	}{
+		{"recent", jwt.NewNumericDate(now.Add(-30 * time.Minute)), nil},
+		{"slightly ahead", jwt.NewNumericDate(now.Add(30 * time.Second)), nil},
+		{"backdated", jwt.NewNumericDate(now.Add(-2 * time.Hour)), jwt.ErrTokenOutsideIssuanceWindow},
+		{"far ahead", jwt.NewNumericDate(now.Add(time.Hour)), jwt.ErrTokenOutsideIssuanceWindow},
+		{"missing", nil, jwt.ErrTokenRequiredClaimMissing},
+	}
+	for _, data := range tests {
+		claims := &jwt.RegisteredClaims{IssuedAt: data.iat, ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour))}
+		err := v.Validate(&jwt.Token{Claims: claims})
+		if data.err == nil && err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		} else if !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+}
+
+func TestWithAuthorizedParty(t *testing.T) {
+	v := jwt.NewValidator(jwt.WithAuthorizedParty("client"))
+	var tests = []struct {
+		name   string
+		claims jwt.MapClaims
+		err    error
+	}{
+		{"single audience", jwt.MapClaims{"aud": "client"}, nil},
+		{"several audiences", jwt.MapClaims{"aud": []interface{}{"client", "api"}, "azp": "client"}, nil},
+		{"missing azp", jwt.MapClaims{"aud": []interface{}{"client", "api"}}, jwt.ErrTokenRequiredClaimMissing},
+		{"other azp", jwt.MapClaims{"aud": "client", "azp": "other"}, jwt.ErrTokenInvalidAuthorizedParty},
+		{"azp of the wrong type", jwt.MapClaims{"aud": "client", "azp": 1}, jwt.ErrInvalidClaimType},
+	}
+	for _, data := range tests {
+		err := v.Validate(&jwt.Token{Claims: data.claims})
+		if data.err == nil && err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		} else if !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+
+	// Without a client ID only the presence of azp is checked.
+	v = jwt.NewValidator(jwt.WithAuthorizedParty(""))
+	if err := v.Validate(&jwt.Token{Claims: &jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"a", "b"}}}); !errors.Is(err, jwt.ErrTokenRequiredClaimMissing) {
+		t.Errorf("Expected ErrTokenRequiredClaimMissing, got %v", err)
+	}
+	if err := v.Validate(&jwt.Token{Claims: jwt.MapClaims{"aud": []interface{}{"a", "b"}, "azp": "any"}}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestValidator_DryRun(t *testing.T) {
+	var candidateErrs, activeErrs []error
+	var failures int
+	v := &jwt.Validator{
+		Policy: jwt.Policy{Issuers: []string{"a", "b"}},
+		Hooks: jwt.Hooks{
+			OnValidationFailure: func(*jwt.Token, error) { failures++ },
+			OnDryRunFailure: func(_ *jwt.Token, candidateErr, activeErr error) {
+				candidateErrs = append(candidateErrs, candidateErr)
+				activeErrs = append(activeErrs, activeErr)
+			},
+		},
+	}
+	candidate := jwt.Policy{Issuers: []string{"a"}}
+
+	if err := v.DryRun(&jwt.Token{Claims: jwt.MapClaims{"iss": "a"}}, candidate); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := v.DryRun(&jwt.Token{Claims: jwt.MapClaims{"iss": "b"}}, candidate); err != nil {
+		t.Errorf("Expected active policy to accept the token. Got: %v", err)
+	}
+	if err := v.DryRun(&jwt.Token{Claims: jwt.MapClaims{"iss": "c"}}, candidate); !errors.Is(err, jwt.ErrTokenInvalidIssuer) {
+		t.Errorf("Expected active policy to reject the token. Got: %v", err)
+	}
+
+	if len(candidateErrs) != 2 || activeErrs[0] != nil || activeErrs[1] == nil {
+		t.Errorf("Unexpected dry run failures: %v, %v", candidateErrs, activeErrs)
+	}
+	if failures != 1 {
+		t.Errorf("Expected 1 validation failure. Got: %v", failures)
+	}
+}
+
+func TestValidator_DryRun_replay(t *testing.T) {
+	exp := jwt.NewNumericDate(time.Now().Add(time.Minute))
+	token := &jwt.Token{Claims: &jwt.RegisteredClaims{ID: "a", ExpiresAt: exp}}
+	v := jwt.NewValidator(jwt.WithReplayDetection(new(jwt.MemoryReplayDetector), 0))
+
+	if err := v.DryRun(token, jwt.Policy{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := v.Validate(token); err != nil {
+		t.Errorf("Expected the dry run not to record the jti. Got: %v", err)
+	}
+	if err := v.Validate(token); !errors.Is(err, jwt.ErrTokenReplayed) {
+		t.Errorf("Expected ErrTokenReplayed. Got: %v", err)
+	}
+}
+
+func TestParser_Validator(t *testing.T) {
+	key := []byte("secret")
+	signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iss": "other"}).SignedString(key)
+	p := &jwt.Parser{Validator: &jwt.Validator{Policy: jwt.Policy{Issuers: []string{"issuer"}}}}
+	if _, err := p.Parse(signed, func(*jwt.Token) (interface{}, error) { return key, nil }); !errors.Is(err, jwt.ErrTokenInvalidIssuer) {
+		t.Errorf("Expected ErrTokenInvalidIssuer. Got: %v", err)
+	}
+}
+
+// getterClaims exposes its registered claims only through ClaimsGetter.
+type getterClaims struct {
+	exp *jwt.NumericDate
+	iss string
+}
+
+func (c getterClaims) Valid() error                                 { return nil }
+func (c getterClaims) GetExpirationTime() (*jwt.NumericDate, error) { return c.exp, nil }
+func (c getterClaims) GetNotBefore() (*jwt.NumericDate, error)      { return nil, nil }
+func (c getterClaims) GetIssuedAt() (*jwt.NumericDate, error)       { return nil, nil }
+func (c getterClaims) GetIssuer() (string, error)                   { return c.iss, nil }
+func (c getterClaims) GetSubject() (string, error)                  { return "", nil }
+func (c getterClaims) GetAudience() (jwt.ClaimStrings, error)       { return nil, nil }
+
+func TestValidator_ClaimsGetter(t *testing.T) {
+	now := time.Now()
+	var tests = []struct {
+		name   string
+		claims jwt.Claims
+		err    error
+	}{
+		{"getter", getterClaims{exp: jwt.NewNumericDate(now.Add(time.Hour)), iss: "a"}, nil},
+		{"getter expired", getterClaims{exp: jwt.NewNumericDate(now.Add(-time.Hour)), iss: "a"}, jwt.ErrTokenExpired},
+		{"getter issuer", getterClaims{iss: "b"}, jwt.ErrTokenInvalidIssuer},
+		{"standard claims", &jwt.StandardClaims{Issuer: "a", ExpiresAt: now.Add(-time.Hour).Unix()}, jwt.ErrTokenExpired},
+		{"map claims", jwt.MapClaims{"iss": "a", "exp": float64(now.Add(time.Hour).Unix())}, nil},
+		{"map claims invalid exp", jwt.MapClaims{"iss": "a", "exp": "tomorrow"}, jwt.ErrInvalidClaimType},
+		{"map claims invalid iss", jwt.MapClaims{"iss": 1}, jwt.ErrInvalidClaimType},
+		{"map claims invalid exp type", jwt.MapClaims{"iss": "a", "exp": []interface{}{1}}, jwt.ErrInvalidClaimType},
+	}
+
+	v := &jwt.Validator{Policy: jwt.Policy{Issuers: []string{"a"}}}
+	for _, data := range tests {
+		err := v.Validate(&jwt.Token{Claims: data.claims})
+		if data.err == nil && err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		} else if !errors.Is(err, data.err) {
+			t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+		}
+	}
+}
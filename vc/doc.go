@@ -0,0 +1,12 @@
+// Package vc provides the claims of Verifiable Credentials and Verifiable
+// Presentations secured as JWTs, following the JWT encoding of the W3C
+// Verifiable Credentials Data Model 1.1,
+// https://www.w3.org/TR/vc-data-model/#json-web-token.
+//
+// A credential is carried in the "vc" claim and a presentation in the "vp"
+// claim, while the registered claims of the JWT stand in for some of their
+// properties: "iss" for the issuer, or the holder of a presentation, "sub"
+// for the id of the credential subject, "jti" for the id, and "nbf" and
+// "exp" for the issuance and expiration dates. Claims.Valid and
+// PresentationClaims.Valid check that the two agree where both are present.
+package vc
@@ -0,0 +1,106 @@
+package vc
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+var (
+	ErrMissingPresentation = errors.New(`vc: the "vp" claim is missing`)
+	ErrHolderMismatch      = errors.New(`vc: the "iss" claim does not match the holder of the presentation`)
+	ErrNonceMismatch       = errors.New(`vc: the "nonce" claim does not match the nonce of the verifier`)
+	ErrNotHolder           = errors.New("vc: the holder of the presentation is not the subject of a credential")
+)
+
+// Presentation is a verifiable presentation, the value of the "vp" claim.
+type Presentation struct {
+	Context []interface{} `json:"@context"`
+	ID      string        `json:"id,omitempty"`
+	Type    []string      `json:"type"`
+	Holder  string        `json:"holder,omitempty"`
+
+	// VerifiableCredential are the credentials presented, each a JWT
+	// secured credential in the compact serialization.
+	VerifiableCredential []string `json:"verifiableCredential,omitempty"`
+}
+
+// PresentationClaims are the claims of a JWT secured verifiable
+// presentation.
+type PresentationClaims struct {
+	jwt.RegisteredClaims
+
+	// the `nonce` claim, the nonce provided by the verifier
+	Nonce string `json:"nonce,omitempty"`
+
+	// the `vp` claim, the presentation
+	Presentation *Presentation `json:"vp,omitempty"`
+}
+
+// Valid validates the time based claims, the base context and type of the
+// presentation, and that "iss" is its holder and "jti" its id, where both
+// are present. All failures are reported, combined with jwt.JoinErrors.
+func (c *PresentationClaims) Valid() error {
+	errs := []error{c.RegisteredClaims.Valid()}
+	vp := c.Presentation
+	if vp == nil {
+		return jwt.JoinErrors(append(errs, &jwt.ValidationError{Err: ErrMissingPresentation, Claim: "vp"})...)
+	}
+	errs = append(errs, checkBase(vp.Context, vp.Type, TypeVerifiablePresentation)...)
+	if vp.Holder != "" && c.Issuer != "" && vp.Holder != c.Issuer {
+		errs = append(errs, &jwt.ValidationError{Err: ErrHolderMismatch, Claim: "iss", Expected: vp.Holder, Actual: c.Issuer})
+	}
+	if vp.ID != "" && c.ID != "" && vp.ID != c.ID {
+		errs = append(errs, &jwt.ValidationError{Err: ErrIDMismatch, Claim: "jti", Expected: vp.ID, Actual: c.ID})
+	}
+	return jwt.JoinErrors(errs...)
+}
+
+// VerifiedPresentation is a presentation verified by VerifyPresentation.
+type VerifiedPresentation struct {
+	Claims      *PresentationClaims
+	Credentials []*Claims // The claims of the presented credentials, in order
+}
+
+// VerifyPresentation verifies the JWT secured presentation raw, made for the
+// verifier identified by audience, which provided nonce, and the credentials
+// it contains. holderKey supplies the key of the holder, which signed the
+// presentation, and issuerKey those of the issuers of the credentials. The
+// holder, the "iss" of the presentation, must be the subject of every
+// credential. parser, if not nil, parses the presentation and the
+// credentials.
+func VerifyPresentation(raw, audience, nonce string, holderKey, issuerKey jwt.Keyfunc, parser *jwt.Parser) (*VerifiedPresentation, error) {
+	if parser == nil {
+		parser = new(jwt.Parser)
+	}
+	claims := new(PresentationClaims)
+	token, err := parser.ParseWithClaims(raw, claims, holderKey)
+	if err != nil {
+		return nil, err
+	}
+	v := jwt.Validator{Policy: jwt.Policy{
+		Audiences:       []string{audience},
+		RequiredClaims:  []string{"iss"},
+		RequireIssuedAt: true,
+	}}
+	if err = v.Validate(token); err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, &jwt.ValidationError{Err: ErrNonceMismatch, Claim: "nonce"}
+	}
+
+	result := &VerifiedPresentation{Claims: claims}
+	for _, credential := range claims.Presentation.VerifiableCredential {
+		vc := new(Claims)
+		if _, err = parser.ParseWithClaims(credential, vc, issuerKey); err != nil {
+			return nil, err
+		}
+		if vc.Subject != claims.Issuer {
+			return nil, ErrNotHolder
+		}
+		result.Credentials = append(result.Credentials, vc)
+	}
+	return result, nil
+}
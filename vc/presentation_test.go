@@ -0,0 +1,93 @@
+package vc_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/vc"
+)
+
+func TestVerifyPresentation(t *testing.T) {
+	issuerPub, issuerKey, _ := ed25519.GenerateKey(rand.Reader)
+	holderPub, holderKey, _ := ed25519.GenerateKey(rand.Reader)
+	const holder = "did:example:ebfeb1f712ebc6f1c276e12ec21"
+	const verifier = "https://verifier.example"
+
+	credential, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, vc.NewClaims(credentialFor(holder))).SignedString(issuerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	present := func(nonce string, credentials ...string) string {
+		claims := &vc.PresentationClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:   holder,
+				Audience: jwt.ClaimStrings{verifier},
+				IssuedAt: jwt.NewNumericDate(time.Now()),
+			},
+			Nonce: nonce,
+			Presentation: &vc.Presentation{
+				Context:              []interface{}{vc.ContextV1},
+				Type:                 []string{vc.TypeVerifiablePresentation},
+				VerifiableCredential: credentials,
+			},
+		}
+		raw, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(holderKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+	holderKeyFunc := func(*jwt.Token) (interface{}, error) { return holderPub, nil }
+	issuerKeyFunc := func(*jwt.Token) (interface{}, error) { return issuerPub, nil }
+
+	vp, err := vc.VerifyPresentation(present("nonce", credential), verifier, "nonce", holderKeyFunc, issuerKeyFunc, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(vp.Credentials) != 1 || vp.Credentials[0].Credential.ID != "http://example.edu/credentials/3732" {
+		t.Errorf("Credentials = %v", vp.Credentials)
+	}
+
+	if _, err = vc.VerifyPresentation(present("other", credential), verifier, "nonce", holderKeyFunc, issuerKeyFunc, nil); !errors.Is(err, vc.ErrNonceMismatch) {
+		t.Errorf("Expected ErrNonceMismatch, got %v", err)
+	}
+	if _, err = vc.VerifyPresentation(present("nonce", credential), "https://other.example", "nonce", holderKeyFunc, issuerKeyFunc, nil); !errors.Is(err, jwt.ErrTokenInvalidAudience) {
+		t.Errorf("Expected ErrTokenInvalidAudience, got %v", err)
+	}
+
+	other, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, vc.NewClaims(credentialFor("did:example:other"))).SignedString(issuerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = vc.VerifyPresentation(present("nonce", other), verifier, "nonce", holderKeyFunc, issuerKeyFunc, nil); !errors.Is(err, vc.ErrNotHolder) {
+		t.Errorf("Expected ErrNotHolder, got %v", err)
+	}
+}
+
+func TestPresentationClaims_Valid(t *testing.T) {
+	c := &vc.PresentationClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Issuer: "did:example:holder"},
+		Presentation: &vc.Presentation{
+			Context: []interface{}{vc.ContextV2},
+			Type:    []string{vc.TypeVerifiablePresentation},
+			Holder:  "did:example:other",
+		},
+	}
+	if err := c.Valid(); !errors.Is(err, vc.ErrHolderMismatch) {
+		t.Errorf("Expected ErrHolderMismatch, got %v", err)
+	}
+	c.Presentation = nil
+	if err := c.Valid(); !errors.Is(err, vc.ErrMissingPresentation) {
+		t.Errorf("Expected ErrMissingPresentation, got %v", err)
+	}
+}
+
+func credentialFor(subject string) *vc.Credential {
+	c := credential()
+	c.CredentialSubject[0]["id"] = subject
+	return c
+}
@@ -0,0 +1,198 @@
+package vc
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+// Base contexts and types required of credentials and presentations.
+const (
+	ContextV1                  = "https://www.w3.org/2018/credentials/v1"
+	ContextV2                  = "https://www.w3.org/ns/credentials/v2"
+	TypeVerifiableCredential   = "VerifiableCredential"
+	TypeVerifiablePresentation = "VerifiablePresentation"
+)
+
+var (
+	ErrMissingCredential = errors.New(`vc: the "vc" claim is missing`)
+	ErrInvalidContext    = errors.New(`vc: "@context" does not begin with the base context`)
+	ErrInvalidType       = errors.New(`vc: "type" does not contain the base type`)
+	ErrIssuerMismatch    = errors.New(`vc: the "iss" claim does not match the issuer of the credential`)
+	ErrSubjectMismatch   = errors.New(`vc: the "sub" claim does not match the id of the credential subject`)
+	ErrIDMismatch        = errors.New(`vc: the "jti" claim does not match the id of the credential`)
+	ErrDateMismatch      = errors.New(`vc: a date of the credential does not match the time claims`)
+)
+
+// Issuer is the issuer of a credential, which is encoded either as its id
+// alone or as an object with an "id" and other properties, such as a name.
+type Issuer struct {
+	ID         string
+	Properties map[string]interface{} // Properties other than "id", if encoded as an object
+}
+
+// MarshalJSON encodes the issuer as its id if it has no other properties.
+func (i Issuer) MarshalJSON() ([]byte, error) {
+	if len(i.Properties) == 0 {
+		return json.Marshal(i.ID)
+	}
+	m := make(map[string]interface{}, len(i.Properties)+1)
+	for k, v := range i.Properties {
+		m[k] = v
+	}
+	m["id"] = i.ID
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON decodes an issuer encoded as a string or an object.
+func (i *Issuer) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*i = Issuer{ID: id}
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	id, _ = m["id"].(string)
+	delete(m, "id")
+	*i = Issuer{ID: id, Properties: m}
+	return nil
+}
+
+// Subjects are the subjects of a credential, the "credentialSubject"
+// property, which is an object or an array of objects.
+type Subjects []map[string]interface{}
+
+// MarshalJSON encodes a single subject as an object.
+func (s Subjects) MarshalJSON() ([]byte, error) {
+	if len(s) == 1 {
+		return json.Marshal(s[0])
+	}
+	return json.Marshal([]map[string]interface{}(s))
+}
+
+// UnmarshalJSON decodes subjects encoded as an object or an array.
+func (s *Subjects) UnmarshalJSON(data []byte) error {
+	var one map[string]interface{}
+	if err := json.Unmarshal(data, &one); err == nil {
+		*s = Subjects{one}
+		return nil
+	}
+	var many []map[string]interface{}
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*s = many
+	return nil
+}
+
+// Credential is a verifiable credential, the value of the "vc" claim.
+// Properties which the registered claims represent, such as "issuer" and
+// "issuanceDate", may be omitted.
+type Credential struct {
+	Context           []interface{}          `json:"@context"`
+	ID                string                 `json:"id,omitempty"`
+	Type              []string               `json:"type"`
+	Issuer            *Issuer                `json:"issuer,omitempty"`
+	IssuanceDate      *time.Time             `json:"issuanceDate,omitempty"`
+	ExpirationDate    *time.Time             `json:"expirationDate,omitempty"`
+	CredentialSubject Subjects               `json:"credentialSubject"`
+	CredentialStatus  map[string]interface{} `json:"credentialStatus,omitempty"`
+	CredentialSchema  interface{}            `json:"credentialSchema,omitempty"`
+	Evidence          interface{}            `json:"evidence,omitempty"`
+	TermsOfUse        interface{}            `json:"termsOfUse,omitempty"`
+}
+
+// Claims are the claims of a JWT secured verifiable credential.
+type Claims struct {
+	jwt.RegisteredClaims
+
+	// the `vc` claim, the credential
+	Credential *Credential `json:"vc,omitempty"`
+}
+
+// Valid validates the time based claims, the base context and type of the
+// credential, and the consistency of the registered claims with the
+// credential: "iss" must be the id of its issuer, "sub" that of its subject,
+// "jti" its id, and "nbf" and "exp" its issuance and expiration dates, where
+// both are present. All failures are reported, combined with jwt.JoinErrors.
+func (c *Claims) Valid() error {
+	errs := []error{c.RegisteredClaims.Valid()}
+	vc := c.Credential
+	if vc == nil {
+		return jwt.JoinErrors(append(errs, &jwt.ValidationError{Err: ErrMissingCredential, Claim: "vc"})...)
+	}
+	errs = append(errs, checkBase(vc.Context, vc.Type, TypeVerifiableCredential)...)
+
+	if vc.Issuer != nil && c.Issuer != "" && vc.Issuer.ID != c.Issuer {
+		errs = append(errs, &jwt.ValidationError{Err: ErrIssuerMismatch, Claim: "iss", Expected: vc.Issuer.ID, Actual: c.Issuer})
+	}
+	if c.Subject != "" {
+		for _, s := range vc.CredentialSubject {
+			if id, ok := s["id"].(string); ok && id != c.Subject {
+				errs = append(errs, &jwt.ValidationError{Err: ErrSubjectMismatch, Claim: "sub", Expected: id, Actual: c.Subject})
+				break
+			}
+		}
+	}
+	if vc.ID != "" && c.ID != "" && vc.ID != c.ID {
+		errs = append(errs, &jwt.ValidationError{Err: ErrIDMismatch, Claim: "jti", Expected: vc.ID, Actual: c.ID})
+	}
+	if !sameTime(vc.IssuanceDate, c.NotBefore) {
+		errs = append(errs, &jwt.ValidationError{Err: ErrDateMismatch, Claim: "nbf", Expected: vc.IssuanceDate, Actual: c.NotBefore})
+	}
+	if !sameTime(vc.ExpirationDate, c.ExpiresAt) {
+		errs = append(errs, &jwt.ValidationError{Err: ErrDateMismatch, Claim: "exp", Expected: vc.ExpirationDate, Actual: c.ExpiresAt})
+	}
+	return jwt.JoinErrors(errs...)
+}
+
+// checkBase checks that context begins with a base context and that types
+// contains base.
+func checkBase(context []interface{}, types []string, base string) []error {
+	var errs []error
+	if len(context) == 0 || (context[0] != ContextV1 && context[0] != ContextV2) {
+		errs = append(errs, ErrInvalidContext)
+	}
+	found := false
+	for _, t := range types {
+		found = found || t == base
+	}
+	if !found {
+		errs = append(errs, ErrInvalidType)
+	}
+	return errs
+}
+
+// sameTime reports whether the date t of a credential and the NumericDate d
+// agree, to the second, if both are present.
+func sameTime(t *time.Time, d *jwt.NumericDate) bool {
+	return t == nil || d == nil || t.Unix() == d.Unix()
+}
+
+// NewClaims returns the claims of a JWT securing vc, with the registered
+// claims set from its properties as described in
+// https://www.w3.org/TR/vc-data-model/#jwt-encoding: "iss" from the issuer,
+// "sub" from the id of its only subject, "jti" from its id, and "nbf" and
+// "exp" from its issuance and expiration dates.
+func NewClaims(vc *Credential) *Claims {
+	c := &Claims{Credential: vc}
+	if vc.Issuer != nil {
+		c.Issuer = vc.Issuer.ID
+	}
+	if len(vc.CredentialSubject) == 1 {
+		c.Subject, _ = vc.CredentialSubject[0]["id"].(string)
+	}
+	c.ID = vc.ID
+	if vc.IssuanceDate != nil {
+		c.NotBefore = jwt.NewNumericDate(*vc.IssuanceDate)
+	}
+	if vc.ExpirationDate != nil {
+		c.ExpiresAt = jwt.NewNumericDate(*vc.ExpirationDate)
+	}
+	return c
+}
@@ -0,0 +1,85 @@
+package vc_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+	"github.com/chanced/go-jwt/v4/vc"
+)
+
+func credential() *vc.Credential {
+	issued := time.Now().Add(-time.Hour).Truncate(time.Second)
+	return &vc.Credential{
+		Context:      []interface{}{vc.ContextV1, "https://www.w3.org/2018/credentials/examples/v1"},
+		ID:           "http://example.edu/credentials/3732",
+		Type:         []string{vc.TypeVerifiableCredential, "UniversityDegreeCredential"},
+		Issuer:       &vc.Issuer{ID: "https://example.edu/issuers/14"},
+		IssuanceDate: &issued,
+		CredentialSubject: vc.Subjects{{
+			"id":     "did:example:ebfeb1f712ebc6f1c276e12ec21",
+			"degree": map[string]interface{}{"type": "BachelorDegree"},
+		}},
+	}
+}
+
+func TestClaims_Valid(t *testing.T) {
+	if err := vc.NewClaims(credential()).Valid(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *vc.Claims)
+		want   error
+	}{
+		{"missing", func(c *vc.Claims) { c.Credential = nil }, vc.ErrMissingCredential},
+		{"context", func(c *vc.Claims) { c.Credential.Context = []interface{}{"https://example.com"} }, vc.ErrInvalidContext},
+		{"type", func(c *vc.Claims) { c.Credential.Type = []string{"UniversityDegreeCredential"} }, vc.ErrInvalidType},
+		{"iss", func(c *vc.Claims) { c.Issuer = "https://other.example" }, vc.ErrIssuerMismatch},
+		{"sub", func(c *vc.Claims) { c.Subject = "did:example:other" }, vc.ErrSubjectMismatch},
+		{"jti", func(c *vc.Claims) { c.ID = "urn:other" }, vc.ErrIDMismatch},
+		{"nbf", func(c *vc.Claims) { c.NotBefore = jwt.NewNumericDate(time.Now().Add(-2 * time.Hour)) }, vc.ErrDateMismatch},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := vc.NewClaims(credential())
+			tc.modify(c)
+			if err := c.Valid(); !errors.Is(err, tc.want) {
+				t.Errorf("Expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestCredential_JSON(t *testing.T) {
+	const data = `{
+		"@context": ["https://www.w3.org/2018/credentials/v1"],
+		"type": ["VerifiableCredential"],
+		"issuer": {"id": "https://example.edu/issuers/14", "name": "Example University"},
+		"credentialSubject": [{"id": "did:example:1"}, {"id": "did:example:2"}]
+	}`
+	var c vc.Credential
+	if err := json.Unmarshal([]byte(data), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Issuer.ID != "https://example.edu/issuers/14" || c.Issuer.Properties["name"] != "Example University" {
+		t.Errorf("Issuer = %+v", c.Issuer)
+	}
+	if len(c.CredentialSubject) != 2 {
+		t.Errorf("CredentialSubject = %v", c.CredentialSubject)
+	}
+
+	b, err := json.Marshal(vc.Issuer{ID: "https://example.edu/issuers/14"})
+	if err != nil || string(b) != `"https://example.edu/issuers/14"` {
+		t.Errorf("Marshal(Issuer) = %s, %v", b, err)
+	}
+	b, _ = json.Marshal(vc.Subjects{{"id": "did:example:1"}})
+	var subject map[string]interface{}
+	if err = json.Unmarshal(b, &subject); err != nil || !reflect.DeepEqual(subject, map[string]interface{}{"id": "did:example:1"}) {
+		t.Errorf("Marshal(Subjects) = %s", b)
+	}
+}
@@ -0,0 +1,146 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/chanced/go-jwt/v4/internal/lru"
+)
+
+// DefaultVerificationCacheSize is the number of tokens a VerificationCache
+// holds when MaxEntries is not set.
+const DefaultVerificationCacheSize = 10000
+
+// VerificationCache remembers tokens whose signatures a Parser has verified,
+// so that a token presented again, such as a bearer token sent with every
+// request to an API gateway, is not verified again. Set it as
+// Parser.VerificationCache, or with WithVerificationCache.
+//
+// Entries are keyed by a hash of the token and the thumbprint of the key
+// returned by the Keyfunc, so a rotated key does not match the entries of
+// the key it replaces. Only keys which have a thumbprint, as computed by
+// Thumbprint, are cached. Claims are always decoded and validated, so a
+// cached token still expires; entries are dropped once the token expires, or
+// after TTL, if set. Tokens without an "exp" claim are only cached if TTL is
+// set.
+//
+// A VerificationCache is safe for concurrent use, and may be shared between
+// Parsers. The zero value is ready to use.
+type VerificationCache struct {
+	MaxEntries int           // Optional. Defaults to DefaultVerificationCacheSize
+	TTL        time.Duration // Optional. The longest an entry is kept. Zero means until the token expires
+	Hooks      Hooks         // Optional. OnCacheEvent is reported to as "verification"
+
+	// SweepInterval is how often Start sweeps out expired entries. Defaults
+	// to DefaultSweepInterval.
+	SweepInterval time.Duration
+
+	mu      sync.Mutex
+	entries lru.Expiring
+	stats   CacheStats
+	sweeps  sweeper
+}
+
+// WithVerificationCache sets the VerificationCache of the Parser.
+func WithVerificationCache(c *VerificationCache) ParserOption {
+	return func(p *Parser) {
+		p.VerificationCache = c
+	}
+}
+
+// verificationKey returns the key of the entry of token, whose signature over
+// signingString was verified with key, or "" if key cannot be cached. The
+// algorithm is part of the entry, as the JWS JSON serialization allows it to
+// be given outside of what is signed.
+func verificationKey(token *Token, signingString string, key interface{}) string {
+	tp, err := Thumbprint(key, crypto.SHA256)
+	if err != nil {
+		return ""
+	}
+	h := sha256.New()
+	h.Write([]byte(token.Method.Alg() + "." + signingString + "." + token.Signature))
+	return string(h.Sum(nil)) + string(tp)
+}
+
+// verified reports whether the entry k holds an unexpired verification.
+func (c *VerificationCache) verified(k string) bool {
+	c.mu.Lock()
+	_, ok := c.entries.Until(k, TimeFunc())
+	event := CacheMiss
+	if ok {
+		event = CacheHit
+	}
+	c.stats.Count(event)
+	c.mu.Unlock()
+	c.Hooks.ReportCacheEvent("verification", event)
+	return ok
+}
+
+// add records the verification of token under k.
+func (c *VerificationCache) add(k string, token *Token) {
+	now := TimeFunc()
+	var until time.Time
+	if c.TTL > 0 {
+		until = now.Add(c.TTL)
+	}
+	if getter, err := claimsGetter(token.Claims); err == nil {
+		if exp, err := getter.GetExpirationTime(); err == nil && exp != nil && (until.IsZero() || exp.Before(until)) {
+			until = exp.Time
+		}
+	}
+	if !until.After(now) {
+		return
+	}
+
+	c.mu.Lock()
+	c.entries.MaxEntries = c.MaxEntries
+	if c.entries.MaxEntries <= 0 {
+		c.entries.MaxEntries = DefaultVerificationCacheSize
+	}
+	c.entries.OnEvict = c.evicted
+	evictions := c.stats.Evictions
+	c.entries.AddUntil(k, until, now)
+	evictions = c.stats.Evictions - evictions
+	c.mu.Unlock()
+	c.Hooks.reportEvictions("verification", evictions)
+}
+
+// Start sweeps expired entries out of c every SweepInterval in the
+// background, until Close is called or ctx is done, so that entries which are
+// never looked up again do not wait for new ones to be added to be removed.
+// VerificationCache implements Component.
+func (c *VerificationCache) Start(ctx context.Context) error {
+	return c.sweeps.start(ctx, c.SweepInterval, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.entries.Sweep(TimeFunc())
+	})
+}
+
+// Close stops the sweeps begun by Start.
+func (c *VerificationCache) Close() error {
+	return c.sweeps.close()
+}
+
+func (c *VerificationCache) evicted(string, interface{}) {
+	c.stats.Count(CacheEviction)
+}
+
+// Purge removes every entry, such as after a key is revoked.
+func (c *VerificationCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries.RemoveFunc(func(string, interface{}) bool { return true })
+}
+
+// Stats returns a snapshot of the counters of c.
+func (c *VerificationCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Entries = c.entries.Len()
+	return s
+}
@@ -0,0 +1,84 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestVerificationCache(t *testing.T) {
+	now := time.Now()
+	defer func(f func() time.Time) { jwt.TimeFunc = f }(jwt.TimeFunc)
+	jwt.TimeFunc = func() time.Time { return now }
+
+	key := []byte("secret")
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+	sign := func(claims jwt.Claims) string {
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	events := map[jwt.CacheEvent]int{}
+	cache := &jwt.VerificationCache{}
+	cache.Hooks.OnCacheEvent = func(name string, e jwt.CacheEvent) {
+		if name != "verification" {
+			t.Errorf("Unexpected cache name %q", name)
+		}
+		events[e]++
+		cache.Stats() // The hook may read the stats of the cache
+	}
+	p := jwt.NewParser(jwt.WithVerificationCache(cache))
+
+	s := sign(&jwt.RegisteredClaims{Subject: "a", ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute))})
+	for i := 0; i < 3; i++ {
+		token, err := p.ParseWithClaims(s, &jwt.RegisteredClaims{}, keyFunc)
+		if err != nil || !token.Valid {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if sub := token.Claims.(*jwt.RegisteredClaims).Subject; sub != "a" {
+			t.Errorf("Unexpected subject %q", sub)
+		}
+	}
+	if st := cache.Stats(); st.Hits != 2 || st.Misses != 1 || st.Entries != 1 || events[jwt.CacheHit] != 2 {
+		t.Errorf("Unexpected stats: %+v", st)
+	}
+
+	// A rotated key does not match the entry of the previous one.
+	other := func(*jwt.Token) (interface{}, error) { return []byte("other"), nil }
+	if _, err := p.ParseWithClaims(s, &jwt.RegisteredClaims{}, other); !errors.Is(err, jwt.ErrSignatureInvalid) {
+		t.Errorf("Expected ErrSignatureInvalid, got %v", err)
+	}
+
+	// Tokens without "exp" are only cached with a TTL.
+	noExp := sign(&jwt.RegisteredClaims{Subject: "b"})
+	if _, err := p.Parse(noExp, keyFunc); err != nil {
+		t.Fatal(err)
+	}
+	if st := cache.Stats(); st.Entries != 1 {
+		t.Errorf("Expected a token without exp not to be cached, got %+v", st)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := p.ParseWithClaims(s, &jwt.RegisteredClaims{}, keyFunc); !errors.Is(err, jwt.ErrTokenExpired) {
+		t.Errorf("Expected ErrTokenExpired, got %v", err)
+	}
+
+	cache.TTL = time.Minute
+	for i := 0; i < 2; i++ {
+		if _, err := p.Parse(noExp, keyFunc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if st := cache.Stats(); st.Hits != 3 {
+		t.Errorf("Expected the token to be cached with a TTL, got %+v", st)
+	}
+	cache.Purge()
+	if st := cache.Stats(); st.Entries != 0 {
+		t.Errorf("Expected no entries after Purge, got %+v", st)
+	}
+}
@@ -0,0 +1,98 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Verifier verifies a raw credential, such as a bearer token taken from a
+// request, and returns the resulting Token.
+type Verifier interface {
+	Verify(ctx context.Context, credential string) (*Token, error)
+}
+
+// VerifierFunc adapts an ordinary function to the Verifier interface.
+type VerifierFunc func(ctx context.Context, credential string) (*Token, error)
+
+// Verify calls f(ctx, credential).
+func (f VerifierFunc) Verify(ctx context.Context, credential string) (*Token, error) {
+	return f(ctx, credential)
+}
+
+// OpaqueValidator validates a credential which is not a JWT, for example by
+// calling an OAuth 2.0 token introspection endpoint, and returns its claims.
+type OpaqueValidator func(ctx context.Context, credential string) (Claims, error)
+
+// FallbackVerifier is a Verifier for environments issuing both JWTs and opaque
+// tokens. Credentials which have the shape of a JWT are parsed and verified with
+// Parser and Keyfunc; any other credential is handed to Opaque.
+//
+// A credential which looks like a JWT but fails verification is rejected; it is
+// never retried as an opaque token, unless FallbackOnUnresolvedKey is set and
+// the Keyfunc could not supply its key.
+type FallbackVerifier struct {
+	Parser    *Parser         // Optional. Defaults to a zero Parser
+	Keyfunc   Keyfunc         // Supplies the key for verifying JWTs
+	NewClaims func() Claims   // Optional. Returns the Claims to parse JWTs into. Defaults to MapClaims
+	Opaque    OpaqueValidator // Validates credentials which are not JWTs
+
+	// FallbackOnUnresolvedKey hands JWTs to Opaque when the Keyfunc fails,
+	// such as with ErrUnknownKeyID for a key the issuer has not published,
+	// so that an authorization server which can still vouch for the token,
+	// such as through token introspection, decides.
+	FallbackOnUnresolvedKey bool
+}
+
+// Verify implements Verifier.
+func (v *FallbackVerifier) Verify(ctx context.Context, credential string) (*Token, error) {
+	p := v.Parser
+	if p == nil {
+		p = new(Parser)
+	}
+
+	if IsJWT(credential) || v.Opaque == nil {
+		var claims Claims = MapClaims{}
+		if v.NewClaims != nil {
+			claims = v.NewClaims()
+		}
+		token, err := p.ParseWithContext(ctx, credential, claims, v.Keyfunc)
+		if err == nil || !v.FallbackOnUnresolvedKey || v.Opaque == nil || !errors.Is(err, ErrKeyFuncError) {
+			return token, err
+		}
+	}
+
+	claims, err := v.Opaque(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+	token := &Token{Raw: credential, Claims: claims}
+	if err = p.validateClaims(ctx, token); err != nil {
+		return token, err
+	}
+	if err = p.checkVerified(ctx, token); err != nil {
+		return token, err
+	}
+	token.Valid = true
+	return token, nil
+}
+
+// IsJWT reports whether s has the shape of a JWT in the JWS compact
+// serialization: three segments, the first of which decodes to a JSON object
+// carrying an "alg" header. It does not verify the token.
+func IsJWT(s string) bool {
+	if strings.Count(s, ".") != 2 {
+		return false
+	}
+	headerBytes, err := DecodeSegment(s[:strings.IndexByte(s, '.')])
+	if err != nil {
+		return false
+	}
+	var header map[string]interface{}
+	if err = json.Unmarshal(headerBytes, &header); err != nil {
+		return false
+	}
+	_, ok := header["alg"].(string)
+	return ok
+}
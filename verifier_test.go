@@ -0,0 +1,96 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chanced/go-jwt/v4"
+)
+
+func TestFallbackVerifier(t *testing.T) {
+	key := []byte("fallback secret")
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "jwt"}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errUnknown := errors.New("unknown opaque token")
+
+	v := &jwt.FallbackVerifier{
+		Keyfunc: func(*jwt.Token) (interface{}, error) { return key, nil },
+		Opaque: func(ctx context.Context, credential string) (jwt.Claims, error) {
+			if credential == "opaque-token" {
+				return jwt.MapClaims{"sub": "opaque"}, nil
+			}
+			return nil, errUnknown
+		},
+	}
+
+	var tests = []struct {
+		name       string
+		credential string
+		subject    string
+		err        error
+	}{
+		{"jwt", signed, "jwt", nil},
+		{"opaque", "opaque-token", "opaque", nil},
+		{"unknown opaque", "other-token", "", errUnknown},
+		{"tampered jwt is not retried as opaque", signed + "x", "", jwt.ErrSignatureInvalid},
+	}
+
+	for _, data := range tests {
+		token, err := v.Verify(context.Background(), data.credential)
+		if data.err != nil {
+			if !errors.Is(err, data.err) {
+				t.Errorf("[%v] Expected %v. Got: %v", data.name, data.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+			continue
+		}
+		if !token.Valid || token.Claims.(jwt.MapClaims)["sub"] != data.subject {
+			t.Errorf("[%v] Unexpected token: %v", data.name, token.Claims)
+		}
+	}
+}
+
+func TestIsJWT(t *testing.T) {
+	var tests = []struct {
+		s        string
+		expected bool
+	}{
+		{"eyJhbGciOiJIUzI1NiJ9.e30.sig", true},
+		{"2YotnFZFEjr1zCsicMWpAA", false},
+		{"a.b.c", false},
+		{"eyJ0eXAiOiJKV1QifQ.e30.sig", false}, // no alg
+	}
+	for _, data := range tests {
+		if got := jwt.IsJWT(data.s); got != data.expected {
+			t.Errorf("[%v] Expected %v. Got: %v", data.s, data.expected, got)
+		}
+	}
+}
+
+func TestFallbackVerifier_unresolvedKey(t *testing.T) {
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "jwt"}, jwt.WithKeyID("unknown")).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &jwt.FallbackVerifier{
+		Keyfunc: func(*jwt.Token) (interface{}, error) { return nil, jwt.ErrUnknownKeyID },
+		Opaque: func(ctx context.Context, credential string) (jwt.Claims, error) {
+			return jwt.MapClaims{"sub": "introspected"}, nil
+		},
+	}
+	if _, err = v.Verify(context.Background(), signed); !errors.Is(err, jwt.ErrUnknownKeyID) {
+		t.Errorf("Expected ErrUnknownKeyID, got %v", err)
+	}
+
+	v.FallbackOnUnresolvedKey = true
+	token, err := v.Verify(context.Background(), signed)
+	if err != nil || token.Claims.(jwt.MapClaims)["sub"] != "introspected" {
+		t.Errorf("Expected the token to be handed to Opaque, got %v, %v", token, err)
+	}
+}